@@ -42,6 +42,17 @@ type FakePushActor struct {
 		result2 bool
 		result3 error
 	}
+	MatchFilesStub        func(localFiles []models.AppFileFields, appDir string) ([]models.AppFileFields, []resources.AppFileResource, error)
+	matchFilesMutex       sync.RWMutex
+	matchFilesArgsForCall []struct {
+		localFiles []models.AppFileFields
+		appDir     string
+	}
+	matchFilesReturns struct {
+		result1 []models.AppFileFields
+		result2 []resources.AppFileResource
+		result3 error
+	}
 }
 
 func (fake *FakePushActor) UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error {
@@ -147,4 +158,39 @@ func (fake *FakePushActor) GatherFilesReturns(result1 []resources.AppFileResourc
 	}{result1, result2, result3}
 }
 
+func (fake *FakePushActor) MatchFiles(localFiles []models.AppFileFields, appDir string) ([]models.AppFileFields, []resources.AppFileResource, error) {
+	fake.matchFilesMutex.Lock()
+	fake.matchFilesArgsForCall = append(fake.matchFilesArgsForCall, struct {
+		localFiles []models.AppFileFields
+		appDir     string
+	}{localFiles, appDir})
+	fake.matchFilesMutex.Unlock()
+	if fake.MatchFilesStub != nil {
+		return fake.MatchFilesStub(localFiles, appDir)
+	} else {
+		return fake.matchFilesReturns.result1, fake.matchFilesReturns.result2, fake.matchFilesReturns.result3
+	}
+}
+
+func (fake *FakePushActor) MatchFilesCallCount() int {
+	fake.matchFilesMutex.RLock()
+	defer fake.matchFilesMutex.RUnlock()
+	return len(fake.matchFilesArgsForCall)
+}
+
+func (fake *FakePushActor) MatchFilesArgsForCall(i int) ([]models.AppFileFields, string) {
+	fake.matchFilesMutex.RLock()
+	defer fake.matchFilesMutex.RUnlock()
+	return fake.matchFilesArgsForCall[i].localFiles, fake.matchFilesArgsForCall[i].appDir
+}
+
+func (fake *FakePushActor) MatchFilesReturns(result1 []models.AppFileFields, result2 []resources.AppFileResource, result3 error) {
+	fake.MatchFilesStub = nil
+	fake.matchFilesReturns = struct {
+		result1 []models.AppFileFields
+		result2 []resources.AppFileResource
+		result3 error
+	}{result1, result2, result3}
+}
+
 var _ actors.PushActor = new(FakePushActor)
@@ -0,0 +1,170 @@
+package pathplugin
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+	"github.com/cloudfoundry/cli/cf/net"
+)
+
+// Prefix is the start of the name of executables that provide cf commands,
+// e.g. cf-deploy provides `cf deploy`.
+const Prefix = "cf-"
+
+// PathPlugin is an executable on PATH providing the command Name.
+type PathPlugin struct {
+	Name     string
+	Location string
+}
+
+// Find looks up the executable providing the command name on PATH.
+func Find(name string) (PathPlugin, bool) {
+	if !isCommandName(name) || isCredentialHelper(Prefix+name) {
+		return PathPlugin{}, false
+	}
+
+	location, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return PathPlugin{}, false
+	}
+
+	return PathPlugin{Name: name, Location: location}, true
+}
+
+// List returns the executables providing commands found on PATH, sorted by
+// command name. When several directories hold an executable for the same
+// command the one found first wins, the way Find picks it.
+func List() []PathPlugin {
+	found := map[string]PathPlugin{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			dir = "."
+		}
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			if !strings.HasPrefix(file.Name(), Prefix) || isCredentialHelper(file.Name()) || !isExecutable(dir, file) {
+				continue
+			}
+
+			name := commandName(file.Name())
+			if _, ok := found[name]; ok || !isCommandName(name) {
+				continue
+			}
+			found[name] = PathPlugin{Name: name, Location: filepath.Join(dir, file.Name())}
+		}
+	}
+
+	names := []string{}
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pathPlugins := []PathPlugin{}
+	for _, name := range names {
+		pathPlugins = append(pathPlugins, found[name])
+	}
+	return pathPlugins
+}
+
+// Env returns the environment of the executables: the environment of the
+// CLI plus the API endpoint, the access token, the target and the
+// certificates to connect with.
+func Env(config coreconfig.Reader, accessToken string) []string {
+	certificates := net.NewCertificateFiles(config)
+
+	return append(os.Environ(),
+		"CF_API_ENDPOINT="+config.APIEndpoint(),
+		"CF_SKIP_SSL_VALIDATION="+strconv.FormatBool(config.IsSSLDisabled()),
+		"CF_ACCESS_TOKEN="+accessToken,
+		"CF_USERNAME="+config.Username(),
+		"CF_ORG="+config.OrganizationFields().Name,
+		"CF_ORG_GUID="+config.OrganizationFields().GUID,
+		"CF_SPACE="+config.SpaceFields().Name,
+		"CF_SPACE_GUID="+config.SpaceFields().GUID,
+		"CF_CA_CERT="+certificates.CACert,
+		"CF_CLIENT_CERT="+certificates.ClientCert,
+		"CF_CLIENT_KEY="+certificates.ClientKey,
+	)
+}
+
+// Run runs the executable with args and env, attached to the terminal, and
+// returns its exit status. An error means the executable could not be run.
+func Run(pathPlugin PathPlugin, args []string, env []string) (int, error) {
+	cmd := exec.Command(pathPlugin.Location, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus(), nil
+		}
+		return 1, nil
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// isCredentialHelper tells whether the executable is a credential helper
+// rather than a command, as their names start with Prefix too.
+func isCredentialHelper(fileName string) bool {
+	return strings.HasPrefix(fileName, credentials.ExecutablePrefix)
+}
+
+func isCommandName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, "-") && !strings.ContainsAny(name, `/\`)
+}
+
+func commandName(fileName string) string {
+	name := strings.TrimPrefix(fileName, Prefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+func isExecutable(dir string, file os.FileInfo) bool {
+	if file.Mode()&os.ModeSymlink != 0 {
+		var err error
+		file, err = os.Stat(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return false
+		}
+	}
+
+	if file.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(file.Name()))
+		for _, executableExt := range filepath.SplitList(strings.ToLower(os.Getenv("PATHEXT"))) {
+			if ext != "" && ext == executableExt {
+				return true
+			}
+		}
+		return false
+	}
+
+	return file.Mode()&0111 != 0
+}
@@ -0,0 +1,13 @@
+package pathplugin_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPathPlugin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PathPlugin Suite")
+}
@@ -0,0 +1,177 @@
+package pathplugin_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/actors/pathplugin"
+	"github.com/cloudfoundry/cli/cf/models"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PathPlugin", func() {
+	var (
+		firstDir  string
+		secondDir string
+		oldPath   string
+	)
+
+	writeExecutable := func(dir string, name string, script string) string {
+		location := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(location, []byte("#!/bin/sh\n"+script+"\n"), 0755)).To(Succeed())
+		return location
+	}
+
+	BeforeEach(func() {
+		var err error
+		firstDir, err = ioutil.TempDir("", "path-plugin-first")
+		Expect(err).NotTo(HaveOccurred())
+		secondDir, err = ioutil.TempDir("", "path-plugin-second")
+		Expect(err).NotTo(HaveOccurred())
+
+		oldPath = os.Getenv("PATH")
+		os.Setenv("PATH", strings.Join([]string{firstDir, secondDir}, string(os.PathListSeparator)))
+	})
+
+	AfterEach(func() {
+		os.Setenv("PATH", oldPath)
+		os.RemoveAll(firstDir)
+		os.RemoveAll(secondDir)
+	})
+
+	Describe("Find", func() {
+		It("finds the cf-NAME executable on PATH", func() {
+			location := writeExecutable(secondDir, "cf-deploy", "")
+
+			pathPlugin, found := Find("deploy")
+			Expect(found).To(BeTrue())
+			Expect(pathPlugin).To(Equal(PathPlugin{Name: "deploy", Location: location}))
+		})
+
+		It("prefers the directory listed first on PATH", func() {
+			location := writeExecutable(firstDir, "cf-deploy", "")
+			writeExecutable(secondDir, "cf-deploy", "")
+
+			pathPlugin, _ := Find("deploy")
+			Expect(pathPlugin.Location).To(Equal(location))
+		})
+
+		It("does not find files that are not executable", func() {
+			Expect(ioutil.WriteFile(filepath.Join(firstDir, "cf-deploy"), []byte(""), 0644)).To(Succeed())
+
+			_, found := Find("deploy")
+			Expect(found).To(BeFalse())
+		})
+
+		It("does not look up names that are paths or flags", func() {
+			Expect(os.Mkdir(filepath.Join(firstDir, "cf-sub"), 0755)).To(Succeed())
+			writeExecutable(filepath.Join(firstDir, "cf-sub"), "deploy", "")
+			writeExecutable(firstDir, "cf--x", "")
+
+			_, found := Find("sub/deploy")
+			Expect(found).To(BeFalse())
+			_, found = Find("-x")
+			Expect(found).To(BeFalse())
+			_, found = Find("")
+			Expect(found).To(BeFalse())
+		})
+
+		It("does not find credential helpers", func() {
+			writeExecutable(firstDir, "cf-credential-vault", "")
+
+			_, found := Find("credential-vault")
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the executables by command name, the first on PATH winning", func() {
+			deploy := writeExecutable(secondDir, "cf-deploy", "")
+			backup := writeExecutable(firstDir, "cf-backup", "")
+			writeExecutable(secondDir, "cf-backup", "")
+			Expect(ioutil.WriteFile(filepath.Join(firstDir, "cf-notes"), []byte(""), 0644)).To(Succeed())
+			Expect(os.Mkdir(filepath.Join(firstDir, "cf-dir"), 0755)).To(Succeed())
+			writeExecutable(firstDir, "other", "")
+			writeExecutable(firstDir, "cf-credential-vault", "")
+
+			Expect(List()).To(Equal([]PathPlugin{
+				{Name: "backup", Location: backup},
+				{Name: "deploy", Location: deploy},
+			}))
+		})
+
+		It("follows symlinks", func() {
+			target := writeExecutable(secondDir, "deploy-tool", "")
+			Expect(os.Symlink(target, filepath.Join(firstDir, "cf-deploy"))).To(Succeed())
+			Expect(os.Symlink(secondDir, filepath.Join(firstDir, "cf-linked-dir"))).To(Succeed())
+
+			Expect(List()).To(Equal([]PathPlugin{
+				{Name: "deploy", Location: filepath.Join(firstDir, "cf-deploy")},
+			}))
+		})
+	})
+
+	Describe("Env", func() {
+		It("adds the API endpoint, the access token and the target to the environment", func() {
+			config := testconfig.NewRepositoryWithDefaults()
+			config.SetAPIEndpoint("https://api.example.com")
+			config.SetSSLDisabled(true)
+			config.SetOrganizationFields(models.OrganizationFields{Name: "my-org", GUID: "my-org-guid"})
+
+			env := Env(config, "bearer my-token")
+			Expect(env).To(ContainElement("PATH=" + os.Getenv("PATH")))
+			Expect(env).To(ContainElement("CF_API_ENDPOINT=https://api.example.com"))
+			Expect(env).To(ContainElement("CF_SKIP_SSL_VALIDATION=true"))
+			Expect(env).To(ContainElement("CF_ACCESS_TOKEN=bearer my-token"))
+			Expect(env).To(ContainElement("CF_USERNAME=my-user"))
+			Expect(env).To(ContainElement("CF_ORG=my-org"))
+			Expect(env).To(ContainElement("CF_ORG_GUID=my-org-guid"))
+			Expect(env).To(ContainElement("CF_SPACE=my-space"))
+			Expect(env).To(ContainElement("CF_SPACE_GUID=my-space-guid"))
+		})
+
+		It("adds the certificates to connect with", func() {
+			config := testconfig.NewRepositoryWithDefaults()
+			config.SetCACertFile("/certs/ca.pem")
+			config.SetClientCertificate("/certs/client.pem", "/certs/client-key.pem")
+
+			env := Env(config, "bearer my-token")
+			Expect(env).To(ContainElement("CF_CA_CERT=/certs/ca.pem"))
+			Expect(env).To(ContainElement("CF_CLIENT_CERT=/certs/client.pem"))
+			Expect(env).To(ContainElement("CF_CLIENT_KEY=/certs/client-key.pem"))
+		})
+	})
+
+	Describe("Run", func() {
+		It("runs the executable with the args and the environment", func() {
+			output := filepath.Join(firstDir, "output")
+			writeExecutable(firstDir, "cf-deploy", `echo "$1 $2 $CF_ORG" > `+output)
+			pathPlugin, _ := Find("deploy")
+
+			exitStatus, err := Run(pathPlugin, []string{"my-app", "--force"}, []string{"CF_ORG=my-org"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitStatus).To(Equal(0))
+			Expect(ioutil.ReadFile(output)).To(Equal([]byte("my-app --force my-org\n")))
+		})
+
+		It("returns the exit status of the executable", func() {
+			writeExecutable(firstDir, "cf-deploy", "exit 3")
+			pathPlugin, _ := Find("deploy")
+
+			exitStatus, err := Run(pathPlugin, []string{}, []string{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitStatus).To(Equal(3))
+		})
+
+		It("returns an error when the executable cannot be run", func() {
+			exitStatus, err := Run(PathPlugin{Name: "deploy", Location: filepath.Join(firstDir, "cf-deploy")}, []string{}, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(exitStatus).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package pluginrepo
+
+import (
+	"strconv"
+	"strings"
+
+	clipr "github.com/cloudfoundry-incubator/cli-plugin-repo/models"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/plugin"
+)
+
+// ParseVersion reads versions such as "1.2.3" or "v1.2" that plugin
+// repositories list. Missing parts are zero, and anything after the numbers
+// of a part, such as "-beta", is ignored.
+func ParseVersion(version string) (plugin.VersionType, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".", 3)
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		end := strings.IndexFunc(part, func(c rune) bool { return c < '0' || c > '9' })
+		if end == -1 {
+			end = len(part)
+		}
+
+		number, err := strconv.Atoi(part[:end])
+		if err != nil {
+			return plugin.VersionType{}, false
+		}
+		numbers[i] = number
+	}
+
+	return plugin.VersionType{Major: numbers[0], Minor: numbers[1], Build: numbers[2]}, true
+}
+
+// IsNewerVersion tells whether version a is newer than version b.
+func IsNewerVersion(a, b plugin.VersionType) bool {
+	if a.Major != b.Major {
+		return a.Major > b.Major
+	}
+	if a.Minor != b.Minor {
+		return a.Minor > b.Minor
+	}
+	return a.Build > b.Build
+}
+
+// LatestPlugin finds the newest version of the named plugin in the plugins
+// listed by the given repositories, and the name of the repository listing
+// it. Names are compared ignoring case, and when repositories list the same
+// version the one registered first wins.
+func LatestPlugin(repoPlugins map[string][]clipr.Plugin, repos []models.PluginRepo, name string) (clipr.Plugin, string, bool) {
+	var (
+		latest        clipr.Plugin
+		latestRepo    string
+		latestVersion plugin.VersionType
+		found         bool
+	)
+
+	for _, repo := range repos {
+		for _, p := range repoPlugins[repo.Name] {
+			if !strings.EqualFold(p.Name, name) {
+				continue
+			}
+
+			version, ok := ParseVersion(p.Version)
+			if !ok {
+				continue
+			}
+
+			if !found || IsNewerVersion(version, latestVersion) {
+				latest, latestRepo, latestVersion, found = p, repo.Name, version, true
+			}
+		}
+	}
+
+	return latest, latestRepo, found
+}
@@ -0,0 +1,84 @@
+package pluginrepo_test
+
+import (
+	clipr "github.com/cloudfoundry-incubator/cli-plugin-repo/models"
+	. "github.com/cloudfoundry/cli/cf/actors/pluginrepo"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/plugin"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Versions", func() {
+	Describe("ParseVersion", func() {
+		It("reads the major, minor and build numbers", func() {
+			version, ok := ParseVersion("1.2.3")
+			Expect(ok).To(BeTrue())
+			Expect(version).To(Equal(plugin.VersionType{Major: 1, Minor: 2, Build: 3}))
+
+			version, _ = ParseVersion("v2.10")
+			Expect(version).To(Equal(plugin.VersionType{Major: 2, Minor: 10}))
+
+			version, _ = ParseVersion("0.3.1-beta")
+			Expect(version).To(Equal(plugin.VersionType{Minor: 3, Build: 1}))
+		})
+
+		It("rejects versions that do not start with numbers", func() {
+			_, ok := ParseVersion("latest")
+			Expect(ok).To(BeFalse())
+
+			_, ok = ParseVersion("")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("IsNewerVersion", func() {
+		It("compares the major, then minor, then build numbers", func() {
+			Expect(IsNewerVersion(plugin.VersionType{Major: 2}, plugin.VersionType{Major: 1, Minor: 9, Build: 9})).To(BeTrue())
+			Expect(IsNewerVersion(plugin.VersionType{Major: 1, Minor: 2}, plugin.VersionType{Major: 1, Minor: 1, Build: 5})).To(BeTrue())
+			Expect(IsNewerVersion(plugin.VersionType{Major: 1, Build: 2}, plugin.VersionType{Major: 1, Build: 1})).To(BeTrue())
+			Expect(IsNewerVersion(plugin.VersionType{Major: 1, Build: 1}, plugin.VersionType{Major: 1, Build: 1})).To(BeFalse())
+			Expect(IsNewerVersion(plugin.VersionType{Major: 1}, plugin.VersionType{Major: 1, Minor: 1})).To(BeFalse())
+		})
+	})
+
+	Describe("LatestPlugin", func() {
+		var repos []models.PluginRepo
+
+		BeforeEach(func() {
+			repos = []models.PluginRepo{{Name: "repo1"}, {Name: "repo2"}}
+		})
+
+		It("finds the newest version of a plugin in all repositories", func() {
+			repoPlugins := map[string][]clipr.Plugin{
+				"repo1": {{Name: "echo", Version: "1.0.0"}, {Name: "other", Version: "9.0.0"}},
+				"repo2": {{Name: "Echo", Version: "1.1.0"}},
+			}
+
+			latest, repoName, found := LatestPlugin(repoPlugins, repos, "echo")
+			Expect(found).To(BeTrue())
+			Expect(latest.Version).To(Equal("1.1.0"))
+			Expect(repoName).To(Equal("repo2"))
+		})
+
+		It("prefers the repository registered first for the same version", func() {
+			repoPlugins := map[string][]clipr.Plugin{
+				"repo1": {{Name: "echo", Version: "1.0.0"}},
+				"repo2": {{Name: "echo", Version: "1.0"}},
+			}
+
+			_, repoName, _ := LatestPlugin(repoPlugins, repos, "echo")
+			Expect(repoName).To(Equal("repo1"))
+		})
+
+		It("ignores plugins without a readable version", func() {
+			repoPlugins := map[string][]clipr.Plugin{
+				"repo1": {{Name: "echo", Version: "unknown"}},
+			}
+
+			_, _, found := LatestPlugin(repoPlugins, repos, "echo")
+			Expect(found).To(BeFalse())
+		})
+	})
+})
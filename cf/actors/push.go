@@ -22,6 +22,7 @@ type PushActor interface {
 	UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error
 	ProcessPath(dirOrZipFile string, f func(string)) error
 	GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string) ([]resources.AppFileResource, bool, error)
+	MatchFiles(localFiles []models.AppFileFields, appDir string) ([]models.AppFileFields, []resources.AppFileResource, error)
 }
 
 type PushActorImpl struct {
@@ -85,7 +86,54 @@ func (actor PushActorImpl) ProcessPath(dirOrZipFile string, f func(string)) erro
 	return nil
 }
 
+// GatherFiles copies the local files that the Cloud Controller does not
+// already have into uploadDir. It returns the files the Cloud Controller has,
+// and whether there are any files to upload.
 func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir string, uploadDir string) ([]resources.AppFileResource, bool, error) {
+	filesToUpload, remoteFiles, err := actor.matchFiles(localFiles)
+	if err != nil {
+		return []resources.AppFileResource{}, false, err
+	}
+
+	err = actor.appfiles.CopyFiles(filesToUpload, appDir, uploadDir)
+	if err != nil {
+		return []resources.AppFileResource{}, false, err
+	}
+
+	_, err = os.Stat(filepath.Join(appDir, ".cfignore"))
+	if err == nil {
+		err = fileutils.CopyPathToPath(filepath.Join(appDir, ".cfignore"), filepath.Join(uploadDir, ".cfignore"))
+		if err != nil {
+			return []resources.AppFileResource{}, false, err
+		}
+	}
+
+	err = setFileModes(remoteFiles, appDir)
+	if err != nil {
+		return []resources.AppFileResource{}, false, err
+	}
+
+	return remoteFiles, len(filesToUpload) > 0, nil
+}
+
+// MatchFiles asks the Cloud Controller which of the local files it already
+// has. It returns the files that still need to be uploaded, and the files the
+// Cloud Controller has along with the modes they should be given.
+func (actor PushActorImpl) MatchFiles(localFiles []models.AppFileFields, appDir string) ([]models.AppFileFields, []resources.AppFileResource, error) {
+	filesToUpload, remoteFiles, err := actor.matchFiles(localFiles)
+	if err != nil {
+		return nil, []resources.AppFileResource{}, err
+	}
+
+	err = setFileModes(remoteFiles, appDir)
+	if err != nil {
+		return nil, []resources.AppFileResource{}, err
+	}
+
+	return filesToUpload, remoteFiles, nil
+}
+
+func (actor PushActorImpl) matchFiles(localFiles []models.AppFileFields) ([]models.AppFileFields, []resources.AppFileResource, error) {
 	appFileResource := []resources.AppFileResource{}
 	for _, file := range localFiles {
 		appFileResource = append(appFileResource, resources.AppFileResource{
@@ -97,7 +145,7 @@ func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir
 
 	remoteFiles, err := actor.appBitsRepo.GetApplicationFiles(appFileResource)
 	if err != nil {
-		return []resources.AppFileResource{}, false, err
+		return nil, nil, err
 	}
 
 	filesToUpload := make([]models.AppFileFields, len(localFiles), len(localFiles))
@@ -111,23 +159,14 @@ func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir
 		}
 	}
 
-	err = actor.appfiles.CopyFiles(filesToUpload, appDir, uploadDir)
-	if err != nil {
-		return []resources.AppFileResource{}, false, err
-	}
-
-	_, err = os.Stat(filepath.Join(appDir, ".cfignore"))
-	if err == nil {
-		err = fileutils.CopyPathToPath(filepath.Join(appDir, ".cfignore"), filepath.Join(uploadDir, ".cfignore"))
-		if err != nil {
-			return []resources.AppFileResource{}, false, err
-		}
-	}
+	return filesToUpload, remoteFiles, nil
+}
 
+func setFileModes(remoteFiles []resources.AppFileResource, appDir string) error {
 	for i := range remoteFiles {
 		fullPath, err := filepath.Abs(filepath.Join(appDir, remoteFiles[i].Path))
 		if err != nil {
-			return []resources.AppFileResource{}, false, err
+			return err
 		}
 
 		if runtime.GOOS == "windows" {
@@ -135,7 +174,7 @@ func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir
 		}
 		fileInfo, err := os.Lstat(fullPath)
 		if err != nil {
-			return []resources.AppFileResource{}, false, err
+			return err
 		}
 		fileMode := fileInfo.Mode()
 
@@ -146,7 +185,7 @@ func (actor PushActorImpl) GatherFiles(localFiles []models.AppFileFields, appDir
 		remoteFiles[i].Mode = fmt.Sprintf("%#o", fileMode)
 	}
 
-	return remoteFiles, len(filesToUpload) > 0, nil
+	return nil
 }
 
 func (actor PushActorImpl) UploadApp(appGUID string, zipFile *os.File, presentFiles []resources.AppFileResource) error {
@@ -261,6 +261,60 @@ var _ = Describe("Push Actor", func() {
 		})
 	})
 
+	Describe("MatchFiles", func() {
+		BeforeEach(func() {
+			appBitsRepo.GetApplicationFilesReturns([]resources.AppFileResource{
+				{Path: "example-app/ignore-me"},
+			}, nil)
+		})
+
+		It("asks the Cloud Controller about every local file", func() {
+			_, _, err := actor.MatchFiles(allFiles, fixturesDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(appBitsRepo.GetApplicationFilesCallCount()).To(Equal(1))
+			Expect(appBitsRepo.GetApplicationFilesArgsForCall(0)).To(HaveLen(len(allFiles)))
+		})
+
+		It("returns the files the Cloud Controller does not have, without copying them", func() {
+			filesToUpload, _, err := actor.MatchFiles(allFiles, fixturesDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filesToUpload).To(Equal([]models.AppFileFields{
+				{Path: "example-app/.cfignore"},
+				{Path: "example-app/app.rb"},
+				{Path: "example-app/config.ru"},
+				{Path: "example-app/Gemfile"},
+				{Path: "example-app/Gemfile.lock"},
+				{Path: "example-app/manifest.yml"},
+			}))
+			Expect(appFiles.CopyFilesCallCount()).To(BeZero())
+		})
+
+		It("returns the files the Cloud Controller has along with their modes", func() {
+			info, err := os.Lstat(filepath.Join(fixturesDir, "example-app/ignore-me"))
+			Expect(err).NotTo(HaveOccurred())
+
+			expectedFileMode := fmt.Sprintf("%#o", info.Mode())
+			if runtime.GOOS == "windows" {
+				expectedFileMode = fmt.Sprintf("%#o", info.Mode()|0700)
+			}
+
+			_, remoteFiles, err := actor.MatchFiles(allFiles, fixturesDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remoteFiles).To(Equal([]resources.AppFileResource{
+				{Path: "example-app/ignore-me", Mode: expectedFileMode},
+			}))
+		})
+
+		It("returns an error if we cannot reach the cc", func() {
+			appBitsRepo.GetApplicationFilesReturns(nil, errors.New("error"))
+
+			_, _, err := actor.MatchFiles(allFiles, fixturesDir)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe(".UploadApp", func() {
 		It("Simply delegates to the UploadApp function on the app bits repo, which is not worth testing", func() {})
 	})
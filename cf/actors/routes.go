@@ -59,6 +59,31 @@ func (routeActor RouteActor) FindOrCreateRoute(hostname string, domain models.Do
 	return
 }
 
+// FindOrCreateTCPRoute finds the route for a specific port on a TCP domain,
+// creating it in the given space if it does not exist yet.
+func (routeActor RouteActor) FindOrCreateTCPRoute(domain models.DomainFields, port int, spaceGUID string) (route models.Route) {
+	route, apiErr := routeActor.routeRepo.Find("", domain, "", port)
+
+	switch apiErr.(type) {
+	case nil:
+		routeActor.ui.Say(T("Using route {{.RouteURL}}", map[string]interface{}{"RouteURL": terminal.EntityNameColor(route.URL())}))
+	case *errors.ModelNotFoundError:
+		routeActor.ui.Say(T("Creating route {{.Hostname}}...", map[string]interface{}{"Hostname": terminal.EntityNameColor(domain.URLForHostAndPath("", "", port))}))
+
+		route, apiErr = routeActor.routeRepo.CreateInSpace("", "", domain.GUID, spaceGUID, port, false)
+		if apiErr != nil {
+			routeActor.ui.Failed(apiErr.Error())
+		}
+
+		routeActor.ui.Ok()
+		routeActor.ui.Say("")
+	default:
+		routeActor.ui.Failed(apiErr.Error())
+	}
+
+	return
+}
+
 func (routeActor RouteActor) BindRoute(app models.Application, route models.Route) {
 	if !app.HasRoute(route) {
 		routeActor.ui.Say(T("Binding {{.URL}} to {{.AppName}}...", map[string]interface{}{"URL": terminal.EntityNameColor(route.URL()), "AppName": terminal.EntityNameColor(app.Name)}))
@@ -84,3 +109,27 @@ func (routeActor RouteActor) UnbindAll(app models.Application) {
 		routeActor.routeRepo.Unbind(route.GUID, app.GUID)
 	}
 }
+
+// MoveRoutes binds every route of one app to another, then unbinds them from
+// the first. Routes are bound before any are unbound, so they are never left
+// without an app. The returned app is 'to' with its routes updated.
+func (routeActor RouteActor) MoveRoutes(from models.Application, to models.Application) models.Application {
+	for _, routeSummary := range from.Routes {
+		route := models.Route{
+			GUID:   routeSummary.GUID,
+			Host:   routeSummary.Host,
+			Domain: routeSummary.Domain,
+			Path:   routeSummary.Path,
+			Port:   routeSummary.Port,
+		}
+
+		if !to.HasRoute(route) {
+			routeActor.BindRoute(to, route)
+			to.Routes = append(to.Routes, routeSummary)
+		}
+	}
+
+	routeActor.UnbindAll(from)
+
+	return to
+}
@@ -6,6 +6,7 @@ import (
 	. "github.com/cloudfoundry/cli/cf/actors"
 
 	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	cferrors "github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/testhelpers/terminal"
 	. "github.com/onsi/ginkgo"
@@ -85,4 +86,121 @@ var _ = Describe("Routes", func() {
 			Expect(actualRoute).To(Equal(models.Route{}))
 		})
 	})
+
+	Describe("finding or creating a TCP route with a port", func() {
+		var domain models.DomainFields
+
+		BeforeEach(func() {
+			domain = models.DomainFields{
+				GUID: "domain-guid",
+				Name: "dies-tcp.com",
+			}
+		})
+
+		It("uses the route when it already exists", func() {
+			existingRoute := models.Route{GUID: "route-guid", Domain: domain, Port: 1234}
+			fakeRouteRepository.FindReturns(existingRoute, nil)
+
+			route := routeActor.FindOrCreateTCPRoute(domain, 1234, "space-guid")
+
+			Expect(route).To(Equal(existingRoute))
+			host, d, path, port := fakeRouteRepository.FindArgsForCall(0)
+			Expect(host).To(BeEmpty())
+			Expect(d).To(Equal(domain))
+			Expect(path).To(BeEmpty())
+			Expect(port).To(Equal(1234))
+			Expect(fakeRouteRepository.CreateInSpaceCallCount()).To(BeZero())
+			Expect(fakeUI.Outputs).To(ContainSubstrings([]string{"Using route", "dies-tcp.com:1234"}))
+		})
+
+		It("creates the route with the port in the given space when it does not exist", func() {
+			createdRoute := models.Route{GUID: "route-guid", Domain: domain, Port: 1234}
+			fakeRouteRepository.FindReturns(models.Route{}, cferrors.NewModelNotFoundError("Route", "dies-tcp.com:1234"))
+			fakeRouteRepository.CreateInSpaceReturns(createdRoute, nil)
+
+			route := routeActor.FindOrCreateTCPRoute(domain, 1234, "space-guid")
+
+			Expect(route).To(Equal(createdRoute))
+			host, path, domainGUID, spaceGUID, port, randomPort := fakeRouteRepository.CreateInSpaceArgsForCall(0)
+			Expect(host).To(BeEmpty())
+			Expect(path).To(BeEmpty())
+			Expect(domainGUID).To(Equal("domain-guid"))
+			Expect(spaceGUID).To(Equal("space-guid"))
+			Expect(port).To(Equal(1234))
+			Expect(randomPort).To(BeFalse())
+			Expect(fakeUI.Outputs).To(ContainSubstrings([]string{"Creating route", "dies-tcp.com:1234"}))
+		})
+
+		It("fails when the route cannot be found", func() {
+			fakeRouteRepository.FindReturns(models.Route{}, errors.New("find failed"))
+
+			Expect(func() {
+				routeActor.FindOrCreateTCPRoute(domain, 1234, "space-guid")
+			}).To(Panic())
+			Expect(fakeUI.Outputs).To(ContainSubstrings([]string{"find failed"}))
+		})
+	})
+
+	Describe("moving routes between apps", func() {
+		var (
+			from models.Application
+			to   models.Application
+		)
+
+		BeforeEach(func() {
+			from = models.Application{}
+			from.Name = "my-app-venerable"
+			from.GUID = "from-guid"
+			from.Routes = []models.RouteSummary{
+				{GUID: "route-1-guid", Host: "my-app", Domain: models.DomainFields{Name: "example.com"}},
+				{GUID: "route-2-guid", Host: "other", Domain: models.DomainFields{Name: "example.com"}},
+			}
+
+			to = models.Application{}
+			to.Name = "my-app"
+			to.GUID = "to-guid"
+			to.Routes = []models.RouteSummary{
+				{GUID: "route-2-guid", Host: "other", Domain: models.DomainFields{Name: "example.com"}},
+			}
+		})
+
+		It("binds the routes the new app does not already have", func() {
+			routeActor.MoveRoutes(from, to)
+
+			Expect(fakeRouteRepository.BindCallCount()).To(Equal(1))
+			routeGUID, appGUID := fakeRouteRepository.BindArgsForCall(0)
+			Expect(routeGUID).To(Equal("route-1-guid"))
+			Expect(appGUID).To(Equal("to-guid"))
+		})
+
+		It("unbinds every route from the old app", func() {
+			routeActor.MoveRoutes(from, to)
+
+			Expect(fakeRouteRepository.UnbindCallCount()).To(Equal(2))
+			routeGUID, appGUID := fakeRouteRepository.UnbindArgsForCall(0)
+			Expect(routeGUID).To(Equal("route-1-guid"))
+			Expect(appGUID).To(Equal("from-guid"))
+			routeGUID, appGUID = fakeRouteRepository.UnbindArgsForCall(1)
+			Expect(routeGUID).To(Equal("route-2-guid"))
+			Expect(appGUID).To(Equal("from-guid"))
+		})
+
+		It("returns the new app with all of the routes", func() {
+			app := routeActor.MoveRoutes(from, to)
+
+			Expect(app.Routes).To(HaveLen(2))
+			Expect(app.Routes[0].GUID).To(Equal("route-2-guid"))
+			Expect(app.Routes[1].GUID).To(Equal("route-1-guid"))
+		})
+
+		It("does not unbind anything when binding fails", func() {
+			fakeRouteRepository.BindReturns(errors.New("bind failed"))
+
+			Expect(func() {
+				routeActor.MoveRoutes(from, to)
+			}).To(Panic())
+
+			Expect(fakeRouteRepository.UnbindCallCount()).To(BeZero())
+		})
+	})
 })
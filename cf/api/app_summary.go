@@ -35,6 +35,8 @@ type ApplicationFromSummary struct {
 	URLs                 []string
 	EnvironmentVars      map[string]interface{} `json:"environment_json,omitempty"`
 	HealthCheckTimeout   int                    `json:"health_check_timeout"`
+	HealthCheckType      string                 `json:"health_check_type"`
+	DockerImage          string                 `json:"docker_image"`
 	State                string
 	DetectedStartCommand string     `json:"detected_start_command"`
 	SpaceGUID            string     `json:"space_guid"`
@@ -61,6 +63,8 @@ func (resource ApplicationFromSummary) ToFields() (app models.ApplicationFields)
 	app.PackageState = resource.PackageState
 	app.DetectedStartCommand = resource.DetectedStartCommand
 	app.HealthCheckTimeout = resource.HealthCheckTimeout
+	app.HealthCheckType = resource.HealthCheckType
+	app.DockerImage = resource.DockerImage
 	app.BuildpackURL = resource.Buildpack
 	app.Command = resource.Command
 	app.AppPorts = resource.AppPorts
@@ -126,6 +126,8 @@ var _ = Describe("AppSummaryRepository", func() {
 			Expect(app.Memory).To(Equal(int64(128)))
 			Expect(app.PackageUpdatedAt.Format("2006-01-02T15:04:05Z07:00")).To(Equal("2014-10-24T19:54:00Z"))
 			Expect(app.StackGUID).To(Equal("the-stack-guid"))
+			Expect(app.HealthCheckType).To(Equal("port"))
+			Expect(app.DockerImage).To(Equal("user/my-image"))
 		})
 	})
 
@@ -241,5 +243,7 @@ const getAppSummaryResponseBody string = `
 		"service_names":[
 			"my-service-instance"
 		],
-		"package_updated_at":"2014-10-24T19:54:00+00:00"
+		"package_updated_at":"2014-10-24T19:54:00+00:00",
+		"health_check_type":"port",
+		"docker_image":"user/my-image"
 }`
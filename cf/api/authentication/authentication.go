@@ -28,6 +28,7 @@ type AuthenticationRepository interface {
 
 	RefreshAuthToken() (updatedToken string, apiErr error)
 	Authenticate(credentials map[string]string) (apiErr error)
+	AuthenticateWithClientCredentials(clientID string, clientSecret string) (apiErr error)
 	Authorize(token string) (string, error)
 	GetLoginPromptsAndSaveUAAServerURL() (map[string]coreconfig.AuthPrompt, error)
 }
@@ -49,6 +50,11 @@ func NewUAAAuthenticationRepository(gateway net.Gateway, config coreconfig.ReadW
 }
 
 func (uaa UAAAuthenticationRepository) Authorize(token string) (string, error) {
+	tlsConfig, err := net.NewTLSConfigForConfig([]tls.Certificate{}, uaa.config)
+	if err != nil {
+		return "", err
+	}
+
 	httpClient := &http.Client{
 		CheckRedirect: func(req *http.Request, _ []*http.Request) error {
 			uaa.DumpRequest(req)
@@ -56,10 +62,8 @@ func (uaa UAAAuthenticationRepository) Authorize(token string) (string, error) {
 		},
 		Timeout: 30 * time.Second,
 		Transport: &http.Transport{
-			DisableKeepAlives: true,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: uaa.config.IsSSLDisabled(),
-			},
+			DisableKeepAlives:   true,
+			TLSClientConfig:     tlsConfig,
 			Proxy:               http.ProxyFromEnvironment,
 			TLSHandshakeTimeout: 10 * time.Second,
 		},
@@ -119,24 +123,49 @@ func (uaa UAAAuthenticationRepository) Authenticate(credentials map[string]strin
 		data[key] = []string{val}
 	}
 
-	err := uaa.getAuthToken(data)
+	err := uaa.getAuthToken(data, "cf", "")
 	if err != nil {
-		httpError, ok := err.(errors.HTTPError)
-		if ok {
-			switch {
-			case httpError.StatusCode() == http.StatusUnauthorized:
-				return errors.New(T("Credentials were rejected, please try again."))
-			case httpError.StatusCode() >= http.StatusInternalServerError:
-				return errors.New(T("The targeted API endpoint could not be reached."))
-			}
-		}
+		return authenticationError(err)
+	}
 
-		return err
+	return nil
+}
+
+// AuthenticateWithClientCredentials logs in as the client itself, with the
+// client_credentials grant. The client's credentials are kept so that
+// RefreshAuthToken can be granted a new token with them, since clients are
+// not given refresh tokens.
+func (uaa UAAAuthenticationRepository) AuthenticateWithClientCredentials(clientID string, clientSecret string) error {
+	data := url.Values{
+		"grant_type": {"client_credentials"},
+	}
+
+	err := uaa.getAuthToken(data, clientID, clientSecret)
+	if err != nil {
+		return authenticationError(err)
 	}
 
+	uaa.config.SetUAAGrantType(coreconfig.ClientCredentialsGrantType)
+	uaa.config.SetUAAOAuthClient(clientID)
+	uaa.config.SetUAAOAuthClientSecret(clientSecret)
+
 	return nil
 }
 
+func authenticationError(err error) error {
+	httpError, ok := err.(errors.HTTPError)
+	if ok {
+		switch {
+		case httpError.StatusCode() == http.StatusUnauthorized:
+			return errors.New(T("Credentials were rejected, please try again."))
+		case httpError.StatusCode() >= http.StatusInternalServerError:
+			return errors.New(T("The targeted API endpoint could not be reached."))
+		}
+	}
+
+	return err
+}
+
 func (uaa UAAAuthenticationRepository) DumpRequest(req *http.Request) {
 	uaa.dumper.DumpRequest(req)
 }
@@ -181,19 +210,27 @@ func (uaa UAAAuthenticationRepository) GetLoginPromptsAndSaveUAAServerURL() (pro
 }
 
 func (uaa UAAAuthenticationRepository) RefreshAuthToken() (string, error) {
-	data := url.Values{
-		"refresh_token": {uaa.config.RefreshToken()},
-		"grant_type":    {"refresh_token"},
-		"scope":         {""},
+	var apiErr error
+	if uaa.config.UAAGrantType() == coreconfig.ClientCredentialsGrantType {
+		data := url.Values{
+			"grant_type": {"client_credentials"},
+		}
+		apiErr = uaa.getAuthToken(data, uaa.config.UAAOAuthClient(), uaa.config.UAAOAuthClientSecret())
+	} else {
+		data := url.Values{
+			"refresh_token": {uaa.config.RefreshToken()},
+			"grant_type":    {"refresh_token"},
+			"scope":         {""},
+		}
+		apiErr = uaa.getAuthToken(data, "cf", "")
 	}
 
-	apiErr := uaa.getAuthToken(data)
 	updatedToken := uaa.config.AccessToken()
 
 	return updatedToken, apiErr
 }
 
-func (uaa UAAAuthenticationRepository) getAuthToken(data url.Values) error {
+func (uaa UAAAuthenticationRepository) getAuthToken(data url.Values, clientID string, clientSecret string) error {
 	type uaaErrorResponse struct {
 		Code        string `json:"error"`
 		Description string `json:"error_description"`
@@ -207,7 +244,7 @@ func (uaa UAAAuthenticationRepository) getAuthToken(data url.Values) error {
 	}
 
 	path := fmt.Sprintf("%s/oauth/token", uaa.config.AuthenticationEndpoint())
-	request, err := uaa.gateway.NewRequest("POST", path, "Basic "+base64.StdEncoding.EncodeToString([]byte("cf:")), strings.NewReader(data.Encode()))
+	request, err := uaa.gateway.NewRequest("POST", path, "Basic "+base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret)), strings.NewReader(data.Encode()))
 	if err != nil {
 		return fmt.Errorf("%s: %s", T("Failed to start oauth request"), err.Error())
 	}
@@ -136,6 +136,43 @@ var _ = Describe("AuthenticationRepository", func() {
 			})
 		})
 
+		Describe("authenticating with client credentials", func() {
+			var err error
+
+			JustBeforeEach(func() {
+				err = auth.AuthenticateWithClientCredentials("my-client", "my-secret")
+			})
+
+			Context("when login succeeds", func() {
+				BeforeEach(func() {
+					setupTestServer(successfulClientCredentialsLoginRequest)
+				})
+
+				It("stores the access token and the client credentials in the config", func() {
+					Expect(handler).To(HaveAllRequestsCalled())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(config.AccessToken()).To(Equal("BEARER my_client_access_token"))
+					Expect(config.RefreshToken()).To(BeEmpty())
+					Expect(config.UAAGrantType()).To(Equal(coreconfig.ClientCredentialsGrantType))
+					Expect(config.UAAOAuthClient()).To(Equal("my-client"))
+					Expect(config.UAAOAuthClientSecret()).To(Equal("my-secret"))
+				})
+			})
+
+			Context("when the client credentials are rejected", func() {
+				BeforeEach(func() {
+					setupTestServer(unsuccessfulLoginRequest)
+				})
+
+				It("returns an error", func() {
+					Expect(handler).To(HaveAllRequestsCalled())
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("Credentials were rejected, please try again."))
+					Expect(config.UAAGrantType()).To(BeEmpty())
+				})
+			})
+		})
+
 		Describe("getting login info", func() {
 			var (
 				apiErr  error
@@ -220,6 +257,21 @@ var _ = Describe("AuthenticationRepository", func() {
 					Expect(apiErr).NotTo(BeNil())
 				})
 			})
+
+			Context("when a client logged in with client credentials", func() {
+				BeforeEach(func() {
+					setupTestServer(successfulClientCredentialsLoginRequest)
+					config.SetUAAGrantType(coreconfig.ClientCredentialsGrantType)
+					config.SetUAAOAuthClient("my-client")
+					config.SetUAAOAuthClientSecret("my-secret")
+				})
+
+				It("is granted a new token with the client credentials", func() {
+					Expect(handler).To(HaveAllRequestsCalled())
+					Expect(apiErr).NotTo(HaveOccurred())
+					Expect(config.AccessToken()).To(Equal("BEARER my_client_access_token"))
+				})
+			})
 		})
 	})
 
@@ -360,6 +412,34 @@ var successfulLoginMatcher = func(request *http.Request) {
 	Expect(request.Form.Get("scope")).To(Equal(""))
 }
 
+var successfulClientCredentialsLoginRequest = testnet.TestRequest{
+	Method: "POST",
+	Path:   "/oauth/token",
+	Header: http.Header{
+		"accept":        {"application/json"},
+		"content-type":  {"application/x-www-form-urlencoded"},
+		"authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte("my-client:my-secret"))},
+	},
+	Matcher: func(request *http.Request) {
+		err := request.ParseForm()
+		if err != nil {
+			Fail(fmt.Sprintf("Failed to parse form: %s", err))
+			return
+		}
+
+		Expect(request.Form.Get("grant_type")).To(Equal("client_credentials"))
+		Expect(request.Form.Get("refresh_token")).To(BeEmpty())
+	},
+	Response: testnet.TestResponse{
+		Status: http.StatusOK,
+		Body: `
+{
+  "access_token": "my_client_access_token",
+  "token_type": "BEARER",
+  "expires_in": 43199
+} `},
+}
+
 var unsuccessfulLoginRequest = testnet.TestRequest{
 	Method: "POST",
 	Path:   "/oauth/token",
@@ -35,6 +35,15 @@ type FakeAuthenticationRepository struct {
 	authenticateReturns struct {
 		result1 error
 	}
+	AuthenticateWithClientCredentialsStub        func(clientID string, clientSecret string) (apiErr error)
+	authenticateWithClientCredentialsMutex       sync.RWMutex
+	authenticateWithClientCredentialsArgsForCall []struct {
+		clientID     string
+		clientSecret string
+	}
+	authenticateWithClientCredentialsReturns struct {
+		result1 error
+	}
 	AuthorizeStub        func(token string) (string, error)
 	authorizeMutex       sync.RWMutex
 	authorizeArgsForCall []struct {
@@ -156,6 +165,39 @@ func (fake *FakeAuthenticationRepository) AuthenticateReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAuthenticationRepository) AuthenticateWithClientCredentials(clientID string, clientSecret string) (apiErr error) {
+	fake.authenticateWithClientCredentialsMutex.Lock()
+	fake.authenticateWithClientCredentialsArgsForCall = append(fake.authenticateWithClientCredentialsArgsForCall, struct {
+		clientID     string
+		clientSecret string
+	}{clientID, clientSecret})
+	fake.authenticateWithClientCredentialsMutex.Unlock()
+	if fake.AuthenticateWithClientCredentialsStub != nil {
+		return fake.AuthenticateWithClientCredentialsStub(clientID, clientSecret)
+	} else {
+		return fake.authenticateWithClientCredentialsReturns.result1
+	}
+}
+
+func (fake *FakeAuthenticationRepository) AuthenticateWithClientCredentialsCallCount() int {
+	fake.authenticateWithClientCredentialsMutex.RLock()
+	defer fake.authenticateWithClientCredentialsMutex.RUnlock()
+	return len(fake.authenticateWithClientCredentialsArgsForCall)
+}
+
+func (fake *FakeAuthenticationRepository) AuthenticateWithClientCredentialsArgsForCall(i int) (string, string) {
+	fake.authenticateWithClientCredentialsMutex.RLock()
+	defer fake.authenticateWithClientCredentialsMutex.RUnlock()
+	return fake.authenticateWithClientCredentialsArgsForCall[i].clientID, fake.authenticateWithClientCredentialsArgsForCall[i].clientSecret
+}
+
+func (fake *FakeAuthenticationRepository) AuthenticateWithClientCredentialsReturns(result1 error) {
+	fake.AuthenticateWithClientCredentialsStub = nil
+	fake.authenticateWithClientCredentialsReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAuthenticationRepository) Authorize(token string) (string, error) {
 	fake.authorizeMutex.Lock()
 	fake.authorizeArgsForCall = append(fake.authorizeArgsForCall, struct {
@@ -0,0 +1,82 @@
+package logs
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LogFilter selects which log messages are shown. A zero LogFilter matches
+// every message.
+type LogFilter struct {
+	// Sources are source types such as RTR or APP. Source types with a path,
+	// such as APP/PROC/WEB, match on their first part.
+	Sources  []string
+	Instance string
+	Pattern  *regexp.Regexp
+	Since    time.Time
+}
+
+func (filter LogFilter) Matches(msg Loggable) bool {
+	if len(filter.Sources) > 0 && !filter.matchesSource(msg.GetSourceName()) {
+		return false
+	}
+
+	if filter.Instance != "" && msg.GetSourceInstance() != filter.Instance {
+		return false
+	}
+
+	if filter.Pattern != nil && !filter.Pattern.MatchString(msg.ToSimpleLog()) {
+		return false
+	}
+
+	if !filter.Since.IsZero() && msg.GetTimestamp().Before(filter.Since) {
+		return false
+	}
+
+	return true
+}
+
+func (filter LogFilter) matchesSource(sourceName string) bool {
+	sourceType := strings.ToUpper(strings.SplitN(sourceName, "/", 2)[0])
+	for _, source := range filter.Sources {
+		if strings.ToUpper(source) == sourceType {
+			return true
+		}
+	}
+	return false
+}
+
+type structuredLog struct {
+	Timestamp      string `json:"timestamp" yaml:"timestamp"`
+	SourceType     string `json:"source_type" yaml:"source_type"`
+	SourceInstance string `json:"source_instance" yaml:"source_instance"`
+	Stream         string `json:"stream" yaml:"stream"`
+	Message        string `json:"message" yaml:"message"`
+}
+
+func newStructuredLog(msg Loggable) structuredLog {
+	return structuredLog{
+		Timestamp:      msg.GetTimestamp().UTC().Format(time.RFC3339Nano),
+		SourceType:     msg.GetSourceName(),
+		SourceInstance: msg.GetSourceInstance(),
+		Stream:         msg.GetMessageType(),
+		Message:        msg.ToSimpleLog(),
+	}
+}
+
+// ToJSONLog formats the message as a single line JSON object.
+func ToJSONLog(msg Loggable) string {
+	contents, _ := json.Marshal(newStructuredLog(msg))
+	return string(contents)
+}
+
+// ToYAMLLog formats the message as a YAML document, starting with the ---
+// separator so that a stream of messages is a stream of documents.
+func ToYAMLLog(msg Loggable) string {
+	contents, _ := yaml.Marshal(newStructuredLog(msg))
+	return "---\n" + strings.TrimSuffix(string(contents), "\n")
+}
@@ -0,0 +1,104 @@
+package logs_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api/logs"
+	testlogs "github.com/cloudfoundry/cli/testhelpers/logs"
+	"github.com/cloudfoundry/loggregatorlib/logmessage"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LogFilter", func() {
+	var msg logs.Loggable
+
+	BeforeEach(func() {
+		msg = testlogs.NewLogMessage("GET /health 200", "", "RTR", "1", logmessage.LogMessage_OUT, time.Now().Add(-5*time.Minute))
+	})
+
+	It("matches every message when it is empty", func() {
+		Expect(logs.LogFilter{}.Matches(msg)).To(BeTrue())
+	})
+
+	Describe("Sources", func() {
+		It("matches messages from any of the sources", func() {
+			Expect(logs.LogFilter{Sources: []string{"APP", "RTR"}}.Matches(msg)).To(BeTrue())
+			Expect(logs.LogFilter{Sources: []string{"APP"}}.Matches(msg)).To(BeFalse())
+		})
+
+		It("ignores case", func() {
+			Expect(logs.LogFilter{Sources: []string{"rtr"}}.Matches(msg)).To(BeTrue())
+		})
+
+		It("matches source types with a path on their first part", func() {
+			msg = testlogs.NewLogMessage("hello", "", "APP/PROC/WEB", "0", logmessage.LogMessage_OUT, time.Now())
+			Expect(logs.LogFilter{Sources: []string{"APP"}}.Matches(msg)).To(BeTrue())
+			Expect(logs.LogFilter{Sources: []string{"PROC"}}.Matches(msg)).To(BeFalse())
+		})
+	})
+
+	It("matches messages from the instance", func() {
+		Expect(logs.LogFilter{Instance: "1"}.Matches(msg)).To(BeTrue())
+		Expect(logs.LogFilter{Instance: "0"}.Matches(msg)).To(BeFalse())
+	})
+
+	It("matches messages whose text matches the pattern", func() {
+		Expect(logs.LogFilter{Pattern: regexp.MustCompile(`/health \d+`)}.Matches(msg)).To(BeTrue())
+		Expect(logs.LogFilter{Pattern: regexp.MustCompile(`POST`)}.Matches(msg)).To(BeFalse())
+	})
+
+	It("matches messages logged since the time", func() {
+		Expect(logs.LogFilter{Since: time.Now().Add(-10 * time.Minute)}.Matches(msg)).To(BeTrue())
+		Expect(logs.LogFilter{Since: time.Now().Add(-time.Minute)}.Matches(msg)).To(BeFalse())
+	})
+
+	It("matches only messages that pass every filter", func() {
+		filter := logs.LogFilter{Sources: []string{"RTR"}, Instance: "2"}
+		Expect(filter.Matches(msg)).To(BeFalse())
+	})
+})
+
+var _ = Describe("ToJSONLog", func() {
+	It("formats the message as a JSON object", func() {
+		date := time.Date(2014, 4, 4, 11, 39, 20, 5, time.UTC)
+		msg := testlogs.NewLogMessage("Hello \"World\"!\n", "", "APP", "4", logmessage.LogMessage_ERR, date)
+
+		jsonLog := logs.ToJSONLog(msg)
+		Expect(jsonLog).NotTo(ContainSubstring("\n"))
+
+		var fields map[string]string
+		Expect(json.Unmarshal([]byte(jsonLog), &fields)).To(Succeed())
+		Expect(fields).To(Equal(map[string]string{
+			"timestamp":       "2014-04-04T11:39:20.000000005Z",
+			"source_type":     "APP",
+			"source_instance": "4",
+			"stream":          "ERR",
+			"message":         "Hello \"World\"!",
+		}))
+	})
+})
+
+var _ = Describe("ToYAMLLog", func() {
+	It("formats the message as a YAML document", func() {
+		date := time.Date(2014, 4, 4, 11, 39, 20, 5, time.UTC)
+		msg := testlogs.NewLogMessage("Hello: World\n", "", "APP", "4", logmessage.LogMessage_OUT, date)
+
+		yamlLog := logs.ToYAMLLog(msg)
+		Expect(yamlLog).To(HavePrefix("---\n"))
+
+		var fields map[string]string
+		Expect(yaml.Unmarshal([]byte(yamlLog), &fields)).To(Succeed())
+		Expect(fields).To(Equal(map[string]string{
+			"timestamp":       "2014-04-04T11:39:20.000000005Z",
+			"source_type":     "APP",
+			"source_instance": "4",
+			"stream":          "OUT",
+			"message":         "Hello: World",
+		}))
+	})
+})
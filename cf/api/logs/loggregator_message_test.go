@@ -43,4 +43,21 @@ var _ = Describe("loggregatorMessage", func() {
 			Expect(terminal.Decolorize(msg.ToLog(time.FixedZone("the-zone", 3*60*60)))).To(Equal("2014-04-04T14:39:20.00+0300 [DEA/4]      ERR Hello World!"))
 		})
 	})
+
+	Describe("structured fields", func() {
+		It("returns the source, instance, time and stream of the message", func() {
+			date := time.Date(2014, 4, 4, 11, 39, 20, 5, time.UTC)
+			msg := testlogs.NewLogMessage("Hello World!", "", "APP", "4", logmessage.LogMessage_ERR, date)
+
+			Expect(msg.GetSourceName()).To(Equal("APP"))
+			Expect(msg.GetSourceInstance()).To(Equal("4"))
+			Expect(msg.GetTimestamp().Equal(date)).To(BeTrue())
+			Expect(msg.GetMessageType()).To(Equal("ERR"))
+		})
+
+		It("returns OUT for messages written to stdout", func() {
+			msg := testlogs.NewLogMessage("Hello World!", "", "APP", "4", logmessage.LogMessage_OUT, time.Now())
+			Expect(msg.GetMessageType()).To(Equal("OUT"))
+		})
+	})
 })
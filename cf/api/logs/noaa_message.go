@@ -30,6 +30,21 @@ func (m *noaaLogMessage) GetSourceName() string {
 	return m.msg.GetSourceType()
 }
 
+func (m *noaaLogMessage) GetSourceInstance() string {
+	return m.msg.GetSourceInstance()
+}
+
+func (m *noaaLogMessage) GetTimestamp() time.Time {
+	return time.Unix(0, m.msg.GetTimestamp())
+}
+
+func (m *noaaLogMessage) GetMessageType() string {
+	if m.msg.GetMessageType() == events.LogMessage_ERR {
+		return "ERR"
+	}
+	return "OUT"
+}
+
 func (m *noaaLogMessage) ToLog(loc *time.Location) string {
 	logMsg := m.msg
 
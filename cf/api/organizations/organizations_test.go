@@ -276,7 +276,7 @@ var _ = Describe("Organization Repository", func() {
 				Response: testnet.TestResponse{Status: http.StatusBadGateway, Body: `{"resources": []}`},
 			})
 
-			testserver, handler, repo := createOrganizationRepo(requestHandler)
+			testserver, handler, repo := createOrganizationRepo(requestHandler, requestHandler, requestHandler)
 			defer testserver.Close()
 
 			_, apiErr := repo.FindByName("org1")
@@ -63,6 +63,7 @@ type RepositoryLocator struct {
 	userRepo                        UserRepository
 	passwordRepo                    password.PasswordRepository
 	logsRepo                        logs.LogsRepository
+	newLogsRepo                     func() logs.LogsRepository
 	authTokenRepo                   ServiceAuthTokenRepository
 	serviceBrokerRepo               ServiceBrokerRepository
 	servicePlanRepo                 CloudControllerServicePlanRepository
@@ -105,19 +106,27 @@ func NewRepositoryLocator(config coreconfig.ReadWriter, gatewaysByName map[strin
 	loc.domainRepo = NewCloudControllerDomainRepository(config, cloudControllerGateway, strategy)
 	loc.endpointRepo = NewEndpointRepository(cloudControllerGateway)
 
-	tlsConfig := net.NewTLSConfig([]tls.Certificate{}, config.IsSSLDisabled())
+	tlsConfig, err := net.NewTLSConfigForConfig([]tls.Certificate{}, config)
+	if err != nil {
+		// the gateways report bad certificate files before logs are streamed
+		tlsConfig = net.NewTLSConfig([]tls.Certificate{}, config.IsSSLDisabled())
+	}
 
 	apiVersion, _ := semver.Make(config.APIVersion())
 
-	if apiVersion.GTE(cf.NoaaMinimumAPIVersion) {
-		consumer := consumer.New(config.DopplerEndpoint(), tlsConfig, http.ProxyFromEnvironment)
-		consumer.SetDebugPrinter(terminal.DebugPrinter{Logger: logger})
-		loc.logsRepo = logs.NewNoaaLogsRepository(config, consumer, loc.authRepo)
-	} else {
+	authRepo := loc.authRepo
+	loc.newLogsRepo = func() logs.LogsRepository {
+		if apiVersion.GTE(cf.NoaaMinimumAPIVersion) {
+			consumer := consumer.New(config.DopplerEndpoint(), tlsConfig, http.ProxyFromEnvironment)
+			consumer.SetDebugPrinter(terminal.DebugPrinter{Logger: logger})
+			return logs.NewNoaaLogsRepository(config, consumer, authRepo)
+		}
+
 		consumer := loggregator_consumer.New(config.LoggregatorEndpoint(), tlsConfig, http.ProxyFromEnvironment)
 		consumer.SetDebugPrinter(terminal.DebugPrinter{Logger: logger})
-		loc.logsRepo = logs.NewLoggregatorLogsRepository(config, consumer, loc.authRepo)
+		return logs.NewLoggregatorLogsRepository(config, consumer, authRepo)
 	}
+	loc.logsRepo = loc.newLogsRepo()
 
 	loc.organizationRepo = organizations.NewCloudControllerOrganizationRepository(config, cloudControllerGateway)
 	loc.passwordRepo = password.NewCloudControllerPasswordRepository(config, uaaGateway)
@@ -356,6 +365,7 @@ func (locator RepositoryLocator) GetPasswordRepository() password.PasswordReposi
 
 func (locator RepositoryLocator) SetLogsRepository(repo logs.LogsRepository) RepositoryLocator {
 	locator.logsRepo = repo
+	locator.newLogsRepo = nil
 	return locator
 }
 
@@ -363,6 +373,17 @@ func (locator RepositoryLocator) GetLogsRepository() logs.LogsRepository {
 	return locator.logsRepo
 }
 
+// NewLogsRepository returns a logs repository with a connection of its own,
+// for tailing the logs of several apps at the same time. Locators that had
+// their logs repository set fall back to sharing it.
+func (locator RepositoryLocator) NewLogsRepository() logs.LogsRepository {
+	if locator.newLogsRepo == nil {
+		return locator.logsRepo
+	}
+
+	return locator.newLogsRepo()
+}
+
 func (locator RepositoryLocator) SetServiceAuthTokenRepository(repo ServiceAuthTokenRepository) RepositoryLocator {
 	locator.authTokenRepo = repo
 	return locator
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/gofileutils/fileutils"
@@ -24,7 +25,16 @@ type AppFiles interface {
 	WalkAppFiles(dir string, onEachFile func(string, string) error) (err error)
 }
 
-type ApplicationFiles struct{}
+// ApplicationFiles reads app files from disk. When Cache is set, file hashes
+// from earlier pushes are reused for files that have not changed.
+type ApplicationFiles struct {
+	Cache *HashCache
+}
+
+type appFileToHash struct {
+	fullPath string
+	fileInfo os.FileInfo
+}
 
 func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.AppFileFields, err error) {
 	dir, err = filepath.Abs(dir)
@@ -32,6 +42,7 @@ func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.Ap
 		return
 	}
 
+	var filesToHash []appFileToHash
 	err = appfiles.WalkAppFiles(dir, func(fileName string, fullPath string) error {
 		fileInfo, err := os.Lstat(fullPath)
 		if err != nil {
@@ -46,30 +57,91 @@ func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.Ap
 		if fileInfo.IsDir() {
 			appFile.Sha1 = "0"
 			appFile.Size = 0
-		} else {
-			hash := sha1.New()
-			file, err := os.Open(fullPath)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
-
-			_, err = io.Copy(hash, file)
-			if err != nil {
-				return err
-			}
-
-			appFile.Sha1 = fmt.Sprintf("%x", hash.Sum(nil))
 		}
 
 		appFiles = append(appFiles, appFile)
+		filesToHash = append(filesToHash, appFileToHash{fullPath: fullPath, fileInfo: fileInfo})
 
 		return nil
 	})
+	if err != nil {
+		return
+	}
+
+	err = appfiles.hashFiles(appFiles, filesToHash)
+	if err != nil {
+		return
+	}
+
+	if appfiles.Cache != nil {
+		// A cache that cannot be written only makes the next push slower.
+		_ = appfiles.Cache.Save()
+	}
 
 	return
 }
 
+// hashFiles sets the SHA1 of every file in appFiles that is not a directory,
+// spreading the work across all CPUs.
+func (appfiles ApplicationFiles) hashFiles(appFiles []models.AppFileFields, filesToHash []appFileToHash) error {
+	indexes := make(chan int)
+	errs := make(chan error, len(filesToHash))
+
+	wg := &sync.WaitGroup{}
+	for worker := 0; worker < runtime.NumCPU(); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				sum, err := appfiles.fileSha1(filesToHash[i].fullPath, filesToHash[i].fileInfo)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				appFiles[i].Sha1 = sum
+			}
+		}()
+	}
+
+	for i := range filesToHash {
+		if !filesToHash[i].fileInfo.IsDir() {
+			indexes <- i
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+func (appfiles ApplicationFiles) fileSha1(fullPath string, fileInfo os.FileInfo) (string, error) {
+	if appfiles.Cache != nil {
+		if sum, found := appfiles.Cache.Get(fullPath, fileInfo); found {
+			return sum, nil
+		}
+	}
+
+	hash := sha1.New()
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	sum := fmt.Sprintf("%x", hash.Sum(nil))
+	if appfiles.Cache != nil {
+		appfiles.Cache.Put(fullPath, fileInfo, sum)
+	}
+
+	return sum, nil
+}
+
 func (appfiles ApplicationFiles) CopyFiles(appFiles []models.AppFileFields, fromDir, toDir string) error {
 	for _, file := range appFiles {
 		err := func() error {
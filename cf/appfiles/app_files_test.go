@@ -98,6 +98,64 @@ var _ = Describe("AppFiles", func() {
 				Expect(sizes).To(Equal([]int64{0}))
 			})
 		})
+		Context("when a hash cache is set", func() {
+			var (
+				tempDir  string
+				appFile  string
+				fileInfo os.FileInfo
+				cache    *appfiles.HashCache
+			)
+
+			BeforeEach(func() {
+				var err error
+				tempDir, err = ioutil.TempDir("", "app-files-cache")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = os.Mkdir(filepath.Join(tempDir, "app"), 0700)
+				Expect(err).NotTo(HaveOccurred())
+				appFile = filepath.Join(tempDir, "app", "app.rb")
+				err = ioutil.WriteFile(appFile, []byte("puts 'hello'"), 0600)
+				Expect(err).NotTo(HaveOccurred())
+				fileInfo, err = os.Lstat(appFile)
+				Expect(err).NotTo(HaveOccurred())
+
+				cache = appfiles.NewHashCache(filepath.Join(tempDir, "cache.json"))
+				appFiles = appfiles.ApplicationFiles{Cache: cache}
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(tempDir)
+			})
+
+			It("reuses the hash of a file that has not changed", func() {
+				cache.Put(appFile, fileInfo, "cached-sha")
+
+				files, err := appFiles.AppFilesInDir(filepath.Join(tempDir, "app"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(HaveLen(1))
+				Expect(files[0].Sha1).To(Equal("cached-sha"))
+			})
+
+			It("hashes a file that has changed since it was cached", func() {
+				cache.Put(appFile, fileInfo, "cached-sha")
+				err := ioutil.WriteFile(appFile, []byte("puts 'hello world'"), 0600)
+				Expect(err).NotTo(HaveOccurred())
+
+				files, err := appFiles.AppFilesInDir(filepath.Join(tempDir, "app"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(HaveLen(1))
+				Expect(files[0].Sha1).To(Equal("29644d541fa13acb7d05857cc236b77ee019f2cc"))
+			})
+
+			It("saves the hashes for the next push", func() {
+				_, err := appFiles.AppFilesInDir(filepath.Join(tempDir, "app"))
+				Expect(err).NotTo(HaveOccurred())
+
+				sha, found := appfiles.NewHashCache(filepath.Join(tempDir, "cache.json")).Get(appFile, fileInfo)
+				Expect(found).To(BeTrue())
+				Expect(sha).To(Equal("5421f5f4b58aa6865ca03b8b3ae57807c594e456"))
+			})
+		})
 	})
 
 	Describe("CopyFiles", func() {
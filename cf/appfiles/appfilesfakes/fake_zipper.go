@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/cloudfoundry/cli/cf/appfiles"
+	"github.com/cloudfoundry/cli/cf/models"
 )
 
 type FakeZipper struct {
@@ -18,6 +19,16 @@ type FakeZipper struct {
 	zipReturns struct {
 		result1 error
 	}
+	ZipFilesStub        func(dir string, files []models.AppFileFields, targetFile *os.File) (err error)
+	zipFilesMutex       sync.RWMutex
+	zipFilesArgsForCall []struct {
+		dir        string
+		files      []models.AppFileFields
+		targetFile *os.File
+	}
+	zipFilesReturns struct {
+		result1 error
+	}
 	IsZipFileStub        func(path string) bool
 	isZipFileMutex       sync.RWMutex
 	isZipFileArgsForCall []struct {
@@ -79,6 +90,40 @@ func (fake *FakeZipper) ZipReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeZipper) ZipFiles(dir string, files []models.AppFileFields, targetFile *os.File) (err error) {
+	fake.zipFilesMutex.Lock()
+	fake.zipFilesArgsForCall = append(fake.zipFilesArgsForCall, struct {
+		dir        string
+		files      []models.AppFileFields
+		targetFile *os.File
+	}{dir, files, targetFile})
+	fake.zipFilesMutex.Unlock()
+	if fake.ZipFilesStub != nil {
+		return fake.ZipFilesStub(dir, files, targetFile)
+	} else {
+		return fake.zipFilesReturns.result1
+	}
+}
+
+func (fake *FakeZipper) ZipFilesCallCount() int {
+	fake.zipFilesMutex.RLock()
+	defer fake.zipFilesMutex.RUnlock()
+	return len(fake.zipFilesArgsForCall)
+}
+
+func (fake *FakeZipper) ZipFilesArgsForCall(i int) (string, []models.AppFileFields, *os.File) {
+	fake.zipFilesMutex.RLock()
+	defer fake.zipFilesMutex.RUnlock()
+	return fake.zipFilesArgsForCall[i].dir, fake.zipFilesArgsForCall[i].files, fake.zipFilesArgsForCall[i].targetFile
+}
+
+func (fake *FakeZipper) ZipFilesReturns(result1 error) {
+	fake.ZipFilesStub = nil
+	fake.zipFilesReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeZipper) IsZipFile(path string) bool {
 	fake.isZipFileMutex.Lock()
 	fake.isZipFileArgsForCall = append(fake.isZipFileArgsForCall, struct {
@@ -0,0 +1,193 @@
+package appfiles
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// hashCacheMaxAge is how long an entry is kept after it was last used.
+	hashCacheMaxAge = 30 * 24 * time.Hour
+	// hashCacheMaxEntries bounds the size of the cache file; the least
+	// recently used entries are dropped first.
+	hashCacheMaxEntries = 250000
+)
+
+// HashCache remembers the SHA1 of app files so that pushing an app again only
+// hashes the files that changed. Entries are keyed on a file's absolute path,
+// size and modification time; a file that changes any of these is hashed
+// again.
+type HashCache struct {
+	path string
+
+	mutex      sync.Mutex
+	loaded     bool
+	skipReads  bool
+	skipWrites bool
+	entries    map[string]hashCacheEntry
+}
+
+type hashCacheEntry struct {
+	Sha1     string `json:"sha1"`
+	LastUsed int64  `json:"last_used"`
+}
+
+func NewHashCache(path string) *HashCache {
+	return &HashCache{path: path}
+}
+
+// Get returns the hash recorded for the file, if its path, size and
+// modification time have not changed since.
+func (cache *HashCache) Get(fullPath string, fileInfo os.FileInfo) (string, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.load()
+
+	if cache.skipReads {
+		return "", false
+	}
+
+	key := hashCacheKey(fullPath, fileInfo)
+	entry, found := cache.entries[key]
+	if !found {
+		return "", false
+	}
+
+	entry.LastUsed = time.Now().Unix()
+	cache.entries[key] = entry
+	return entry.Sha1, true
+}
+
+// Put records the hash of the file.
+func (cache *HashCache) Put(fullPath string, fileInfo os.FileInfo, sha1 string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.load()
+
+	cache.entries[hashCacheKey(fullPath, fileInfo)] = hashCacheEntry{
+		Sha1:     sha1,
+		LastUsed: time.Now().Unix(),
+	}
+}
+
+// SkipReads makes Get ignore the recorded hashes, so every file is hashed
+// again. The new hashes are still recorded, and the hashes of other files are
+// kept when the cache is saved.
+func (cache *HashCache) SkipReads() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.skipReads = true
+}
+
+// SkipWrites makes Save leave the cache file as it is, for commands that must
+// not change anything.
+func (cache *HashCache) SkipWrites() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.skipWrites = true
+}
+
+// Save prunes entries that have not been used recently and writes the cache
+// to disk.
+func (cache *HashCache) Save() error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.skipWrites {
+		return nil
+	}
+	cache.load()
+	cache.prune()
+
+	contents, err := json.Marshal(cache.entries)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(cache.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(cache.path), filepath.Base(cache.path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(contents)
+	tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), cache.path)
+}
+
+// load reads the cache file the first time the cache is used. A missing or
+// unreadable cache file is treated as an empty cache.
+func (cache *HashCache) load() {
+	if cache.loaded {
+		return
+	}
+	cache.loaded = true
+	cache.entries = map[string]hashCacheEntry{}
+
+	contents, err := ioutil.ReadFile(cache.path)
+	if err != nil {
+		return
+	}
+
+	entries := map[string]hashCacheEntry{}
+	if json.Unmarshal(contents, &entries) == nil {
+		cache.entries = entries
+	}
+}
+
+func (cache *HashCache) prune() {
+	oldest := time.Now().Add(-hashCacheMaxAge).Unix()
+	for key, entry := range cache.entries {
+		if entry.LastUsed < oldest {
+			delete(cache.entries, key)
+		}
+	}
+
+	if len(cache.entries) <= hashCacheMaxEntries {
+		return
+	}
+
+	keys := byLastUsed{entries: cache.entries}
+	for key := range cache.entries {
+		keys.keys = append(keys.keys, key)
+	}
+	sort.Sort(keys)
+
+	for _, key := range keys.keys[:len(keys.keys)-hashCacheMaxEntries] {
+		delete(cache.entries, key)
+	}
+}
+
+type byLastUsed struct {
+	keys    []string
+	entries map[string]hashCacheEntry
+}
+
+func (s byLastUsed) Len() int      { return len(s.keys) }
+func (s byLastUsed) Swap(i, j int) { s.keys[i], s.keys[j] = s.keys[j], s.keys[i] }
+func (s byLastUsed) Less(i, j int) bool {
+	return s.entries[s.keys[i]].LastUsed < s.entries[s.keys[j]].LastUsed
+}
+
+func hashCacheKey(fullPath string, fileInfo os.FileInfo) string {
+	key := fmt.Sprintf("%s\x00%d\x00%d", fullPath, fileInfo.Size(), fileInfo.ModTime().UnixNano())
+	return fmt.Sprintf("%x", sha1.Sum([]byte(key)))
+}
@@ -0,0 +1,182 @@
+package appfiles_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/appfiles"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HashCache", func() {
+	var (
+		tempDir   string
+		cachePath string
+		appFile   string
+		fileInfo  os.FileInfo
+		cache     *appfiles.HashCache
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "hash-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		appFile = filepath.Join(tempDir, "app.rb")
+		err = ioutil.WriteFile(appFile, []byte("puts 'hello'"), 0600)
+		Expect(err).NotTo(HaveOccurred())
+		fileInfo, err = os.Stat(appFile)
+		Expect(err).NotTo(HaveOccurred())
+
+		cachePath = filepath.Join(tempDir, "cache", "app_files_cache.json")
+		cache = appfiles.NewHashCache(cachePath)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("returns the hash that was put for a file", func() {
+		cache.Put(appFile, fileInfo, "some-sha")
+
+		sha, found := cache.Get(appFile, fileInfo)
+		Expect(found).To(BeTrue())
+		Expect(sha).To(Equal("some-sha"))
+	})
+
+	It("does not return a hash for a file that was not put", func() {
+		_, found := cache.Get(appFile, fileInfo)
+		Expect(found).To(BeFalse())
+	})
+
+	It("does not return a hash once the file's size has changed", func() {
+		cache.Put(appFile, fileInfo, "some-sha")
+
+		err := ioutil.WriteFile(appFile, []byte("puts 'hello world'"), 0600)
+		Expect(err).NotTo(HaveOccurred())
+		newInfo, err := os.Stat(appFile)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, found := cache.Get(appFile, newInfo)
+		Expect(found).To(BeFalse())
+	})
+
+	It("does not return a hash once the file's modification time has changed", func() {
+		cache.Put(appFile, fileInfo, "some-sha")
+
+		later := fileInfo.ModTime().Add(time.Minute)
+		err := os.Chtimes(appFile, later, later)
+		Expect(err).NotTo(HaveOccurred())
+		newInfo, err := os.Stat(appFile)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, found := cache.Get(appFile, newInfo)
+		Expect(found).To(BeFalse())
+	})
+
+	It("does not return a hash for another file with the same size and modification time", func() {
+		cache.Put(appFile, fileInfo, "some-sha")
+
+		_, found := cache.Get(filepath.Join(tempDir, "other.rb"), fileInfo)
+		Expect(found).To(BeFalse())
+	})
+
+	Describe("Save", func() {
+		It("writes the hashes so that a new cache can read them", func() {
+			cache.Put(appFile, fileInfo, "some-sha")
+			Expect(cache.Save()).To(Succeed())
+
+			sha, found := appfiles.NewHashCache(cachePath).Get(appFile, fileInfo)
+			Expect(found).To(BeTrue())
+			Expect(sha).To(Equal("some-sha"))
+		})
+
+		It("prunes entries that have not been used for a long time", func() {
+			contents, err := json.Marshal(map[string]interface{}{
+				"old-entry": map[string]interface{}{
+					"sha1":      "old-sha",
+					"last_used": time.Now().Add(-31 * 24 * time.Hour).Unix(),
+				},
+				"recent-entry": map[string]interface{}{
+					"sha1":      "recent-sha",
+					"last_used": time.Now().Add(-24 * time.Hour).Unix(),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.MkdirAll(filepath.Dir(cachePath), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(cachePath, contents, 0600)).To(Succeed())
+
+			Expect(cache.Save()).To(Succeed())
+
+			contents, err = ioutil.ReadFile(cachePath)
+			Expect(err).NotTo(HaveOccurred())
+			entries := map[string]interface{}{}
+			Expect(json.Unmarshal(contents, &entries)).To(Succeed())
+			Expect(entries).To(HaveKey("recent-entry"))
+			Expect(entries).NotTo(HaveKey("old-entry"))
+		})
+	})
+
+	Context("when the cache file is corrupt", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(cachePath), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(cachePath, []byte("{not json"), 0600)).To(Succeed())
+		})
+
+		It("starts with an empty cache", func() {
+			_, found := cache.Get(appFile, fileInfo)
+			Expect(found).To(BeFalse())
+
+			cache.Put(appFile, fileInfo, "some-sha")
+			Expect(cache.Save()).To(Succeed())
+
+			_, found = appfiles.NewHashCache(cachePath).Get(appFile, fileInfo)
+			Expect(found).To(BeTrue())
+		})
+	})
+
+	Describe("SkipReads", func() {
+		It("ignores the hashes saved earlier but keeps them", func() {
+			otherFile := filepath.Join(tempDir, "other.rb")
+			cache.Put(appFile, fileInfo, "some-sha")
+			cache.Put(otherFile, fileInfo, "other-sha")
+			Expect(cache.Save()).To(Succeed())
+
+			cache = appfiles.NewHashCache(cachePath)
+			cache.SkipReads()
+
+			_, found := cache.Get(appFile, fileInfo)
+			Expect(found).To(BeFalse())
+
+			cache.Put(appFile, fileInfo, "new-sha")
+			Expect(cache.Save()).To(Succeed())
+
+			cache = appfiles.NewHashCache(cachePath)
+			sha, _ := cache.Get(appFile, fileInfo)
+			Expect(sha).To(Equal("new-sha"))
+			sha, _ = cache.Get(otherFile, fileInfo)
+			Expect(sha).To(Equal("other-sha"))
+		})
+	})
+
+	Describe("SkipWrites", func() {
+		It("leaves the cache file as it is", func() {
+			cache.Put(appFile, fileInfo, "some-sha")
+			Expect(cache.Save()).To(Succeed())
+
+			cache = appfiles.NewHashCache(cachePath)
+			cache.SkipWrites()
+			cache.Put(appFile, fileInfo, "new-sha")
+			Expect(cache.Save()).To(Succeed())
+
+			cache = appfiles.NewHashCache(cachePath)
+			sha, _ := cache.Get(appFile, fileInfo)
+			Expect(sha).To(Equal("some-sha"))
+		})
+	})
+})
@@ -10,6 +10,7 @@ import (
 	"runtime"
 
 	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/gofileutils/fileutils"
 )
 
@@ -17,6 +18,7 @@ import (
 
 type Zipper interface {
 	Zip(dirToZip string, targetFile *os.File) (err error)
+	ZipFiles(dir string, files []models.AppFileFields, targetFile *os.File) (err error)
 	IsZipFile(path string) bool
 	Unzip(appDir string, destDir string) (err error)
 	GetZipSize(zipFile *os.File) (int64, error)
@@ -48,6 +50,44 @@ func (zipper ApplicationZipper) Zip(dirOrZipFilePath string, targetFile *os.File
 	return nil
 }
 
+// ZipFiles zips only the given files from dir, such as the files of an app
+// that the Cloud Controller does not already have, without copying them
+// anywhere first.
+func (zipper ApplicationZipper) ZipFiles(dir string, files []models.AppFileFields, targetFile *os.File) error {
+	if len(files) == 0 {
+		return errors.NewEmptyDirError(dir)
+	}
+
+	writer := zip.NewWriter(targetFile)
+
+	for _, file := range files {
+		fullPath, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(file.Path)))
+		if err != nil {
+			writer.Close()
+			return err
+		}
+
+		if runtime.GOOS == "windows" {
+			fullPath = windowsPathPrefix + fullPath
+		}
+
+		err = addFileToZip(writer, file.Path, fullPath)
+		if err != nil {
+			writer.Close()
+			return err
+		}
+	}
+
+	err := writer.Close()
+	if err != nil {
+		return err
+	}
+
+	targetFile.Seek(0, os.SEEK_SET)
+
+	return nil
+}
+
 func (zipper ApplicationZipper) IsZipFile(name string) bool {
 	f, err := os.Open(name)
 	if err != nil {
@@ -148,48 +188,52 @@ func writeZipFile(dir string, targetFile *os.File) error {
 
 	appfiles := ApplicationFiles{}
 	return appfiles.WalkAppFiles(dir, func(fileName string, fullPath string) error {
-		fileInfo, err := os.Stat(fullPath)
-		if err != nil {
-			return err
-		}
+		return addFileToZip(writer, fileName, fullPath)
+	})
+}
 
-		header, err := zip.FileInfoHeader(fileInfo)
-		if err != nil {
-			return err
-		}
+func addFileToZip(writer *zip.Writer, fileName string, fullPath string) error {
+	fileInfo, err := os.Stat(fullPath)
+	if err != nil {
+		return err
+	}
 
-		if runtime.GOOS == "windows" {
-			header.SetMode(header.Mode() | 0700)
-		}
+	header, err := zip.FileInfoHeader(fileInfo)
+	if err != nil {
+		return err
+	}
 
-		header.Name = filepath.ToSlash(fileName)
+	if runtime.GOOS == "windows" {
+		header.SetMode(header.Mode() | 0700)
+	}
 
-		if fileInfo.IsDir() {
-			header.Name += "/"
-		}
+	header.Name = filepath.ToSlash(fileName)
 
-		zipFilePart, err := writer.CreateHeader(header)
-		if err != nil {
-			return err
-		}
+	if fileInfo.IsDir() {
+		header.Name += "/"
+	}
 
-		if fileInfo.IsDir() {
-			return nil
-		}
+	zipFilePart, err := writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
 
-		file, err := os.Open(fullPath)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
+	if fileInfo.IsDir() {
+		return nil
+	}
 
-		_, err = io.Copy(zipFilePart, file)
-		if err != nil {
-			return err
-		}
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
 
-		return nil
-	})
+	_, err = io.Copy(zipFilePart, file)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (zipper ApplicationZipper) zipFileHeaderLocation(name string) (int64, error) {
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	. "github.com/cloudfoundry/cli/cf/appfiles"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/gofileutils/fileutils"
 
 	. "github.com/onsi/ginkgo"
@@ -229,6 +230,67 @@ var _ = Describe("Zipper", func() {
 		})
 	})
 
+	Describe("ZipFiles", func() {
+		var (
+			zipFile *os.File
+			dir     string
+			zipper  ApplicationZipper
+		)
+
+		BeforeEach(func() {
+			var err error
+			zipFile, err = ioutil.TempFile("", "zip_test")
+			Expect(err).NotTo(HaveOccurred())
+
+			workingDir, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			dir = filepath.Join(workingDir, "../../fixtures/zip/")
+
+			zipper = ApplicationZipper{}
+		})
+
+		AfterEach(func() {
+			zipFile.Close()
+			os.Remove(zipFile.Name())
+		})
+
+		It("creates a zip with only the given files", func() {
+			err := zipper.ZipFiles(dir, []models.AppFileFields{
+				{Path: "foo.txt"},
+				{Path: "subDir"},
+				{Path: "subDir/otherDir/file.txt"},
+			}, zipFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			fileStat, err := zipFile.Stat()
+			Expect(err).NotTo(HaveOccurred())
+
+			reader, err := zip.NewReader(zipFile, fileStat.Size())
+			Expect(err).NotTo(HaveOccurred())
+
+			filenames := []string{}
+			for _, file := range reader.File {
+				filenames = append(filenames, file.Name)
+			}
+			Expect(filenames).To(Equal([]string{"foo.txt", "subDir/", "subDir/otherDir/file.txt"}))
+
+			name, contents := readFileInZip(0, reader)
+			Expect(name).To(Equal("foo.txt"))
+			Expect(contents).To(Equal("This is a simple text file."))
+		})
+
+		It("returns an error when a file cannot be read", func() {
+			err := zipper.ZipFiles(dir, []models.AppFileFields{{Path: "not-a-file.txt"}}, zipFile)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when there are no files", func() {
+			err := zipper.ZipFiles(dir, []models.AppFileFields{}, zipFile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is empty"))
+		})
+	})
+
 	Describe("IsZipFile", func() {
 		var (
 			inDir, outDir string
@@ -1,8 +1,10 @@
 package commandregistry
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf/actors"
@@ -14,7 +16,9 @@ import (
 	"github.com/cloudfoundry/cli/cf/appfiles"
 	"github.com/cloudfoundry/cli/cf/configuration/confighelpers"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
 	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/cf/manifest"
 	"github.com/cloudfoundry/cli/cf/net"
 	"github.com/cloudfoundry/cli/cf/terminal"
@@ -43,6 +47,7 @@ type Dependency struct {
 	WordGenerator      generator.WordGenerator
 	AppZipper          appfiles.Zipper
 	AppFiles           appfiles.AppFiles
+	AppFilesCache      *appfiles.HashCache
 	PushActor          actors.PushActor
 	ChecksumUtil       utils.Sha1Checksum
 	WildcardDependency interface{} //use for injecting fakes
@@ -64,16 +69,31 @@ type PluginModels struct {
 }
 
 func NewDependency(logger trace.Printer) Dependency {
+	return newDependency(logger, true)
+}
+
+// NewNonInteractiveDependency is NewDependency for commands that must never
+// prompt or fail on the config, such as shell completion. Stored credentials
+// that need a passphrase are only read when CF_CREDENTIAL_PASSPHRASE is set;
+// otherwise the CLI appears to be logged out.
+func NewNonInteractiveDependency(logger trace.Printer) Dependency {
+	return newDependency(logger, false)
+}
+
+func newDependency(logger trace.Printer, interactive bool) Dependency {
 	deps := Dependency{}
 	deps.TeePrinter = terminal.NewTeePrinter()
 	deps.UI = terminal.NewUI(os.Stdin, deps.TeePrinter, logger)
 
 	errorHandler := func(err error) {
-		if err != nil {
+		if err != nil && interactive {
 			deps.UI.Failed(fmt.Sprintf("Config error: %s", err))
 		}
 	}
-	deps.Config = coreconfig.NewRepositoryFromFilepath(confighelpers.DefaultFilePath(), errorHandler)
+	credentialHelpers := func(name string) credentials.Helper {
+		return credentials.NewHelper(name, filepath.Dir(confighelpers.DefaultFilePath()), credentialsPassphrase(deps.UI, interactive))
+	}
+	deps.Config = coreconfig.NewRepositoryForProfile(confighelpers.DefaultFilePath(), os.Getenv("CF_PROFILE"), credentialHelpers, errorHandler)
 
 	deps.ManifestRepo = manifest.NewManifestDiskRepository()
 	deps.AppManifest = manifest.NewGenerator()
@@ -126,7 +146,8 @@ func NewDependency(logger trace.Printer) Dependency {
 	deps.WordGenerator = generator.NewWordGenerator()
 
 	deps.AppZipper = appfiles.ApplicationZipper{}
-	deps.AppFiles = appfiles.ApplicationFiles{}
+	deps.AppFilesCache = appfiles.NewHashCache(filepath.Join(filepath.Dir(confighelpers.DefaultFilePath()), "app_files_cache.json"))
+	deps.AppFiles = appfiles.ApplicationFiles{Cache: deps.AppFilesCache}
 
 	deps.PushActor = actors.NewPushActor(deps.RepoLocator.GetApplicationBitsRepository(), deps.AppZipper, deps.AppFiles)
 
@@ -136,3 +157,18 @@ func NewDependency(logger trace.Printer) Dependency {
 
 	return deps
 }
+
+// credentialsPassphrase reads the passphrase of the encrypted credential
+// helper from CF_CREDENTIAL_PASSPHRASE, or asks for it when interactive.
+func credentialsPassphrase(ui terminal.UI, interactive bool) func() (string, error) {
+	return func() (string, error) {
+		passphrase := os.Getenv("CF_CREDENTIAL_PASSPHRASE")
+		if passphrase == "" && interactive {
+			passphrase = ui.AskForPassword(T("Passphrase for stored credentials"))
+		}
+		if passphrase == "" {
+			return "", errors.New(T("A passphrase is required to use the stored credentials"))
+		}
+		return passphrase, nil
+	}
+}
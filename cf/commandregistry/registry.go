@@ -3,6 +3,7 @@ package commandregistry
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"unicode/utf8"
 
@@ -61,6 +62,27 @@ func (r *registry) FindCommand(name string) Command {
 	return nil
 }
 
+// CopyCommand returns a shallow copy of the named command, which can be
+// given dependencies of its own without affecting the registered command.
+// Commands that are not pointers are already copied when returned, so they
+// are handed back as they are.
+func (r *registry) CopyCommand(name string) Command {
+	cmd := r.FindCommand(name)
+	if cmd == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(cmd)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return cmd
+	}
+
+	cmdCopy := reflect.New(value.Elem().Type())
+	cmdCopy.Elem().Set(value.Elem())
+
+	return cmdCopy.Interface().(Command)
+}
+
 func (r *registry) CommandExists(name string) bool {
 	if strings.TrimSpace(name) == "" {
 		return false
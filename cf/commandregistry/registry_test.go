@@ -98,6 +98,34 @@ var _ = Describe("CommandRegistry", func() {
 		})
 	})
 
+	Describe("CopyCommand()", func() {
+		AfterEach(func() {
+			commandregistry.Commands.RemoveCommand("fake-command")
+		})
+
+		It("returns a copy of a command registered as a pointer", func() {
+			registeredCmd := &FakeCommand1{Data: "registered data"}
+			commandregistry.Register(registeredCmd)
+
+			cmd := commandregistry.Commands.CopyCommand("fc1")
+			Expect(cmd).To(Equal(registeredCmd))
+
+			cmd.(*FakeCommand1).Data = "copied data"
+			Expect(registeredCmd.Data).To(Equal("registered data"))
+		})
+
+		It("returns a command registered as a value as it is", func() {
+			commandregistry.Register(FakeCommand1{Data: "registered data"})
+
+			cmd := commandregistry.Commands.CopyCommand("fake-command")
+			Expect(cmd).To(Equal(FakeCommand1{Data: "registered data"}))
+		})
+
+		It("returns nil when the command has not been registered", func() {
+			Expect(commandregistry.Commands.CopyCommand("fake-command")).To(BeNil())
+		})
+	})
+
 	Describe("SetCommand()", func() {
 		It("replaces the command in registry with command provided", func() {
 			updatedCmd := FakeCommand1{Data: "This is new data"}
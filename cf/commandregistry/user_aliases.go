@@ -0,0 +1,96 @@
+package commandregistry
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+// ExpandAlias replaces a user defined alias at the start of args with the
+// words of the command it stands for, followed by the rest of args. Aliases
+// may use other aliases, but an alias never shadows a command of the
+// registry, and an alias that leads back to itself is an error.
+func (r *registry) ExpandAlias(args []string, aliases map[string]string) ([]string, error) {
+	expanded := map[string]bool{}
+
+	for len(args) > 0 {
+		name := args[0]
+		command, found := aliases[name]
+		if !found || r.CommandExists(name) {
+			break
+		}
+
+		if expanded[name] {
+			return nil, errors.New(T("Alias {{.Name}} expands to itself", map[string]interface{}{"Name": name}))
+		}
+		expanded[name] = true
+
+		words, err := SplitAliasCommand(command)
+		if err != nil {
+			return nil, err
+		}
+		args = append(words, args[1:]...)
+	}
+
+	return args, nil
+}
+
+// shellMetacharacters are what would make a shell run more than a single
+// command, or expand variables. Aliases are not run by a shell, so these are
+// rejected unless quoted or escaped rather than passed on as arguments.
+const shellMetacharacters = "|&;<>$`"
+
+// SplitAliasCommand splits the command of an alias into words the way a
+// shell would, so that single and double quotes and backslashes can be used
+// for arguments with spaces.
+func SplitAliasCommand(command string) ([]string, error) {
+	words := []string{}
+	var word []rune
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, c := range command {
+		switch {
+		case escaped:
+			word = append(word, c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				word = append(word, c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case unicode.IsSpace(c):
+			if inWord {
+				words = append(words, string(word))
+				word = nil
+				inWord = false
+			}
+		case strings.ContainsRune(shellMetacharacters, c):
+			return nil, errors.New(T("Alias commands are not run by a shell, so '{{.Character}}' cannot be used in: {{.Command}}\nTIP: quote it to pass it to the command as it is",
+				map[string]interface{}{"Character": string(c), "Command": strings.TrimSpace(command)}))
+		default:
+			word = append(word, c)
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New(T("Unterminated quote or escape in alias command: {{.Command}}", map[string]interface{}{"Command": strings.TrimSpace(command)}))
+	}
+
+	if inWord {
+		words = append(words, string(word))
+	}
+
+	return words, nil
+}
@@ -0,0 +1,101 @@
+package commandregistry_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+
+	. "github.com/cloudfoundry/cli/cf/commandregistry/fakecommand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("User aliases", func() {
+	Describe("ExpandAlias()", func() {
+		var aliases map[string]string
+
+		BeforeEach(func() {
+			commandregistry.Commands = commandregistry.NewRegistry()
+			commandregistry.Register(FakeCommand1{})
+
+			aliases = map[string]string{
+				"deploy-prod":  "push -f manifests/prod.yml",
+				"prod":         "target -o big-org -s prod",
+				"go-prod":      "prod",
+				"fake-command": "other-command",
+				"fc1":          "other-command",
+			}
+		})
+
+		It("replaces an alias with its command and keeps the arguments after it", func() {
+			args, err := commandregistry.Commands.ExpandAlias([]string{"deploy-prod", "--no-start"}, aliases)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{"push", "-f", "manifests/prod.yml", "--no-start"}))
+		})
+
+		It("leaves arguments that do not start with an alias alone", func() {
+			args, err := commandregistry.Commands.ExpandAlias([]string{"apps", "prod"}, aliases)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{"apps", "prod"}))
+
+			args, err = commandregistry.Commands.ExpandAlias([]string{}, aliases)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(BeEmpty())
+		})
+
+		It("expands aliases that use other aliases", func() {
+			args, err := commandregistry.Commands.ExpandAlias([]string{"go-prod"}, aliases)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{"target", "-o", "big-org", "-s", "prod"}))
+		})
+
+		It("never expands the names of commands", func() {
+			args, err := commandregistry.Commands.ExpandAlias([]string{"fake-command"}, aliases)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{"fake-command"}))
+
+			args, err = commandregistry.Commands.ExpandAlias([]string{"fc1"}, aliases)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{"fc1"}))
+		})
+
+		It("fails for aliases that expand to themselves", func() {
+			aliases["ping"] = "pong --fast"
+			aliases["pong"] = "ping"
+
+			_, err := commandregistry.Commands.ExpandAlias([]string{"ping"}, aliases)
+			Expect(err).To(MatchError("Alias ping expands to itself"))
+		})
+	})
+
+	Describe("SplitAliasCommand()", func() {
+		It("splits on spaces", func() {
+			Expect(commandregistry.SplitAliasCommand("  target -o  big-org\t-s prod ")).To(Equal([]string{"target", "-o", "big-org", "-s", "prod"}))
+		})
+
+		It("keeps quoted and escaped spaces", func() {
+			Expect(commandregistry.SplitAliasCommand(`set-env app MSG "hello world"`)).To(Equal([]string{"set-env", "app", "MSG", "hello world"}))
+			Expect(commandregistry.SplitAliasCommand(`set-env app MSG 'say "hi"'`)).To(Equal([]string{"set-env", "app", "MSG", `say "hi"`}))
+			Expect(commandregistry.SplitAliasCommand(`set-env app MSG hello\ world ''`)).To(Equal([]string{"set-env", "app", "MSG", "hello world", ""}))
+		})
+
+		It("fails for unquoted shell metacharacters", func() {
+			_, err := commandregistry.SplitAliasCommand("logs my-app | grep ERR")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Alias commands are not run by a shell, so '|' cannot be used in: logs my-app | grep ERR"))
+
+			for _, command := range []string{"apps && apps", "apps; apps", "apps > out", "env $APP", "env `app`"} {
+				_, err = commandregistry.SplitAliasCommand(command)
+				Expect(err).To(HaveOccurred(), command)
+			}
+		})
+
+		It("keeps quoted and escaped shell metacharacters", func() {
+			Expect(commandregistry.SplitAliasCommand(`logs my-app --grep 'a|b' --grep "c&d" \$HOME`)).To(Equal([]string{"logs", "my-app", "--grep", "a|b", "--grep", "c&d", "$HOME"}))
+		})
+
+		It("fails for unterminated quotes", func() {
+			_, err := commandregistry.SplitAliasCommand(`set-env app MSG "hello`)
+			Expect(err).To(MatchError(`Unterminated quote or escape in alias command: set-env app MSG "hello`))
+		})
+	})
+})
@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type Alias struct {
+	ui           terminal.UI
+	config       coreconfig.ReadWriter
+	pluginConfig pluginconfig.PluginConfiguration
+}
+
+func init() {
+	commandregistry.Register(&Alias{})
+}
+
+func (cmd *Alias) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "alias",
+		Description: T("Create, list or delete aliases for commands"),
+		Usage: []string{
+			T("Create or change an alias"),
+			":\n   CF_NAME alias set NAME COMMAND\n\n   ",
+			T("List aliases"),
+			":\n   CF_NAME alias list\n\n   ",
+			T("Delete an alias"),
+			":\n   CF_NAME alias delete NAME\n\n   ",
+			T("Arguments given after an alias are added to the end of its command. Aliases cannot replace cf commands."),
+		},
+		Examples: []string{
+			"CF_NAME alias set deploy-prod \"push -f manifests/prod.yml\"",
+			"CF_NAME alias set prod \"target -o big-org -s prod\"",
+			"CF_NAME deploy-prod --no-start",
+		},
+	}
+}
+
+func (cmd *Alias) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("Requires 'set' with a name and a command, 'list', or 'delete' with a name as arguments"),
+		func() bool {
+			args := fc.Args()
+			if len(args) == 0 {
+				return true
+			}
+			switch args[0] {
+			case "set":
+				return len(args) != 3
+			case "list":
+				return len(args) != 1
+			case "delete":
+				return len(args) != 2
+			}
+			return true
+		},
+	)
+
+	return []requirements.Requirement{usageReq}
+}
+
+func (cmd *Alias) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.pluginConfig = deps.PluginConfig
+	return cmd
+}
+
+func (cmd *Alias) Execute(c flags.FlagContext) {
+	switch c.Args()[0] {
+	case "set":
+		cmd.setAlias(c.Args()[1], c.Args()[2])
+	case "delete":
+		cmd.deleteAlias(c.Args()[1])
+	default:
+		cmd.listAliases()
+	}
+}
+
+func (cmd *Alias) setAlias(name string, command string) {
+	cmd.ui.Say(T("Setting alias {{.Name}} to {{.Command}}...", map[string]interface{}{
+		"Name":    terminal.EntityNameColor(name),
+		"Command": terminal.EntityNameColor(command),
+	}))
+
+	if name == "" || strings.HasPrefix(name, "-") || strings.IndexFunc(name, unicode.IsSpace) != -1 {
+		cmd.ui.Failed(T("Alias names cannot be empty, start with '-' or contain spaces."))
+	}
+
+	if commandregistry.Commands.CommandExists(name) || cmd.isPluginCommand(name) {
+		cmd.ui.Failed(T("{{.Name}} is already the name of a command and cannot be used as an alias.", map[string]interface{}{"Name": name}))
+	}
+
+	words, err := commandregistry.SplitAliasCommand(command)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+	if len(words) == 0 {
+		cmd.ui.Failed(T("The command of an alias cannot be empty."))
+	}
+
+	aliases := cmd.config.Aliases()
+	aliases[name] = command
+	_, err = commandregistry.Commands.ExpandAlias([]string{name}, aliases)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.config.SetAlias(name, command)
+	cmd.ui.Ok()
+}
+
+func (cmd *Alias) deleteAlias(name string) {
+	cmd.ui.Say(T("Deleting alias {{.Name}}...", map[string]interface{}{"Name": terminal.EntityNameColor(name)}))
+
+	if _, found := cmd.config.Aliases()[name]; !found {
+		cmd.ui.Ok()
+		cmd.ui.Warn(T("Alias {{.Name}} does not exist.", map[string]interface{}{"Name": name}))
+		return
+	}
+
+	cmd.config.DeleteAlias(name)
+	cmd.ui.Ok()
+}
+
+func (cmd *Alias) listAliases() {
+	aliases := cmd.config.Aliases()
+
+	cmd.ui.Say(T("Getting aliases..."))
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	table := cmd.ui.Table([]string{T("alias"), T("command")})
+	if len(aliases) == 0 {
+		table.PrintNoRecords(T("No aliases found"))
+		return
+	}
+
+	names := []string{}
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		table.AddRecord(aliasRecord{Name: name, Command: aliases[name]}, name, aliases[name])
+	}
+	table.Print()
+}
+
+// aliasRecord is what --output json|yaml shows of an alias.
+type aliasRecord struct {
+	Name    string
+	Command string
+}
+
+func (cmd *Alias) isPluginCommand(name string) bool {
+	for _, plugin := range cmd.pluginConfig.Plugins() {
+		for _, command := range plugin.Commands {
+			if command.Name == name || command.Alias == name {
+				return true
+			}
+		}
+	}
+	return false
+}
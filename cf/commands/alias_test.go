@@ -0,0 +1,209 @@
+package commands_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig/pluginconfigfakes"
+	"github.com/cloudfoundry/cli/cf/requirements/requirementsfakes"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/plugin"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Alias", func() {
+	var (
+		ui           *testterm.FakeUI
+		config       coreconfig.Repository
+		pluginConfig *pluginconfigfakes.FakePluginConfiguration
+		cmd          commandregistry.Command
+		factory      *requirementsfakes.FakeFactory
+		flagContext  flags.FlagContext
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepository()
+		pluginConfig = new(pluginconfigfakes.FakePluginConfiguration)
+		pluginConfig.PluginsReturns(map[string]pluginconfig.PluginMetadata{
+			"Test1": {
+				Commands: []plugin.Command{{Name: "test1_cmd", Alias: "t1"}},
+			},
+		})
+
+		cmd = &commands.Alias{}
+		cmd.SetDependency(commandregistry.Dependency{UI: ui, Config: config, PluginConfig: pluginConfig}, false)
+
+		flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+		factory = new(requirementsfakes.FakeFactory)
+	})
+
+	runCommand := func(args ...string) {
+		flagContext.Parse(args...)
+		cmd.Execute(flagContext)
+	}
+
+	Describe("Requirements", func() {
+		It("fails with usage without a subcommand", func() {
+			flagContext.Parse()
+
+			reqs := cmd.Requirements(factory, flagContext)
+			err := reqs[0].Execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Incorrect Usage. Requires 'set' with a name and a command, 'list', or 'delete' with a name as arguments"))
+		})
+
+		It("fails with usage when set is missing the command", func() {
+			flagContext.Parse("set", "deploy-prod")
+
+			reqs := cmd.Requirements(factory, flagContext)
+			Expect(reqs[0].Execute()).NotTo(Succeed())
+		})
+
+		It("fails with usage with an unknown subcommand", func() {
+			flagContext.Parse("rename", "a", "b")
+
+			reqs := cmd.Requirements(factory, flagContext)
+			Expect(reqs[0].Execute()).NotTo(Succeed())
+		})
+
+		It("passes for list", func() {
+			flagContext.Parse("list")
+
+			reqs := cmd.Requirements(factory, flagContext)
+			Expect(reqs[0].Execute()).To(Succeed())
+		})
+	})
+
+	Describe("set", func() {
+		It("saves the alias", func() {
+			runCommand("set", "deploy-prod", "push -f manifests/prod.yml")
+
+			Expect(config.Aliases()).To(Equal(map[string]string{"deploy-prod": "push -f manifests/prod.yml"}))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Setting alias deploy-prod to push -f manifests/prod.yml..."},
+				[]string{"OK"},
+			))
+		})
+
+		It("does not shadow core commands", func() {
+			Expect(func() { runCommand("set", "target", "apps") }).To(Panic())
+
+			Expect(config.Aliases()).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"target is already the name of a command and cannot be used as an alias."},
+			))
+		})
+
+		It("does not shadow the short names of core commands", func() {
+			Expect(func() { runCommand("set", "t", "apps") }).To(Panic())
+			Expect(config.Aliases()).To(BeEmpty())
+		})
+
+		It("does not shadow plugin commands", func() {
+			Expect(func() { runCommand("set", "t1", "apps") }).To(Panic())
+			Expect(config.Aliases()).To(BeEmpty())
+		})
+
+		It("fails for names with spaces", func() {
+			Expect(func() { runCommand("set", "my alias", "apps") }).To(Panic())
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Alias names cannot be empty, start with '-' or contain spaces."}))
+		})
+
+		It("fails for empty commands", func() {
+			Expect(func() { runCommand("set", "nothing", "  ") }).To(Panic())
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"The command of an alias cannot be empty."}))
+		})
+
+		It("fails for commands with unterminated quotes", func() {
+			Expect(func() { runCommand("set", "greet", `set-env app MSG "hi`) }).To(Panic())
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Unterminated quote"}))
+		})
+
+		It("fails for commands using shell operators", func() {
+			Expect(func() { runCommand("set", "errors", "logs my-app --recent | grep ERR") }).To(Panic())
+
+			Expect(config.Aliases()).NotTo(HaveKey("errors"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Alias commands are not run by a shell, so '|' cannot be used in: logs my-app --recent | grep ERR"},
+				[]string{"TIP: quote it to pass it to the command as it is"},
+			))
+		})
+
+		It("fails for aliases that would expand to themselves", func() {
+			config.SetAlias("pong", "ping --fast")
+
+			Expect(func() { runCommand("set", "ping", "pong") }).To(Panic())
+
+			Expect(config.Aliases()).NotTo(HaveKey("ping"))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Alias ping expands to itself"}))
+		})
+	})
+
+	Describe("list", func() {
+		It("lists the aliases by name", func() {
+			config.SetAlias("prod", "target -o big-org -s prod")
+			config.SetAlias("deploy-prod", "push -f manifests/prod.yml")
+
+			runCommand("list")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Getting aliases..."},
+				[]string{"OK"},
+				[]string{"alias", "command"},
+				[]string{"deploy-prod", "push -f manifests/prod.yml"},
+				[]string{"prod", "target -o big-org -s prod"},
+			))
+		})
+
+		It("lists the aliases as a structured document", func() {
+			ui.OutputFormat = terminal.YAMLOutput
+			config.SetAlias("deploy-prod", "push -f manifests/prod.yml")
+
+			runCommand("list")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Command: push -f manifests/prod.yml"},
+				[]string{"Name: deploy-prod"},
+			))
+		})
+
+		It("says when there are no aliases", func() {
+			runCommand("list")
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"No aliases found"}))
+		})
+	})
+
+	Describe("delete", func() {
+		It("deletes the alias", func() {
+			config.SetAlias("deploy-prod", "push -f manifests/prod.yml")
+
+			runCommand("delete", "deploy-prod")
+
+			Expect(config.Aliases()).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Deleting alias deploy-prod..."},
+				[]string{"OK"},
+			))
+		})
+
+		It("warns when the alias does not exist", func() {
+			runCommand("delete", "deploy-prod")
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"OK"}))
+			Expect(ui.WarnOutputs).To(ContainSubstrings([]string{"Alias deploy-prod does not exist."}))
+		})
+	})
+})
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"crypto/tls"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/cloudfoundry/cli/cf"
@@ -9,6 +11,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/errors"
 	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/net"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/flags"
@@ -28,12 +31,15 @@ func (cmd Api) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["unset"] = &flags.BoolFlag{Name: "unset", Usage: T("Remove all api endpoint targeting")}
 	fs["skip-ssl-validation"] = &flags.BoolFlag{Name: "skip-ssl-validation", Usage: T("Skip verification of the API endpoint. Not recommended!")}
+	fs["ca-cert"] = &flags.StringFlag{Name: "ca-cert", Usage: T("PEM file of CA certificates to trust, in addition to the system ones, when connecting to the API, UAA and Doppler (Default: CF_CA_CERT environment variable)")}
+	fs["client-cert"] = &flags.StringFlag{Name: "client-cert", Usage: T("PEM file of the client certificate presented to foundations that require one (Default: CF_CLIENT_CERT environment variable)")}
+	fs["client-key"] = &flags.StringFlag{Name: "client-key", Usage: T("PEM file of the key of the client certificate, if it is not in the certificate file (Default: CF_CLIENT_KEY environment variable)")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "api",
 		Description: T("Set or view target api url"),
 		Usage: []string{
-			T("CF_NAME api [URL]"),
+			T("CF_NAME api [URL] [--skip-ssl-validation] [--ca-cert CA_CERT_FILE] [--client-cert CERT_FILE [--client-key KEY_FILE]]"),
 		},
 		Flags: fs,
 	}
@@ -73,6 +79,7 @@ func (cmd Api) Execute(c flags.FlagContext) {
 
 		cmd.ui.Say(T("Setting api endpoint to {{.Endpoint}}...",
 			map[string]interface{}{"Endpoint": terminal.EntityNameColor(endpoint)}))
+		cmd.setCertificateFiles(c.String("ca-cert"), c.String("client-cert"), c.String("client-key"))
 		cmd.setAPIEndpoint(endpoint, c.Bool("skip-ssl-validation"), cmd.MetaData().Name)
 		cmd.ui.Ok()
 
@@ -98,6 +105,8 @@ func (cmd Api) setAPIEndpoint(endpoint string, skipSSL bool, cmdName string) {
 	if err != nil {
 		cmd.config.SetAPIEndpoint("")
 		cmd.config.SetSSLDisabled(false)
+		cmd.config.SetCACertFile("")
+		cmd.config.SetClientCertificate("", "")
 
 		switch typedErr := err.(type) {
 		case *errors.InvalidSSLCert:
@@ -117,3 +126,35 @@ func (cmd Api) setAPIEndpoint(endpoint string, skipSSL bool, cmdName string) {
 		cmd.ui.Say(terminal.WarningColor(warning.Warn()))
 	}
 }
+
+func (cmd Api) setCertificateFiles(caCertFile, clientCertFile, clientKeyFile string) {
+	if clientKeyFile != "" && clientCertFile == "" {
+		cmd.ui.Failed(T("Incorrect Usage: --client-key requires --client-cert") + "\n\n" + commandregistry.Commands.CommandUsage("api"))
+	}
+
+	files := net.CertificateFiles{
+		CACert:     absolutePath(caCertFile),
+		ClientCert: absolutePath(clientCertFile),
+		ClientKey:  absolutePath(clientKeyFile),
+	}
+
+	err := files.AddTo(&tls.Config{})
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.config.SetCACertFile(files.CACert)
+	cmd.config.SetClientCertificate(files.ClientCert, files.ClientKey)
+}
+
+func absolutePath(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return absPath
+}
@@ -1,13 +1,19 @@
 package commands_test
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/errors"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testnet "github.com/cloudfoundry/cli/testhelpers/net"
 	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
 	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
 	. "github.com/onsi/ginkgo"
@@ -224,6 +230,71 @@ var _ = Describe("Api", func() {
 			})
 		})
 
+		Context("when the user passes certificate files", func() {
+			var certDir string
+
+			BeforeEach(func() {
+				var err error
+				certDir, err = ioutil.TempDir("", "api-certs")
+				Expect(err).NotTo(HaveOccurred())
+
+				cert := testnet.MakeSelfSignedTLSCert()
+				certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+				key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+				Expect(err).NotTo(HaveOccurred())
+				keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})
+
+				ioutil.WriteFile(filepath.Join(certDir, "ca.pem"), certPEM, 0600)
+				ioutil.WriteFile(filepath.Join(certDir, "cert.pem"), certPEM, 0600)
+				ioutil.WriteFile(filepath.Join(certDir, "key.pem"), keyPEM, 0600)
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(certDir)
+			})
+
+			It("stores the CA certificate and client certificate files in the config", func() {
+				callApi([]string{
+					"--ca-cert", filepath.Join(certDir, "ca.pem"),
+					"--client-cert", filepath.Join(certDir, "cert.pem"),
+					"--client-key", filepath.Join(certDir, "key.pem"),
+					"https://example.com",
+				})
+
+				Expect(config.CACertFile()).To(Equal(filepath.Join(certDir, "ca.pem")))
+				Expect(config.ClientCertFile()).To(Equal(filepath.Join(certDir, "cert.pem")))
+				Expect(config.ClientKeyFile()).To(Equal(filepath.Join(certDir, "key.pem")))
+				Expect(config.APIEndpoint()).To(Equal("https://example.com"))
+			})
+
+			It("forgets the certificate files when targeting without them", func() {
+				callApi([]string{"--ca-cert", filepath.Join(certDir, "ca.pem"), "https://example.com"})
+				flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+				callApi([]string{"https://example.com"})
+
+				Expect(config.CACertFile()).To(Equal(""))
+			})
+
+			It("fails when the CA certificate file does not contain certificates", func() {
+				Expect(func() {
+					callApi([]string{"--ca-cert", filepath.Join(certDir, "key.pem"), "https://example.com"})
+				}).To(Panic())
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"No PEM encoded certificates found in", "key.pem"},
+				))
+				Expect(config.APIEndpoint()).To(Equal(""))
+			})
+
+			It("fails with usage when a client key is passed without a client certificate", func() {
+				Expect(func() {
+					callApi([]string{"--client-key", filepath.Join(certDir, "key.pem"), "https://example.com"})
+				}).To(Panic())
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"Incorrect Usage"}))
+			})
+		})
+
 		Describe("unencrypted http endpoints", func() {
 			It("warns the user", func() {
 				callApi([]string{"http://example.com"})
@@ -85,11 +85,6 @@ func (cmd *ListApps) Execute(c flags.FlagContext) {
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
-	if len(apps) == 0 {
-		cmd.ui.Say(T("No apps found"))
-		return
-	}
-
 	table := cmd.ui.Table([]string{
 		T("name"),
 		T("requested state"),
@@ -101,6 +96,11 @@ func (cmd *ListApps) Execute(c flags.FlagContext) {
 		T("urls"),
 	})
 
+	if len(apps) == 0 {
+		table.PrintNoRecords(T("No apps found"))
+		return
+	}
+
 	for _, application := range apps {
 		var urls []string
 		for _, route := range application.Routes {
@@ -112,7 +112,17 @@ func (cmd *ListApps) Execute(c flags.FlagContext) {
 			appPorts[i] = strconv.Itoa(p)
 		}
 
-		table.Add(
+		record := appRecord{
+			Name:             application.Name,
+			State:            application.State,
+			RunningInstances: application.RunningInstances,
+			Instances:        application.InstanceCount,
+			Memory:           formatters.ByteSize(application.Memory * formatters.MEGABYTE),
+			Disk:             formatters.ByteSize(application.DiskQuota * formatters.MEGABYTE),
+			URLs:             urls,
+		}
+
+		table.AddRecord(record,
 			application.Name,
 			uihelpers.ColoredAppState(application.ApplicationFields),
 			uihelpers.ColoredAppInstances(application.ApplicationFields),
@@ -131,6 +141,18 @@ func (cmd *ListApps) Execute(c flags.FlagContext) {
 	}
 }
 
+// appRecord is what --output json|yaml shows of an app: the columns of the
+// table, leaving out its environment variables.
+type appRecord struct {
+	Name             string
+	State            string
+	RunningInstances int
+	Instances        int
+	Memory           string
+	Disk             string
+	URLs             []string
+}
+
 func (cmd *ListApps) populatePluginModel(apps []models.Application) {
 	for _, app := range apps {
 		appModel := plugin_models.GetAppsModel{}
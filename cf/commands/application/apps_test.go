@@ -5,6 +5,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/cf/trace/tracefakes"
 	"github.com/cloudfoundry/cli/plugin/models"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
@@ -233,5 +234,45 @@ var _ = Describe("list-apps command", func() {
 				))
 			})
 		})
+
+		Context("when a structured output format is requested", func() {
+			BeforeEach(func() {
+				ui.OutputFormat = terminal.JSONOutput
+			})
+
+			It("lists the apps as a document instead of a table", func() {
+				runCommand()
+
+				Expect(ui.Outputs).NotTo(ContainSubstrings(
+					[]string{"name", "requested state", "instances", "memory", "disk", "urls"},
+				))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{`"Name": "Application-1"`},
+					[]string{`"State": "started"`},
+					[]string{`"Name": "Application-2"`},
+				))
+			})
+
+			It("leaves out everything but the columns of the table", func() {
+				apps := appSummaryRepo.GetSummariesInCurrentSpaceApps
+				apps[0].EnvironmentVars = map[string]interface{}{"DB_PASSWORD": "secret"}
+
+				runCommand()
+
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"DB_PASSWORD"}))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"secret"}))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"GUID"}))
+			})
+
+			It("prints an empty document when there are no apps", func() {
+				appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{}
+
+				runCommand()
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"No apps found"},
+					[]string{"[]"},
+				))
+			})
+		})
 	})
 })
@@ -1,6 +1,9 @@
 package application
 
 import (
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf/api/logs"
@@ -15,12 +18,16 @@ import (
 )
 
 type Logs struct {
-	ui       terminal.UI
-	logsRepo logs.LogsRepository
-	config   coreconfig.Reader
-	appReq   requirements.ApplicationRequirement
+	ui           terminal.UI
+	logsRepo     logs.LogsRepository
+	config       coreconfig.Reader
+	appReq       requirements.ApplicationRequirement
+	filter       logs.LogFilter
+	outputFormat terminal.OutputFormat
 }
 
+var logSources = []string{"API", "APP", "CELL", "HEALTH", "LGR", "RTR", "SSH", "STG"}
+
 func init() {
 	commandregistry.Register(&Logs{})
 }
@@ -28,12 +35,17 @@ func init() {
 func (cmd *Logs) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["recent"] = &flags.BoolFlag{Name: "recent", Usage: T("Dump recent logs instead of tailing")}
+	fs["source"] = &flags.StringSliceFlag{Name: "source", Usage: T("Only show logs from this source, such as RTR, APP, STG, CELL or API. Can be specified multiple times")}
+	fs["instance"] = &flags.IntFlag{Name: "instance", Usage: T("Only show logs from this app instance")}
+	fs["grep"] = &flags.StringFlag{Name: "grep", Usage: T("Only show log messages matching this regular expression")}
+	fs["since"] = &flags.StringFlag{Name: "since", Usage: T("Only show logs from the last DURATION (e.g. 30s, 10m, 2h)")}
+	fs["output"] = &flags.StringFlag{Name: "output", Usage: T("Output format: json, yaml, table or text. Use 'json' or 'yaml' to print one document per log message; 'table' and 'text' print log lines")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "logs",
 		Description: T("Tail or show recent logs for an app"),
 		Usage: []string{
-			T("CF_NAME logs APP_NAME"),
+			T("CF_NAME logs APP_NAME [--recent] [--source SOURCE] [--instance INDEX] [--grep REGEX] [--since DURATION] [--output json|yaml|text]"),
 		},
 		Flags: fs,
 	}
@@ -65,6 +77,10 @@ func (cmd *Logs) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 func (cmd *Logs) Execute(c flags.FlagContext) {
 	app := cmd.appReq.GetApplication()
 
+	cmd.filter = cmd.logFilter(c)
+
+	cmd.outputFormat = cmd.parseOutputFormat(c.String("output"))
+
 	if c.Bool("recent") {
 		cmd.recentLogsFor(app)
 	} else {
@@ -72,13 +88,90 @@ func (cmd *Logs) Execute(c flags.FlagContext) {
 	}
 }
 
+// parseOutputFormat accepts the formats of the global --output flag, which
+// this flag stands in for, and text. table and text both print log lines.
+func (cmd *Logs) parseOutputFormat(value string) terminal.OutputFormat {
+	if value == "text" {
+		return terminal.TableOutput
+	}
+
+	outputFormat, err := terminal.ParseOutputFormat(value)
+	if err != nil {
+		cmd.ui.Failed(T("Invalid output format: {{.Output}}. Use 'json', 'yaml', 'table' or 'text'.", map[string]interface{}{"Output": value}))
+	}
+	return outputFormat
+}
+
+func (cmd *Logs) logFilter(c flags.FlagContext) logs.LogFilter {
+	var filter logs.LogFilter
+
+	for _, source := range c.StringSlice("source") {
+		if !isLogSource(source) {
+			cmd.ui.Failed(T("Invalid log source: {{.Source}}. Use one of {{.Sources}}.",
+				map[string]interface{}{"Source": source, "Sources": strings.Join(logSources, ", ")}))
+		}
+		filter.Sources = append(filter.Sources, source)
+	}
+
+	if c.IsSet("instance") {
+		if c.Int("instance") < 0 {
+			cmd.ui.Failed(T("Invalid instance: {{.Instance}}\nThe instance index must be zero or more", map[string]interface{}{"Instance": c.Int("instance")}))
+		}
+		filter.Instance = strconv.Itoa(c.Int("instance"))
+	}
+
+	if c.String("grep") != "" {
+		pattern, err := regexp.Compile(c.String("grep"))
+		if err != nil {
+			cmd.ui.Failed(T("Invalid regular expression for --grep: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		}
+		filter.Pattern = pattern
+	}
+
+	if c.String("since") != "" {
+		duration, err := time.ParseDuration(c.String("since"))
+		if err != nil || duration <= 0 {
+			cmd.ui.Failed(T("Invalid duration for --since: {{.Since}}. Use a duration such as 30s, 10m or 2h.", map[string]interface{}{"Since": c.String("since")}))
+		}
+		filter.Since = time.Now().Add(-duration)
+	}
+
+	return filter
+}
+
+func isLogSource(source string) bool {
+	for _, logSource := range logSources {
+		if strings.ToUpper(source) == logSource {
+			return true
+		}
+	}
+	return false
+}
+
+func (cmd *Logs) printLog(msg logs.Loggable) {
+	if !cmd.filter.Matches(msg) {
+		return
+	}
+
+	switch cmd.outputFormat {
+	case terminal.JSONOutput:
+		cmd.ui.Say("%s", logs.ToJSONLog(msg))
+	case terminal.YAMLOutput:
+		cmd.ui.Say("%s", logs.ToYAMLLog(msg))
+	default:
+		cmd.ui.Say("%s", msg.ToLog(time.Local))
+	}
+}
+
 func (cmd *Logs) recentLogsFor(app models.Application) {
-	cmd.ui.Say(T("Connected, dumping recent logs for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
-		map[string]interface{}{
-			"AppName":   terminal.EntityNameColor(app.Name),
-			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	if !cmd.outputFormat.IsStructured() {
+		cmd.ui.Say(T("Connected, dumping recent logs for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(app.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	}
 
 	messages, err := cmd.logsRepo.RecentLogsFor(app.GUID)
 	if err != nil {
@@ -86,12 +179,16 @@ func (cmd *Logs) recentLogsFor(app models.Application) {
 	}
 
 	for _, msg := range messages {
-		cmd.ui.Say("%s", msg.ToLog(time.Local))
+		cmd.printLog(msg)
 	}
 }
 
 func (cmd *Logs) tailLogsFor(app models.Application) {
 	onConnect := func() {
+		if cmd.outputFormat.IsStructured() {
+			return
+		}
+
 		cmd.ui.Say(T("Connected, tailing logs for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
 			map[string]interface{}{
 				"AppName":   terminal.EntityNameColor(app.Name),
@@ -111,7 +208,7 @@ func (cmd *Logs) tailLogsFor(app models.Application) {
 			if !ok {
 				return
 			}
-			cmd.ui.Say("%s", msg.ToLog(time.Local))
+			cmd.printLog(msg)
 		case err := <-e:
 			cmd.handleError(err)
 		}
@@ -1,6 +1,7 @@
 package application_test
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf/api/logs"
@@ -143,6 +144,136 @@ var _ = Describe("logs command", func() {
 			))
 		})
 
+		Describe("filtering", func() {
+			BeforeEach(func() {
+				logsRepo.RecentLogsForReturns([]logs.Loggable{
+					testlogs.NewLogMessage("GET /", app.GUID, "RTR", "0", logmessage.LogMessage_OUT, time.Now().Add(-2*time.Hour)),
+					testlogs.NewLogMessage("app started", app.GUID, "APP/PROC/WEB", "0", logmessage.LogMessage_OUT, time.Now()),
+					testlogs.NewLogMessage("app crashed", app.GUID, "APP/PROC/WEB", "1", logmessage.LogMessage_ERR, time.Now()),
+					testlogs.NewLogMessage("staging done", app.GUID, "STG", "0", logmessage.LogMessage_OUT, time.Now()),
+				}, nil)
+			})
+
+			It("only shows logs from the given sources", func() {
+				runCommand("--recent", "--source", "APP", "--source", "stg", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"app started"},
+					[]string{"app crashed"},
+					[]string{"staging done"},
+				))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"GET /"}))
+			})
+
+			It("only shows logs from the given instance", func() {
+				runCommand("--recent", "--instance", "1", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"app crashed"}))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"app started"}))
+			})
+
+			It("only shows logs matching the regular expression", func() {
+				runCommand("--recent", "--grep", "^app (started|stopped)$", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"app started"}))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"app crashed"}))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"GET /"}))
+			})
+
+			It("only shows logs from the given duration", func() {
+				runCommand("--recent", "--since", "1h", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"app started"}))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"GET /"}))
+			})
+
+			It("filters logs while tailing", func() {
+				runCommand("--source", "RTR", "my-app")
+
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Log Line 1"}))
+			})
+
+			It("fails with an unknown source", func() {
+				runCommand("--recent", "--source", "NOPE", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Invalid log source: NOPE"},
+				))
+			})
+
+			It("fails with an invalid regular expression", func() {
+				runCommand("--recent", "--grep", "(", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Invalid regular expression for --grep"},
+				))
+			})
+
+			It("fails with an invalid duration", func() {
+				runCommand("--recent", "--since", "yesterday", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Invalid duration for --since: yesterday"},
+				))
+			})
+		})
+
+		Describe("--output", func() {
+			It("prints one JSON object per log message without any other output", func() {
+				runCommand("--recent", "--output", "json", "my-app")
+
+				Expect(ui.Outputs).To(HaveLen(2))
+				for _, line := range ui.Outputs {
+					var fields map[string]string
+					Expect(json.Unmarshal([]byte(line), &fields)).To(Succeed())
+					Expect(fields["source_type"]).To(Equal("DEA"))
+					Expect(fields["source_instance"]).To(Equal("1"))
+					Expect(fields["stream"]).To(Equal("ERR"))
+				}
+				Expect(ui.Outputs[0]).To(ContainSubstring(`"message":"Log Line 1"`))
+			})
+
+			It("prints JSON while tailing", func() {
+				runCommand("--output", "json", "my-app")
+
+				Expect(ui.Outputs).To(HaveLen(1))
+				Expect(ui.Outputs[0]).To(ContainSubstring(`"message":"Log Line 1"`))
+			})
+
+			It("prints one YAML document per log message with --output yaml", func() {
+				runCommand("--recent", "--output", "yaml", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"---"},
+					[]string{"source_type: DEA"},
+					[]string{"message: Log Line 1"},
+					[]string{"---"},
+				))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Connected, dumping recent logs"}))
+			})
+
+			It("prints log lines with --output table, like the global flag", func() {
+				runCommand("--recent", "--output", "table", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Connected, dumping recent logs"},
+					[]string{"Log Line 1"},
+				))
+			})
+
+			It("fails with an unknown output format", func() {
+				runCommand("--recent", "--output", "xml", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Invalid output format: xml"},
+				))
+			})
+		})
+
 		Context("when the loggregator server has an invalid cert", func() {
 			Context("when the skip-ssl-validation flag is not set", func() {
 				It("fails and informs the user about the skip-ssl-validation flag", func() {
@@ -5,8 +5,11 @@ import (
 	"io/ioutil"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/flags"
@@ -14,6 +17,7 @@ import (
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/cf/actors"
 	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/appinstances"
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/api/authentication"
 	"github.com/cloudfoundry/cli/cf/api/stacks"
@@ -30,23 +34,37 @@ import (
 	"github.com/cloudfoundry/cli/words/generator"
 )
 
+const (
+	BlueGreenStrategy = "blue-green"
+
+	PendingAppSuffix   = "-pending"
+	VenerableAppSuffix = "-venerable"
+)
+
 type Push struct {
-	ui            terminal.UI
-	config        coreconfig.Reader
-	manifestRepo  manifest.ManifestRepository
-	appStarter    ApplicationStarter
-	appStopper    ApplicationStopper
-	serviceBinder service.ServiceBinder
-	appRepo       applications.ApplicationRepository
-	domainRepo    api.DomainRepository
-	routeRepo     api.RouteRepository
-	serviceRepo   api.ServiceRepository
-	stackRepo     stacks.StackRepository
-	authRepo      authentication.AuthenticationRepository
-	wordGenerator generator.WordGenerator
-	actor         actors.PushActor
-	zipper        appfiles.Zipper
-	appfiles      appfiles.AppFiles
+	ui               terminal.UI
+	config           coreconfig.Reader
+	manifestRepo     manifest.ManifestRepository
+	appStarter       ApplicationStarter
+	appStopper       ApplicationStopper
+	serviceBinder    service.ServiceBinder
+	appRepo          applications.ApplicationRepository
+	appSummaryRepo   api.AppSummaryRepository
+	appInstancesRepo appinstances.AppInstancesRepository
+	domainRepo       api.DomainRepository
+	routeRepo        api.RouteRepository
+	serviceRepo      api.ServiceRepository
+	stackRepo        stacks.StackRepository
+	authRepo         authentication.AuthenticationRepository
+	wordGenerator    generator.WordGenerator
+	actor            actors.PushActor
+	zipper           appfiles.Zipper
+	appfiles         appfiles.AppFiles
+	appFilesCache    *appfiles.HashCache
+	deps             commandregistry.Dependency
+
+	StartupTimeout time.Duration
+	PingerThrottle time.Duration
 }
 
 func init() {
@@ -58,7 +76,7 @@ func (cmd *Push) MetaData() commandregistry.CommandMetadata {
 	fs["b"] = &flags.StringFlag{ShortName: "b", Usage: T("Custom buildpack by name (e.g. my-buildpack) or Git URL (e.g. 'https://github.com/cloudfoundry/java-buildpack.git') or Git URL with a branch or tag (e.g. 'https://github.com/cloudfoundry/java-buildpack.git#v3.3.0' for 'v3.3.0' tag). To use built-in buildpacks only, specify 'default' or 'null'")}
 	fs["c"] = &flags.StringFlag{ShortName: "c", Usage: T("Startup command, set to null to reset to default start command")}
 	fs["d"] = &flags.StringFlag{ShortName: "d", Usage: T("Domain (e.g. example.com)")}
-	fs["f"] = &flags.StringFlag{ShortName: "f", Usage: T("Path to manifest")}
+	fs["f"] = &flags.StringSliceFlag{ShortName: "f", Usage: T("Path to manifest. Can be specified multiple times to merge each manifest onto the ones before it")}
 	fs["i"] = &flags.IntFlag{ShortName: "i", Usage: T("Number of instances")}
 	fs["k"] = &flags.StringFlag{ShortName: "k", Usage: T("Disk limit (e.g. 256M, 1024M, 1G)")}
 	fs["m"] = &flags.StringFlag{ShortName: "m", Usage: T("Memory limit (e.g. 256M, 1024M, 1G)")}
@@ -68,12 +86,18 @@ func (cmd *Push) MetaData() commandregistry.CommandMetadata {
 	fs["t"] = &flags.StringFlag{ShortName: "t", Usage: T("Maximum time (in seconds) for CLI to wait for application start, other server side timeouts may apply")}
 	fs["docker-image"] = &flags.StringFlag{Name: "docker-image", ShortName: "o", Usage: T("Docker-image to be used (e.g. user/docker-image-name)")}
 	fs["health-check-type"] = &flags.StringFlag{Name: "health-check-type", ShortName: "u", Usage: T("Application health check type (e.g. 'port' or 'none')")}
+	fs["dry-run"] = &flags.BoolFlag{Name: "dry-run", Usage: T("Show what push would create, update, bind and upload without changing anything")}
+	fs["no-cache"] = &flags.BoolFlag{Name: "no-cache", Usage: T("Hash every app file again instead of reusing hashes from earlier pushes")}
 	fs["no-hostname"] = &flags.BoolFlag{Name: "no-hostname", Usage: T("Map the root domain to this app")}
 	fs["no-manifest"] = &flags.BoolFlag{Name: "no-manifest", Usage: T("Ignore manifest file")}
 	fs["no-route"] = &flags.BoolFlag{Name: "no-route", Usage: T("Do not map a route to this app and remove routes from previous pushes of this app")}
 	fs["no-start"] = &flags.BoolFlag{Name: "no-start", Usage: T("Do not start an app after pushing")}
+	fs["parallel"] = &flags.IntFlag{Name: "parallel", Usage: T("Number of apps from a manifest to push at the same time")}
 	fs["random-route"] = &flags.BoolFlag{Name: "random-route", Usage: T("Create a random route for this app")}
 	fs["route-path"] = &flags.StringFlag{Name: "route-path", Usage: T("Path for the route")}
+	fs["var"] = &flags.StringSliceFlag{Name: "var", Usage: T("Variable to substitute for ((NAME)) in the manifest, as NAME=VALUE. Can be specified multiple times")}
+	fs["vars-file"] = &flags.StringSliceFlag{Name: "vars-file", Usage: T("Path to a YAML file of variables to substitute in the manifest. Can be specified multiple times")}
+	fs["strategy"] = &flags.StringFlag{Name: "strategy", Usage: T("Deployment strategy for an existing app. 'blue-green' starts a new copy of the app and moves its routes before deleting the old one")}
 	// Hidden:true to hide app-ports for release #117189491
 	fs["app-ports"] = &flags.StringFlag{Name: "app-ports", Usage: T("Comma delimited list of ports the application may listen on"), Hidden: true}
 
@@ -100,15 +124,23 @@ func (cmd *Push) MetaData() commandregistry.CommandMetadata {
 			fmt.Sprintf("[-t %s] ", T("TIMEOUT")),
 			fmt.Sprintf("[-u %s] ", T("HEALTH_CHECK_TYPE")),
 			fmt.Sprintf("[--route-path %s] ", T("ROUTE_PATH")),
+			fmt.Sprintf("[--strategy %s] ", T("STRATEGY")),
+			"\n   ",
+			fmt.Sprintf("[--vars-file %s] ", T("VARS_FILE_PATH")),
+			fmt.Sprintf("[--var %s] ", T("NAME=VALUE")),
 			"\n   ",
 			// Commented to hide app-ports for release #117189491
 			// fmt.Sprintf("[--app-ports %s] ", T("APP_PORTS")),
-			"[--no-hostname] [--no-manifest] [--no-route] [--no-start]\n",
+			"[--no-hostname] [--no-manifest] [--no-route] [--no-start] [--no-cache] [--dry-run]\n",
 			"\n   ",
 			T("Push multiple apps with a manifest"),
 			":\n   ",
 			"CF_NAME push ",
 			fmt.Sprintf("[-f %s] ", T("MANIFEST_PATH")),
+			fmt.Sprintf("[--parallel %s] ", T("NUM_APPS")),
+			fmt.Sprintf("[--vars-file %s] ", T("VARS_FILE_PATH")),
+			fmt.Sprintf("[--var %s] ", T("NAME=VALUE")),
+			"[--no-cache] [--dry-run]",
 			"\n",
 		},
 		Flags: fs,
@@ -143,26 +175,33 @@ func (cmd *Push) Requirements(requirementsFactory requirements.Factory, fc flags
 }
 
 func (cmd *Push) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	return cmd.setDependency(deps, commandregistry.Commands.FindCommand)
+}
+
+func (cmd *Push) setDependency(deps commandregistry.Dependency, findCommand func(string) commandregistry.Command) *Push {
 	cmd.ui = deps.UI
 	cmd.config = deps.Config
 	cmd.manifestRepo = deps.ManifestRepo
+	cmd.deps = deps
 
 	//set appStarter
-	appCommand := commandregistry.Commands.FindCommand("start")
+	appCommand := findCommand("start")
 	appCommand = appCommand.SetDependency(deps, false)
 	cmd.appStarter = appCommand.(ApplicationStarter)
 
 	//set appStopper
-	appCommand = commandregistry.Commands.FindCommand("stop")
+	appCommand = findCommand("stop")
 	appCommand = appCommand.SetDependency(deps, false)
 	cmd.appStopper = appCommand.(ApplicationStopper)
 
 	//set serviceBinder
-	appCommand = commandregistry.Commands.FindCommand("bind-service")
+	appCommand = findCommand("bind-service")
 	appCommand = appCommand.SetDependency(deps, false)
 	cmd.serviceBinder = appCommand.(service.ServiceBinder)
 
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.appInstancesRepo = deps.RepoLocator.GetAppInstancesRepository()
 	cmd.domainRepo = deps.RepoLocator.GetDomainRepository()
 	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
 	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
@@ -172,14 +211,28 @@ func (cmd *Push) SetDependency(deps commandregistry.Dependency, pluginCall bool)
 	cmd.actor = deps.PushActor
 	cmd.zipper = deps.AppZipper
 	cmd.appfiles = deps.AppFiles
+	cmd.appFilesCache = deps.AppFilesCache
+	cmd.StartupTimeout = DefaultStartupTimeout
+	cmd.PingerThrottle = DefaultPingerThrottle
 
 	return cmd
 }
 
 func (cmd *Push) Execute(c flags.FlagContext) {
+	blueGreen := cmd.isBlueGreen(c)
+
+	parallel := 1
+	if c.IsSet("parallel") {
+		parallel = c.Int("parallel")
+		if parallel < 1 {
+			cmd.ui.Failed(T("Invalid number of parallel pushes: {{.Parallel}}\nThe number must be a positive integer",
+				map[string]interface{}{"Parallel": parallel}))
+		}
+	}
+
 	appsFromManifest := cmd.getAppParamsFromManifest(c)
 	appFromContext := cmd.getAppParamsFromContext(c)
-	appSet := cmd.createAppSetFromContextAndManifest(appFromContext, appsFromManifest)
+	appSet := cmd.orderByDependencies(cmd.createAppSetFromContextAndManifest(appFromContext, appsFromManifest))
 
 	_, err := cmd.authRepo.RefreshAuthToken()
 	if err != nil {
@@ -187,86 +240,630 @@ func (cmd *Push) Execute(c flags.FlagContext) {
 		return
 	}
 
+	if c.Bool("no-cache") && cmd.appFilesCache != nil {
+		cmd.appFilesCache.SkipReads()
+	}
+
+	if c.Bool("dry-run") {
+		if cmd.appFilesCache != nil {
+			cmd.appFilesCache.SkipWrites()
+		}
+		cmd.dryRunPush(appSet)
+		return
+	}
+
+	if parallel > 1 && len(appSet) > 1 {
+		cmd.pushInParallel(appSet, parallel, blueGreen, c)
+		return
+	}
+
 	routeActor := actors.NewRouteActor(cmd.ui, cmd.routeRepo)
 
 	for _, appParams := range appSet {
-		if appParams.Name == nil {
-			cmd.ui.Failed(T("Error: No name found for app"))
+		cmd.pushApp(routeActor, appParams, blueGreen, c)
+	}
+}
+
+func (cmd *Push) pushApp(routeActor actors.RouteActor, appParams models.AppParams, blueGreen bool, c flags.FlagContext) {
+	if appParams.Name == nil {
+		cmd.ui.Failed(T("Error: No name found for app"))
+	}
+
+	cmd.fetchStackGUID(&appParams)
+
+	if appParams.DockerImage != nil {
+		diego := true
+		appParams.Diego = &diego
+	}
+
+	var app models.Application
+	existingApp, err := cmd.appRepo.Read(*appParams.Name)
+	switch err.(type) {
+	case nil:
+		if blueGreen {
+			cmd.blueGreenPush(routeActor, existingApp, appParams)
+			return
 		}
 
-		cmd.fetchStackGUID(&appParams)
+		cmd.ui.Say(T("Updating app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(existingApp.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+
+		if appParams.EnvironmentVars != nil {
+			for key, val := range existingApp.EnvironmentVars {
+				if _, ok := (*appParams.EnvironmentVars)[key]; !ok {
+					(*appParams.EnvironmentVars)[key] = val
+				}
+			}
+		}
 
-		if c.IsSet("docker-image") {
-			diego := true
-			appParams.Diego = &diego
+		app, err = cmd.appRepo.Update(existingApp.GUID, appParams)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
 		}
+	case *errors.ModelNotFoundError:
+		spaceGUID := cmd.config.SpaceFields().GUID
+		appParams.SpaceGUID = &spaceGUID
 
-		var app models.Application
-		existingApp, err := cmd.appRepo.Read(*appParams.Name)
-		switch err.(type) {
-		case nil:
-			cmd.ui.Say(T("Updating app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
-				map[string]interface{}{
-					"AppName":   terminal.EntityNameColor(existingApp.Name),
-					"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-					"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-					"Username":  terminal.EntityNameColor(cmd.config.Username())}))
-
-			if appParams.EnvironmentVars != nil {
-				for key, val := range existingApp.EnvironmentVars {
-					if _, ok := (*appParams.EnvironmentVars)[key]; !ok {
-						(*appParams.EnvironmentVars)[key] = val
-					}
+		cmd.ui.Say(T("Creating app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(*appParams.Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+
+		app, err = cmd.appRepo.Create(appParams)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	default:
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	cmd.updateRoutes(routeActor, app, appParams)
+
+	if appParams.DockerImage == nil {
+		err := cmd.actor.ProcessPath(*appParams.Path, cmd.processPathCallback(*appParams.Path, app))
+		if err != nil {
+			cmd.ui.Failed(
+				T("Error processing app files: {{.Error}}",
+					map[string]interface{}{
+						"Error": err.Error(),
+					}),
+			)
+			return
+		}
+	}
+
+	if appParams.ServicesToBind != nil {
+		cmd.bindAppToServices(*appParams.ServicesToBind, app)
+	}
+
+	cmd.restart(app, appParams, c)
+}
+
+// orderByDependencies sorts apps so that every app comes after the apps it
+// depends on, keeping the manifest order otherwise. Dependencies on apps that
+// are not being pushed are ignored.
+func (cmd *Push) orderByDependencies(apps []models.AppParams) []models.AppParams {
+	pushing := map[string]bool{}
+	for _, app := range apps {
+		if app.Name == nil {
+			cmd.ui.Failed(T("Error: No name found for app"))
+		}
+		pushing[*app.Name] = true
+	}
+
+	ordered := []models.AppParams{}
+	placed := map[string]bool{}
+	remaining := apps
+
+	for len(remaining) > 0 {
+		var blocked []models.AppParams
+
+		for _, app := range remaining {
+			ready := true
+			for _, dependency := range appDependencies(app) {
+				if pushing[dependency] && !placed[dependency] {
+					ready = false
+					break
 				}
 			}
 
-			app, err = cmd.appRepo.Update(existingApp.GUID, appParams)
-			if err != nil {
-				cmd.ui.Failed(err.Error())
+			if ready {
+				ordered = append(ordered, app)
+				placed[*app.Name] = true
+			} else {
+				blocked = append(blocked, app)
 			}
-		case *errors.ModelNotFoundError:
-			spaceGUID := cmd.config.SpaceFields().GUID
-			appParams.SpaceGUID = &spaceGUID
+		}
 
-			cmd.ui.Say(T("Creating app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
-				map[string]interface{}{
-					"AppName":   terminal.EntityNameColor(*appParams.Name),
-					"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-					"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-					"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+		if len(blocked) == len(remaining) {
+			names := []string{}
+			for _, app := range blocked {
+				names = append(names, *app.Name)
+			}
+			cmd.ui.Failed(T("Apps in the manifest depend on each other in a cycle: {{.AppNames}}",
+				map[string]interface{}{"AppNames": strings.Join(names, ", ")}))
+		}
 
-			app, err = cmd.appRepo.Create(appParams)
-			if err != nil {
-				cmd.ui.Failed(err.Error())
+		remaining = blocked
+	}
+
+	return ordered
+}
+
+func appDependencies(app models.AppParams) []string {
+	if app.DependsOn == nil {
+		return nil
+	}
+	return *app.DependsOn
+}
+
+type appPushStatus string
+
+const (
+	appPushed      appPushStatus = "pushed"
+	appPushFailed  appPushStatus = "failed"
+	appPushSkipped appPushStatus = "skipped"
+)
+
+func (status appPushStatus) String() string {
+	switch status {
+	case appPushed:
+		return T("pushed")
+	case appPushFailed:
+		return T("failed")
+	default:
+		return T("skipped")
+	}
+}
+
+// pushInParallel pushes up to parallel apps at the same time. An app only
+// starts once the apps it depends on have been pushed, and is skipped if any
+// of them fail. Each app's output is prefixed with its name, and a summary
+// of all of them is shown at the end.
+func (cmd *Push) pushInParallel(appSet []models.AppParams, parallel int, blueGreen bool, c flags.FlagContext) {
+	index := map[string]int{}
+	done := make([]chan struct{}, len(appSet))
+	pushers := make([]*Push, len(appSet))
+	for i, appParams := range appSet {
+		index[*appParams.Name] = i
+		done[i] = make(chan struct{})
+		pushers[i] = cmd.newAppPusher(*appParams.Name)
+	}
+
+	statuses := make([]appPushStatus, len(appSet))
+	details := make([]string, len(appSet))
+	slots := make(chan struct{}, parallel)
+
+	wg := &sync.WaitGroup{}
+	for i, appParams := range appSet {
+		wg.Add(1)
+
+		go func(i int, appParams models.AppParams) {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dependency := range appDependencies(appParams) {
+				j, ok := index[dependency]
+				if !ok {
+					continue
+				}
+
+				<-done[j]
+				if statuses[j] != appPushed {
+					statuses[i] = appPushSkipped
+					details[i] = T("{{.AppName}} was not pushed", map[string]interface{}{"AppName": dependency})
+					return
+				}
 			}
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			statuses[i], details[i] = pushers[i].tryPushApp(appParams, blueGreen, c)
+		}(i, appParams)
+	}
+	wg.Wait()
+
+	cmd.ui.Say("")
+	table := cmd.ui.Table([]string{T("name"), T("status"), T("details")})
+
+	notPushed := 0
+	for i, appParams := range appSet {
+		if statuses[i] != appPushed {
+			notPushed++
+		}
+		table.Add(*appParams.Name, statuses[i].String(), details[i])
+	}
+	table.Print()
+
+	if notPushed > 0 {
+		cmd.ui.Failed(T("{{.NotPushedCount}} of {{.AppCount}} apps were not pushed",
+			map[string]interface{}{"NotPushedCount": notPushed, "AppCount": len(appSet)}))
+	}
+}
+
+// appPusherUI remembers what the push of an app failed with, for the
+// summary of a parallel push.
+type appPusherUI struct {
+	terminal.UI
+	failure string
+}
+
+func (ui *appPusherUI) Failed(message string, args ...interface{}) {
+	ui.failure = fmt.Sprintf(message, args...)
+	ui.UI.Failed(message, args...)
+}
+
+// newAppPusher creates a push command for a single app of a parallel push.
+// It has its own copies of the commands it uses and its own logs
+// connection, and says everything prefixed with the app's name.
+func (cmd *Push) newAppPusher(appName string) *Push {
+	deps := cmd.deps
+	deps.UI = &appPusherUI{UI: terminal.NewPrefixedUI(cmd.ui, fmt.Sprintf("[%s] ", appName))}
+	deps.RepoLocator = deps.RepoLocator.SetLogsRepository(deps.RepoLocator.NewLogsRepository())
+
+	pusher := new(Push).setDependency(deps, commandregistry.Commands.CopyCommand)
+	if starter, ok := pusher.appStarter.(*Start); ok {
+		appCommand := commandregistry.Commands.CopyCommand("app")
+		appCommand = appCommand.SetDependency(deps, false)
+		starter.appDisplayer = appCommand.(ApplicationDisplayer)
+	}
+	pusher.StartupTimeout = cmd.StartupTimeout
+	pusher.PingerThrottle = cmd.PingerThrottle
+
+	return pusher
+}
+
+// tryPushApp pushes an app, reporting failures through its status and the
+// error as details rather than by ending the command, so that other apps can
+// carry on. It runs in a goroutine of its own, where a panic could not be
+// recovered by the CLI, so every panic is turned into a failure.
+func (cmd *Push) tryPushApp(appParams models.AppParams, blueGreen bool, c flags.FlagContext) (status appPushStatus, details string) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		status = appPushFailed
+		if r != terminal.QuietPanic {
+			details = T("Unexpected error: {{.Error}}", map[string]interface{}{"Error": fmt.Sprint(r)})
+			cmd.ui.Say(details)
+		} else if ui, ok := cmd.ui.(*appPusherUI); ok {
+			details = ui.failure
+		}
+		details = strings.Join(strings.Fields(details), " ")
+	}()
+
+	routeActor := actors.NewRouteActor(cmd.ui, cmd.routeRepo)
+	cmd.pushApp(routeActor, appParams, blueGreen, c)
+
+	return appPushed, ""
+}
+
+func (cmd *Push) isBlueGreen(c flags.FlagContext) bool {
+	switch strategy := c.String("strategy"); strategy {
+	case "":
+		return false
+	case BlueGreenStrategy:
+		if c.Bool("no-start") {
+			cmd.ui.Failed(T("Cannot use --no-start with --strategy {{.Strategy}}",
+				map[string]interface{}{"Strategy": strategy}))
+		}
+		return true
+	default:
+		cmd.ui.Failed(T("Invalid deployment strategy: {{.Strategy}}\nValid strategies are: {{.ValidStrategies}}",
+			map[string]interface{}{"Strategy": strategy, "ValidStrategies": BlueGreenStrategy}))
+	}
+
+	return false
+}
+
+// blueGreenPush replaces liveApp without downtime. The new version is pushed
+// as a separate app and only takes over liveApp's routes once every one of
+// its instances is running; if it fails to stage or start it is deleted and
+// liveApp is left untouched.
+func (cmd *Push) blueGreenPush(routeActor actors.RouteActor, liveApp models.Application, appParams models.AppParams) {
+	pendingName := liveApp.Name + PendingAppSuffix
+	venerableName := liveApp.Name + VenerableAppSuffix
+
+	for _, name := range []string{pendingName, venerableName} {
+		_, err := cmd.appRepo.Read(name)
+		switch err.(type) {
+		case nil:
+			cmd.ui.Failed(T("App {{.AppName}} already exists, it may be left over from an earlier blue-green push of {{.LiveAppName}}.\nTIP: use '{{.Command}}' to delete it and push again",
+				map[string]interface{}{
+					"AppName":     name,
+					"LiveAppName": liveApp.Name,
+					"Command":     terminal.CommandColor(fmt.Sprintf("%s delete %s", cf.Name, name)),
+				}))
+		case *errors.ModelNotFoundError:
 		default:
 			cmd.ui.Failed(err.Error())
 		}
+	}
 
-		cmd.ui.Ok()
-		cmd.ui.Say("")
+	cmd.ui.Say(T("Creating app {{.AppName}} to replace {{.LiveAppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		map[string]interface{}{
+			"AppName":     terminal.EntityNameColor(pendingName),
+			"LiveAppName": terminal.EntityNameColor(liveApp.Name),
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":    terminal.EntityNameColor(cmd.config.Username())}))
 
-		cmd.updateRoutes(routeActor, app, appParams)
+	pendingApp, err := cmd.appRepo.Create(cmd.pendingAppParams(liveApp, pendingName, appParams))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
 
-		if c.String("docker-image") == "" {
-			err := cmd.actor.ProcessPath(*appParams.Path, cmd.processPathCallback(*appParams.Path, app))
-			if err != nil {
-				cmd.ui.Failed(
-					T("Error processing app files: {{.Error}}",
-						map[string]interface{}{
-							"Error": err.Error(),
-						}),
-				)
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	cmd.startPendingApp(liveApp, pendingApp, appParams)
+	cmd.switchToPendingApp(routeActor, liveApp, pendingApp, appParams)
+
+	cmd.ui.Say(T("Deleting app {{.AppName}}...",
+		map[string]interface{}{"AppName": terminal.EntityNameColor(venerableName)}))
+
+	err = cmd.appRepo.Delete(liveApp.GUID)
+	if err != nil {
+		cmd.ui.Failed(T("Could not delete app {{.AppName}}: {{.Err}}\nTIP: Its routes have been moved to {{.NewAppName}}; use '{{.Command}}' to delete it",
+			map[string]interface{}{
+				"AppName":    venerableName,
+				"Err":        err.Error(),
+				"NewAppName": liveApp.Name,
+				"Command":    terminal.CommandColor(fmt.Sprintf("%s delete %s", cf.Name, venerableName)),
+			}))
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+}
+
+// pendingAppParams builds the parameters for the app that will replace
+// liveApp. Settings not given on the command line or in the manifest are
+// carried over from liveApp, as they would be by an in-place push.
+func (cmd *Push) pendingAppParams(liveApp models.Application, name string, appParams models.AppParams) models.AppParams {
+	if appParams.EnvironmentVars != nil {
+		for key, val := range liveApp.EnvironmentVars {
+			if _, ok := (*appParams.EnvironmentVars)[key]; !ok {
+				(*appParams.EnvironmentVars)[key] = val
+			}
+		}
+	}
+
+	params := liveApp.ToParams()
+	params.GUID = nil
+	params.State = nil
+	if liveApp.DockerImage == "" {
+		params.DockerImage = nil
+	}
+	params.Diego = &liveApp.Diego
+
+	params.Merge(&appParams)
+	if appParams.Diego != nil {
+		params.Diego = appParams.Diego
+	}
+
+	spaceGUID := cmd.config.SpaceFields().GUID
+	params.Name = &name
+	params.SpaceGUID = &spaceGUID
+
+	return params
+}
+
+// startPendingApp uploads, binds and starts pendingApp, deleting it again if
+// any of those steps fail.
+func (cmd *Push) startPendingApp(liveApp models.Application, pendingApp models.Application, appParams models.AppParams) {
+	defer func() {
+		if r := recover(); r != nil {
+			cmd.rollBack(pendingApp)
+			panic(r)
+		}
+	}()
+
+	if appParams.DockerImage == nil && pendingApp.DockerImage == "" {
+		err := cmd.actor.ProcessPath(*appParams.Path, cmd.processPathCallback(*appParams.Path, pendingApp))
+		if err != nil {
+			cmd.ui.Failed(
+				T("Error processing app files: {{.Error}}",
+					map[string]interface{}{
+						"Error": err.Error(),
+					}),
+			)
+		}
+	}
+
+	cmd.bindAppToServices(cmd.servicesToBind(liveApp, appParams), pendingApp)
+
+	cmd.ui.Say("")
+
+	startupTimeout := cmd.StartupTimeout
+	if appParams.HealthCheckTimeout != nil {
+		cmd.appStarter.SetStartTimeoutInSeconds(*appParams.HealthCheckTimeout)
+		startupTimeout = time.Duration(*appParams.HealthCheckTimeout) * time.Second
+	}
+
+	_, err := cmd.appStarter.ApplicationStart(pendingApp, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.waitForAllInstancesRunning(pendingApp, startupTimeout)
+}
+
+// switchToPendingApp moves the routes of liveApp to pendingApp, renames
+// liveApp to its venerable name and gives pendingApp the name of liveApp. If
+// any of those steps fail, the routes and names of liveApp are restored and
+// pendingApp is deleted.
+func (cmd *Push) switchToPendingApp(routeActor actors.RouteActor, liveApp models.Application, pendingApp models.Application, appParams models.AppParams) {
+	liveRenamed := false
+	pendingRenamed := false
+
+	defer func() {
+		if r := recover(); r != nil {
+			cmd.restoreLiveApp(liveApp, pendingApp, liveRenamed, pendingRenamed)
+			panic(r)
+		}
+	}()
+
+	pendingApp = routeActor.MoveRoutes(liveApp, pendingApp)
+	cmd.updateRoutes(routeActor, pendingApp, appParams)
+
+	cmd.renameApp(liveApp, liveApp.Name+VenerableAppSuffix)
+	liveRenamed = true
+	cmd.renameApp(pendingApp, liveApp.Name)
+	pendingRenamed = true
+}
+
+// restoreLiveApp undoes switchToPendingApp as far as it got. Errors are
+// only warned about, so that every step is tried.
+func (cmd *Push) restoreLiveApp(liveApp models.Application, pendingApp models.Application, liveRenamed bool, pendingRenamed bool) {
+	cmd.ui.Say("")
+	cmd.ui.Say(T("Rolling back: restoring app {{.AppName}}...",
+		map[string]interface{}{"AppName": terminal.EntityNameColor(liveApp.Name)}))
+
+	restoreName := func(app models.Application, name string) {
+		_, err := cmd.appRepo.Update(app.GUID, models.AppParams{Name: &name})
+		if err != nil {
+			cmd.ui.Warn(T("Could not rename app {{.AppName}} back to {{.NewName}}: {{.Err}}",
+				map[string]interface{}{"AppName": app.Name, "NewName": name, "Err": err.Error()}))
+		}
+	}
+
+	if pendingRenamed {
+		restoreName(pendingApp, pendingApp.Name)
+	}
+	if liveRenamed {
+		restoreName(liveApp, liveApp.Name)
+	}
+
+	for _, route := range liveApp.Routes {
+		err := cmd.routeRepo.Bind(route.GUID, liveApp.GUID)
+		if err != nil {
+			cmd.ui.Warn(T("Could not bind route {{.URL}} back to {{.AppName}}: {{.Err}}",
+				map[string]interface{}{"URL": route.URL(), "AppName": liveApp.Name, "Err": err.Error()}))
+		}
+	}
+
+	cmd.rollBack(pendingApp)
+}
+
+// servicesToBind lists the services bound to liveApp together with any
+// requested for the push, without duplicates.
+func (cmd *Push) servicesToBind(liveApp models.Application, appParams models.AppParams) []string {
+	summary, err := cmd.appSummaryRepo.GetSummary(liveApp.GUID)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	services := []string{}
+	for _, service := range summary.Services {
+		services = append(services, service.Name)
+	}
+
+	if appParams.ServicesToBind != nil {
+		for _, name := range *appParams.ServicesToBind {
+			if !stringInSlice(name, services) {
+				services = append(services, name)
+			}
+		}
+	}
+
+	return services
+}
+
+func stringInSlice(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (cmd *Push) waitForAllInstancesRunning(app models.Application, timeout time.Duration) {
+	//an app scaled to zero has no instances to wait for
+	if app.InstanceCount == 0 {
+		return
+	}
+
+	cmd.ui.Say(T("Waiting for all instances of {{.AppName}} to be running...",
+		map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+	instanceCounter := Start{appInstancesRepo: cmd.appInstancesRepo}
+	startTime := time.Now()
+
+	for {
+		count, err := instanceCounter.fetchInstanceCount(app.GUID)
+		if err != nil {
+			cmd.ui.Warn(T("Could not fetch instance count: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		} else {
+			cmd.ui.Say(instancesDetails(count))
+
+			if count.flapping > 0 || count.crashed > 0 {
+				cmd.ui.Failed(T("Start unsuccessful\n\nTIP: use '{{.Command}}' for more information",
+					map[string]interface{}{"Command": terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name, app.Name))}))
+			}
+
+			if count.total > 0 && count.running == count.total && count.total >= app.InstanceCount {
+				cmd.ui.Ok()
+				cmd.ui.Say("")
 				return
 			}
 		}
 
-		if appParams.ServicesToBind != nil {
-			cmd.bindAppToServices(*appParams.ServicesToBind, app)
+		if time.Since(startTime) >= timeout {
+			cmd.ui.Failed(T("Timed out waiting for all instances of {{.AppName}} to be running\n\nTIP: use '{{.Command}}' for more information",
+				map[string]interface{}{
+					"AppName": app.Name,
+					"Command": terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name, app.Name)),
+				}))
 		}
 
-		cmd.restart(app, appParams, c)
+		time.Sleep(cmd.PingerThrottle)
+	}
+}
+
+// rollBack deletes an app that failed to replace the live app. It runs while
+// a failure is already being reported, so problems are only warned about.
+func (cmd *Push) rollBack(app models.Application) {
+	cmd.ui.Say("")
+	cmd.ui.Say(T("Rolling back: deleting app {{.AppName}}...",
+		map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+	err := cmd.appRepo.Delete(app.GUID)
+	if err != nil {
+		cmd.ui.Warn(T("Could not delete app {{.AppName}}: {{.Err}}",
+			map[string]interface{}{"AppName": app.Name, "Err": err.Error()}))
+		return
 	}
+
+	cmd.ui.Ok()
+}
+
+func (cmd *Push) renameApp(app models.Application, newName string) {
+	cmd.ui.Say(T("Renaming app {{.AppName}} to {{.NewName}}...",
+		map[string]interface{}{
+			"AppName": terminal.EntityNameColor(app.Name),
+			"NewName": terminal.EntityNameColor(newName),
+		}))
+
+	_, err := cmd.appRepo.Update(app.GUID, models.AppParams{Name: &newName})
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
 }
 
 func (cmd *Push) processPathCallback(path string, app models.Application) func(string) {
@@ -318,6 +915,13 @@ func (cmd *Push) updateRoutes(routeActor actors.RouteActor, app models.Applicati
 		return
 	}
 
+	if appParams.Routes != nil {
+		for _, route := range *appParams.Routes {
+			cmd.createAndBindManifestRoute(routeActor, app, route)
+		}
+		return
+	}
+
 	if routeDefined || defaultRouteAcceptable {
 		if appParams.Domains == nil {
 			domain := cmd.findDomain(nil)
@@ -405,6 +1009,61 @@ func (cmd *Push) createAndBindRoute(
 	routeActor.BindRoute(app, route)
 }
 
+// createAndBindManifestRoute binds a route given in the routes property of a
+// manifest, such as "host.example.com/path" or "tcp.example.com:1234".
+func (cmd *Push) createAndBindManifestRoute(routeActor actors.RouteActor, app models.Application, routeURL string) {
+	host, domain, path, port := cmd.parseRoute(routeURL)
+
+	var route models.Route
+	if port > 0 {
+		route = routeActor.FindOrCreateTCPRoute(domain, port, cmd.config.SpaceFields().GUID)
+	} else {
+		route = routeActor.FindOrCreateRoute(host, domain, path, false)
+	}
+	routeActor.BindRoute(app, route)
+}
+
+// parseRoute splits a route into its parts. The domain is the longest suffix
+// of the hostname that is a domain of the current org, so both
+// "example.com" and "my-app.example.com" can be given.
+func (cmd *Push) parseRoute(routeURL string) (host string, domain models.DomainFields, path string, port int) {
+	hostname := routeURL
+	if index := strings.Index(hostname, "/"); index >= 0 {
+		path = hostname[index:]
+		hostname = hostname[:index]
+	}
+
+	if index := strings.LastIndex(hostname, ":"); index >= 0 {
+		var err error
+		port, err = strconv.Atoi(hostname[index+1:])
+		if err != nil {
+			cmd.ui.Failed(T("Invalid port in route {{.Route}}", map[string]interface{}{"Route": routeURL}))
+		}
+		hostname = hostname[:index]
+	}
+
+	orgGUID := cmd.config.OrganizationFields().GUID
+	domain, err := cmd.domainRepo.FindByNameInOrg(hostname, orgGUID)
+	switch err.(type) {
+	case nil:
+		return "", domain, path, port
+	case *errors.ModelNotFoundError:
+	default:
+		cmd.ui.Failed(err.Error())
+	}
+
+	parts := strings.SplitN(hostname, ".", 2)
+	if len(parts) == 2 {
+		domain, err = cmd.domainRepo.FindByNameInOrg(parts[1], orgGUID)
+		if err == nil {
+			return parts[0], domain, path, port
+		}
+	}
+
+	cmd.ui.Failed(T("The route {{.Route}} did not match any existing domains.", map[string]interface{}{"Route": routeURL}))
+	return
+}
+
 var forbiddenHostCharRegex = regexp.MustCompile("[^a-z0-9-]")
 var whitespaceRegex = regexp.MustCompile(`[\s_]+`)
 
@@ -503,31 +1162,63 @@ func (cmd *Push) getAppParamsFromManifest(c flags.FlagContext) []models.AppParam
 		return []models.AppParams{}
 	}
 
-	var path string
-	if c.String("f") != "" {
-		path = c.String("f")
-	} else {
-		var err error
-		path, err = os.Getwd()
+	paths := c.StringSlice("f")
+	if len(paths) == 0 {
+		path, err := os.Getwd()
 		if err != nil {
 			cmd.ui.Failed(T("Could not determine the current working directory!"), err)
 		}
+		paths = []string{path}
 	}
 
-	m, err := cmd.manifestRepo.ReadManifest(path)
+	m, err := cmd.manifestRepo.ReadManifest(paths[0])
 
 	if err != nil {
-		if m.Path == "" && c.String("f") == "" {
+		if m.Path == "" && !c.IsSet("f") {
 			return []models.AppParams{}
 		}
 		cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
 	}
 
+	var overlayPaths []string
+	for _, path := range paths[1:] {
+		overlay, err := cmd.manifestRepo.ReadManifest(path)
+		if err != nil {
+			cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		}
+		err = m.Merge(overlay)
+		if err != nil {
+			cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		}
+		overlayPaths = append(overlayPaths, overlay.Path)
+	}
+
+	validationErrs := m.Validate()
+	for _, warning := range validationErrs.Warnings() {
+		cmd.ui.Warn(warning.Error())
+	}
+	if errs := validationErrs.Errors(); len(errs) > 0 {
+		message := ""
+		for _, err := range errs {
+			message = message + fmt.Sprintf("%s\n", err.Error())
+		}
+		cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": message}))
+	}
+
+	m.Variables, err = manifest.ReadVariables(c.StringSlice("vars-file"), c.StringSlice("var"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
 	apps, err := m.Applications()
 	if err != nil {
 		cmd.ui.Failed("Error reading manifest file:\n%s", err)
 	}
 
+	for _, path := range overlayPaths {
+		cmd.ui.Say(T("Merging manifest file {{.Path}}",
+			map[string]interface{}{"Path": terminal.EntityNameColor(path)}))
+	}
 	cmd.ui.Say(T("Using manifest file {{.Path}}\n",
 		map[string]interface{}{"Path": terminal.EntityNameColor(m.Path)}))
 	return apps
@@ -725,13 +1416,7 @@ func (cmd *Push) getAppParamsFromContext(c flags.FlagContext) models.AppParams {
 }
 
 func (cmd *Push) uploadApp(appGUID, appDir, appDirOrZipFile string, localFiles []models.AppFileFields) error {
-	uploadDir, err := ioutil.TempDir("", "apps")
-	if err != nil {
-		return err
-	}
-	defer os.RemoveAll(uploadDir)
-
-	remoteFiles, hasFileToUpload, err := cmd.actor.GatherFiles(localFiles, appDir, uploadDir)
+	filesToUpload, remoteFiles, err := cmd.actor.MatchFiles(localFiles, appDir)
 	if err != nil {
 		return err
 	}
@@ -745,8 +1430,8 @@ func (cmd *Push) uploadApp(appGUID, appDir, appDirOrZipFile string, localFiles [
 		os.Remove(zipFile.Name())
 	}()
 
-	if hasFileToUpload {
-		err = cmd.zipper.Zip(uploadDir, zipFile)
+	if len(filesToUpload) > 0 {
+		err = cmd.zipper.ZipFiles(appDir, filesToUpload, zipFile)
 		if err != nil {
 			if emptyDirErr, ok := err.(*errors.EmptyDirError); ok {
 				return emptyDirErr
@@ -759,15 +1444,340 @@ func (cmd *Push) uploadApp(appGUID, appDir, appDirOrZipFile string, localFiles [
 			return err
 		}
 
-		zipFileCount := cmd.appfiles.CountFiles(uploadDir)
-		if zipFileCount > 0 {
-			cmd.ui.Say(T("Uploading app files from: {{.Path}}", map[string]interface{}{"Path": appDir}))
-			cmd.ui.Say(T("Uploading {{.ZipFileBytes}}, {{.FileCount}} files",
-				map[string]interface{}{
-					"ZipFileBytes": formatters.ByteSize(zipFileSize),
-					"FileCount":    zipFileCount}))
-		}
+		cmd.ui.Say(T("Uploading app files from: {{.Path}}", map[string]interface{}{"Path": appDir}))
+		cmd.ui.Say(T("Uploading {{.ZipFileBytes}}, {{.FileCount}} files",
+			map[string]interface{}{
+				"ZipFileBytes": formatters.ByteSize(zipFileSize),
+				"FileCount":    len(filesToUpload)}))
 	}
 
 	return cmd.actor.UploadApp(appGUID, zipFile, remoteFiles)
 }
+
+// Lines of a dry-run plan are marked like a diff: what push would add, what it
+// would change, what it would remove, and what is already in place.
+const (
+	planAdd       = "+"
+	planChange    = "~"
+	planRemove    = "-"
+	planUnchanged = "="
+)
+
+// dryRunPush prints what pushing appSet would change. It only reads from the
+// Cloud Controller, so nothing is created, updated, bound or uploaded.
+func (cmd *Push) dryRunPush(appSet []models.AppParams) {
+	cmd.ui.Say(T("Dry run of push in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}. Nothing will be changed.",
+		map[string]interface{}{
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	cmd.ui.Say("")
+
+	for _, appParams := range appSet {
+		cmd.planApp(appParams)
+		cmd.ui.Say("")
+	}
+
+	cmd.ui.Ok()
+}
+
+func (cmd *Push) planApp(appParams models.AppParams) {
+	if appParams.Name == nil {
+		cmd.ui.Failed(T("Error: No name found for app"))
+	}
+
+	if appParams.StackName != nil {
+		stack, err := cmd.stackRepo.FindByName(*appParams.StackName)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+		appParams.StackGUID = &stack.GUID
+	}
+
+	var app models.Application
+	existingApp, err := cmd.appRepo.Read(*appParams.Name)
+	switch err.(type) {
+	case nil:
+		summary, err := cmd.appSummaryRepo.GetSummary(existingApp.GUID)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+		app = existingApp
+		app.Routes = summary.Routes
+		app.Services = summary.Services
+
+		cmd.ui.Say(T("{{.Marker}} update app {{.AppName}}",
+			map[string]interface{}{"Marker": planChange, "AppName": terminal.EntityNameColor(app.Name)}))
+		cmd.sayPlan(cmd.attributeChanges(&app, appParams), T("no attribute changes"))
+	case *errors.ModelNotFoundError:
+		app.Name = *appParams.Name
+
+		cmd.ui.Say(T("{{.Marker}} create app {{.AppName}}",
+			map[string]interface{}{"Marker": planAdd, "AppName": terminal.EntityNameColor(app.Name)}))
+		cmd.sayPlan(cmd.attributeChanges(nil, appParams), "")
+	default:
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Say(T("  routes:"))
+	cmd.sayPlan(cmd.routeChanges(app, appParams), T("no routes"))
+
+	if appParams.ServicesToBind != nil {
+		cmd.ui.Say(T("  services:"))
+		cmd.sayPlan(cmd.serviceChanges(app, *appParams.ServicesToBind), "")
+	}
+
+	cmd.ui.Say(T("  files:"))
+	cmd.sayPlan(cmd.fileChanges(appParams), "")
+}
+
+func (cmd *Push) sayPlan(lines []string, none string) {
+	if len(lines) == 0 && none != "" {
+		lines = []string{none}
+	}
+
+	for _, line := range lines {
+		cmd.ui.Say("    " + line)
+	}
+}
+
+func planLine(marker, message string) string {
+	return marker + " " + message
+}
+
+// attributeChanges compares appParams with the attributes of an existing app,
+// or lists every attribute that would be set when app is nil.
+func (cmd *Push) attributeChanges(app *models.Application, appParams models.AppParams) []string {
+	var lines []string
+
+	change := func(name, from, to string) {
+		switch {
+		case app == nil:
+			lines = append(lines, planLine(planAdd, fmt.Sprintf("%s: %s", name, to)))
+		case from != to:
+			lines = append(lines, planLine(planChange, fmt.Sprintf("%s: %s -> %s", name, from, to)))
+		}
+	}
+
+	current := models.Application{}
+	if app != nil {
+		current = *app
+	}
+
+	if appParams.Memory != nil {
+		change("memory", fmt.Sprintf("%dM", current.Memory), fmt.Sprintf("%dM", *appParams.Memory))
+	}
+	if appParams.DiskQuota != nil {
+		change("disk_quota", fmt.Sprintf("%dM", current.DiskQuota), fmt.Sprintf("%dM", *appParams.DiskQuota))
+	}
+	if appParams.InstanceCount != nil {
+		change("instances", fmt.Sprintf("%d", current.InstanceCount), fmt.Sprintf("%d", *appParams.InstanceCount))
+	}
+	if appParams.StackGUID != nil && *appParams.StackGUID != current.StackGUID {
+		from := current.StackGUID
+		if stack, err := cmd.stackRepo.FindByGUID(current.StackGUID); err == nil {
+			from = stack.Name
+		}
+		change("stack", planValue(from), *appParams.StackName)
+	}
+	if appParams.BuildpackURL != nil {
+		change("buildpack", planValue(current.BuildpackURL), planValue(*appParams.BuildpackURL))
+	}
+	if appParams.Command != nil {
+		change("command", planValue(current.Command), planValue(*appParams.Command))
+	}
+	if appParams.DockerImage != nil {
+		change("docker-image", planValue(current.DockerImage), planValue(*appParams.DockerImage))
+	}
+	if appParams.HealthCheckType != nil {
+		change("health-check-type", planValue(current.HealthCheckType), planValue(*appParams.HealthCheckType))
+	}
+	if appParams.HealthCheckTimeout != nil {
+		change("timeout", fmt.Sprintf("%d", current.HealthCheckTimeout), fmt.Sprintf("%d", *appParams.HealthCheckTimeout))
+	}
+
+	if appParams.EnvironmentVars != nil {
+		var names []string
+		for name := range *appParams.EnvironmentVars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// Values are left out because environment variables often hold secrets.
+		for _, name := range names {
+			currentValue, found := current.EnvironmentVars[name]
+			switch {
+			case app == nil || !found:
+				lines = append(lines, planLine(planAdd, "env "+name))
+			case fmt.Sprintf("%v", currentValue) != fmt.Sprintf("%v", (*appParams.EnvironmentVars)[name]):
+				lines = append(lines, planLine(planChange, "env "+name))
+			}
+		}
+	}
+
+	return lines
+}
+
+func planValue(value string) string {
+	if value == "" {
+		return T("(none)")
+	}
+	return value
+}
+
+func (cmd *Push) routeChanges(app models.Application, appParams models.AppParams) []string {
+	var lines []string
+
+	if appParams.NoRoute {
+		for _, route := range app.Routes {
+			lines = append(lines, planLine(planRemove, T("unmap route {{.URL}}", map[string]interface{}{"URL": route.URL()})))
+		}
+		return lines
+	}
+
+	if appParams.Routes != nil {
+		for _, routeURL := range *appParams.Routes {
+			hostname, domain, path, port := cmd.parseRoute(routeURL)
+			lines = append(lines, cmd.routeChange(app, hostname, domain, path, port))
+		}
+		return lines
+	}
+
+	defaultRouteAcceptable := len(app.Routes) == 0
+	routeDefined := appParams.Domains != nil || !appParams.IsHostEmpty() || appParams.NoHostname
+
+	if !routeDefined && !defaultRouteAcceptable {
+		for _, route := range app.Routes {
+			lines = append(lines, planLine(planUnchanged, T("route {{.URL}} is already mapped", map[string]interface{}{"URL": route.URL()})))
+		}
+		return lines
+	}
+
+	var domains []models.DomainFields
+	if appParams.Domains == nil {
+		domains = append(domains, cmd.findDomain(nil))
+	} else {
+		for _, d := range *appParams.Domains {
+			domainName := d
+			domains = append(domains, cmd.findDomain(&domainName))
+		}
+	}
+
+	routePath := ""
+	if appParams.RoutePath != nil {
+		routePath = *appParams.RoutePath
+	}
+
+	for _, domain := range domains {
+		if isTcp(domain) {
+			lines = append(lines, planLine(planAdd, T("create route {{.Domain}} with a random port and map it",
+				map[string]interface{}{"Domain": domain.Name})))
+			continue
+		}
+
+		var hostnames []string
+		switch {
+		case appParams.NoHostname:
+			hostnames = []string{""}
+		case !appParams.IsHostEmpty():
+			hostnames = *appParams.Hosts
+		case appParams.UseRandomRoute:
+			lines = append(lines, planLine(planAdd, T("create route {{.URL}} and map it",
+				map[string]interface{}{"URL": domain.URLForHostAndPath(hostNameForString(app.Name)+"-"+T("RANDOM-WORDS"), routePath, 0)})))
+			continue
+		default:
+			hostnames = []string{hostNameForString(app.Name)}
+		}
+
+		for _, hostname := range hostnames {
+			lines = append(lines, cmd.routeChange(app, hostname, domain, routePath, 0))
+		}
+	}
+
+	return lines
+}
+
+func (cmd *Push) routeChange(app models.Application, hostname string, domain models.DomainFields, path string, port int) string {
+	url := domain.URLForHostAndPath(hostname, path, port)
+
+	route, err := cmd.routeRepo.Find(hostname, domain, path, port)
+	switch err.(type) {
+	case nil:
+		if app.HasRoute(route) {
+			return planLine(planUnchanged, T("route {{.URL}} is already mapped", map[string]interface{}{"URL": url}))
+		}
+		return planLine(planAdd, T("map route {{.URL}}", map[string]interface{}{"URL": url}))
+	case *errors.ModelNotFoundError:
+		return planLine(planAdd, T("create route {{.URL}} and map it", map[string]interface{}{"URL": url}))
+	default:
+		cmd.ui.Failed(err.Error())
+		return ""
+	}
+}
+
+func (cmd *Push) serviceChanges(app models.Application, services []string) []string {
+	var lines []string
+
+	for _, serviceName := range services {
+		_, err := cmd.serviceRepo.FindInstanceByName(serviceName)
+		if err != nil {
+			cmd.ui.Failed(T("Could not find service {{.ServiceName}} to bind to {{.AppName}}",
+				map[string]interface{}{"ServiceName": serviceName, "AppName": app.Name}))
+		}
+
+		bound := false
+		for _, service := range app.Services {
+			if service.Name == serviceName {
+				bound = true
+				break
+			}
+		}
+
+		if bound {
+			lines = append(lines, planLine(planUnchanged, T("service {{.ServiceName}} is already bound", map[string]interface{}{"ServiceName": serviceName})))
+		} else {
+			lines = append(lines, planLine(planAdd, T("bind service {{.ServiceName}}", map[string]interface{}{"ServiceName": serviceName})))
+		}
+	}
+
+	return lines
+}
+
+// fileChanges asks the resource-match API which of the app's files the Cloud
+// Controller already has, without uploading anything.
+func (cmd *Push) fileChanges(appParams models.AppParams) []string {
+	if appParams.DockerImage != nil {
+		return []string{planLine(planUnchanged, T("docker image, no files to upload"))}
+	}
+
+	var lines []string
+	err := cmd.actor.ProcessPath(*appParams.Path, func(appDir string) {
+		localFiles, err := cmd.appfiles.AppFilesInDir(appDir)
+		if err != nil {
+			cmd.ui.Failed(
+				T("Error processing app files in '{{.Path}}': {{.Error}}",
+					map[string]interface{}{
+						"Path":  *appParams.Path,
+						"Error": err.Error(),
+					}),
+			)
+		}
+
+		_, remoteFiles, err := cmd.actor.MatchFiles(localFiles, appDir)
+		if err != nil {
+			cmd.ui.Failed(T("Error processing app files: {{.Error}}", map[string]interface{}{"Error": err.Error()}))
+		}
+
+		lines = append(lines, planLine(planAdd, T("upload {{.Count}} of {{.Total}} files from {{.Path}}",
+			map[string]interface{}{
+				"Count": len(localFiles) - len(remoteFiles),
+				"Total": len(localFiles),
+				"Path":  appDir,
+			})))
+	})
+	if err != nil {
+		cmd.ui.Failed(T("Error processing app files: {{.Error}}", map[string]interface{}{"Error": err.Error()}))
+	}
+
+	return lines
+}
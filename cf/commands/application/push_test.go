@@ -1,17 +1,22 @@
 package application_test
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/cloudfoundry/cli/cf/actors/actorsfakes"
 	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/appinstances/appinstancesfakes"
 	"github.com/cloudfoundry/cli/cf/api/applications/applicationsfakes"
 	"github.com/cloudfoundry/cli/cf/api/authentication/authenticationfakes"
 	"github.com/cloudfoundry/cli/cf/api/resources"
 	"github.com/cloudfoundry/cli/cf/api/stacks/stacksfakes"
+	cfappfiles "github.com/cloudfoundry/cli/cf/appfiles"
 	"github.com/cloudfoundry/cli/cf/appfiles/appfilesfakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/commands/application"
@@ -36,6 +41,7 @@ import (
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/flags"
 	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	"gopkg.in/yaml.v2"
 )
 
 var _ = Describe("Push Command", func() {
@@ -47,6 +53,8 @@ var _ = Describe("Push Command", func() {
 		stopper                    *applicationfakes.FakeApplicationStopper
 		serviceBinder              *servicefakes.OldFakeAppBinder
 		appRepo                    *applicationsfakes.FakeApplicationRepository
+		appSummaryRepo             *apifakes.FakeAppSummaryRepository
+		appInstancesRepo           *appinstancesfakes.FakeAppInstancesRepository
 		domainRepo                 *apifakes.FakeDomainRepository
 		routeRepo                  *apifakes.FakeRouteRepository
 		stackRepo                  *stacksfakes.FakeStackRepository
@@ -56,6 +64,8 @@ var _ = Describe("Push Command", func() {
 		authRepo                   *authenticationfakes.FakeAuthenticationRepository
 		actor                      *actorsfakes.FakePushActor
 		appfiles                   *appfilesfakes.FakeAppFiles
+		appFilesInUse              cfappfiles.AppFiles
+		appFilesCache              *cfappfiles.HashCache
 		zipper                     *appfilesfakes.FakeZipper
 		OriginalCommandStart       commandregistry.Command
 		OriginalCommandStop        commandregistry.Command
@@ -68,6 +78,8 @@ var _ = Describe("Push Command", func() {
 		deps.Config = configRepo
 		deps.ManifestRepo = manifestRepo
 		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppInstancesRepository(appInstancesRepo)
 		deps.RepoLocator = deps.RepoLocator.SetDomainRepository(domainRepo)
 		deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
 		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
@@ -76,7 +88,8 @@ var _ = Describe("Push Command", func() {
 		deps.WordGenerator = wordGenerator
 		deps.PushActor = actor
 		deps.AppZipper = zipper
-		deps.AppFiles = appfiles
+		deps.AppFiles = appFilesInUse
+		deps.AppFilesCache = appFilesCache
 
 		//inject fake commands dependencies into registry
 		commandregistry.Register(starter)
@@ -105,6 +118,8 @@ var _ = Describe("Push Command", func() {
 		stopper.MetaDataReturns(commandregistry.CommandMetadata{Name: "stop"})
 
 		appRepo = new(applicationsfakes.FakeApplicationRepository)
+		appSummaryRepo = new(apifakes.FakeAppSummaryRepository)
+		appInstancesRepo = new(appinstancesfakes.FakeAppInstancesRepository)
 
 		domainRepo = new(apifakes.FakeDomainRepository)
 		sharedDomain := maker.NewSharedDomainFields(maker.Overrides{"name": "foo.cf-app.com", "guid": "foo-domain-guid"})
@@ -166,6 +181,8 @@ var _ = Describe("Push Command", func() {
 
 		zipper = new(appfilesfakes.FakeZipper)
 		appfiles = new(appfilesfakes.FakeAppFiles)
+		appFilesInUse = appfiles
+		appFilesCache = nil
 		appfiles.AppFilesInDirReturns([]models.AppFileFields{
 			{
 				Path: "some-path",
@@ -271,9 +288,9 @@ var _ = Describe("Push Command", func() {
 				return a, nil
 			}
 
-			zipper.ZipReturns(nil)
+			zipper.ZipFilesReturns(nil)
 			zipper.GetZipSizeReturns(9001, nil)
-			actor.GatherFilesReturns(nil, true, nil)
+			actor.MatchFilesReturns([]models.AppFileFields{{Path: "app.rb"}}, nil, nil)
 			actor.UploadAppReturns(nil)
 		})
 
@@ -316,8 +333,8 @@ var _ = Describe("Push Command", func() {
 				routeRepo.FindReturns(route, nil)
 			})
 
-			It("notifies users about the error actor.GatherFiles() returns", func() {
-				actor.GatherFilesReturns([]resources.AppFileResource{}, false, errors.New("failed to get file mode"))
+			It("notifies users about the error actor.MatchFiles() returns", func() {
+				actor.MatchFilesReturns(nil, []resources.AppFileResource{}, errors.New("failed to get file mode"))
 
 				callPush("app-name")
 
@@ -589,6 +606,97 @@ var _ = Describe("Push Command", func() {
 				Expect(ui.Outputs).To(ContainElement("App port must be a number"))
 			})
 
+			Context("when routes are specified in the manifest", func() {
+				BeforeEach(func() {
+					domainRepo.FindByNameInOrgStub = func(name string, owningOrgGUID string) (models.DomainFields, error) {
+						domain, found := map[string]models.DomainFields{
+							"example.com":     {Name: "example.com", GUID: "example-domain-guid"},
+							"tcp.example.com": {Name: "tcp.example.com", GUID: "tcp-domain-guid", RouterGroupType: "tcp"},
+						}[name]
+						if !found {
+							return models.DomainFields{}, errors.NewModelNotFoundError("Domain", name)
+						}
+						return domain, nil
+					}
+					routeRepo.CreateInSpaceStub = func(host, path, domainGUID, spaceGUID string, port int, randomPort bool) (models.Route, error) {
+						return models.Route{
+							GUID:   "tcp-route-guid",
+							Domain: models.DomainFields{Name: "tcp.example.com", GUID: domainGUID},
+							Port:   port,
+						}, nil
+					}
+
+					manifestRepo.ReadManifestReturns.Manifest = routesManifest()
+				})
+
+				It("creates and binds each route", func() {
+					callPush()
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Creating", "manifest-host.example.com/api"},
+						[]string{"Binding", "manifest-host.example.com/api"},
+						[]string{"Creating", "example.com"},
+						[]string{"Binding", "example.com"},
+						[]string{"Creating", "tcp.example.com:1234"},
+						[]string{"Binding", "tcp.example.com:1234"},
+					))
+
+					Expect(routeRepo.CreateCallCount()).To(Equal(2))
+					host, domain, path, randomPort := routeRepo.CreateArgsForCall(0)
+					Expect(host).To(Equal("manifest-host"))
+					Expect(domain.GUID).To(Equal("example-domain-guid"))
+					Expect(path).To(Equal("/api"))
+					Expect(randomPort).To(BeFalse())
+
+					host, domain, path, _ = routeRepo.CreateArgsForCall(1)
+					Expect(host).To(BeEmpty())
+					Expect(domain.GUID).To(Equal("example-domain-guid"))
+					Expect(path).To(BeEmpty())
+
+					Expect(routeRepo.CreateInSpaceCallCount()).To(Equal(1))
+					_, _, domainGUID, spaceGUID, port, randomPort := routeRepo.CreateInSpaceArgsForCall(0)
+					Expect(domainGUID).To(Equal("tcp-domain-guid"))
+					Expect(spaceGUID).To(Equal("my-space-guid"))
+					Expect(port).To(Equal(1234))
+					Expect(randomPort).To(BeFalse())
+
+					Expect(routeRepo.BindCallCount()).To(Equal(3))
+				})
+
+				It("does not create the default route", func() {
+					callPush()
+
+					Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"manifest-app-name.foo.cf-app.com"}))
+				})
+
+				It("uses the hostname and domain flags instead of the routes", func() {
+					callPush("-n", "flag-host", "-d", "example.com")
+
+					Expect(routeRepo.CreateCallCount()).To(Equal(1))
+					host, domain, _, _ := routeRepo.CreateArgsForCall(0)
+					Expect(host).To(Equal("flag-host"))
+					Expect(domain.Name).To(Equal("example.com"))
+					Expect(routeRepo.CreateInSpaceCallCount()).To(BeZero())
+				})
+
+				It("fails when a route does not match any domain", func() {
+					manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+						Path: "manifest.yml",
+						Data: generic.NewMap(map[interface{}]interface{}{
+							"name":   "manifest-app-name",
+							"routes": []interface{}{map[interface{}]interface{}{"route": "my-app.unknown.com"}},
+						}),
+					}
+
+					callPush()
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"The route my-app.unknown.com did not match any existing domains."},
+					))
+				})
+			})
+
 			Context("when pushing a docker image with --docker-image or -o", func() {
 				It("sets diego to true", func() {
 					callPush("testApp", "--docker-image", "sample/dockerImage")
@@ -613,6 +721,23 @@ var _ = Describe("Push Command", func() {
 						[]string{"Uploading testApp"},
 					))
 				})
+
+				It("pushes the docker image from the manifest", func() {
+					manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+						Path: "manifest.yml",
+						Data: generic.NewMap(map[interface{}]interface{}{
+							"name":         "testApp",
+							"docker-image": "sample/dockerImage",
+						}),
+					}
+
+					callPush()
+
+					params := appRepo.CreateArgsForCall(0)
+					Expect(*params.DockerImage).To(Equal("sample/dockerImage"))
+					Expect(*params.Diego).To(BeTrue())
+					Expect(actor.ProcessPathCallCount()).To(BeZero())
+				})
 			})
 
 			Context("when health-check-type '-u' or '--health-check-type' is supplied", func() {
@@ -842,7 +967,7 @@ var _ = Describe("Push Command", func() {
 				appfiles.AppFilesInDirReturns(expectedLocalFiles, nil)
 				callPush("-p", "../some/path-to/an-app/file.zip", "app-with-path")
 
-				actualLocalFiles, _, _ := actor.GatherFilesArgsForCall(0)
+				actualLocalFiles, _ := actor.MatchFilesArgsForCall(0)
 				Expect(actualLocalFiles).To(Equal(expectedLocalFiles))
 			})
 
@@ -867,7 +992,7 @@ var _ = Describe("Push Command", func() {
 			It("pushes the contents of the app directory or zip file specified using the -p flag", func() {
 				callPush("-p", "../some/path-to/an-app/file.zip", "app-with-path")
 
-				_, appDir, _ := actor.GatherFilesArgsForCall(0)
+				_, appDir := actor.MatchFilesArgsForCall(0)
 				Expect(appDir).To(Equal("../some/path-to/an-app/file.zip"))
 			})
 
@@ -875,7 +1000,7 @@ var _ = Describe("Push Command", func() {
 				callPush("app-with-default-path")
 				dir, _ := os.Getwd()
 
-				_, appDir, _ := actor.GatherFilesArgsForCall(0)
+				_, appDir := actor.MatchFilesArgsForCall(0)
 				Expect(appDir).To(Equal(dir))
 			})
 
@@ -964,6 +1089,173 @@ var _ = Describe("Push Command", func() {
 				}))
 			})
 
+			It("fails before creating anything when the manifest is invalid", func() {
+				m := singleAppManifest()
+				app := m.Data.Get("applications").([]interface{})[0].(generic.Map)
+				app.Set("instances", "lots")
+				manifestRepo.ReadManifestReturns.Manifest = m
+
+				callPush()
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Error reading manifest file"},
+					[]string{"Expected instances to be a number, but it was a lots."},
+				))
+				Expect(appRepo.CreateCallCount()).To(BeZero())
+				Expect(appRepo.ReadCallCount()).To(BeZero())
+			})
+
+			It("warns about unknown properties and pushes anyway", func() {
+				m := singleAppManifest()
+				app := m.Data.Get("applications").([]interface{})[0].(generic.Map)
+				app.Set("memroy", "256M")
+				manifestRepo.ReadManifestReturns.Manifest = m
+
+				callPush()
+
+				Expect(ui.WarnOutputs).To(ContainSubstrings([]string{"Unknown property 'memroy' will be ignored"}))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"FAILED"}))
+				Expect(appRepo.CreateCallCount()).To(Equal(1))
+			})
+
+			It("pushes the apps of a manifest that merges in settings from YAML anchors", func() {
+				m, err := manifest.NewManifestDiskRepository().ReadManifest("../../../fixtures/manifests/merge-manifest.yml")
+				Expect(err).NotTo(HaveOccurred())
+				manifestRepo.ReadManifestReturns.Manifest = m
+
+				callPush()
+
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"FAILED"}))
+				Expect(ui.WarnOutputs).To(BeEmpty())
+				Expect(appRepo.CreateCallCount()).To(Equal(3))
+
+				bigBlue := appRepo.CreateArgsForCall(2)
+				Expect(*bigBlue.Name).To(Equal("big-blue"))
+				Expect(*bigBlue.Memory).To(Equal(int64(256)))
+				Expect(*bigBlue.InstanceCount).To(Equal(3))
+			})
+
+			Context("when several manifests are given with -f", func() {
+				BeforeEach(func() {
+					domainRepo.FindByNameInOrgReturns(models.DomainFields{
+						Name: "manifest-example.com",
+						GUID: "bar-domain-guid",
+					}, nil)
+
+					manifestRepo.ReadManifestStub = func(path string) (*manifest.Manifest, error) {
+						if path == "prod.yml" {
+							return &manifest.Manifest{
+								Path: "prod.yml",
+								Data: generic.NewMap(map[interface{}]interface{}{
+									"applications": []interface{}{
+										map[interface{}]interface{}{
+											"name":      "manifest-app-name",
+											"instances": 3,
+											"host":      "manifest-host-prod",
+											"env":       map[interface{}]interface{}{"FOO": "prod"},
+										},
+									},
+								}),
+							}, nil
+						}
+						return singleAppManifest(), nil
+					}
+				})
+
+				It("merges each manifest onto the ones before it", func() {
+					callPush("-f", "manifest.yml", "-f", "prod.yml")
+
+					Expect(manifestRepo.ReadManifestArgs.Paths).To(Equal([]string{"manifest.yml", "prod.yml"}))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Merging manifest file", "prod.yml"},
+						[]string{"Using manifest file", "manifest.yml"},
+						[]string{"Creating route", "manifest-host-prod.manifest-example.com"},
+					))
+
+					params := appRepo.CreateArgsForCall(0)
+					Expect(*params.Name).To(Equal("manifest-app-name"))
+					Expect(*params.InstanceCount).To(Equal(3))
+					Expect(*params.Memory).To(Equal(int64(128)))
+					Expect(*params.EnvironmentVars).To(Equal(map[string]interface{}{
+						"PATH": "/u/apps/my-app/bin",
+						"FOO":  "prod",
+					}))
+				})
+
+				It("fails when one of the manifests cannot be read", func() {
+					manifestRepo.ReadManifestStub = func(path string) (*manifest.Manifest, error) {
+						if path == "missing.yml" {
+							return manifest.NewEmptyManifest(), errors.New("no such file")
+						}
+						return singleAppManifest(), nil
+					}
+
+					callPush("-f", "manifest.yml", "-f", "missing.yml")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"Error reading manifest file"},
+						[]string{"no such file"},
+					))
+					Expect(appRepo.CreateCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the manifest contains ((var)) variables", func() {
+				BeforeEach(func() {
+					domainRepo.FindByNameInOrgReturns(models.DomainFields{
+						Name: "manifest-example.com",
+						GUID: "bar-domain-guid",
+					}, nil)
+
+					m := singleAppManifest()
+					app := m.Data.Get("applications").([]interface{})[0].(generic.Map)
+					app.Set("instances", "((instances))")
+					app.Set("host", "manifest-host-((env))")
+					manifestRepo.ReadManifestReturns.Manifest = m
+				})
+
+				It("substitutes variables given with --var and --vars-file", func() {
+					varsFile, err := ioutil.TempFile("", "vars-file")
+					Expect(err).NotTo(HaveOccurred())
+					defer os.Remove(varsFile.Name())
+
+					_, err = varsFile.WriteString("instances: 3\nenv: staging\n")
+					Expect(err).NotTo(HaveOccurred())
+					varsFile.Close()
+
+					callPush("--vars-file", varsFile.Name(), "--var", "env=prod")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Creating route", "manifest-host-prod.manifest-example.com"},
+					))
+
+					params := appRepo.CreateArgsForCall(0)
+					Expect(*params.InstanceCount).To(Equal(3))
+				})
+
+				It("fails when a variable has no value", func() {
+					callPush("--var", "instances=3")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"Error reading manifest file"},
+						[]string{"((env))"},
+					))
+					Expect(appRepo.CreateCallCount()).To(BeZero())
+				})
+
+				It("fails when a variable is not NAME=VALUE", func() {
+					callPush("--var", "instances")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"Invalid variable 'instances'"},
+					))
+				})
+			})
+
 			It("pushes an app with multiple routes when multiple hosts are provided", func() {
 				domainRepo.FindByNameInOrgReturns(models.DomainFields{
 					Name: "manifest-example.com",
@@ -1375,6 +1667,648 @@ var _ = Describe("Push Command", func() {
 		})
 	})
 
+	Describe("re-pushing an existing app with --strategy blue-green", func() {
+		var (
+			liveApp    models.Application
+			pendingApp models.Application
+		)
+
+		BeforeEach(func() {
+			liveApp = models.Application{}
+			liveApp.Name = "existing-app"
+			liveApp.GUID = "existing-app-guid"
+			liveApp.State = "started"
+			liveApp.InstanceCount = 2
+			liveApp.EnvironmentVars = map[string]interface{}{"crazy": "pants"}
+			liveApp.Routes = []models.RouteSummary{
+				{GUID: "existing-route-guid", Host: "existing-app", Domain: models.DomainFields{Name: "example.com"}},
+			}
+
+			pendingApp = models.Application{}
+			pendingApp.Name = "existing-app-pending"
+			pendingApp.GUID = "pending-app-guid"
+			pendingApp.State = "stopped"
+			pendingApp.InstanceCount = 2
+
+			appRepo.ReadStub = func(name string) (models.Application, error) {
+				if name == liveApp.Name {
+					return liveApp, nil
+				}
+				return models.Application{}, errors.NewModelNotFoundError("App", name)
+			}
+			appRepo.CreateReturns(pendingApp, nil)
+
+			summary := liveApp
+			summary.Services = []models.ServicePlanSummary{{GUID: "service-guid", Name: "existing-service"}}
+			appSummaryRepo.GetSummaryReturns(summary, nil)
+
+			serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+				return models.ServiceInstance{ServiceInstanceFields: models.ServiceInstanceFields{Name: name}}, nil
+			}
+
+			appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{
+				{State: models.InstanceRunning},
+				{State: models.InstanceRunning},
+			}, nil)
+		})
+
+		It("creates a pending app with the live app's settings instead of updating it", func() {
+			callPush("--strategy", "blue-green", "-m", "512M", "existing-app")
+
+			Expect(appRepo.CreateCallCount()).To(Equal(1))
+			params := appRepo.CreateArgsForCall(0)
+			Expect(*params.Name).To(Equal("existing-app-pending"))
+			Expect(*params.Memory).To(Equal(int64(512)))
+			Expect(*params.InstanceCount).To(Equal(2))
+			Expect(*params.EnvironmentVars).To(Equal(map[string]interface{}{"crazy": "pants"}))
+			Expect(params.GUID).To(BeNil())
+			Expect(params.State).To(BeNil())
+
+			appGUID, _, _ := actor.UploadAppArgsForCall(0)
+			Expect(appGUID).To(Equal("pending-app-guid"))
+		})
+
+		It("binds the live app's services to the pending app", func() {
+			callPush("--strategy", "blue-green", "existing-app")
+
+			Expect(serviceBinder.AppsToBind).To(HaveLen(1))
+			Expect(serviceBinder.AppsToBind[0].GUID).To(Equal("pending-app-guid"))
+			Expect(serviceBinder.InstancesToBindTo[0].Name).To(Equal("existing-service"))
+		})
+
+		It("starts the pending app and waits for all of its instances to run", func() {
+			callPush("--strategy", "blue-green", "existing-app")
+
+			Expect(starter.ApplicationStartCallCount()).To(Equal(1))
+			app, _, _ := starter.ApplicationStartArgsForCall(0)
+			Expect(app.GUID).To(Equal("pending-app-guid"))
+			Expect(appInstancesRepo.GetInstancesArgsForCall(0)).To(Equal("pending-app-guid"))
+			Expect(stopper.ApplicationStopCallCount()).To(BeZero())
+		})
+
+		It("does not wait for the instances of an app scaled to zero", func() {
+			pendingApp.InstanceCount = 0
+			appRepo.CreateReturns(pendingApp, nil)
+			appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{}, nil)
+
+			callPush("--strategy", "blue-green", "existing-app")
+
+			Expect(appInstancesRepo.GetInstancesCallCount()).To(BeZero())
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"FAILED"}))
+			Expect(appRepo.DeleteArgsForCall(0)).To(Equal("existing-app-guid"))
+		})
+
+		It("moves the routes, renames both apps and deletes the old one", func() {
+			callPush("--strategy", "blue-green", "existing-app")
+
+			Expect(routeRepo.BindCallCount()).To(Equal(1))
+			routeGUID, appGUID := routeRepo.BindArgsForCall(0)
+			Expect(routeGUID).To(Equal("existing-route-guid"))
+			Expect(appGUID).To(Equal("pending-app-guid"))
+
+			Expect(routeRepo.UnbindCallCount()).To(Equal(1))
+			routeGUID, appGUID = routeRepo.UnbindArgsForCall(0)
+			Expect(routeGUID).To(Equal("existing-route-guid"))
+			Expect(appGUID).To(Equal("existing-app-guid"))
+
+			Expect(appRepo.UpdateCallCount()).To(Equal(2))
+			appGUID, params := appRepo.UpdateArgsForCall(0)
+			Expect(appGUID).To(Equal("existing-app-guid"))
+			Expect(*params.Name).To(Equal("existing-app-venerable"))
+			appGUID, params = appRepo.UpdateArgsForCall(1)
+			Expect(appGUID).To(Equal("pending-app-guid"))
+			Expect(*params.Name).To(Equal("existing-app"))
+
+			Expect(appRepo.DeleteCallCount()).To(Equal(1))
+			Expect(appRepo.DeleteArgsForCall(0)).To(Equal("existing-app-guid"))
+		})
+
+		It("pushes new apps normally", func() {
+			callPush("--strategy", "blue-green", "new-app")
+
+			params := appRepo.CreateArgsForCall(0)
+			Expect(*params.Name).To(Equal("new-app"))
+			Expect(appRepo.DeleteCallCount()).To(BeZero())
+		})
+
+		It("fails before creating anything when an app is left over from an earlier push", func() {
+			appRepo.ReadStub = func(name string) (models.Application, error) {
+				if name == "existing-app-pending" {
+					return models.Application{}, errors.NewModelNotFoundError("App", name)
+				}
+				return liveApp, nil
+			}
+
+			callPush("--strategy", "blue-green", "existing-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"App existing-app-venerable already exists"},
+			))
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+		})
+
+		Context("when the live app cannot be renamed", func() {
+			BeforeEach(func() {
+				appRepo.UpdateReturns(models.Application{}, errors.New("rename failed"))
+			})
+
+			It("moves the routes back and deletes the pending app", func() {
+				callPush("--strategy", "blue-green", "existing-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"rename failed"},
+					[]string{"Rolling back", "existing-app"},
+				))
+
+				Expect(appRepo.UpdateCallCount()).To(Equal(1))
+
+				Expect(routeRepo.BindCallCount()).To(Equal(2))
+				routeGUID, appGUID := routeRepo.BindArgsForCall(1)
+				Expect(routeGUID).To(Equal("existing-route-guid"))
+				Expect(appGUID).To(Equal("existing-app-guid"))
+
+				Expect(appRepo.DeleteCallCount()).To(Equal(1))
+				Expect(appRepo.DeleteArgsForCall(0)).To(Equal("pending-app-guid"))
+			})
+		})
+
+		Context("when the pending app cannot be renamed", func() {
+			BeforeEach(func() {
+				appRepo.UpdateStub = func(guid string, params models.AppParams) (models.Application, error) {
+					if guid == "pending-app-guid" && *params.Name == "existing-app" {
+						return models.Application{}, errors.New("rename failed")
+					}
+					return models.Application{}, nil
+				}
+			})
+
+			It("gives the live app its name back", func() {
+				callPush("--strategy", "blue-green", "existing-app")
+
+				Expect(appRepo.UpdateCallCount()).To(Equal(3))
+				appGUID, params := appRepo.UpdateArgsForCall(2)
+				Expect(appGUID).To(Equal("existing-app-guid"))
+				Expect(*params.Name).To(Equal("existing-app"))
+
+				routeGUID, appGUID := routeRepo.BindArgsForCall(1)
+				Expect(routeGUID).To(Equal("existing-route-guid"))
+				Expect(appGUID).To(Equal("existing-app-guid"))
+
+				Expect(appRepo.DeleteCallCount()).To(Equal(1))
+				Expect(appRepo.DeleteArgsForCall(0)).To(Equal("pending-app-guid"))
+			})
+		})
+
+		Context("when an instance of the pending app crashes", func() {
+			BeforeEach(func() {
+				appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{
+					{State: models.InstanceRunning},
+					{State: models.InstanceCrashed},
+				}, nil)
+			})
+
+			It("deletes the pending app and leaves the live app alone", func() {
+				callPush("--strategy", "blue-green", "existing-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Start unsuccessful"},
+					[]string{"Rolling back", "existing-app-pending"},
+				))
+
+				Expect(appRepo.DeleteCallCount()).To(Equal(1))
+				Expect(appRepo.DeleteArgsForCall(0)).To(Equal("pending-app-guid"))
+				Expect(routeRepo.BindCallCount()).To(BeZero())
+				Expect(routeRepo.UnbindCallCount()).To(BeZero())
+				Expect(appRepo.UpdateCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the pending app fails to stage", func() {
+			BeforeEach(func() {
+				starter.ApplicationStartStub = func(models.Application, string, string) (models.Application, error) {
+					ui.Failed("staging failed")
+					return models.Application{}, nil
+				}
+			})
+
+			It("deletes the pending app", func() {
+				callPush("--strategy", "blue-green", "existing-app")
+
+				Expect(appRepo.DeleteCallCount()).To(Equal(1))
+				Expect(appRepo.DeleteArgsForCall(0)).To(Equal("pending-app-guid"))
+				Expect(routeRepo.BindCallCount()).To(BeZero())
+			})
+		})
+
+		It("fails with an unknown strategy", func() {
+			callPush("--strategy", "canary", "existing-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Invalid deployment strategy", "canary"},
+			))
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+		})
+
+		It("fails when --no-start is given", func() {
+			callPush("--strategy", "blue-green", "--no-start", "existing-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Cannot use --no-start"},
+			))
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+		})
+	})
+
+	Describe("--dry-run", func() {
+		BeforeEach(func() {
+			appfiles.AppFilesInDirReturns([]models.AppFileFields{
+				{Path: "app.rb"},
+				{Path: "Gemfile"},
+				{Path: "Gemfile.lock"},
+			}, nil)
+			actor.MatchFilesReturns([]models.AppFileFields{{Path: "app.rb"}, {Path: "Gemfile"}}, []resources.AppFileResource{{Path: "Gemfile.lock"}}, nil)
+		})
+
+		itDoesNotChangeAnything := func() {
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+			Expect(appRepo.UpdateCallCount()).To(BeZero())
+			Expect(routeRepo.CreateCallCount()).To(BeZero())
+			Expect(routeRepo.BindCallCount()).To(BeZero())
+			Expect(serviceBinder.AppsToBind).To(BeEmpty())
+			Expect(actor.UploadAppCallCount()).To(BeZero())
+			Expect(zipper.ZipFilesCallCount()).To(BeZero())
+			Expect(stopper.ApplicationStopCallCount()).To(BeZero())
+			Expect(starter.ApplicationStartCallCount()).To(BeZero())
+		}
+
+		Context("when the app does not exist", func() {
+			BeforeEach(func() {
+				appRepo.ReadReturns(models.Application{}, errors.NewModelNotFoundError("App", "app-name"))
+				routeRepo.FindReturns(models.Route{}, errors.NewModelNotFoundError("Route", "app-name"))
+			})
+
+			It("shows the app, route and files that would be created", func() {
+				callPush("app-name", "-m", "512M", "-i", "2", "-p", "/some/path", "--dry-run")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Dry run of push", "Nothing will be changed"},
+					[]string{"+ create app app-name"},
+					[]string{"+ memory: 512M"},
+					[]string{"+ instances: 2"},
+					[]string{"+ create route app-name.foo.cf-app.com and map it"},
+					[]string{"+ upload 2 of 3 files from /some/path"},
+					[]string{"OK"},
+				))
+				itDoesNotChangeAnything()
+			})
+		})
+
+		Context("when the app already exists", func() {
+			BeforeEach(func() {
+				existingApp := models.Application{}
+				existingApp.Name = "app-name"
+				existingApp.GUID = "app-guid"
+				existingApp.Memory = 256
+				existingApp.InstanceCount = 2
+				existingApp.EnvironmentVars = map[string]interface{}{"FOO": "old", "KEEP": "same"}
+				appRepo.ReadReturns(existingApp, nil)
+
+				route := models.Route{GUID: "route-guid", Host: "app-name", Domain: models.DomainFields{Name: "foo.cf-app.com"}}
+				summary := existingApp
+				summary.Routes = []models.RouteSummary{{GUID: "route-guid", Host: "app-name", Domain: models.DomainFields{Name: "foo.cf-app.com"}}}
+				summary.Services = []models.ServicePlanSummary{{Name: "db"}}
+				appSummaryRepo.GetSummaryReturns(summary, nil)
+				routeRepo.FindReturns(route, nil)
+
+				manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+					Path: "manifest.yml",
+					Data: generic.NewMap(map[interface{}]interface{}{
+						"applications": []interface{}{
+							map[interface{}]interface{}{
+								"name":      "app-name",
+								"memory":    "512M",
+								"instances": 2,
+								"path":      "/some/path",
+								"services":  []interface{}{"db", "cache"},
+								"env": map[interface{}]interface{}{
+									"FOO":  "new",
+									"KEEP": "same",
+									"BAR":  "added",
+								},
+							},
+						},
+					}),
+				}
+			})
+
+			It("shows what would change as a diff", func() {
+				callPush("--dry-run")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"~ update app app-name"},
+					[]string{"~ memory: 256M -> 512M"},
+					[]string{"+ env BAR"},
+					[]string{"~ env FOO"},
+					[]string{"= route app-name.foo.cf-app.com is already mapped"},
+					[]string{"= service db is already bound"},
+					[]string{"+ bind service cache"},
+					[]string{"+ upload 2 of 3 files"},
+				))
+				Expect(strings.Join(ui.Outputs, "\n")).NotTo(ContainSubstring("instances"))
+				Expect(strings.Join(ui.Outputs, "\n")).NotTo(ContainSubstring("env KEEP"))
+				Expect(strings.Join(ui.Outputs, "\n")).NotTo(ContainSubstring("old"))
+				itDoesNotChangeAnything()
+			})
+
+			It("shows routes that would be unmapped with --no-route", func() {
+				callPush("--dry-run", "--no-route")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"- unmap route app-name.foo.cf-app.com"},
+				))
+				Expect(routeRepo.UnbindCallCount()).To(BeZero())
+				itDoesNotChangeAnything()
+			})
+
+			It("fails when a service to bind does not exist", func() {
+				serviceRepo.FindInstanceByNameReturns(models.ServiceInstance{}, errors.New("not found"))
+
+				callPush("--dry-run")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Could not find service db to bind to app-name"},
+				))
+				itDoesNotChangeAnything()
+			})
+		})
+
+		Context("when the manifest was generated from the app", func() {
+			BeforeEach(func() {
+				existingApp := models.Application{}
+				existingApp.Name = "app-name"
+				existingApp.GUID = "app-guid"
+				existingApp.Memory = 256
+				existingApp.DiskQuota = 512
+				existingApp.InstanceCount = 2
+				existingApp.StackGUID = "stack-guid"
+				existingApp.BuildpackURL = "ruby_buildpack"
+				existingApp.Command = "bundle exec rackup"
+				existingApp.HealthCheckType = "port"
+				existingApp.HealthCheckTimeout = 120
+				existingApp.EnvironmentVars = map[string]interface{}{"FOO": "bar"}
+				existingApp.Routes = []models.RouteSummary{
+					{GUID: "web-route-guid", Host: "app-name", Domain: models.DomainFields{Name: "foo.cf-app.com"}},
+					{GUID: "api-route-guid", Host: "api", Domain: models.DomainFields{Name: "foo.cf-app.com"}, Path: "/v1"},
+					{GUID: "tcp-route-guid", Domain: models.DomainFields{Name: "tcp.cf-app.com"}, Port: 1234},
+				}
+				existingApp.Services = []models.ServicePlanSummary{{Name: "db"}}
+				existingApp.Stack = &models.Stack{GUID: "stack-guid", Name: "cflinuxfs2"}
+				appRepo.ReadReturns(existingApp, nil)
+				appSummaryRepo.GetSummaryReturns(existingApp, nil)
+				stackRepo.FindByNameReturns(*existingApp.Stack, nil)
+
+				domainRepo.FindByNameInOrgStub = func(name string, owningOrgGUID string) (models.DomainFields, error) {
+					switch name {
+					case "foo.cf-app.com", "tcp.cf-app.com":
+						return models.DomainFields{Name: name}, nil
+					}
+					return models.DomainFields{}, errors.NewModelNotFoundError("Domain", name)
+				}
+				routeRepo.FindStub = func(host string, domain models.DomainFields, path string, port int) (models.Route, error) {
+					for _, route := range existingApp.Routes {
+						if route.Host == host && route.Domain.Name == domain.Name && route.Path == path && route.Port == port {
+							return models.Route{GUID: route.GUID}, nil
+						}
+					}
+					return models.Route{}, errors.NewModelNotFoundError("Route", host)
+				}
+
+				generator := manifest.NewGenerator()
+				generator.Memory(existingApp.Name, existingApp.Memory)
+				generator.DiskQuota(existingApp.Name, existingApp.DiskQuota)
+				generator.Instances(existingApp.Name, existingApp.InstanceCount)
+				generator.Stack(existingApp.Name, existingApp.Stack.Name)
+				generator.BuildpackURL(existingApp.Name, existingApp.BuildpackURL)
+				generator.StartCommand(existingApp.Name, existingApp.Command)
+				generator.HealthCheckType(existingApp.Name, existingApp.HealthCheckType)
+				generator.HealthCheckTimeout(existingApp.Name, existingApp.HealthCheckTimeout)
+				generator.EnvironmentVars(existingApp.Name, "FOO", "bar")
+				generator.Service(existingApp.Name, "db")
+				generator.Path(existingApp.Name, "/some/path")
+				for _, route := range existingApp.Routes {
+					generator.Route(existingApp.Name, route.Host, route.Domain.Name, route.Path, route.Port)
+				}
+
+				contents := &bytes.Buffer{}
+				Expect(generator.Save(contents)).To(Succeed())
+
+				data := map[interface{}]interface{}{}
+				Expect(yaml.Unmarshal(contents.Bytes(), &data)).To(Succeed())
+				manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{Path: "manifest.yml", Data: generic.NewMap(data)}
+			})
+
+			It("plans no changes other than uploading the files", func() {
+				callPush("--dry-run")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"~ update app app-name"},
+					[]string{"no attribute changes"},
+					[]string{"= route app-name.foo.cf-app.com is already mapped"},
+					[]string{"= route api.foo.cf-app.com/v1 is already mapped"},
+					[]string{"= route tcp.cf-app.com:1234 is already mapped"},
+					[]string{"= service db is already bound"},
+					[]string{"+ upload 2 of 3 files from /some/path"},
+				))
+				Expect(strings.Join(ui.Outputs, "\n")).NotTo(ContainSubstring("create route"))
+				Expect(strings.Join(ui.Outputs, "\n")).NotTo(ContainSubstring("map route"))
+				itDoesNotChangeAnything()
+			})
+		})
+	})
+
+	Describe("pushing apps that depend on each other", func() {
+		BeforeEach(func() {
+			appRepo.ReadReturns(models.Application{}, errors.NewModelNotFoundError("App", "the-app"))
+			appRepo.CreateStub = func(params models.AppParams) (models.Application, error) {
+				app := models.Application{}
+				app.Name = *params.Name
+				app.GUID = *params.Name + "-guid"
+				return app, nil
+			}
+
+			manifestRepo.ReadManifestReturns.Manifest = dependentAppsManifest()
+		})
+
+		createdAppNames := func() []string {
+			names := []string{}
+			for i := 0; i < appRepo.CreateCallCount(); i++ {
+				names = append(names, *appRepo.CreateArgsForCall(i).Name)
+			}
+			return names
+		}
+
+		It("pushes apps after the apps they depend on", func() {
+			callPush()
+
+			Expect(createdAppNames()).To(Equal([]string{"app-a", "app-c", "app-b"}))
+		})
+
+		It("fails when the apps depend on each other in a cycle", func() {
+			manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+				Path: "manifest.yml",
+				Data: generic.NewMap(map[interface{}]interface{}{
+					"applications": []interface{}{
+						generic.NewMap(map[interface{}]interface{}{"name": "app-a", "depends-on": []interface{}{"app-b"}}),
+						generic.NewMap(map[interface{}]interface{}{"name": "app-b", "depends-on": []interface{}{"app-a"}}),
+					},
+				}),
+			}
+
+			callPush()
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"depend on each other in a cycle", "app-a, app-b"},
+			))
+			Expect(appRepo.CreateCallCount()).To(BeZero())
+		})
+
+		It("ignores dependencies on apps that are not being pushed", func() {
+			callPush("app-b")
+
+			Expect(createdAppNames()).To(Equal([]string{"app-b"}))
+		})
+
+		Context("with --parallel", func() {
+			It("pushes every app, prefixing the output with the app's name", func() {
+				callPush("--parallel", "2")
+
+				Expect(createdAppNames()).To(ConsistOf("app-a", "app-b", "app-c"))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"[app-a]", "Creating app", "app-a"},
+					[]string{"[app-b]", "Creating app", "app-b"},
+					[]string{"[app-c]", "Creating app", "app-c"},
+				))
+			})
+
+			It("waits for the apps an app depends on", func() {
+				callPush("--parallel", "3")
+
+				names := createdAppNames()
+				Expect(names).To(HaveLen(3))
+
+				indexOf := func(name string) int {
+					for i, n := range names {
+						if n == name {
+							return i
+						}
+					}
+					return -1
+				}
+				Expect(indexOf("app-a")).To(BeNumerically("<", indexOf("app-b")))
+				Expect(indexOf("app-c")).To(BeNumerically("<", indexOf("app-b")))
+			})
+
+			It("shows a summary of the pushed apps", func() {
+				callPush("--parallel", "2")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"name", "status", "details"},
+					[]string{"app-a", "pushed"},
+					[]string{"app-b", "pushed"},
+					[]string{"app-c", "pushed"},
+				))
+				Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"FAILED"}))
+			})
+
+			Context("when an app fails to push", func() {
+				BeforeEach(func() {
+					actor.ProcessPathStub = func(dirOrZipFile string, f func(string)) error {
+						if strings.HasSuffix(dirOrZipFile, "app-a") {
+							return errors.New("upload went wrong")
+						}
+						f(dirOrZipFile)
+						return nil
+					}
+				})
+
+				It("carries on with the other apps and skips the ones depending on it", func() {
+					callPush("--parallel", "2")
+
+					Expect(createdAppNames()).To(ConsistOf("app-a", "app-c"))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"[app-a]", "FAILED"},
+						[]string{"[app-a]", "upload went wrong"},
+					))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"app-a", "failed", "upload went wrong"},
+						[]string{"app-b", "skipped", "app-a was not pushed"},
+						[]string{"app-c", "pushed"},
+						[]string{"FAILED"},
+						[]string{"2 of 3 apps were not pushed"},
+					))
+				})
+			})
+
+			Context("when the push of an app panics", func() {
+				BeforeEach(func() {
+					actor.ProcessPathStub = func(dirOrZipFile string, f func(string)) error {
+						if strings.HasSuffix(dirOrZipFile, "app-a") {
+							panic("something unexpected")
+						}
+						f(dirOrZipFile)
+						return nil
+					}
+				})
+
+				It("reports the app as failed instead of crashing", func() {
+					callPush("--parallel", "2")
+
+					Expect(createdAppNames()).To(ConsistOf("app-a", "app-c"))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"app-a", "failed", "Unexpected error: something unexpected"},
+						[]string{"app-b", "skipped", "app-a was not pushed"},
+						[]string{"app-c", "pushed"},
+						[]string{"2 of 3 apps were not pushed"},
+					))
+				})
+			})
+
+			It("leaves out apps in the manifest that have no name", func() {
+				manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+					Path: "manifest.yml",
+					Data: generic.NewMap(map[interface{}]interface{}{
+						"applications": []interface{}{
+							generic.NewMap(map[interface{}]interface{}{"name": "app-a"}),
+							generic.NewMap(map[interface{}]interface{}{"memory": "128M"}),
+						},
+					}),
+				}
+
+				Expect(func() { callPush("--parallel", "2") }).ToNot(Panic())
+
+				Expect(appRepo.CreateCallCount()).To(Equal(1))
+				Expect(*appRepo.CreateArgsForCall(0).Name).To(Equal("app-a"))
+			})
+
+			It("fails when the number of parallel pushes is not positive", func() {
+				callPush("--parallel", "0")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Invalid number of parallel pushes", "0"},
+				))
+				Expect(appRepo.CreateCallCount()).To(BeZero())
+			})
+		})
+	})
+
 	Describe("service instances", func() {
 		BeforeEach(func() {
 			serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
@@ -1471,10 +2405,10 @@ var _ = Describe("Push Command", func() {
 
 	Describe("displaying information about files being uploaded", func() {
 		It("displays information about the files being uploaded", func() {
-			appfiles.CountFilesReturns(11)
-			zipper.ZipReturns(nil)
+			filesToUpload := make([]models.AppFileFields, 11)
+			zipper.ZipFilesReturns(nil)
 			zipper.GetZipSizeReturns(6100000, nil)
-			actor.GatherFilesReturns([]resources.AppFileResource{resources.AppFileResource{Path: "path/to/app"}, resources.AppFileResource{Path: "bar"}}, true, nil)
+			actor.MatchFilesReturns(filesToUpload, []resources.AppFileResource{resources.AppFileResource{Path: "path/to/app"}, resources.AppFileResource{Path: "bar"}}, nil)
 
 			curDir, err := os.Getwd()
 			Expect(err).NotTo(HaveOccurred())
@@ -1484,6 +2418,81 @@ var _ = Describe("Push Command", func() {
 				[]string{"Uploading", curDir},
 				[]string{"5.8M", "11 files"},
 			))
+
+			Expect(zipper.ZipFilesCallCount()).To(Equal(1))
+			appDir, files, _ := zipper.ZipFilesArgsForCall(0)
+			Expect(appDir).To(Equal(curDir))
+			Expect(files).To(Equal(filesToUpload))
+		})
+
+		It("does not zip anything when the Cloud Controller already has every file", func() {
+			actor.MatchFilesReturns([]models.AppFileFields{}, []resources.AppFileResource{{Path: "app.rb"}}, nil)
+
+			callPush("appName")
+
+			Expect(zipper.ZipFilesCallCount()).To(BeZero())
+			Expect(actor.UploadAppCallCount()).To(Equal(1))
+			_, _, presentFiles := actor.UploadAppArgsForCall(0)
+			Expect(presentFiles).To(Equal([]resources.AppFileResource{{Path: "app.rb"}}))
+		})
+	})
+
+	Describe("--no-cache", func() {
+		var (
+			cacheDir string
+			appFile  string
+			fileInfo os.FileInfo
+		)
+
+		BeforeEach(func() {
+			var err error
+			cacheDir, err = ioutil.TempDir("", "push-hash-cache")
+			Expect(err).NotTo(HaveOccurred())
+
+			appFile = filepath.Join(cacheDir, "app.rb")
+			err = ioutil.WriteFile(appFile, []byte("puts 'hello'"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+			fileInfo, err = os.Stat(appFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			appFilesCache = cfappfiles.NewHashCache(filepath.Join(cacheDir, "cache.json"))
+			appFilesCache.Put(appFile, fileInfo, "some-sha")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(cacheDir)
+		})
+
+		It("reuses the hashes from earlier pushes by default", func() {
+			callPush("app-name")
+
+			_, found := appFilesCache.Get(appFile, fileInfo)
+			Expect(found).To(BeTrue())
+		})
+
+		It("does not reuse the hashes from earlier pushes", func() {
+			callPush("--no-cache", "app-name")
+
+			_, found := appFilesCache.Get(appFile, fileInfo)
+			Expect(found).To(BeFalse())
+		})
+
+		It("keeps the hashes from earlier pushes for later pushes", func() {
+			callPush("--no-cache", "app-name")
+			Expect(appFilesCache.Save()).To(Succeed())
+
+			_, found := cfappfiles.NewHashCache(filepath.Join(cacheDir, "cache.json")).Get(appFile, fileInfo)
+			Expect(found).To(BeTrue())
+		})
+
+		It("does not change the cache on a dry run", func() {
+			appFilesInUse = cfappfiles.ApplicationFiles{Cache: appFilesCache}
+
+			callPush("--no-cache", "--dry-run", "-p", cacheDir, "app-name")
+			Expect(actor.MatchFilesCallCount()).To(Equal(1))
+
+			_, err := os.Stat(filepath.Join(cacheDir, "cache.json"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
 		})
 	})
 
@@ -1618,6 +2627,50 @@ func multipleDomainsManifest() *manifest.Manifest {
 	}
 }
 
+func routesManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		Path: "manifest.yml",
+		Data: generic.NewMap(map[interface{}]interface{}{
+			"applications": []interface{}{
+				generic.NewMap(map[interface{}]interface{}{
+					"name": "manifest-app-name",
+					"routes": []interface{}{
+						map[interface{}]interface{}{"route": "manifest-host.example.com/api"},
+						map[interface{}]interface{}{"route": "example.com"},
+						map[interface{}]interface{}{"route": "tcp.example.com:1234"},
+					},
+				}),
+			},
+		}),
+	}
+}
+
+func dependentAppsManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		Path: "manifest.yml",
+		Data: generic.NewMap(map[interface{}]interface{}{
+			"applications": []interface{}{
+				generic.NewMap(map[interface{}]interface{}{
+					"name": "app-b",
+					"path": "app-b",
+					"depends-on": []interface{}{
+						"app-a",
+						"app-c",
+					},
+				}),
+				generic.NewMap(map[interface{}]interface{}{
+					"name": "app-a",
+					"path": "app-a",
+				}),
+				generic.NewMap(map[interface{}]interface{}{
+					"name": "app-c",
+					"path": "app-c",
+				}),
+			},
+		}),
+	}
+}
+
 func singleAppManifest() *manifest.Manifest {
 	return &manifest.Manifest{
 		Path: "manifest.yml",
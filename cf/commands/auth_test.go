@@ -60,6 +60,14 @@ var _ = Describe("auth command", func() {
 			))
 		})
 
+		It("asks for the client id and secret when given too few arguments with --client-credentials", func() {
+			testcmd.RunCLICommand("auth", []string{"my-client", "--client-credentials"}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires 'client_id client_secret' as arguments"},
+			))
+		})
+
 		It("fails if the user has not set an api endpoint", func() {
 			Expect(testcmd.RunCLICommand("auth", []string{"username", "password"}, requirementsFactory, updateCommandDependency, false)).To(BeFalse())
 		})
@@ -106,6 +114,31 @@ var _ = Describe("auth command", func() {
 			Expect(authRepo.GetLoginPromptsAndSaveUAAServerURLCallCount()).To(Equal(1))
 		})
 
+		It("authenticates a client with its client credentials", func() {
+			testcmd.RunCLICommand("auth", []string{"my-client", "my-secret", "--client-credentials"}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Authenticating..."},
+				[]string{"OK"},
+			))
+
+			Expect(authRepo.AuthenticateCallCount()).To(Equal(0))
+			Expect(authRepo.AuthenticateWithClientCredentialsCallCount()).To(Equal(1))
+			clientID, clientSecret := authRepo.AuthenticateWithClientCredentialsArgsForCall(0)
+			Expect(clientID).To(Equal("my-client"))
+			Expect(clientSecret).To(Equal("my-secret"))
+		})
+
+		It("fails when the client credentials are rejected", func() {
+			authRepo.AuthenticateWithClientCredentialsReturns(errors.New("Credentials were rejected, please try again."))
+			testcmd.RunCLICommand("auth", []string{"my-client", "my-secret", "--client-credentials"}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Credentials were rejected"},
+			))
+		})
+
 		Describe("when authentication fails", func() {
 			BeforeEach(func() {
 				authRepo.AuthenticateReturns(errors.New("Error authenticating."))
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/completion"
+	"github.com/cloudfoundry/cli/cf/configuration/confighelpers"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+// Complete is the hook the scripts written by 'cf completion' call to
+// suggest the words that can follow a partial command line.
+type Complete struct {
+	ui           terminal.UI
+	pluginConfig pluginconfig.PluginConfiguration
+	source       completion.ValueSource
+}
+
+func init() {
+	commandregistry.Register(&Complete{})
+}
+
+func (cmd *Complete) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:            "__complete",
+		Description:     T("Suggest the words that can follow a partial command line"),
+		Usage:           []string{"CF_NAME __complete [WORD...]"},
+		SkipFlagParsing: true,
+		Hidden:          true,
+	}
+}
+
+func (cmd *Complete) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	return []requirements.Requirement{}
+}
+
+func (cmd *Complete) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.pluginConfig = deps.PluginConfig
+
+	if deps.WildcardDependency != nil {
+		cmd.source = deps.WildcardDependency.(completion.ValueSource)
+	} else {
+		cmd.source = completion.NewCachedValueSource(
+			completion.NewRepositoryValueSource(deps.Config, deps.RepoLocator),
+			completionCachePath(),
+			completion.TargetKey(deps.Config),
+			completion.CacheTTL,
+			time.Now,
+		)
+	}
+
+	return cmd
+}
+
+func (cmd *Complete) Execute(c flags.FlagContext) {
+	commands := completion.NewCommands(commandregistry.Commands.Metadatas(), cmd.pluginConfig.Plugins())
+	suggestions := completion.NewCompleter(commands, cmd.source).Complete(c.Args())
+
+	if len(suggestions) > 0 {
+		cmd.ui.Say(strings.Join(suggestions, "\n"))
+	}
+}
+
+func completionCachePath() string {
+	return filepath.Join(filepath.Dir(confighelpers.DefaultFilePath()), "completion_cache.json")
+}
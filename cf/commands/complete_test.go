@@ -0,0 +1,70 @@
+package commands_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/completion"
+	"github.com/cloudfoundry/cli/cf/completion/completionfakes"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig/pluginconfigfakes"
+	"github.com/cloudfoundry/cli/flags"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("__complete", func() {
+	var (
+		ui           *testterm.FakeUI
+		pluginConfig *pluginconfigfakes.FakePluginConfiguration
+		source       *completionfakes.FakeValueSource
+		cmd          commandregistry.Command
+		flagContext  flags.FlagContext
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		pluginConfig = new(pluginconfigfakes.FakePluginConfiguration)
+		pluginConfig.PluginsReturns(map[string]pluginconfig.PluginMetadata{})
+		source = new(completionfakes.FakeValueSource)
+
+		cmd = &commands.Complete{}
+		cmd.SetDependency(commandregistry.Dependency{
+			UI:                 ui,
+			PluginConfig:       pluginConfig,
+			WildcardDependency: source,
+		}, false)
+
+		flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+		flagContext.SkipFlagParsing(cmd.MetaData().SkipFlagParsing)
+	})
+
+	It("is hidden", func() {
+		Expect(cmd.MetaData().Hidden).To(BeTrue())
+	})
+
+	It("prints one suggestion per line", func() {
+		flagContext.Parse("targ")
+		cmd.Execute(flagContext)
+
+		Expect(ui.Outputs).To(Equal([]string{"target"}))
+	})
+
+	It("suggests values looked up for the current target", func() {
+		source.ValuesReturns([]string{"my-org", "other-org"}, nil)
+
+		flagContext.Parse("target", "-o", "")
+		cmd.Execute(flagContext)
+
+		Expect(source.ValuesArgsForCall(0)).To(Equal(completion.OrgNames))
+		Expect(ui.Outputs).To(Equal([]string{"my-org", "other-org"}))
+	})
+
+	It("prints nothing when there is nothing to suggest", func() {
+		flagContext.Parse("not-a-command", "")
+		cmd.Execute(flagContext)
+
+		Expect(ui.Outputs).To(BeEmpty())
+	})
+})
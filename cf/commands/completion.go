@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/completion"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+type Completion struct {
+	ui           terminal.UI
+	pluginConfig pluginconfig.PluginConfiguration
+}
+
+func init() {
+	commandregistry.Register(&Completion{})
+}
+
+func (cmd *Completion) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "completion",
+		Description: T("Print a shell completion script"),
+		Usage: []string{
+			"CF_NAME completion (bash | zsh | fish)",
+		},
+		Examples: []string{
+			"source <(CF_NAME completion bash)",
+			"CF_NAME completion zsh > \"${fpath[1]}/_cf\"",
+			"CF_NAME completion fish > ~/.config/fish/completions/cf.fish",
+		},
+	}
+}
+
+func (cmd *Completion) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("Requires one of {{.Shells}} as an argument", map[string]interface{}{"Shells": strings.Join(completion.Shells, ", ")}),
+		func() bool {
+			return len(fc.Args()) != 1
+		},
+	)
+
+	return []requirements.Requirement{usageReq}
+}
+
+func (cmd *Completion) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.pluginConfig = deps.PluginConfig
+	return cmd
+}
+
+func (cmd *Completion) Execute(c flags.FlagContext) {
+	commands := completion.NewCommands(commandregistry.Commands.Metadatas(), cmd.pluginConfig.Plugins())
+
+	script := new(bytes.Buffer)
+	err := completion.WriteScript(script, c.Args()[0], commands)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+
+	cmd.ui.Say(strings.TrimSuffix(script.String(), "\n"))
+}
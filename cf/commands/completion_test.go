@@ -0,0 +1,80 @@
+package commands_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig/pluginconfigfakes"
+	"github.com/cloudfoundry/cli/cf/requirements/requirementsfakes"
+	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/plugin"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Completion", func() {
+	var (
+		ui           *testterm.FakeUI
+		pluginConfig *pluginconfigfakes.FakePluginConfiguration
+		cmd          commandregistry.Command
+		factory      *requirementsfakes.FakeFactory
+		flagContext  flags.FlagContext
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		pluginConfig = new(pluginconfigfakes.FakePluginConfiguration)
+		pluginConfig.PluginsReturns(map[string]pluginconfig.PluginMetadata{
+			"Test1": {
+				Commands: []plugin.Command{{Name: "test1_cmd", HelpText: "a test command"}},
+			},
+		})
+
+		cmd = &commands.Completion{}
+		cmd.SetDependency(commandregistry.Dependency{UI: ui, PluginConfig: pluginConfig}, false)
+
+		flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+		factory = new(requirementsfakes.FakeFactory)
+	})
+
+	Describe("Requirements", func() {
+		It("fails with usage without a shell", func() {
+			flagContext.Parse()
+
+			reqs := cmd.Requirements(factory, flagContext)
+			err := reqs[0].Execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Incorrect Usage. Requires one of bash, zsh, fish as an argument"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints a script with the commands and the plugin commands", func() {
+			flagContext.Parse("bash")
+			cmd.Execute(flagContext)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"# bash completion for cf"},
+				[]string{"compgen -W", "target", "test1_cmd"},
+				[]string{"complete -F _cf_complete cf"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"__complete)"}))
+		})
+
+		It("fails for unsupported shells", func() {
+			flagContext.Parse("tcsh")
+
+			Expect(func() {
+				cmd.Execute(flagContext)
+			}).To(Panic())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Unsupported shell tcsh"},
+			))
+		})
+	})
+})
@@ -2,9 +2,11 @@ package commands
 
 import (
 	"sort"
+	"strconv"
 
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/flags"
@@ -24,15 +26,18 @@ func init() {
 func (cmd *ConfigCommands) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["async-timeout"] = &flags.IntFlag{Name: "async-timeout", Usage: T("Timeout for async HTTP requests")}
+	fs["retries"] = &flags.StringFlag{Name: "retries", Usage: T("Number of times to retry HTTP requests that fail with a connection error, 429, 502 or 503. If COUNT is 'CLEAR', the default of 2 is used.")}
+	fs["retry-max-wait"] = &flags.IntFlag{Name: "retry-max-wait", Usage: T("Longest time to wait between retries of HTTP requests. 0 restores the default of 30 seconds.")}
 	fs["trace"] = &flags.StringFlag{Name: "trace", Usage: T("Trace HTTP requests")}
 	fs["color"] = &flags.StringFlag{Name: "color", Usage: T("Enable or disable color")}
 	fs["locale"] = &flags.StringFlag{Name: "locale", Usage: T("Set default locale. If LOCALE is 'CLEAR', previous locale is deleted.")}
+	fs["credential-helper"] = &flags.StringFlag{Name: "credential-helper", Usage: T("Keep tokens with the cf-credential-NAME executable, or encrypted with a passphrase if NAME is 'encrypted'. If NAME is 'CLEAR', tokens are kept in the config file again.")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "config",
 		Description: T("Write default values to the config"),
 		Usage: []string{
-			T("CF_NAME config [--async-timeout TIMEOUT_IN_MINUTES] [--trace (true | false | path/to/file)] [--color (true | false)] [--locale (LOCALE | CLEAR)]"),
+			T("CF_NAME config [--async-timeout TIMEOUT_IN_MINUTES] [--retries (COUNT | CLEAR)] [--retry-max-wait SECONDS] [--trace (true | false | path/to/file)] [--color (true | false)] [--locale (LOCALE | CLEAR)] [--credential-helper (NAME | CLEAR)]"),
 		},
 		Flags: fs,
 	}
@@ -50,7 +55,7 @@ func (cmd *ConfigCommands) SetDependency(deps commandregistry.Dependency, plugin
 }
 
 func (cmd *ConfigCommands) Execute(context flags.FlagContext) {
-	if !context.IsSet("trace") && !context.IsSet("async-timeout") && !context.IsSet("color") && !context.IsSet("locale") {
+	if !context.IsSet("trace") && !context.IsSet("async-timeout") && !context.IsSet("retries") && !context.IsSet("retry-max-wait") && !context.IsSet("color") && !context.IsSet("locale") && !context.IsSet("credential-helper") {
 		cmd.ui.Failed(T("Incorrect Usage") + "\n\n" + commandregistry.Commands.CommandUsage("config"))
 		return
 	}
@@ -64,6 +69,29 @@ func (cmd *ConfigCommands) Execute(context flags.FlagContext) {
 		cmd.config.SetAsyncTimeout(uint(asyncTimeout))
 	}
 
+	if context.IsSet("retries") {
+		value := context.String("retries")
+		if value == "CLEAR" {
+			cmd.config.SetRetries(-1)
+		} else {
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				cmd.ui.Failed(T("Incorrect Usage") + "\n\n" + commandregistry.Commands.CommandUsage("config"))
+			}
+
+			cmd.config.SetRetries(retries)
+		}
+	}
+
+	if context.IsSet("retry-max-wait") {
+		retryMaxWait := context.Int("retry-max-wait")
+		if retryMaxWait < 0 {
+			cmd.ui.Failed(T("Incorrect Usage") + "\n\n" + commandregistry.Commands.CommandUsage("config"))
+		}
+
+		cmd.config.SetRetryMaxWait(uint(retryMaxWait))
+	}
+
 	if context.IsSet("trace") {
 		cmd.config.SetTrace(context.String("trace"))
 	}
@@ -80,6 +108,20 @@ func (cmd *ConfigCommands) Execute(context flags.FlagContext) {
 		}
 	}
 
+	if context.IsSet("credential-helper") {
+		helper := context.String("credential-helper")
+
+		if helper == "CLEAR" {
+			cmd.config.SetCredentialHelper("")
+		} else {
+			err := credentials.Validate(helper)
+			if err != nil {
+				cmd.ui.Failed(err.Error())
+			}
+			cmd.config.SetCredentialHelper(helper)
+		}
+	}
+
 	if context.IsSet("locale") {
 		locale := context.String("locale")
 
@@ -66,6 +66,45 @@ var _ = Describe("config command", func() {
 		})
 	})
 
+	Context("--retries flag", func() {
+		It("stores the number of retries", func() {
+			runCommand("--retries", "5")
+			Expect(configRepo.Retries()).To(Equal(5))
+
+			runCommand("--retries", "0")
+			Expect(configRepo.Retries()).To(Equal(0))
+		})
+
+		It("goes back to the default when the '--retries CLEAR' flag is provided", func() {
+			runCommand("--retries", "5")
+			runCommand("--retries", "CLEAR")
+			Expect(configRepo.Retries()).To(Equal(-1))
+		})
+
+		It("fails with usage when the number of retries is not a positive number", func() {
+			runCommand("--retries", "many")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage"},
+			))
+			Expect(configRepo.Retries()).To(Equal(-1))
+		})
+	})
+
+	Context("--retry-max-wait flag", func() {
+		It("stores the maximum wait in seconds", func() {
+			runCommand("--retry-max-wait", "45")
+			Expect(configRepo.RetryMaxWait()).To(Equal(uint(45)))
+		})
+
+		It("fails with usage when a negative wait is passed", func() {
+			runCommand("--retry-max-wait", "-1")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage"},
+			))
+			Expect(configRepo.RetryMaxWait()).To(Equal(uint(0)))
+		})
+	})
+
 	Context("--trace flag", func() {
 		It("stores the trace value when --trace flag is provided", func() {
 			runCommand("--trace", "true")
@@ -125,4 +164,28 @@ var _ = Describe("config command", func() {
 			})
 		})
 	})
+
+	Context("--credential-helper flag", func() {
+		It("uses the built-in encrypted helper", func() {
+			runCommand("--credential-helper", "encrypted")
+			Expect(configRepo.CredentialHelper()).To(Equal("encrypted"))
+		})
+
+		It("fails when the helper cannot be found", func() {
+			runCommand("--credential-helper", "does-not-exist")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Could not find the credential helper cf-credential-does-not-exist in your PATH"},
+			))
+			Expect(configRepo.CredentialHelper()).To(BeEmpty())
+		})
+
+		It("keeps tokens in the config again when the '--credential-helper CLEAR' flag is provided", func() {
+			configRepo.SetCredentialHelper("encrypted")
+
+			runCommand("--credential-helper", "CLEAR")
+			Expect(configRepo.CredentialHelper()).To(BeEmpty())
+		})
+	})
 })
@@ -27,6 +27,7 @@ type CreateAppManifest struct {
 	appInstancesRepo appinstances.AppInstancesRepository
 	appReq           requirements.ApplicationRequirement
 	manifest         manifest.AppManifest
+	manifestRepo     manifest.ManifestRepository
 }
 
 func init() {
@@ -36,12 +37,16 @@ func init() {
 func (cmd *CreateAppManifest) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["p"] = &flags.StringFlag{ShortName: "p", Usage: T("Specify a path for file creation. If path not specified, manifest file is created in current working directory.")}
+	fs["app-path"] = &flags.StringFlag{Name: "app-path", Usage: T("Path to the app's files to record in the manifest, as given to push with -p")}
+	fs["f"] = &flags.StringFlag{ShortName: "f", Usage: T("Path to the manifest the app was pushed with, used with --var and --vars-file. If not specified, the manifest in the current working directory is used")}
+	fs["var"] = &flags.StringSliceFlag{Name: "var", Usage: T("Variable the app's manifest was pushed with, as NAME=VALUE. Properties it was interpolated into keep their ((NAME)) reference. Can be specified multiple times")}
+	fs["vars-file"] = &flags.StringSliceFlag{Name: "vars-file", Usage: T("Path to a YAML file of variables the app's manifest was pushed with. Properties they were interpolated into keep their ((NAME)) references. Can be specified multiple times")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "create-app-manifest",
 		Description: T("Create an app manifest for an app that has been pushed successfully"),
 		Usage: []string{
-			T("CF_NAME create-app-manifest APP_NAME [-p /path/to/<app-name>-manifest.yml ]"),
+			T("CF_NAME create-app-manifest APP_NAME [-p /path/to/<app-name>-manifest.yml ] [--app-path APP_PATH] [-f MANIFEST_PATH] [--vars-file VARS_FILE_PATH] [--var NAME=VALUE]"),
 		},
 		Flags: fs,
 	}
@@ -69,6 +74,7 @@ func (cmd *CreateAppManifest) SetDependency(deps commandregistry.Dependency, plu
 	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
 	cmd.stackRepo = deps.RepoLocator.GetStackRepository()
 	cmd.manifest = deps.AppManifest
+	cmd.manifestRepo = deps.ManifestRepo
 	return cmd
 }
 
@@ -85,6 +91,8 @@ func (cmd *CreateAppManifest) Execute(c flags.FlagContext) {
 
 	application.Stack = &stack
 
+	variableReferences := cmd.variableReferences(c)
+
 	cmd.ui.Say(T("Creating an app manifest from current settings of app ") + application.Name + " ...")
 	cmd.ui.Say("")
 
@@ -101,6 +109,12 @@ func (cmd *CreateAppManifest) Execute(c flags.FlagContext) {
 	defer f.Close()
 
 	cmd.createManifest(application)
+	if c.String("app-path") != "" {
+		cmd.manifest.Path(application.Name, c.String("app-path"))
+	}
+	if len(variableReferences) > 0 {
+		cmd.manifest.Variables(variableReferences)
+	}
 	err = cmd.manifest.Save(f)
 	if err != nil {
 		cmd.ui.Failed(T("Error creating manifest file: ") + err.Error())
@@ -111,6 +125,39 @@ func (cmd *CreateAppManifest) Execute(c flags.FlagContext) {
 	cmd.ui.Say("")
 }
 
+// variableReferences finds where the variables given on the command line
+// were interpolated into the manifest the app was pushed with, so that those
+// properties can be written back as ((var)) references.
+func (cmd *CreateAppManifest) variableReferences(c flags.FlagContext) []manifest.VariableReference {
+	variables, err := manifest.ReadVariables(c.StringSlice("vars-file"), c.StringSlice("var"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+	if len(variables) == 0 {
+		return nil
+	}
+
+	path := c.String("f")
+	if path == "" {
+		path, err = os.Getwd()
+		if err != nil {
+			cmd.ui.Failed(T("Could not determine the current working directory!"), err)
+		}
+	}
+
+	m, err := cmd.manifestRepo.ReadManifest(path)
+	if err != nil {
+		cmd.ui.Failed(T("Error reading the manifest the app was pushed with, needed for --var and --vars-file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	m.Variables = variables
+	references, err := m.VariableReferences()
+	if err != nil {
+		cmd.ui.Failed(T("Error reading the manifest the app was pushed with, needed for --var and --vars-file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+	return references
+}
+
 func (cmd *CreateAppManifest) createManifest(app models.Application) {
 	cmd.manifest.Memory(app.Name, app.Memory)
 	cmd.manifest.Instances(app.Name, app.InstanceCount)
@@ -134,6 +181,14 @@ func (cmd *CreateAppManifest) createManifest(app models.Application) {
 		}
 	}
 
+	if app.HealthCheckType != "" {
+		cmd.manifest.HealthCheckType(app.Name, app.HealthCheckType)
+	}
+
+	if app.DockerImage != "" {
+		cmd.manifest.DockerImage(app.Name, app.DockerImage)
+	}
+
 	if app.HealthCheckTimeout > 0 {
 		cmd.manifest.HealthCheckTimeout(app.Name, app.HealthCheckTimeout)
 	}
@@ -157,8 +212,12 @@ func (cmd *CreateAppManifest) createManifest(app models.Application) {
 	}
 
 	if len(app.Routes) > 0 {
-		for i := 0; i < len(app.Routes); i++ {
-			cmd.manifest.Domain(app.Name, app.Routes[i].Host, app.Routes[i].Domain.Name)
+		for _, route := range app.Routes {
+			if route.Path == "" && route.Port == 0 {
+				cmd.manifest.Domain(app.Name, route.Host, route.Domain.Name)
+			} else {
+				cmd.manifest.Route(app.Name, route.Host, route.Domain.Name, route.Path, route.Port)
+			}
 		}
 	}
 
@@ -6,11 +6,13 @@ import (
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/commands"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/manifest"
 	"github.com/cloudfoundry/cli/cf/manifest/manifestfakes"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/requirements/requirementsfakes"
 	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/generic"
 
 	"github.com/cloudfoundry/cli/cf/api/apifakes"
 	"github.com/cloudfoundry/cli/cf/api/stacks/stacksfakes"
@@ -41,6 +43,7 @@ var _ = Describe("CreateAppManifest", func() {
 		applicationRequirement   *requirementsfakes.FakeApplicationRequirement
 
 		fakeManifest *manifestfakes.FakeAppManifest
+		manifestRepo *manifestfakes.FakeManifestRepository
 	)
 
 	BeforeEach(func() {
@@ -52,12 +55,14 @@ var _ = Describe("CreateAppManifest", func() {
 		repoLocator = repoLocator.SetStackRepository(stackRepo)
 
 		fakeManifest = new(manifestfakes.FakeAppManifest)
+		manifestRepo = new(manifestfakes.FakeManifestRepository)
 
 		deps = commandregistry.Dependency{
-			UI:          ui,
-			Config:      configRepo,
-			RepoLocator: repoLocator,
-			AppManifest: fakeManifest,
+			UI:           ui,
+			Config:       configRepo,
+			RepoLocator:  repoLocator,
+			AppManifest:  fakeManifest,
+			ManifestRepo: manifestRepo,
 		}
 
 		cmd = &commands.CreateAppManifest{}
@@ -179,6 +184,81 @@ var _ = Describe("CreateAppManifest", func() {
 				Expect(instances).To(Equal(2))
 			})
 
+			It("does not set variables when none are given", func() {
+				cmd.Execute(flagContext)
+				Expect(fakeManifest.VariablesCallCount()).To(Equal(0))
+			})
+
+			Context("when variables are given", func() {
+				BeforeEach(func() {
+					manifestRepo.ReadManifestReturns(&manifest.Manifest{
+						Path: "/some/path/manifest.yml",
+						Data: generic.NewMap(map[interface{}]interface{}{
+							"applications": []interface{}{
+								map[interface{}]interface{}{
+									"name":      "app-name",
+									"instances": "((instances))",
+									"host":      "app-name-((space))",
+								},
+							},
+						}),
+					}, nil)
+
+					err := flagContext.Parse("app-name", "-f", "/some/path/manifest.yml", "--var", "instances=2", "--var", "space=staging")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("reads the manifest the app was pushed with", func() {
+					cmd.Execute(flagContext)
+					Expect(manifestRepo.ReadManifestCallCount()).To(Equal(1))
+					Expect(manifestRepo.ReadManifestArgsForCall(0)).To(Equal("/some/path/manifest.yml"))
+				})
+
+				It("sets the properties the variables were interpolated into", func() {
+					cmd.Execute(flagContext)
+					Expect(fakeManifest.VariablesCallCount()).To(Equal(1))
+					Expect(fakeManifest.VariablesArgsForCall(0)).To(Equal([]manifest.VariableReference{
+						{Property: "host", Template: "app-name-((space))", Value: "app-name-staging"},
+						{Property: "instances", Template: "((instances))", Value: 2},
+					}))
+				})
+
+				Context("when the manifest cannot be read", func() {
+					BeforeEach(func() {
+						manifestRepo.ReadManifestReturns(manifest.NewEmptyManifest(), errors.New("no such file"))
+					})
+
+					It("fails", func() {
+						Expect(func() { cmd.Execute(flagContext) }).To(Panic())
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"FAILED"},
+							[]string{"needed for --var and --vars-file"},
+							[]string{"no such file"},
+						))
+					})
+				})
+			})
+
+			It("does not set a path when none is given", func() {
+				cmd.Execute(flagContext)
+				Expect(fakeManifest.PathCallCount()).To(Equal(0))
+			})
+
+			Context("when an app path is given", func() {
+				BeforeEach(func() {
+					err := flagContext.Parse("app-name", "--app-path", "./app-dir")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("sets the path", func() {
+					cmd.Execute(flagContext)
+					Expect(fakeManifest.PathCallCount()).To(Equal(1))
+					name, path := fakeManifest.PathArgsForCall(0)
+					Expect(name).To(Equal("app-name"))
+					Expect(path).To(Equal("./app-dir"))
+				})
+			})
+
 			Context("when there are app ports specified", func() {
 				BeforeEach(func() {
 					application.AppPorts = []int{1111, 2222}
@@ -338,6 +418,34 @@ var _ = Describe("CreateAppManifest", func() {
 				})
 			})
 
+			Context("when the app has a health check type", func() {
+				BeforeEach(func() {
+					application.HealthCheckType = "none"
+				})
+
+				It("sets the health check type", func() {
+					cmd.Execute(flagContext)
+					Expect(fakeManifest.HealthCheckTypeCallCount()).To(Equal(1))
+					name, healthCheckType := fakeManifest.HealthCheckTypeArgsForCall(0)
+					Expect(name).To(Equal("app-name"))
+					Expect(healthCheckType).To(Equal("none"))
+				})
+			})
+
+			Context("when the app has a docker image", func() {
+				BeforeEach(func() {
+					application.DockerImage = "user/my-image"
+				})
+
+				It("sets the docker image", func() {
+					cmd.Execute(flagContext)
+					Expect(fakeManifest.DockerImageCallCount()).To(Equal(1))
+					name, dockerImage := fakeManifest.DockerImageArgsForCall(0)
+					Expect(name).To(Equal("app-name"))
+					Expect(dockerImage).To(Equal("user/my-image"))
+				})
+			})
+
 			Context("when the app has environment vars", func() {
 				BeforeEach(func() {
 					application.EnvironmentVars = map[string]interface{}{
@@ -414,6 +522,46 @@ var _ = Describe("CreateAppManifest", func() {
 				})
 			})
 
+			Context("when the app has routes with paths or ports", func() {
+				BeforeEach(func() {
+					application.Routes = []models.RouteSummary{
+						{
+							Host: "route-1-host",
+							Domain: models.DomainFields{
+								Name: "domain-1-name",
+							},
+							Path: "/api",
+						},
+						{
+							Domain: models.DomainFields{
+								Name: "tcp-domain-name",
+							},
+							Port: 1234,
+						},
+					}
+				})
+
+				It("sets the routes with their paths and ports", func() {
+					cmd.Execute(flagContext)
+					Expect(fakeManifest.DomainCallCount()).To(Equal(0))
+					Expect(fakeManifest.RouteCallCount()).To(Equal(2))
+
+					name, host, domainName, path, port := fakeManifest.RouteArgsForCall(0)
+					Expect(name).To(Equal("app-name"))
+					Expect(host).To(Equal("route-1-host"))
+					Expect(domainName).To(Equal("domain-1-name"))
+					Expect(path).To(Equal("/api"))
+					Expect(port).To(Equal(0))
+
+					name, host, domainName, path, port = fakeManifest.RouteArgsForCall(1)
+					Expect(name).To(Equal("app-name"))
+					Expect(host).To(BeEmpty())
+					Expect(domainName).To(Equal("tcp-domain-name"))
+					Expect(path).To(BeEmpty())
+					Expect(port).To(Equal(1234))
+				})
+			})
+
 			Context("when the app has a disk quota", func() {
 				BeforeEach(func() {
 					application.DiskQuota = 1024
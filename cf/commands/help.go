@@ -3,7 +3,9 @@ package commands
 import (
 	"strings"
 
+	"github.com/cloudfoundry/cli/cf/actors/pathplugin"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
 	"github.com/cloudfoundry/cli/cf/help"
 	"github.com/cloudfoundry/cli/cf/requirements"
@@ -14,8 +16,9 @@ import (
 )
 
 type Help struct {
-	ui     terminal.UI
-	config pluginconfig.PluginConfiguration
+	ui         terminal.UI
+	config     pluginconfig.PluginConfiguration
+	coreConfig coreconfig.Reader
 }
 
 func init() {
@@ -41,6 +44,7 @@ func (cmd *Help) Requirements(requirementsFactory requirements.Factory, fc flags
 func (cmd *Help) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
 	cmd.ui = deps.UI
 	cmd.config = deps.PluginConfig
+	cmd.coreConfig = deps.Config
 	return cmd
 }
 
@@ -92,6 +96,25 @@ func (cmd *Help) Execute(c flags.FlagContext) {
 			}
 
 			if !found {
+				//check user defined aliases
+				command, isAlias := cmd.coreConfig.Aliases()[cmdName]
+				if isAlias {
+					cmd.ui.Say(T("'{{.Alias}}' is an alias for '{{.Command}}'", map[string]interface{}{
+						"Alias":   cmdName,
+						"Command": command,
+					}))
+					return
+				}
+
+				//check cf-NAME executables on PATH
+				if pathPlugin, isPathPlugin := pathplugin.Find(cmdName); isPathPlugin {
+					cmd.ui.Say(T("'{{.Command}}' runs the executable {{.Location}}, see its own help", map[string]interface{}{
+						"Command":  cmdName,
+						"Location": pathPlugin.Location,
+					}))
+					return
+				}
+
 				cmd.ui.Failed("'" + cmdName + "' is not a registered command. See 'cf help'")
 			}
 		}
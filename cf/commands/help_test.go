@@ -1,12 +1,18 @@
 package commands_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
 	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
 	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig/pluginconfigfakes"
 	"github.com/cloudfoundry/cli/commandsloader"
 	"github.com/cloudfoundry/cli/plugin"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
 	io_helpers "github.com/cloudfoundry/cli/testhelpers/io"
 	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
 	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
@@ -24,12 +30,14 @@ var _ = Describe("Help", func() {
 		ui                  *testterm.FakeUI
 		requirementsFactory *testreq.FakeReqFactory
 		config              *pluginconfigfakes.FakePluginConfiguration
+		coreConfig          coreconfig.Repository
 		deps                commandregistry.Dependency
 	)
 
 	updateCommandDependency := func(pluginCall bool) {
 		deps.UI = ui
 		deps.PluginConfig = config
+		deps.Config = coreConfig
 		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("help").SetDependency(deps, pluginCall))
 	}
 
@@ -37,6 +45,7 @@ var _ = Describe("Help", func() {
 		ui = &testterm.FakeUI{}
 		requirementsFactory = &testreq.FakeReqFactory{}
 		config = new(pluginconfigfakes.FakePluginConfiguration)
+		coreConfig = testconfig.NewRepository()
 	})
 
 	runCommand := func(args ...string) bool {
@@ -120,5 +129,54 @@ var _ = Describe("Help", func() {
 			})
 		})
 
+		Context("command is a user defined alias", func() {
+			It("prints the command the alias expands to", func() {
+				coreConfig.SetAlias("deploy-prod", "push -f manifests/prod.yml")
+				runCommand("deploy-prod")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"'deploy-prod' is an alias for 'push -f manifests/prod.yml'"}))
+			})
+		})
+
+		Context("command is a cf-NAME executable on PATH", func() {
+			var (
+				pathDir string
+				oldPath string
+			)
+
+			BeforeEach(func() {
+				var err error
+				pathDir, err = ioutil.TempDir("", "help-path-plugin")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(filepath.Join(pathDir, "cf-deploy"), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+
+				oldPath = os.Getenv("PATH")
+				os.Setenv("PATH", pathDir)
+			})
+
+			AfterEach(func() {
+				os.Setenv("PATH", oldPath)
+				os.RemoveAll(pathDir)
+			})
+
+			It("prints the executable the command runs", func() {
+				runCommand("deploy")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"'deploy' runs the executable", filepath.Join(pathDir, "cf-deploy")},
+				))
+			})
+		})
+
+		Context("command does not exist", func() {
+			It("fails", func() {
+				runCommand("not-a-command")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"'not-a-command' is not a registered command"},
+				))
+			})
+		})
 	})
 })
@@ -74,7 +74,7 @@ func (cmd ListOrgs) Execute(fc flags.FlagContext) {
 		cmd.ui.Failed(apiErr.Error())
 	}
 	for _, org := range orgs {
-		table.Add(org.Name)
+		table.AddRecord(org, org.Name)
 		noOrgs = false
 	}
 
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"net/rpc"
 	"os"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/cloudfoundry/cli/cf/actors/plugininstaller"
@@ -167,18 +166,27 @@ func (cmd *PluginInstall) ensurePluginIsSafeForInstallation(pluginMetadata *plug
 		cmd.ui.Failed(fmt.Sprintf(T("Error getting command list from plugin {{.FilePath}}", map[string]interface{}{"FilePath": pluginSourceFilepath})))
 	}
 
+	if conflict := findCommandConflict(pluginMetadata, plugins); conflict != "" {
+		cmd.ui.Failed(conflict)
+	}
+}
+
+// findCommandConflict describes the first command or alias of a plugin that
+// is already a core command/alias or a command/alias of an installed plugin,
+// or returns an empty string when there is none.
+func findCommandConflict(pluginMetadata *plugin.PluginMetadata, plugins map[string]pluginconfig.PluginMetadata) string {
 	for _, pluginCmd := range pluginMetadata.Commands {
 
 		//check for command conflicting core commands/alias
 		if pluginCmd.Name == "help" || commandregistry.Commands.CommandExists(pluginCmd.Name) {
-			cmd.ui.Failed(fmt.Sprintf(T("Command `{{.Command}}` in the plugin being installed is a native CF command/alias.  Rename the `{{.Command}}` command in the plugin being installed in order to enable its installation and use.",
-				map[string]interface{}{"Command": pluginCmd.Name})))
+			return fmt.Sprintf(T("Command `{{.Command}}` in the plugin being installed is a native CF command/alias.  Rename the `{{.Command}}` command in the plugin being installed in order to enable its installation and use.",
+				map[string]interface{}{"Command": pluginCmd.Name}))
 		}
 
 		//check for alias conflicting core command/alias
 		if pluginCmd.Alias == "help" || commandregistry.Commands.CommandExists(pluginCmd.Alias) {
-			cmd.ui.Failed(fmt.Sprintf(T("Alias `{{.Command}}` in the plugin being installed is a native CF command/alias.  Rename the `{{.Command}}` command in the plugin being installed in order to enable its installation and use.",
-				map[string]interface{}{"Command": pluginCmd.Alias})))
+			return fmt.Sprintf(T("Alias `{{.Command}}` in the plugin being installed is a native CF command/alias.  Rename the `{{.Command}}` command in the plugin being installed in order to enable its installation and use.",
+				map[string]interface{}{"Command": pluginCmd.Alias}))
 		}
 
 		for installedPluginName, installedPlugin := range plugins {
@@ -186,19 +194,20 @@ func (cmd *PluginInstall) ensurePluginIsSafeForInstallation(pluginMetadata *plug
 
 				//check for command conflicting other plugin commands/alias
 				if installedPluginCmd.Name == pluginCmd.Name || installedPluginCmd.Alias == pluginCmd.Name {
-					cmd.ui.Failed(fmt.Sprintf(T("Command `{{.Command}}` is a command/alias in plugin '{{.PluginName}}'.  You could try uninstalling plugin '{{.PluginName}}' and then install this plugin in order to invoke the `{{.Command}}` command.  However, you should first fully understand the impact of uninstalling the existing '{{.PluginName}}' plugin.",
-						map[string]interface{}{"Command": pluginCmd.Name, "PluginName": installedPluginName})))
+					return fmt.Sprintf(T("Command `{{.Command}}` is a command/alias in plugin '{{.PluginName}}'.  You could try uninstalling plugin '{{.PluginName}}' and then install this plugin in order to invoke the `{{.Command}}` command.  However, you should first fully understand the impact of uninstalling the existing '{{.PluginName}}' plugin.",
+						map[string]interface{}{"Command": pluginCmd.Name, "PluginName": installedPluginName}))
 				}
 
 				//check for alias conflicting other plugin commands/alias
 				if pluginCmd.Alias != "" && (installedPluginCmd.Name == pluginCmd.Alias || installedPluginCmd.Alias == pluginCmd.Alias) {
-					cmd.ui.Failed(fmt.Sprintf(T("Alias `{{.Command}}` is a command/alias in plugin '{{.PluginName}}'.  You could try uninstalling plugin '{{.PluginName}}' and then install this plugin in order to invoke the `{{.Command}}` command.  However, you should first fully understand the impact of uninstalling the existing '{{.PluginName}}' plugin.",
-						map[string]interface{}{"Command": pluginCmd.Alias, "PluginName": installedPluginName})))
+					return fmt.Sprintf(T("Alias `{{.Command}}` is a command/alias in plugin '{{.PluginName}}'.  You could try uninstalling plugin '{{.PluginName}}' and then install this plugin in order to invoke the `{{.Command}}` command.  However, you should first fully understand the impact of uninstalling the existing '{{.PluginName}}' plugin.",
+						map[string]interface{}{"Command": pluginCmd.Alias, "PluginName": installedPluginName}))
 				}
 			}
 		}
 	}
 
+	return ""
 }
 
 func (cmd *PluginInstall) installPlugin(pluginMetadata *plugin.PluginMetadata, pluginDestinationFilepath, pluginSourceFilepath string) {
@@ -208,9 +217,12 @@ func (cmd *PluginInstall) installPlugin(pluginMetadata *plugin.PluginMetadata, p
 	}
 
 	configMetadata := pluginconfig.PluginMetadata{
-		Location: pluginDestinationFilepath,
-		Version:  pluginMetadata.Version,
-		Commands: pluginMetadata.Commands,
+		Location:         pluginDestinationFilepath,
+		Version:          pluginMetadata.Version,
+		Commands:         pluginMetadata.Commands,
+		AuthenticatedRPC: !cmd.rpcService.RpcCmd.LegacyPlugin,
+		LegacyRPC:        cmd.rpcService.RpcCmd.LegacyPlugin,
+		Hooks:            pluginMetadata.Hooks,
 	}
 
 	cmd.pluginConfig.SetPlugin(pluginMetadata.Name, configMetadata)
@@ -223,13 +235,13 @@ func (cmd *PluginInstall) runBinaryAndObtainPluginMetadata(pluginSourceFilepath
 	}
 	defer cmd.rpcService.Stop()
 
-	cmd.runPluginBinary(pluginSourceFilepath, cmd.rpcService.Port())
+	cmd.runPluginBinary(pluginSourceFilepath)
 
 	return cmd.rpcService.RpcCmd.PluginMetadata
 }
 
-func (cmd *PluginInstall) runPluginBinary(location string, servicePort string) {
-	pluginInvocation := exec.Command(location, servicePort, "SendMetadata")
+func (cmd *PluginInstall) runPluginBinary(location string) {
+	pluginInvocation := cmd.rpcService.PluginCommand(location, "SendMetadata")
 
 	err := pluginInvocation.Run()
 	if err != nil {
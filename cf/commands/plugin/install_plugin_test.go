@@ -595,6 +595,8 @@ var _ = Describe("Install", func() {
 			Expect(pluginMetadata.Commands[0].HelpText).To(Equal("help text for test_1_cmd1"))
 			Expect(pluginMetadata.Commands[1].Name).To(Equal("test_1_cmd2"))
 			Expect(pluginMetadata.Commands[1].HelpText).To(Equal("help text for test_1_cmd2"))
+			Expect(pluginMetadata.AuthenticatedRPC).To(BeTrue())
+			Expect(pluginMetadata.LegacyRPC).To(BeFalse())
 			Expect(ui.Outputs).To(ContainSubstrings(
 				[]string{"Installing plugin", test_1},
 				[]string{"OK"},
@@ -2,19 +2,29 @@ package plugin
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/cloudfoundry/cli/cf"
+
+	"github.com/cloudfoundry/cli/cf/actors/pathplugin"
+	"github.com/cloudfoundry/cli/cf/actors/pluginrepo"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/plugin"
 	"github.com/cloudfoundry/cli/utils"
 )
 
 type Plugins struct {
-	ui     terminal.UI
-	config pluginconfig.PluginConfiguration
+	ui         terminal.UI
+	config     pluginconfig.PluginConfiguration
+	coreConfig coreconfig.Reader
+	pluginRepo pluginrepo.PluginRepo
 }
 
 func init() {
@@ -24,12 +34,13 @@ func init() {
 func (cmd *Plugins) MetaData() commandregistry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["checksum"] = &flags.BoolFlag{Name: "checksum", Usage: T("Compute and show the sha1 value of the plugin binary file")}
+	fs["outdated"] = &flags.BoolFlag{Name: "outdated", Usage: T("Search the plugin repositories for newer versions of installed plugins")}
 
 	return commandregistry.CommandMetadata{
 		Name:        "plugins",
 		Description: T("List all available plugin commands"),
 		Usage: []string{
-			T("CF_NAME plugins"),
+			T("CF_NAME plugins [--checksum | --outdated]"),
 		},
 		Flags: fs,
 	}
@@ -52,10 +63,17 @@ func (cmd *Plugins) Requirements(requirementsFactory requirements.Factory, fc fl
 func (cmd *Plugins) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
 	cmd.ui = deps.UI
 	cmd.config = deps.PluginConfig
+	cmd.coreConfig = deps.Config
+	cmd.pluginRepo = deps.PluginRepo
 	return cmd
 }
 
 func (cmd *Plugins) Execute(c flags.FlagContext) {
+	if c.Bool("outdated") {
+		cmd.listOutdatedPlugins()
+		return
+	}
+
 	var version string
 
 	cmd.ui.Say(T("Listing Installed Plugins..."))
@@ -71,13 +89,16 @@ func (cmd *Plugins) Execute(c flags.FlagContext) {
 	}
 
 	for pluginName, metadata := range plugins {
-		if metadata.Version.Major == 0 && metadata.Version.Minor == 0 && metadata.Version.Build == 0 {
-			version = "N/A"
-		} else {
-			version = fmt.Sprintf("%d.%d.%d", metadata.Version.Major, metadata.Version.Minor, metadata.Version.Build)
-		}
+		version = formatVersion(metadata.Version)
 
 		for _, command := range metadata.Commands {
+			record := pluginCommandRecord{
+				Plugin:   pluginName,
+				Version:  version,
+				Command:  command.Name,
+				Alias:    command.Alias,
+				HelpText: command.HelpText,
+			}
 			args := []string{pluginName, version}
 
 			if command.Alias != "" {
@@ -90,19 +111,172 @@ func (cmd *Plugins) Execute(c flags.FlagContext) {
 				checksum := utils.NewSha1Checksum(metadata.Location)
 				sha1, err := checksum.ComputeFileSha1()
 				if err != nil {
-					args = append(args, "n/a")
+					record.Sha1 = "n/a"
 				} else {
-					args = append(args, fmt.Sprintf("%x", sha1))
+					record.Sha1 = fmt.Sprintf("%x", sha1)
 				}
+				args = append(args, record.Sha1)
 			}
 
 			args = append(args, command.HelpText)
-			table.Add(args...)
+			table.AddRecord(record, args...)
 		}
 	}
 
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
+	//structured output is a single document, holding the commands of both
+	if table.OutputFormat.IsStructured() {
+		for _, pathPlugin := range listablePathPlugins(plugins) {
+			table.AddRecord(pluginCommandRecord{Command: pathPlugin.Name, Location: pathPlugin.Location})
+		}
+		table.Print()
+		return
+	}
+
 	table.Print()
+
+	cmd.listPathPlugins(plugins)
+}
+
+// listPathPlugins lists the cf-NAME executables on PATH in a table of their own.
+func (cmd *Plugins) listPathPlugins(plugins map[string]pluginconfig.PluginMetadata) {
+	pathPlugins := listablePathPlugins(plugins)
+	if len(pathPlugins) == 0 {
+		return
+	}
+
+	table := cmd.ui.Table([]string{T("Command Name"), T("Location")})
+	for _, pathPlugin := range pathPlugins {
+		table.Add(pathPlugin.Name, pathPlugin.Location)
+	}
+
+	cmd.ui.Say("")
+	cmd.ui.Say(T("Commands provided by executables on PATH:"))
+	cmd.ui.Say("")
+	table.Print()
+}
+
+// listablePathPlugins returns the cf-NAME executables on PATH, leaving out
+// those whose command is shadowed by a core or plugin command of the same name.
+func listablePathPlugins(plugins map[string]pluginconfig.PluginMetadata) []pathplugin.PathPlugin {
+	pathPlugins := []pathplugin.PathPlugin{}
+	for _, pathPlugin := range pathplugin.List() {
+		if commandregistry.Commands.CommandExists(pathPlugin.Name) || isPluginCommand(plugins, pathPlugin.Name) {
+			continue
+		}
+		pathPlugins = append(pathPlugins, pathPlugin)
+	}
+	return pathPlugins
+}
+
+func isPluginCommand(plugins map[string]pluginconfig.PluginMetadata, name string) bool {
+	for _, metadata := range plugins {
+		for _, command := range metadata.Commands {
+			if command.Name == name || command.Alias == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cmd *Plugins) listOutdatedPlugins() {
+	repos := registeredPluginRepos(cmd.coreConfig)
+	if len(repos) == 0 {
+		cmd.ui.Failed(T("No plugin repositories are registered.\nTip: use `add-plugin-repo` command to add repos."))
+	}
+
+	repoNames := []string{}
+	for _, repo := range repos {
+		repoNames = append(repoNames, repo.Name)
+	}
+
+	cmd.ui.Say(T("Searching {{.RepoNames}} for newer versions of installed plugins...", map[string]interface{}{
+		"RepoNames": terminal.EntityNameColor(strings.Join(repoNames, ", ")),
+	}))
+
+	repoPlugins, repoErrors := cmd.pluginRepo.GetPlugins(repos)
+
+	plugins := cmd.config.Plugins()
+	pluginNames := []string{}
+	for pluginName := range plugins {
+		pluginNames = append(pluginNames, pluginName)
+	}
+	sort.Strings(pluginNames)
+
+	table := cmd.ui.Table([]string{T("Plugin Name"), T("Version"), T("Latest Version"), T("Repository")})
+	outdated := 0
+	for _, pluginName := range pluginNames {
+		installed := plugins[pluginName]
+
+		latest, repoName, found := pluginrepo.LatestPlugin(repoPlugins, repos, pluginName)
+		if !found {
+			continue
+		}
+
+		latestVersion, _ := pluginrepo.ParseVersion(latest.Version)
+		if !pluginrepo.IsNewerVersion(latestVersion, installed.Version) {
+			continue
+		}
+
+		record := outdatedPluginRecord{
+			Plugin:        pluginName,
+			Version:       formatVersion(installed.Version),
+			LatestVersion: formatVersion(latestVersion),
+			Repository:    repoName,
+		}
+		table.AddRecord(record, record.Plugin, record.Version, record.LatestVersion, record.Repository)
+		outdated++
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	if outdated == 0 {
+		table.PrintNoRecords(T("All installed plugins are up to date"))
+	} else {
+		table.Print()
+		cmd.ui.Say("")
+		cmd.ui.Say(T("Use '{{.Command}}' to update a plugin", map[string]interface{}{
+			"Command": terminal.CommandColor(cf.Name + " update-plugin PLUGIN_NAME"),
+		}))
+	}
+
+	if len(repoErrors) > 0 {
+		cmd.ui.Say("")
+		cmd.ui.Say(terminal.ColorizeBold(T("Logged errors:"), 31))
+		for _, e := range repoErrors {
+			cmd.ui.Say(terminal.Colorize(e, 31))
+		}
+	}
+}
+
+// pluginCommandRecord is what --output json|yaml shows of a plugin command,
+// or of a cf-NAME executable on PATH, which has a Location instead.
+type pluginCommandRecord struct {
+	Plugin   string
+	Version  string
+	Command  string
+	Alias    string
+	HelpText string
+	Sha1     string `json:",omitempty"`
+	Location string `json:",omitempty"`
+}
+
+// outdatedPluginRecord is what --output json|yaml shows of a plugin with a
+// newer version in the repositories.
+type outdatedPluginRecord struct {
+	Plugin        string
+	Version       string
+	LatestVersion string
+	Repository    string
+}
+
+func formatVersion(version plugin.VersionType) string {
+	if version.Major == 0 && version.Minor == 0 && version.Build == 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Build)
 }
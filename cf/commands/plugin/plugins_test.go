@@ -1,19 +1,29 @@
 package plugin_test
 
 import (
+	"io/ioutil"
 	"net/rpc"
+	"os"
+	"path/filepath"
 
+	"github.com/cloudfoundry/cli/cf/actors/pluginrepo/pluginrepofakes"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	plugincmd "github.com/cloudfoundry/cli/cf/commands/plugin"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
 	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
 	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig/pluginconfigfakes"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/flags"
 	"github.com/cloudfoundry/cli/plugin"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
 	. "github.com/cloudfoundry/cli/testhelpers/matchers"
 	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
 	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
 
+	clipr "github.com/cloudfoundry-incubator/cli-plugin-repo/models"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -23,12 +33,16 @@ var _ = Describe("Plugins", func() {
 		ui                  *testterm.FakeUI
 		requirementsFactory *testreq.FakeReqFactory
 		config              *pluginconfigfakes.FakePluginConfiguration
+		coreConfig          coreconfig.Repository
+		fakePluginRepo      *pluginrepofakes.FakePluginRepo
 		deps                commandregistry.Dependency
 	)
 
 	updateCommandDependency := func(pluginCall bool) {
 		deps.UI = ui
 		deps.PluginConfig = config
+		deps.Config = coreConfig
+		deps.PluginRepo = fakePluginRepo
 		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("plugins").SetDependency(deps, pluginCall))
 	}
 
@@ -36,6 +50,8 @@ var _ = Describe("Plugins", func() {
 		ui = &testterm.FakeUI{}
 		requirementsFactory = &testreq.FakeReqFactory{}
 		config = new(pluginconfigfakes.FakePluginConfiguration)
+		coreConfig = testconfig.NewRepositoryWithDefaults()
+		fakePluginRepo = new(pluginrepofakes.FakePluginRepo)
 
 		rpc.DefaultServer = rpc.NewServer()
 	})
@@ -64,6 +80,88 @@ var _ = Describe("Plugins", func() {
 		})
 	})
 
+	Context("If --outdated flag is provided", func() {
+		BeforeEach(func() {
+			coreConfig.SetPluginRepo(models.PluginRepo{Name: "repo1", URL: "http://repo1.example.com"})
+
+			config.PluginsReturns(map[string]pluginconfig.PluginMetadata{
+				"Test1": pluginconfig.PluginMetadata{Version: plugin.VersionType{Major: 1, Minor: 2, Build: 3}},
+				"Test2": pluginconfig.PluginMetadata{Version: plugin.VersionType{Major: 2}},
+				"Test3": pluginconfig.PluginMetadata{Version: plugin.VersionType{Major: 1}},
+			})
+		})
+
+		It("lists the installed plugins with newer versions in the repositories", func() {
+			fakePluginRepo.GetPluginsReturns(map[string][]clipr.Plugin{
+				"repo1": []clipr.Plugin{
+					{Name: "Test1", Version: "1.3.0"},
+					{Name: "Test2", Version: "2.0.0"},
+				},
+			}, nil)
+
+			runCommand("--outdated")
+
+			Expect(fakePluginRepo.GetPluginsCallCount()).To(Equal(1))
+			Expect(fakePluginRepo.GetPluginsArgsForCall(0)).To(ContainElement(models.PluginRepo{Name: "repo1", URL: "http://repo1.example.com"}))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Searching", "repo1", "for newer versions of installed plugins..."},
+				[]string{"OK"},
+				[]string{"Plugin Name", "Version", "Latest Version", "Repository"},
+				[]string{"Test1", "1.2.3", "1.3.0", "repo1"},
+				[]string{"update-plugin"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Test2"}))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Test3"}))
+		})
+
+		It("lists the outdated plugins as a structured document", func() {
+			ui.OutputFormat = terminal.JSONOutput
+			fakePluginRepo.GetPluginsReturns(map[string][]clipr.Plugin{
+				"repo1": []clipr.Plugin{{Name: "Test1", Version: "1.3.0"}},
+			}, nil)
+
+			runCommand("--outdated")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{`"Plugin": "Test1"`},
+				[]string{`"Version": "1.2.3"`},
+				[]string{`"LatestVersion": "1.3.0"`},
+				[]string{`"Repository": "repo1"`},
+			))
+		})
+
+		It("says so when all plugins are up to date", func() {
+			fakePluginRepo.GetPluginsReturns(map[string][]clipr.Plugin{}, nil)
+
+			runCommand("--outdated")
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"All installed plugins are up to date"}))
+		})
+
+		It("shows the errors of the repositories", func() {
+			fakePluginRepo.GetPluginsReturns(map[string][]clipr.Plugin{}, []string{"repo error"})
+
+			runCommand("--outdated")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Logged errors:"},
+				[]string{"repo error"},
+			))
+		})
+
+		It("fails when no repositories are registered", func() {
+			for len(coreConfig.PluginRepos()) > 0 {
+				coreConfig.UnSetPluginRepo(0)
+			}
+
+			runCommand("--outdated")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"No plugin repositories are registered."},
+			))
+		})
+	})
+
 	Context("when arguments are provided", func() {
 		var cmd commandregistry.Command
 		var flagContext flags.FlagContext
@@ -108,6 +206,30 @@ var _ = Describe("Plugins", func() {
 		))
 	})
 
+	It("lists the plugin commands as a structured document", func() {
+		ui.OutputFormat = terminal.JSONOutput
+		config.PluginsReturns(map[string]pluginconfig.PluginMetadata{
+			"Test1": pluginconfig.PluginMetadata{
+				Location: "path/to/plugin",
+				Version:  plugin.VersionType{Major: 1, Minor: 2, Build: 3},
+				Commands: []plugin.Command{
+					{Name: "test_1_cmd1", Alias: "t1", HelpText: "help text for test_1_cmd1"},
+				},
+			},
+		})
+
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{`"Plugin": "Test1"`},
+			[]string{`"Version": "1.2.3"`},
+			[]string{`"Command": "test_1_cmd1"`},
+			[]string{`"Alias": "t1"`},
+			[]string{`"HelpText": "help text for test_1_cmd1"`},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Sha1"}))
+	})
+
 	It("lists the name of the command, it's alias and version", func() {
 		config.PluginsReturns(map[string]pluginconfig.PluginMetadata{
 			"Test1": pluginconfig.PluginMetadata{
@@ -168,4 +290,63 @@ var _ = Describe("Plugins", func() {
 			[]string{"Test2", "test_2_cmd1", "help text for test_2_cmd1"},
 		))
 	})
+
+	Context("when cf-NAME executables are on PATH", func() {
+		var (
+			pathDir string
+			oldPath string
+		)
+
+		BeforeEach(func() {
+			var err error
+			pathDir, err = ioutil.TempDir("", "plugins-path-plugins")
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, name := range []string{"cf-deploy", "cf-plugins", "cf-test_1_cmd1"} {
+				Expect(ioutil.WriteFile(filepath.Join(pathDir, name), []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+			}
+			Expect(ioutil.WriteFile(filepath.Join(pathDir, "cf-not-executable"), []byte(""), 0644)).To(Succeed())
+
+			oldPath = os.Getenv("PATH")
+			os.Setenv("PATH", pathDir)
+
+			config.PluginsReturns(map[string]pluginconfig.PluginMetadata{
+				"Test1": {Location: "path/to/plugin1", Commands: []plugin.Command{{Name: "test_1_cmd1"}}},
+			})
+		})
+
+		AfterEach(func() {
+			os.Setenv("PATH", oldPath)
+			os.RemoveAll(pathDir)
+		})
+
+		It("lists their commands separately from the installed plugins", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Test1", "test_1_cmd1"},
+				[]string{"Commands provided by executables on PATH:"},
+				[]string{"deploy", filepath.Join(pathDir, "cf-deploy")},
+			))
+		})
+
+		It("lists their commands in the same structured document as the installed plugins", func() {
+			ui.OutputFormat = terminal.JSONOutput
+
+			runCommand()
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{`"Plugin": "Test1"`},
+				[]string{`"Command": "deploy"`},
+				[]string{`"Location": "` + filepath.Join(pathDir, "cf-deploy") + `"`},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Commands provided by executables on PATH:"}))
+		})
+
+		It("leaves out executables that are not executable or shadowed by other commands", func() {
+			runCommand()
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"cf-not-executable"}))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"cf-plugins"}))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"cf-test_1_cmd1"}))
+		})
+	})
 })
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"net/rpc"
 	"os"
-	"os/exec"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf/commandregistry"
@@ -96,13 +95,18 @@ func (cmd *PluginUninstall) Execute(c flags.FlagContext) {
 }
 
 func (cmd *PluginUninstall) notifyPluginUninstalling(meta pluginconfig.PluginMetadata) error {
-	err := cmd.rpcService.Start()
+	meta, _, err := rpcService.DetectTransport(cmd.rpcService, meta)
+	if err != nil {
+		return err
+	}
+
+	err = rpcService.StartForPlugin(cmd.rpcService, meta)
 	if err != nil {
 		cmd.ui.Failed(err.Error())
 	}
 	defer cmd.rpcService.Stop()
 
-	pluginInvocation := exec.Command(meta.Location, cmd.rpcService.Port(), "CLI-MESSAGE-UNINSTALL")
+	pluginInvocation := cmd.rpcService.PluginCommand(meta.Location, "CLI-MESSAGE-UNINSTALL")
 	pluginInvocation.Stdout = os.Stdout
 
 	return pluginInvocation.Run()
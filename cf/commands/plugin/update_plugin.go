@@ -0,0 +1,290 @@
+package plugin
+
+import (
+	"errors"
+	"net/rpc"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/actors/plugininstaller"
+	"github.com/cloudfoundry/cli/cf/actors/pluginrepo"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/downloader"
+	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/plugin"
+	"github.com/cloudfoundry/cli/utils"
+	"github.com/cloudfoundry/gofileutils/fileutils"
+
+	pluginRPCService "github.com/cloudfoundry/cli/plugin/rpc"
+)
+
+type PluginUpdate struct {
+	ui           terminal.UI
+	config       coreconfig.Reader
+	pluginConfig pluginconfig.PluginConfiguration
+	pluginRepo   pluginrepo.PluginRepo
+	checksum     utils.Sha1Checksum
+	rpcService   *pluginRPCService.CliRpcService
+}
+
+func init() {
+	commandregistry.Register(&PluginUpdate{})
+}
+
+func (cmd *PluginUpdate) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["r"] = &flags.StringFlag{ShortName: "r", Usage: T("Name of a registered repository to look for the new version in")}
+	fs["f"] = &flags.BoolFlag{ShortName: "f", Usage: T("Force update of plugin without confirmation")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "update-plugin",
+		Description: T("Update an installed plugin to the latest version in the plugin repositories"),
+		Usage: []string{
+			T(`CF_NAME update-plugin PLUGIN_NAME [-r REPO_NAME] [-f]
+
+   Looks for the latest version in all registered repositories unless '-r' is provided.
+   Prompts for confirmation unless '-f' is provided.`),
+		},
+		Examples: []string{
+			"CF_NAME update-plugin plugin-echo",
+			"CF_NAME update-plugin -r My-Repo plugin-echo",
+		},
+		Flags:     fs,
+		TotalArgs: 1,
+	}
+}
+
+func (cmd *PluginUpdate) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("Requires PLUGIN_NAME as an argument"),
+		func() bool {
+			return len(fc.Args()) != 1
+		},
+	)
+
+	reqs := []requirements.Requirement{
+		usageReq,
+	}
+	return reqs
+}
+
+func (cmd *PluginUpdate) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	cmd.pluginConfig = deps.PluginConfig
+	cmd.pluginRepo = deps.PluginRepo
+	cmd.checksum = deps.ChecksumUtil
+
+	//reset rpc registration in case there is other running instance,
+	//each service can only be registered once
+	rpc.DefaultServer = rpc.NewServer()
+
+	rpcService, err := pluginRPCService.NewRpcService(deps.TeePrinter, deps.TeePrinter, deps.Config, deps.RepoLocator, pluginRPCService.NewCommandRunner(), deps.Logger)
+	if err != nil {
+		cmd.ui.Failed("Error initializing RPC service: " + err.Error())
+	}
+
+	cmd.rpcService = rpcService
+
+	return cmd
+}
+
+func (cmd *PluginUpdate) Execute(c flags.FlagContext) {
+	pluginName := c.Args()[0]
+
+	installed, found := cmd.pluginConfig.Plugins()[pluginName]
+	if !found {
+		cmd.ui.Failed(T("Plugin name {{.PluginName}} does not exist", map[string]interface{}{"PluginName": pluginName}))
+	}
+
+	repos := registeredPluginRepos(cmd.config)
+	if repoName := c.String("r"); repoName != "" {
+		repo, found := findPluginRepo(repos, repoName)
+		if !found {
+			cmd.ui.Failed(T("{{.RepoName}} does not exist as an available plugin repo.\nTip: use `add-plugin-repo` command to add repos.", map[string]interface{}{"RepoName": repoName}))
+		}
+		repos = []models.PluginRepo{repo}
+	} else if len(repos) == 0 {
+		cmd.ui.Failed(T("No plugin repositories are registered.\nTip: use `add-plugin-repo` command to add repos."))
+	}
+
+	cmd.ui.Say(T("Looking up the latest version of plugin {{.PluginName}}...", map[string]interface{}{"PluginName": terminal.EntityNameColor(pluginName)}))
+
+	repoPlugins, repoErrors := cmd.pluginRepo.GetPlugins(repos)
+	for _, repoError := range repoErrors {
+		cmd.ui.Warn(repoError)
+	}
+
+	latest, repoName, found := pluginrepo.LatestPlugin(repoPlugins, repos, pluginName)
+	if !found {
+		cmd.ui.Failed(T("Plugin {{.PluginName}} is not available in the plugin repositories", map[string]interface{}{"PluginName": pluginName}))
+	}
+
+	latestVersion, _ := pluginrepo.ParseVersion(latest.Version)
+	if !pluginrepo.IsNewerVersion(latestVersion, installed.Version) {
+		cmd.ui.Ok()
+		cmd.ui.Say(T("Plugin {{.PluginName}} v{{.Version}} is already up to date.", map[string]interface{}{"PluginName": pluginName, "Version": formatVersion(installed.Version)}))
+		return
+	}
+
+	if !c.Bool("f") && !cmd.ui.Confirm(T("**Attention: Plugins are binaries written by potentially untrusted authors. Install and use plugins at your own risk.**\n\nDo you want to update the plugin {{.Plugin}} from v{{.Version}} to v{{.LatestVersion}}? (y or n)",
+		map[string]interface{}{"Plugin": pluginName, "Version": formatVersion(installed.Version), "LatestVersion": formatVersion(latestVersion)})) {
+		cmd.ui.Failed(T("Plugin update cancelled"))
+	}
+
+	fileDownloader := downloader.NewDownloader(os.TempDir())
+
+	removeTmpFile := func() {
+		err := fileDownloader.RemoveFile()
+		if err != nil {
+			cmd.ui.Say(T("Problem removing downloaded binary in temp directory: ") + err.Error())
+		}
+	}
+	defer removeTmpFile()
+
+	deps := &plugininstaller.PluginInstallerContext{
+		Checksummer:    cmd.checksum,
+		GetPluginRepos: func() []models.PluginRepo { return repos },
+		FileDownloader: fileDownloader,
+		PluginRepo:     cmd.pluginRepo,
+		RepoName:       repoName,
+		UI:             cmd.ui,
+	}
+	installer := plugininstaller.NewPluginInstaller(deps)
+	pluginSourceFilepath := installer.Install(latest.Name)
+
+	cmd.ui.Say(T("Updating plugin {{.PluginName}}...", map[string]interface{}{"PluginName": terminal.EntityNameColor(pluginName)}))
+
+	backupFilepath := installed.Location + ".old"
+	err := swapPluginBinary(pluginSourceFilepath, installed.Location, backupFilepath)
+	if err != nil {
+		cmd.ui.Failed(T("Could not replace plugin binary: \n{{.Error}}", map[string]interface{}{"Error": err.Error()}))
+	}
+
+	pluginMetadata, err := cmd.runBinaryAndObtainPluginMetadata(installed.Location)
+	if err == nil {
+		err = ensureUpdatedPluginIsSafe(pluginName, pluginMetadata, cmd.pluginConfig.Plugins())
+	}
+	if err != nil {
+		rollbackErr := os.Rename(backupFilepath, installed.Location)
+		if rollbackErr != nil {
+			cmd.ui.Failed(T("The new version of plugin {{.PluginName}} is not usable and the previous version could not be restored from {{.Backup}}:\n{{.Error}}",
+				map[string]interface{}{"PluginName": pluginName, "Backup": backupFilepath, "Error": rollbackErr.Error()}))
+		}
+		cmd.ui.Failed(T("The new version of plugin {{.PluginName}} is not usable, the previous version was restored:\n{{.Error}}",
+			map[string]interface{}{"PluginName": pluginName, "Error": err.Error()}))
+	}
+
+	err = os.Remove(backupFilepath)
+	if err != nil {
+		cmd.ui.Warn(T("Problem removing the previous plugin binary: ") + err.Error())
+	}
+
+	cmd.pluginConfig.SetPlugin(pluginName, pluginconfig.PluginMetadata{
+		Location:         installed.Location,
+		Version:          pluginMetadata.Version,
+		Commands:         pluginMetadata.Commands,
+		AuthenticatedRPC: !cmd.rpcService.RpcCmd.LegacyPlugin,
+		LegacyRPC:        cmd.rpcService.RpcCmd.LegacyPlugin,
+		Hooks:            pluginMetadata.Hooks,
+	})
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Plugin {{.PluginName}} successfully updated to v{{.Version}}.", map[string]interface{}{"PluginName": pluginName, "Version": formatVersion(pluginMetadata.Version)}))
+}
+
+func (cmd *PluginUpdate) runBinaryAndObtainPluginMetadata(location string) (*plugin.PluginMetadata, error) {
+	err := cmd.rpcService.Start()
+	if err != nil {
+		return nil, err
+	}
+	defer cmd.rpcService.Stop()
+
+	err = cmd.rpcService.PluginCommand(location, "SendMetadata").Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd.rpcService.RpcCmd.PluginMetadata, nil
+}
+
+// swapPluginBinary keeps a copy of the installed binary at backupFilepath and
+// renames the new binary over the installed one, so that the plugin is never
+// missing and can be restored by renaming the backup.
+func swapPluginBinary(pluginSourceFilepath, pluginFilepath, backupFilepath string) error {
+	newFilepath := pluginFilepath + ".new"
+
+	err := fileutils.CopyPathToPath(pluginSourceFilepath, newFilepath)
+	if err != nil {
+		return err
+	}
+
+	err = fileutils.CopyPathToPath(pluginFilepath, backupFilepath)
+	if err != nil {
+		os.Remove(newFilepath)
+		return err
+	}
+
+	err = os.Rename(newFilepath, pluginFilepath)
+	if err != nil {
+		os.Remove(newFilepath)
+		os.Remove(backupFilepath)
+		return err
+	}
+
+	return nil
+}
+
+func ensureUpdatedPluginIsSafe(pluginName string, pluginMetadata *plugin.PluginMetadata, plugins map[string]pluginconfig.PluginMetadata) error {
+	if pluginMetadata == nil || pluginMetadata.Name == "" {
+		return errors.New(T("Unable to obtain plugin name for executable {{.Executable}}", map[string]interface{}{"Executable": pluginName}))
+	}
+
+	if pluginMetadata.Name != pluginName {
+		return errors.New(T("The new binary is plugin {{.NewPluginName}} instead of {{.PluginName}}", map[string]interface{}{"NewPluginName": pluginMetadata.Name, "PluginName": pluginName}))
+	}
+
+	if pluginMetadata.Commands == nil {
+		return errors.New(T("Error getting command list from plugin {{.FilePath}}", map[string]interface{}{"FilePath": pluginName}))
+	}
+
+	otherPlugins := map[string]pluginconfig.PluginMetadata{}
+	for name, metadata := range plugins {
+		if name != pluginName {
+			otherPlugins[name] = metadata
+		}
+	}
+
+	if conflict := findCommandConflict(pluginMetadata, otherPlugins); conflict != "" {
+		return errors.New(conflict)
+	}
+
+	return nil
+}
+
+// registeredPluginRepos returns the registered plugin repositories, using
+// https for the community repository the way repo-plugins does.
+func registeredPluginRepos(config coreconfig.Reader) []models.PluginRepo {
+	repos := append([]models.PluginRepo{}, config.PluginRepos()...)
+	for i := range repos {
+		if repos[i].URL == "http://plugins.cloudfoundry.org" {
+			repos[i].URL = "https://plugins.cloudfoundry.org"
+		}
+	}
+	return repos
+}
+
+func findPluginRepo(repos []models.PluginRepo, repoName string) (models.PluginRepo, bool) {
+	for _, repo := range repos {
+		if strings.ToLower(repo.Name) == strings.ToLower(repoName) {
+			return repo, true
+		}
+	}
+	return models.PluginRepo{}, false
+}
@@ -0,0 +1,236 @@
+package plugin_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/actors/pluginrepo/pluginrepofakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig/pluginconfigfakes"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/plugin"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	"github.com/cloudfoundry/cli/utils/utilsfakes"
+
+	clipr "github.com/cloudfoundry-incubator/cli-plugin-repo/models"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("update-plugin", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		config              coreconfig.Repository
+		pluginConfig        *pluginconfigfakes.FakePluginConfiguration
+		fakePluginRepo      *pluginrepofakes.FakePluginRepo
+		fakeChecksum        *utilsfakes.FakeSha1Checksum
+		deps                commandregistry.Dependency
+
+		homeDir         string
+		pluginLocation  string
+		installedBinary []byte
+		servedBinary    []byte
+		testServer      *httptest.Server
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.UI = ui
+		deps.Config = config
+		deps.PluginConfig = pluginConfig
+		deps.PluginRepo = fakePluginRepo
+		deps.ChecksumUtil = fakeChecksum
+		commandregistry.Commands.SetCommand(commandregistry.Commands.FindCommand("update-plugin").SetDependency(deps, pluginCall))
+	}
+
+	fixture := func(name string) []byte {
+		contents, err := ioutil.ReadFile(filepath.Join("..", "..", "..", "fixtures", "plugins", name))
+		Expect(err).NotTo(HaveOccurred())
+		return contents
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		requirementsFactory = &testreq.FakeReqFactory{}
+		config = testconfig.NewRepositoryWithDefaults()
+		config.SetPluginRepo(models.PluginRepo{Name: "repo1", URL: "http://repo1.example.com"})
+		pluginConfig = new(pluginconfigfakes.FakePluginConfiguration)
+		fakePluginRepo = new(pluginrepofakes.FakePluginRepo)
+		fakeChecksum = new(utilsfakes.FakeSha1Checksum)
+		fakeChecksum.CheckSha1Returns(true)
+
+		var err error
+		homeDir, err = ioutil.TempDir("", "update-plugin")
+		Expect(err).NotTo(HaveOccurred())
+
+		installedBinary = fixture("test_1.exe")
+		pluginLocation = filepath.Join(homeDir, "test_1.exe")
+		Expect(ioutil.WriteFile(pluginLocation, installedBinary, 0700)).To(Succeed())
+
+		pluginConfig.PluginsReturns(map[string]pluginconfig.PluginMetadata{
+			"Test1": {
+				Location: pluginLocation,
+				Version:  plugin.VersionType{Major: 1},
+				Commands: []plugin.Command{{Name: "test_1_cmd1"}},
+			},
+		})
+
+		servedBinary = installedBinary
+		testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(servedBinary)
+		}))
+
+		binaries := []clipr.Binary{}
+		for _, platform := range []string{"osx", "win32", "win64", "linux32", "linux64"} {
+			binaries = append(binaries, clipr.Binary{Platform: platform, Url: testServer.URL + "/test_1.exe"})
+		}
+		fakePluginRepo.GetPluginsReturns(map[string][]clipr.Plugin{
+			"repo1": []clipr.Plugin{{Name: "Test1", Version: "1.2.4", Binaries: binaries}},
+		}, nil)
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+		os.RemoveAll(homeDir)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCLICommand("update-plugin", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails with usage when not provided a plugin name", func() {
+			Expect(runCommand()).ToNot(HavePassedRequirements())
+		})
+	})
+
+	It("fails when the plugin is not installed", func() {
+		runCommand("Unknown", "-f")
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Plugin name Unknown does not exist"},
+		))
+	})
+
+	It("fails when the repository given with -r is not registered", func() {
+		runCommand("Test1", "-r", "unknown-repo", "-f")
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"unknown-repo does not exist as an available plugin repo."},
+		))
+		Expect(fakePluginRepo.GetPluginsCallCount()).To(Equal(0))
+	})
+
+	It("only looks in the repository given with -r, ignoring case", func() {
+		runCommand("Test1", "-r", "REPO1", "-f")
+
+		Expect(fakePluginRepo.GetPluginsArgsForCall(0)).To(Equal([]models.PluginRepo{{Name: "repo1", URL: "http://repo1.example.com"}}))
+	})
+
+	It("fails when the plugin is not in the repositories", func() {
+		fakePluginRepo.GetPluginsReturns(map[string][]clipr.Plugin{}, []string{"repo error"})
+
+		runCommand("Test1", "-f")
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"repo error"},
+			[]string{"Plugin Test1 is not available in the plugin repositories"},
+		))
+	})
+
+	It("does nothing when the installed version is the latest", func() {
+		fakePluginRepo.GetPluginsReturns(map[string][]clipr.Plugin{
+			"repo1": []clipr.Plugin{{Name: "Test1", Version: "1.0.0"}},
+		}, nil)
+
+		runCommand("Test1", "-f")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"OK"},
+			[]string{"Plugin Test1 v1.0.0 is already up to date."},
+		))
+		Expect(pluginConfig.SetPluginCallCount()).To(Equal(0))
+	})
+
+	It("asks for confirmation unless -f is provided", func() {
+		ui.Inputs = []string{"n"}
+
+		runCommand("Test1")
+		Expect(ui.Prompts).To(ContainSubstrings(
+			[]string{"Do you want to update the plugin Test1 from v1.0.0 to v1.2.4?"},
+		))
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"Plugin update cancelled"}))
+		Expect(pluginConfig.SetPluginCallCount()).To(Equal(0))
+	})
+
+	It("downloads, verifies and installs the new version", func() {
+		runCommand("Test1", "-f")
+
+		Expect(fakeChecksum.CheckSha1CallCount()).To(Equal(1))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Updating plugin Test1..."},
+			[]string{"OK"},
+			[]string{"Plugin Test1 successfully updated to v1.2.4."},
+		))
+
+		Expect(pluginConfig.SetPluginCallCount()).To(Equal(1))
+		name, metadata := pluginConfig.SetPluginArgsForCall(0)
+		Expect(name).To(Equal("Test1"))
+		Expect(metadata.Location).To(Equal(pluginLocation))
+		Expect(metadata.Version).To(Equal(plugin.VersionType{Major: 1, Minor: 2, Build: 4}))
+		Expect(metadata.Commands).NotTo(BeEmpty())
+
+		Expect(pluginLocation + ".old").NotTo(BeAnExistingFile())
+		Expect(pluginLocation + ".new").NotTo(BeAnExistingFile())
+	})
+
+	It("fails when the checksum of the download does not match", func() {
+		fakeChecksum.CheckSha1Returns(false)
+
+		runCommand("Test1", "-f")
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"checksum does not match"}))
+		Expect(ioutil.ReadFile(pluginLocation)).To(Equal(installedBinary))
+	})
+
+	Context("when the new binary does not answer the metadata handshake", func() {
+		BeforeEach(func() {
+			servedBinary = []byte("not a plugin")
+		})
+
+		It("restores the previous binary", func() {
+			runCommand("Test1", "-f")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"The new version of plugin Test1 is not usable, the previous version was restored"},
+			))
+			Expect(ioutil.ReadFile(pluginLocation)).To(Equal(installedBinary))
+			Expect(pluginLocation + ".old").NotTo(BeAnExistingFile())
+			Expect(pluginConfig.SetPluginCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the new binary is a different plugin", func() {
+		BeforeEach(func() {
+			servedBinary = fixture("test_2.exe")
+		})
+
+		It("restores the previous binary", func() {
+			runCommand("Test1", "-f")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"The new binary is plugin Uninstall-Test instead of Test1"},
+			))
+			Expect(ioutil.ReadFile(pluginLocation)).To(Equal(installedBinary))
+			Expect(pluginConfig.SetPluginCallCount()).To(Equal(0))
+		})
+	})
+})
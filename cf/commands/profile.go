@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type Profile struct {
+	ui     terminal.UI
+	config coreconfig.ReadWriter
+}
+
+func init() {
+	commandregistry.Register(&Profile{})
+}
+
+func (cmd *Profile) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "profile",
+		Description: T("Switch to or delete a profile"),
+		Usage: []string{
+			T("Switch to a profile, creating it if it does not exist"),
+			":\n   CF_NAME profile use NAME\n\n   ",
+			T("Delete a profile"),
+			":\n   CF_NAME profile delete NAME",
+		},
+		Examples: []string{
+			"CF_NAME profile use staging",
+			"CF_NAME login -a https://api.staging.example.com",
+			"CF_NAME --profile prod apps",
+		},
+	}
+}
+
+func (cmd *Profile) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("Requires 'use' or 'delete' and a profile name as arguments"),
+		func() bool {
+			return len(fc.Args()) != 2 || (fc.Args()[0] != "use" && fc.Args()[0] != "delete")
+		},
+	)
+
+	return []requirements.Requirement{usageReq}
+}
+
+func (cmd *Profile) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	return cmd
+}
+
+func (cmd *Profile) Execute(c flags.FlagContext) {
+	name := c.Args()[1]
+
+	if c.Args()[0] == "delete" {
+		cmd.deleteProfile(name)
+	} else {
+		cmd.useProfile(name)
+	}
+}
+
+func (cmd *Profile) useProfile(name string) {
+	if cmd.hasProfile(name) {
+		cmd.ui.Say(T("Switching to profile {{.Name}}...", map[string]interface{}{"Name": terminal.EntityNameColor(name)}))
+	} else {
+		cmd.ui.Say(T("Creating profile {{.Name}}...", map[string]interface{}{"Name": terminal.EntityNameColor(name)}))
+	}
+
+	cmd.config.UseProfile(name)
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+	cmd.ui.ShowConfiguration(cmd.config)
+}
+
+func (cmd *Profile) deleteProfile(name string) {
+	cmd.ui.Say(T("Deleting profile {{.Name}}...", map[string]interface{}{"Name": terminal.EntityNameColor(name)}))
+
+	if name == cmd.config.ProfileName() {
+		cmd.ui.Failed(T("Profile {{.Name}} is in use. Switch to another profile before deleting it.", map[string]interface{}{"Name": name}))
+	}
+
+	if !cmd.hasProfile(name) {
+		cmd.ui.Ok()
+		cmd.ui.Warn(T("Profile {{.Name}} does not exist.", map[string]interface{}{"Name": name}))
+		return
+	}
+
+	cmd.config.DeleteProfile(name)
+	cmd.ui.Ok()
+}
+
+func (cmd *Profile) hasProfile(name string) bool {
+	for _, profile := range cmd.config.Profiles() {
+		if profile.Name == name {
+			return true
+		}
+	}
+	return false
+}
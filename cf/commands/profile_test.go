@@ -0,0 +1,120 @@
+package commands_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/requirements/requirementsfakes"
+	"github.com/cloudfoundry/cli/flags"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profile", func() {
+	var (
+		ui          *testterm.FakeUI
+		config      coreconfig.Repository
+		cmd         commandregistry.Command
+		factory     *requirementsfakes.FakeFactory
+		flagContext flags.FlagContext
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepository()
+		config.SetAPIEndpoint("https://api.dev.example.com")
+
+		cmd = &commands.Profile{}
+		cmd.SetDependency(commandregistry.Dependency{UI: ui, Config: config}, false)
+
+		flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+		factory = new(requirementsfakes.FakeFactory)
+	})
+
+	Describe("Requirements", func() {
+		It("fails with usage without a subcommand and a name", func() {
+			flagContext.Parse("use")
+
+			reqs := cmd.Requirements(factory, flagContext)
+			err := reqs[0].Execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Incorrect Usage. Requires 'use' or 'delete' and a profile name as arguments"))
+		})
+
+		It("fails with usage with an unknown subcommand", func() {
+			flagContext.Parse("rename", "prod")
+
+			reqs := cmd.Requirements(factory, flagContext)
+			Expect(reqs[0].Execute()).NotTo(Succeed())
+		})
+	})
+
+	Describe("use", func() {
+		It("creates and switches to a new profile", func() {
+			flagContext.Parse("use", "prod")
+			cmd.Execute(flagContext)
+
+			Expect(config.ProfileName()).To(Equal("prod"))
+			Expect(config.APIEndpoint()).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Creating profile prod..."},
+				[]string{"OK"},
+			))
+			Expect(ui.ShowConfigurationCalled).To(BeTrue())
+		})
+
+		It("switches to an existing profile", func() {
+			config.UseProfile("prod")
+
+			flagContext.Parse("use", "default")
+			cmd.Execute(flagContext)
+
+			Expect(config.ProfileName()).To(Equal("default"))
+			Expect(config.APIEndpoint()).To(Equal("https://api.dev.example.com"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Switching to profile default..."},
+				[]string{"OK"},
+			))
+		})
+	})
+
+	Describe("delete", func() {
+		It("deletes the profile", func() {
+			config.UseProfile("prod")
+			config.UseProfile("default")
+
+			flagContext.Parse("delete", "prod")
+			cmd.Execute(flagContext)
+
+			Expect(config.Profiles()).To(HaveLen(1))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Deleting profile prod..."},
+				[]string{"OK"},
+			))
+		})
+
+		It("warns when the profile does not exist", func() {
+			flagContext.Parse("delete", "prod")
+			cmd.Execute(flagContext)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"OK"},
+				[]string{"Profile prod does not exist."},
+			))
+		})
+
+		It("fails to delete the profile in use", func() {
+			flagContext.Parse("delete", "default")
+
+			Expect(func() { cmd.Execute(flagContext) }).To(Panic())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Profile default is in use"},
+			))
+		})
+	})
+})
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type Profiles struct {
+	ui     terminal.UI
+	config coreconfig.Reader
+}
+
+func init() {
+	commandregistry.Register(&Profiles{})
+}
+
+func (cmd *Profiles) MetaData() commandregistry.CommandMetadata {
+	return commandregistry.CommandMetadata{
+		Name:        "profiles",
+		Description: T("List profiles, each with its own API endpoint, login and target"),
+		Usage: []string{
+			T("CF_NAME profiles"),
+		},
+	}
+}
+
+func (cmd *Profiles) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("No argument required"),
+		func() bool {
+			return len(fc.Args()) != 0
+		},
+	)
+
+	return []requirements.Requirement{usageReq}
+}
+
+// profileRecord is what --output json|yaml shows of a profile, leaving
+// out its tokens.
+type profileRecord struct {
+	Name        string
+	Current     bool
+	APIEndpoint string
+	User        string
+	Org         string
+	Space       string
+}
+
+func (cmd *Profiles) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.config = deps.Config
+	return cmd
+}
+
+func (cmd *Profiles) Execute(c flags.FlagContext) {
+	current := cmd.config.ProfileName()
+
+	table := cmd.ui.Table([]string{"", T("name"), T("api endpoint"), T("user"), T("org"), T("space")})
+
+	for _, profile := range cmd.config.Profiles() {
+		marker := ""
+		if profile.Name == current {
+			marker = "*"
+		}
+
+		record := profileRecord{
+			Name:        profile.Name,
+			Current:     profile.Name == current,
+			APIEndpoint: profile.Target,
+			User:        coreconfig.NewTokenInfo(profile.AccessToken).Username,
+			Org:         profile.OrganizationFields.Name,
+			Space:       profile.SpaceFields.Name,
+		}
+
+		table.AddRecord(record,
+			marker,
+			record.Name,
+			record.APIEndpoint,
+			record.User,
+			record.Org,
+			record.Space,
+		)
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	table.Print()
+}
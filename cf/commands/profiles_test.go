@@ -0,0 +1,89 @@
+package commands_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements/requirementsfakes"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profiles", func() {
+	var (
+		ui          *testterm.FakeUI
+		config      coreconfig.Repository
+		cmd         commandregistry.Command
+		factory     *requirementsfakes.FakeFactory
+		flagContext flags.FlagContext
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		config = testconfig.NewRepository()
+
+		cmd = &commands.Profiles{}
+		cmd.SetDependency(commandregistry.Dependency{UI: ui, Config: config}, false)
+
+		flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+		factory = new(requirementsfakes.FakeFactory)
+	})
+
+	Describe("Requirements", func() {
+		It("fails with usage when given an argument", func() {
+			flagContext.Parse("extra-arg")
+
+			reqs := cmd.Requirements(factory, flagContext)
+			err := reqs[0].Execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Incorrect Usage. No argument required"))
+		})
+	})
+
+	Describe("Execute", func() {
+		BeforeEach(func() {
+			accessToken, err := testconfig.EncodeAccessToken(coreconfig.TokenInfo{Username: "prod-user"})
+			Expect(err).NotTo(HaveOccurred())
+
+			config.SetAPIEndpoint("https://api.dev.example.com")
+			config.UseProfile("prod")
+			config.SetAPIEndpoint("https://api.prod.example.com")
+			config.SetAccessToken(accessToken)
+			config.SetOrganizationFields(models.OrganizationFields{GUID: "org-guid", Name: "prod-org"})
+			config.SetSpaceFields(models.SpaceFields{GUID: "space-guid", Name: "prod-space"})
+		})
+
+		It("lists every profile and marks the one in use", func() {
+			cmd.Execute(flagContext)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"OK"},
+				[]string{"name", "api endpoint", "user", "org", "space"},
+				[]string{"default", "https://api.dev.example.com"},
+				[]string{"*", "prod", "https://api.prod.example.com", "prod-user", "prod-org", "prod-space"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"*", "default"}))
+		})
+
+		It("lists the profiles without their tokens as a structured document", func() {
+			ui.OutputFormat = terminal.JSONOutput
+
+			cmd.Execute(flagContext)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{`"Name": "prod"`},
+				[]string{`"Current": true`},
+				[]string{`"APIEndpoint": "https://api.prod.example.com"`},
+				[]string{`"User": "prod-user"`},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Token"}))
+		})
+	})
+})
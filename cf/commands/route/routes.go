@@ -107,7 +107,7 @@ func (cmd *ListRoutes) Execute(c flags.FlagContext) {
 
 		domain := d[route.Domain.GUID]
 
-		table.Add(
+		table.AddRecord(route,
 			route.Space.Name,
 			route.Host,
 			route.Domain.Name,
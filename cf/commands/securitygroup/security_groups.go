@@ -70,18 +70,18 @@ func (cmd *SecurityGroups) Execute(c flags.FlagContext) {
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
+	table := cmd.ui.Table([]string{"", T("Name"), T("Organization"), T("Space")})
+
 	if len(securityGroups) == 0 {
-		cmd.ui.Say(T("No security groups"))
+		table.PrintNoRecords(T("No security groups"))
 		return
 	}
 
-	table := cmd.ui.Table([]string{"", T("Name"), T("Organization"), T("Space")})
-
 	for index, securityGroup := range securityGroups {
 		if len(securityGroup.Spaces) > 0 {
 			cmd.printSpaces(table, securityGroup, index)
 		} else {
-			table.Add(fmt.Sprintf("#%d", index), securityGroup.Name, "", "")
+			table.AddRecord(securityGroup, fmt.Sprintf("#%d", index), securityGroup.Name, "", "")
 		}
 	}
 	table.Print()
@@ -89,6 +89,7 @@ func (cmd *SecurityGroups) Execute(c flags.FlagContext) {
 
 type table interface {
 	Add(row ...string)
+	AddRecord(record interface{}, row ...string)
 	Print()
 }
 
@@ -97,7 +98,7 @@ func (cmd SecurityGroups) printSpaces(table table, securityGroup models.Security
 
 	for _, space := range securityGroup.Spaces {
 		if !outputted_index {
-			table.Add(fmt.Sprintf("#%d", index), securityGroup.Name, space.Organization.Name, space.Name)
+			table.AddRecord(securityGroup, fmt.Sprintf("#%d", index), securityGroup.Name, space.Organization.Name, space.Name)
 			outputted_index = true
 		} else {
 			table.Add("", securityGroup.Name, space.Organization.Name, space.Name)
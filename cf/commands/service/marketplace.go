@@ -102,12 +102,12 @@ func (cmd MarketplaceServices) marketplaceByService(serviceName string) {
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
+	table := cmd.ui.Table([]string{T("service plan"), T("description"), T("free or paid")})
+
 	if serviceOffering.GUID == "" {
-		cmd.ui.Say(T("Service offering not found"))
+		table.PrintNoRecords(T("Service offering not found"))
 		return
 	}
-
-	table := cmd.ui.Table([]string{T("service plan"), T("description"), T("free or paid")})
 	for _, plan := range serviceOffering.Plans {
 		var freeOrPaid string
 		if plan.Free {
@@ -115,7 +115,7 @@ func (cmd MarketplaceServices) marketplaceByService(serviceName string) {
 		} else {
 			freeOrPaid = "paid"
 		}
-		table.Add(plan.Name, plan.Description, freeOrPaid)
+		table.AddRecord(plan, plan.Name, plan.Description, freeOrPaid)
 	}
 
 	table.Print()
@@ -150,13 +150,13 @@ func (cmd MarketplaceServices) marketplace() {
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
+	table := cmd.ui.Table([]string{T("service"), T("plans"), T("description")})
+
 	if len(serviceOfferings) == 0 {
-		cmd.ui.Say(T("No service offerings found"))
+		table.PrintNoRecords(T("No service offerings found"))
 		return
 	}
 
-	table := cmd.ui.Table([]string{T("service"), T("plans"), T("description")})
-
 	sort.Sort(serviceOfferings)
 	var paidPlanExists bool
 	for _, offering := range serviceOfferings {
@@ -176,7 +176,7 @@ func (cmd MarketplaceServices) marketplace() {
 
 		planNames = strings.TrimPrefix(planNames, ", ")
 
-		table.Add(offering.Label, planNames, offering.Description)
+		table.AddRecord(offering, offering.Label, planNames, offering.Description)
 	}
 
 	table.Print()
@@ -81,13 +81,13 @@ func (cmd *ListServices) Execute(fc flags.FlagContext) {
 	cmd.ui.Ok()
 	cmd.ui.Say("")
 
+	table := cmd.ui.Table([]string{T("name"), T("service"), T("plan"), T("bound apps"), T("last operation")})
+
 	if len(serviceInstances) == 0 {
-		cmd.ui.Say(T("No services found"))
+		table.PrintNoRecords(T("No services found"))
 		return
 	}
 
-	table := cmd.ui.Table([]string{T("name"), T("service"), T("plan"), T("bound apps"), T("last operation")})
-
 	for _, instance := range serviceInstances {
 		var serviceColumn string
 		var serviceStatus string
@@ -99,7 +99,7 @@ func (cmd *ListServices) Execute(fc flags.FlagContext) {
 		}
 		serviceStatus = ServiceInstanceStateToStatus(instance.LastOperation.Type, instance.LastOperation.State, instance.IsUserProvided())
 
-		table.Add(
+		table.AddRecord(instance,
 			instance.Name,
 			serviceColumn,
 			instance.ServicePlan.Name,
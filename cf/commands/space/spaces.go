@@ -72,7 +72,7 @@ func (cmd *ListSpaces) Execute(c flags.FlagContext) {
 	foundSpaces := false
 	table := cmd.ui.Table([]string{T("name")})
 	apiErr := cmd.spaceRepo.ListSpaces(func(space models.Space) bool {
-		table.Add(space.Name)
+		table.AddRecord(space, space.Name)
 		foundSpaces = true
 
 		if cmd.pluginCall {
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+type ValidateManifest struct {
+	ui           terminal.UI
+	manifestRepo manifest.ManifestRepository
+}
+
+func init() {
+	commandregistry.Register(&ValidateManifest{})
+}
+
+func (cmd *ValidateManifest) MetaData() commandregistry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["f"] = &flags.StringFlag{ShortName: "f", Usage: T("Path to manifest. If not specified, the manifest in the current working directory is validated.")}
+
+	return commandregistry.CommandMetadata{
+		Name:        "validate-manifest",
+		Description: T("Check a manifest for errors without pushing any apps"),
+		Usage: []string{
+			T("CF_NAME validate-manifest [-f MANIFEST_PATH]"),
+		},
+		Flags: fs,
+	}
+}
+
+func (cmd *ValidateManifest) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	usageReq := requirements.NewUsageRequirement(commandregistry.CLICommandUsagePresenter(cmd),
+		T("No argument required"),
+		func() bool {
+			return len(fc.Args()) != 0
+		},
+	)
+
+	return []requirements.Requirement{usageReq}
+}
+
+func (cmd *ValidateManifest) SetDependency(deps commandregistry.Dependency, pluginCall bool) commandregistry.Command {
+	cmd.ui = deps.UI
+	cmd.manifestRepo = deps.ManifestRepo
+	return cmd
+}
+
+func (cmd *ValidateManifest) Execute(c flags.FlagContext) {
+	path := c.String("f")
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			cmd.ui.Failed(T("Could not determine the current working directory!"))
+		}
+	}
+
+	m, err := cmd.manifestRepo.ReadManifest(path)
+	if err != nil {
+		cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	cmd.ui.Say(T("Validating manifest file {{.Path}}...", map[string]interface{}{"Path": terminal.EntityNameColor(m.Path)}))
+
+	validationErrs := m.Validate()
+	for _, warning := range validationErrs.Warnings() {
+		cmd.ui.Warn(warning.Error())
+	}
+
+	errs := validationErrs.Errors()
+	if len(errs) > 0 {
+		for _, err := range errs {
+			cmd.ui.Say(err.Error())
+		}
+		cmd.ui.Failed(T("Found {{.Count}} problem(s) in the manifest", map[string]interface{}{"Count": len(errs)}))
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Manifest is valid"))
+}
@@ -0,0 +1,91 @@
+package commands_test
+
+import (
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/commands"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/requirements/requirementsfakes"
+	"github.com/cloudfoundry/cli/flags"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateManifest", func() {
+	var (
+		ui          *testterm.FakeUI
+		cmd         commandregistry.Command
+		deps        commandregistry.Dependency
+		factory     *requirementsfakes.FakeFactory
+		flagContext flags.FlagContext
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		deps = commandregistry.Dependency{
+			UI:           ui,
+			ManifestRepo: manifest.NewManifestDiskRepository(),
+		}
+
+		cmd = &commands.ValidateManifest{}
+		cmd.SetDependency(deps, false)
+
+		flagContext = flags.NewFlagContext(cmd.MetaData().Flags)
+		factory = new(requirementsfakes.FakeFactory)
+	})
+
+	Describe("Requirements", func() {
+		It("fails with usage when given an argument", func() {
+			flagContext.Parse("extra-arg")
+
+			reqs := cmd.Requirements(factory, flagContext)
+			err := reqs[0].Execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Incorrect Usage. No argument required"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("says the manifest is valid when it has no problems", func() {
+			flagContext.Parse("-f", "../../fixtures/manifests/inherited-manifest.yml")
+
+			cmd.Execute(flagContext)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Validating manifest file", "inherited-manifest.yml"},
+				[]string{"OK"},
+				[]string{"Manifest is valid"},
+			))
+		})
+
+		It("lists every problem with its location", func() {
+			path := filepath.Clean("../../fixtures/manifests/invalid/manifest.yml")
+			flagContext.Parse("-f", path)
+
+			Expect(func() { cmd.Execute(flagContext) }).To(Panic())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{path + ":7:3", "'framework' is deprecated"},
+				[]string{path + ":2:1", "Unknown property 'memroy' will be ignored"},
+				[]string{path + ":15:3", "Application 'web' is defined more than once"},
+				[]string{"FAILED"},
+				[]string{"Found 6 problem(s) in the manifest"},
+			))
+		})
+
+		It("fails when the manifest cannot be read", func() {
+			flagContext.Parse("-f", "../../fixtures/manifests/does-not-exist.yml")
+
+			Expect(func() { cmd.Execute(flagContext) }).To(Panic())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Error reading manifest file"},
+			))
+		})
+	})
+})
@@ -0,0 +1,263 @@
+package completion
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/flags"
+)
+
+// ValueKind names the kind of value an argument or flag takes when the values
+// have to be looked up, such as the names of the apps in the targeted space.
+type ValueKind string
+
+const (
+	NoValues     ValueKind = ""
+	AppNames     ValueKind = "app"
+	ServiceNames ValueKind = "service"
+	SpaceNames   ValueKind = "space"
+	OrgNames     ValueKind = "org"
+	CommandNames ValueKind = "command"
+)
+
+var placeholderKinds = map[string]ValueKind{
+	"APP":              AppNames,
+	"APP_NAME":         AppNames,
+	"SERVICE":          ServiceNames,
+	"SERVICE_INSTANCE": ServiceNames,
+	"SPACE":            SpaceNames,
+	"SPACE_NAME":       SpaceNames,
+	"ORG":              OrgNames,
+	"ORG_NAME":         OrgNames,
+	"COMMAND":          CommandNames,
+}
+
+// Command describes what can be completed for one command.
+type Command struct {
+	Name        string
+	ShortName   string
+	Description string
+	Flags       []Flag
+	Args        []Arg
+}
+
+// Flag is a flag of a command, with each of the ways it can be written.
+type Flag struct {
+	Names      []string
+	Usage      string
+	TakesValue bool
+	Values     ValueKind
+}
+
+// Arg is a positional argument of a command. It takes either fixed words,
+// such as the 'use' and 'delete' of profile, or values looked up by kind.
+type Arg struct {
+	Kind  ValueKind
+	Words []string
+}
+
+type commandsByName []Command
+
+func (c commandsByName) Len() int           { return len(c) }
+func (c commandsByName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c commandsByName) Less(i, j int) bool { return c[i].Name < c[j].Name }
+
+type flagsByName []Flag
+
+func (f flagsByName) Len() int           { return len(f) }
+func (f flagsByName) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f flagsByName) Less(i, j int) bool { return f[i].Names[0] < f[j].Names[0] }
+
+// NewCommands describes the visible commands in metadatas and the commands
+// of the installed plugins, sorted by name.
+func NewCommands(metadatas []commandregistry.CommandMetadata, plugins map[string]pluginconfig.PluginMetadata) []Command {
+	commands := []Command{}
+
+	for _, metadata := range metadatas {
+		if metadata.Hidden {
+			continue
+		}
+		commands = append(commands, newCommand(metadata))
+	}
+
+	for _, plugin := range plugins {
+		for _, pluginCommand := range plugin.Commands {
+			commands = append(commands, newPluginCommand(pluginCommand.Name, pluginCommand.Alias, pluginCommand.HelpText, pluginCommand.UsageDetails.Options))
+		}
+	}
+
+	sort.Sort(commandsByName(commands))
+	return commands
+}
+
+func newCommand(metadata commandregistry.CommandMetadata) Command {
+	command := Command{
+		Name:        metadata.Name,
+		ShortName:   metadata.ShortName,
+		Description: metadata.Description,
+	}
+
+	usage := parseUsage(metadata.Name, strings.Join(metadata.Usage, ""))
+	command.Args = usage.args
+
+	for _, flagSet := range metadata.Flags {
+		if !flagSet.Visible() {
+			continue
+		}
+
+		flag := Flag{Usage: flagSet.String()}
+		if flagSet.GetName() != "" {
+			flag.Names = append(flag.Names, "--"+flagSet.GetName())
+		}
+		if flagSet.GetShortName() != "" {
+			flag.Names = append(flag.Names, "-"+flagSet.GetShortName())
+		}
+		if len(flag.Names) == 0 {
+			continue
+		}
+
+		_, isBool := flagSet.(*flags.BoolFlag)
+		flag.TakesValue = !isBool
+		for _, name := range flag.Names {
+			if kind, found := usage.flagValues[name]; found {
+				flag.Values = kind
+			}
+		}
+
+		command.Flags = append(command.Flags, flag)
+	}
+
+	sort.Sort(flagsByName(command.Flags))
+	return command
+}
+
+func newPluginCommand(name, alias, helpText string, options map[string]string) Command {
+	command := Command{
+		Name:        name,
+		ShortName:   alias,
+		Description: helpText,
+	}
+
+	for option, usage := range options {
+		command.Flags = append(command.Flags, Flag{Names: []string{"-" + option}, Usage: usage})
+	}
+
+	sort.Sort(flagsByName(command.Flags))
+	return command
+}
+
+// Find returns the command with the given name or short name.
+func Find(commands []Command, name string) (Command, bool) {
+	for _, command := range commands {
+		if command.Name == name || (command.ShortName != "" && command.ShortName == name) {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+// Flag returns the flag of the command written as arg, which may include an
+// '=' and a value.
+func (command Command) Flag(arg string) (Flag, bool) {
+	arg = strings.SplitN(arg, "=", 2)[0]
+	for _, flag := range command.Flags {
+		for _, name := range flag.Names {
+			if name == arg {
+				return flag, true
+			}
+		}
+	}
+	return Flag{}, false
+}
+
+// FlagNames returns every way of writing the flags of the command.
+func (command Command) FlagNames() []string {
+	names := []string{}
+	for _, flag := range command.Flags {
+		names = append(names, flag.Names...)
+	}
+	return names
+}
+
+type usage struct {
+	args       []Arg
+	flagValues map[string]ValueKind
+}
+
+// parseUsage finds the lines of the usage text that show how to call the
+// command, such as 'CF_NAME bind-service APP_NAME SERVICE_INSTANCE [-c
+// PARAMETERS_AS_JSON]', and works out what each argument takes from the
+// placeholders. Usage lines that offer alternatives are merged by position.
+func parseUsage(name string, text string) usage {
+	parsed := usage{flagValues: map[string]ValueKind{}}
+	prefix := "CF_NAME " + name
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != prefix && !strings.HasPrefix(line, prefix+" ") {
+			continue
+		}
+
+		tokens := strings.Fields(strings.TrimPrefix(line, prefix))
+		position := -1
+		alternative := false
+
+		for i := 0; i < len(tokens); i++ {
+			token := strings.Trim(tokens[i], "[]().")
+			switch {
+			case token == "":
+				continue
+			case token == "|":
+				alternative = true
+				continue
+			case strings.HasPrefix(token, "-"):
+				if i+1 < len(tokens) {
+					next := strings.Trim(tokens[i+1], "[]().")
+					if isPlaceholder(next) {
+						if kind := placeholderKinds[next]; kind != CommandNames {
+							parsed.flagValues[token] = kind
+						}
+						i++
+					}
+				}
+				alternative = false
+				continue
+			}
+
+			if !alternative || position < 0 {
+				position++
+			}
+			alternative = false
+
+			for len(parsed.args) <= position {
+				parsed.args = append(parsed.args, Arg{})
+			}
+
+			arg := &parsed.args[position]
+			if isPlaceholder(token) {
+				if arg.Kind == NoValues {
+					arg.Kind = placeholderKinds[token]
+				}
+			} else if !containsString(arg.Words, token) {
+				arg.Words = append(arg.Words, token)
+			}
+		}
+	}
+
+	return parsed
+}
+
+func isPlaceholder(token string) bool {
+	return token != "" && token == strings.ToUpper(token) && strings.ToLower(token) != token
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package completion_test
+
+import (
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	. "github.com/cloudfoundry/cli/cf/completion"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/plugin"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewCommands", func() {
+	var (
+		metadatas []commandregistry.CommandMetadata
+		plugins   map[string]pluginconfig.PluginMetadata
+		commands  []Command
+	)
+
+	BeforeEach(func() {
+		metadatas = []commandregistry.CommandMetadata{
+			{
+				Name:        "target",
+				ShortName:   "t",
+				Description: "Set or view the targeted org or space",
+				Usage:       []string{"CF_NAME target [-o ORG] [-s SPACE]"},
+				Flags: map[string]flags.FlagSet{
+					"o": &flags.StringFlag{ShortName: "o", Usage: "Organization"},
+					"s": &flags.StringFlag{ShortName: "s", Usage: "Space"},
+				},
+			},
+			{
+				Name:        "bind-service",
+				ShortName:   "bs",
+				Description: "Bind a service instance to an app",
+				Usage:       []string{"CF_NAME bind-service APP_NAME SERVICE_INSTANCE [-c PARAMETERS_AS_JSON]"},
+				Flags: map[string]flags.FlagSet{
+					"c": &flags.StringFlag{ShortName: "c", Usage: "Valid JSON object"},
+				},
+			},
+			{
+				Name:        "profile",
+				Description: "Switch to or delete a profile",
+				Usage: []string{
+					"Switch to a profile",
+					":\n   CF_NAME profile use NAME\n\n   ",
+					"Delete a profile",
+					":\n   CF_NAME profile delete NAME",
+				},
+			},
+			{
+				Name:        "delete",
+				ShortName:   "d",
+				Description: "Delete an app",
+				Usage:       []string{"CF_NAME delete APP_NAME [-f] [-r]"},
+				Flags: map[string]flags.FlagSet{
+					"f":      &flags.BoolFlag{ShortName: "f", Usage: "Force deletion without confirmation"},
+					"r":      &flags.BoolFlag{ShortName: "r", Usage: "Also delete any mapped routes"},
+					"secret": &flags.BoolFlag{Name: "secret", Hidden: true},
+				},
+			},
+			{
+				Name:   "hidden",
+				Usage:  []string{"CF_NAME hidden"},
+				Hidden: true,
+			},
+		}
+
+		plugins = map[string]pluginconfig.PluginMetadata{
+			"Test1": {
+				Commands: []plugin.Command{
+					{
+						Name:     "test1_cmd",
+						Alias:    "t1",
+						HelpText: "a test command",
+						UsageDetails: plugin.Usage{
+							Options: map[string]string{"flag": "a flag"},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		commands = NewCommands(metadatas, plugins)
+	})
+
+	It("includes the visible commands and the plugin commands sorted by name", func() {
+		names := []string{}
+		for _, command := range commands {
+			names = append(names, command.Name)
+		}
+		Expect(names).To(Equal([]string{"bind-service", "delete", "profile", "target", "test1_cmd"}))
+	})
+
+	It("finds commands by name and short name", func() {
+		command, found := Find(commands, "bs")
+		Expect(found).To(BeTrue())
+		Expect(command.Name).To(Equal("bind-service"))
+
+		command, found = Find(commands, "t1")
+		Expect(found).To(BeTrue())
+		Expect(command.Name).To(Equal("test1_cmd"))
+
+		_, found = Find(commands, "hidden")
+		Expect(found).To(BeFalse())
+	})
+
+	It("works out what the arguments take from the usage", func() {
+		command, _ := Find(commands, "bind-service")
+		Expect(command.Args).To(Equal([]Arg{
+			{Kind: AppNames},
+			{Kind: ServiceNames},
+		}))
+	})
+
+	It("merges the fixed words of usage lines that offer alternatives", func() {
+		command, _ := Find(commands, "profile")
+		Expect(command.Args).To(Equal([]Arg{
+			{Words: []string{"use", "delete"}},
+			{},
+		}))
+	})
+
+	It("works out what the flags take from the usage", func() {
+		command, _ := Find(commands, "target")
+		Expect(command.Flags).To(Equal([]Flag{
+			{Names: []string{"-o"}, Usage: "Organization", TakesValue: true, Values: OrgNames},
+			{Names: []string{"-s"}, Usage: "Space", TakesValue: true, Values: SpaceNames},
+		}))
+	})
+
+	It("leaves out hidden flags and knows that bool flags take no value", func() {
+		command, _ := Find(commands, "delete")
+		Expect(command.FlagNames()).To(Equal([]string{"-f", "-r"}))
+
+		flag, found := command.Flag("-f")
+		Expect(found).To(BeTrue())
+		Expect(flag.TakesValue).To(BeFalse())
+	})
+
+	It("includes the options of plugin commands", func() {
+		command, _ := Find(commands, "test1_cmd")
+		Expect(command.ShortName).To(Equal("t1"))
+		Expect(command.Description).To(Equal("a test command"))
+		Expect(command.FlagNames()).To(Equal([]string{"-flag"}))
+	})
+})
@@ -0,0 +1,116 @@
+package completion
+
+import (
+	"sort"
+	"strings"
+)
+
+//go:generate counterfeiter . ValueSource
+
+// ValueSource looks up the values of a kind, such as the names of the apps
+// in the targeted space.
+type ValueSource interface {
+	Values(kind ValueKind) ([]string, error)
+}
+
+// Completer suggests the words that can follow a partial command line.
+type Completer struct {
+	commands []Command
+	source   ValueSource
+}
+
+func NewCompleter(commands []Command, source ValueSource) Completer {
+	return Completer{
+		commands: commands,
+		source:   source,
+	}
+}
+
+// Complete takes the words of a command line after 'cf', the last of which
+// is the word being completed and may be empty, and returns the suggestions
+// that start with it. Values that cannot be looked up are left out, since
+// nothing must get in the way of the shell.
+func (c Completer) Complete(args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	current := args[len(args)-1]
+
+	if len(args) == 1 {
+		return withPrefix(c.commandNames(), current)
+	}
+
+	command, found := Find(c.commands, args[0])
+	if !found {
+		return []string{}
+	}
+
+	if strings.HasPrefix(current, "-") {
+		return withPrefix(command.FlagNames(), current)
+	}
+
+	position := 0
+	var pendingFlag *Flag
+	for _, arg := range args[1 : len(args)-1] {
+		if pendingFlag != nil {
+			pendingFlag = nil
+			continue
+		}
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+			if flag, found := command.Flag(arg); found && flag.TakesValue && !strings.Contains(arg, "=") {
+				pendingFlag = &flag
+			}
+			continue
+		}
+		position++
+	}
+
+	if pendingFlag != nil {
+		return withPrefix(c.values(pendingFlag.Values), current)
+	}
+
+	if position >= len(command.Args) {
+		return []string{}
+	}
+
+	arg := command.Args[position]
+	words := append([]string{}, arg.Words...)
+	return withPrefix(append(words, c.values(arg.Kind)...), current)
+}
+
+func (c Completer) commandNames() []string {
+	names := []string{}
+	for _, command := range c.commands {
+		names = append(names, command.Name)
+		if command.ShortName != "" {
+			names = append(names, command.ShortName)
+		}
+	}
+	return names
+}
+
+func (c Completer) values(kind ValueKind) []string {
+	switch kind {
+	case NoValues:
+		return []string{}
+	case CommandNames:
+		return c.commandNames()
+	}
+
+	values, err := c.source.Values(kind)
+	if err != nil {
+		return []string{}
+	}
+	return values
+}
+
+func withPrefix(values []string, prefix string) []string {
+	matches := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) && !containsString(matches, value) {
+			matches = append(matches, value)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
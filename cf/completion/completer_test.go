@@ -0,0 +1,114 @@
+package completion_test
+
+import (
+	"errors"
+
+	. "github.com/cloudfoundry/cli/cf/completion"
+	"github.com/cloudfoundry/cli/cf/completion/completionfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Completer", func() {
+	var (
+		source    *completionfakes.FakeValueSource
+		completer Completer
+	)
+
+	BeforeEach(func() {
+		commands := []Command{
+			{
+				Name:      "bind-service",
+				ShortName: "bs",
+				Flags: []Flag{
+					{Names: []string{"-c"}, TakesValue: true},
+				},
+				Args: []Arg{{Kind: AppNames}, {Kind: ServiceNames}},
+			},
+			{
+				Name: "target",
+				Flags: []Flag{
+					{Names: []string{"-o"}, TakesValue: true, Values: OrgNames},
+					{Names: []string{"-s"}, TakesValue: true, Values: SpaceNames},
+				},
+			},
+			{
+				Name: "delete",
+				Flags: []Flag{
+					{Names: []string{"-f"}},
+					{Names: []string{"--force"}},
+				},
+				Args: []Arg{{Kind: AppNames}},
+			},
+			{
+				Name: "profile",
+				Args: []Arg{{Words: []string{"use", "delete"}}},
+			},
+			{
+				Name: "help",
+				Args: []Arg{{Kind: CommandNames}},
+			},
+		}
+
+		source = new(completionfakes.FakeValueSource)
+		source.ValuesStub = func(kind ValueKind) ([]string, error) {
+			switch kind {
+			case AppNames:
+				return []string{"my-app", "other-app", "my-worker"}, nil
+			case ServiceNames:
+				return []string{"my-db"}, nil
+			case OrgNames:
+				return []string{"my-org"}, nil
+			}
+			return []string{}, nil
+		}
+
+		completer = NewCompleter(commands, source)
+	})
+
+	It("suggests command names and short names for the first word", func() {
+		Expect(completer.Complete([]string{""})).To(Equal([]string{"bind-service", "bs", "delete", "help", "profile", "target"}))
+		Expect(completer.Complete([]string{"b"})).To(Equal([]string{"bind-service", "bs"}))
+		Expect(completer.Complete([]string{})).To(HaveLen(6))
+	})
+
+	It("suggests nothing after unknown commands", func() {
+		Expect(completer.Complete([]string{"unknown", ""})).To(BeEmpty())
+		Expect(source.ValuesCallCount()).To(Equal(0))
+	})
+
+	It("suggests flags for words starting with a dash", func() {
+		Expect(completer.Complete([]string{"delete", "-"})).To(Equal([]string{"--force", "-f"}))
+		Expect(completer.Complete([]string{"delete", "--"})).To(Equal([]string{"--force"}))
+	})
+
+	It("suggests the values each argument takes", func() {
+		Expect(completer.Complete([]string{"bs", "my"})).To(Equal([]string{"my-app", "my-worker"}))
+		Expect(completer.Complete([]string{"bs", "my-app", ""})).To(Equal([]string{"my-db"}))
+		Expect(completer.Complete([]string{"bs", "my-app", "my-db", ""})).To(BeEmpty())
+	})
+
+	It("suggests the values a flag takes", func() {
+		Expect(completer.Complete([]string{"target", "-o", ""})).To(Equal([]string{"my-org"}))
+		Expect(completer.Complete([]string{"target", "-s", ""})).To(BeEmpty())
+	})
+
+	It("skips flags and their values when counting arguments", func() {
+		Expect(completer.Complete([]string{"bs", "-c", "{}", "my-app", ""})).To(Equal([]string{"my-db"}))
+		Expect(completer.Complete([]string{"bs", "-c={}", "my-app", ""})).To(Equal([]string{"my-db"}))
+		Expect(completer.Complete([]string{"delete", "-f", "my-"})).To(Equal([]string{"my-app", "my-worker"}))
+	})
+
+	It("suggests fixed words and command names", func() {
+		Expect(completer.Complete([]string{"profile", ""})).To(Equal([]string{"delete", "use"}))
+		Expect(completer.Complete([]string{"help", "ta"})).To(Equal([]string{"target"}))
+	})
+
+	It("suggests nothing when the values cannot be looked up", func() {
+		source.ValuesStub = nil
+		source.ValuesReturns(nil, errors.New("not authorized"))
+
+		Expect(completer.Complete([]string{"delete", ""})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,55 @@
+// This file was generated by counterfeiter
+package completionfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cli/cf/completion"
+)
+
+type FakeValueSource struct {
+	ValuesStub        func(kind completion.ValueKind) ([]string, error)
+	valuesMutex       sync.RWMutex
+	valuesArgsForCall []struct {
+		kind completion.ValueKind
+	}
+	valuesReturns struct {
+		result1 []string
+		result2 error
+	}
+}
+
+func (fake *FakeValueSource) Values(kind completion.ValueKind) ([]string, error) {
+	fake.valuesMutex.Lock()
+	fake.valuesArgsForCall = append(fake.valuesArgsForCall, struct {
+		kind completion.ValueKind
+	}{kind})
+	fake.valuesMutex.Unlock()
+	if fake.ValuesStub != nil {
+		return fake.ValuesStub(kind)
+	} else {
+		return fake.valuesReturns.result1, fake.valuesReturns.result2
+	}
+}
+
+func (fake *FakeValueSource) ValuesCallCount() int {
+	fake.valuesMutex.RLock()
+	defer fake.valuesMutex.RUnlock()
+	return len(fake.valuesArgsForCall)
+}
+
+func (fake *FakeValueSource) ValuesArgsForCall(i int) completion.ValueKind {
+	fake.valuesMutex.RLock()
+	defer fake.valuesMutex.RUnlock()
+	return fake.valuesArgsForCall[i].kind
+}
+
+func (fake *FakeValueSource) ValuesReturns(result1 []string, result2 error) {
+	fake.ValuesStub = nil
+	fake.valuesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+var _ completion.ValueSource = new(FakeValueSource)
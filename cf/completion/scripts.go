@@ -0,0 +1,201 @@
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+// Shells are the shells a completion script can be written for.
+var Shells = []string{"bash", "zsh", "fish"}
+
+// WriteScript writes the completion script for shell. Command names and
+// flags are written into the script, while everything else is asked of the
+// hidden '__complete' command, which looks values up for the current target.
+func WriteScript(w io.Writer, shell string, commands []Command) error {
+	var script string
+
+	switch shell {
+	case "bash":
+		script = bashScript(commands)
+	case "zsh":
+		script = zshScript(commands)
+	case "fish":
+		script = fishScript(commands)
+	default:
+		return errors.New(T("Unsupported shell {{.Shell}}. Supported shells are: {{.Shells}}", map[string]interface{}{
+			"Shell":  shell,
+			"Shells": strings.Join(Shells, ", "),
+		}))
+	}
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+func bashScript(commands []Command) string {
+	buffer := new(bytes.Buffer)
+
+	fmt.Fprintln(buffer, "# bash completion for cf")
+	fmt.Fprintln(buffer, "# Generated by: cf completion bash")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, "_cf_complete() {")
+	fmt.Fprintln(buffer, `    local cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, `    if [ "$COMP_CWORD" -eq 1 ]; then`)
+	fmt.Fprintf(buffer, "        COMPREPLY=( $(compgen -W %s -- \"$cur\") )\n", bashQuote(strings.Join(commandNames(commands), " ")))
+	fmt.Fprintln(buffer, "        return")
+	fmt.Fprintln(buffer, "    fi")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, `    if [[ "$cur" == -* ]]; then`)
+	fmt.Fprintln(buffer, `        local flags=""`)
+	fmt.Fprintln(buffer, `        case "${COMP_WORDS[1]}" in`)
+	for _, command := range commands {
+		if len(command.Flags) == 0 {
+			continue
+		}
+		fmt.Fprintf(buffer, "            %s) flags=%s ;;\n", strings.Join(names(command), "|"), bashQuote(strings.Join(command.FlagNames(), " ")))
+	}
+	fmt.Fprintln(buffer, "        esac")
+	fmt.Fprintln(buffer, `        COMPREPLY=( $(compgen -W "$flags" -- "$cur") )`)
+	fmt.Fprintln(buffer, "        return")
+	fmt.Fprintln(buffer, "    fi")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, `    local IFS=$'\n'`)
+	fmt.Fprintln(buffer, `    COMPREPLY=( $(cf __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null) )`)
+	fmt.Fprintln(buffer, "}")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, "complete -F _cf_complete cf")
+
+	return buffer.String()
+}
+
+func zshScript(commands []Command) string {
+	buffer := new(bytes.Buffer)
+
+	fmt.Fprintln(buffer, "#compdef cf")
+	fmt.Fprintln(buffer, "# zsh completion for cf")
+	fmt.Fprintln(buffer, "# Generated by: cf completion zsh")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, "_cf_complete() {")
+	fmt.Fprintln(buffer, "    local -a suggestions")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, "    if (( CURRENT == 2 )); then")
+	fmt.Fprintln(buffer, "        suggestions=(")
+	for _, command := range commands {
+		for _, name := range names(command) {
+			fmt.Fprintf(buffer, "            %s\n", bashQuote(name+":"+summary(command.Description)))
+		}
+	}
+	fmt.Fprintln(buffer, "        )")
+	fmt.Fprintln(buffer, "        _describe -t commands 'cf command' suggestions")
+	fmt.Fprintln(buffer, "        return")
+	fmt.Fprintln(buffer, "    fi")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, `    if [[ "${words[CURRENT]}" == -* ]]; then`)
+	fmt.Fprintln(buffer, `        case "${words[2]}" in`)
+	for _, command := range commands {
+		if len(command.Flags) == 0 {
+			continue
+		}
+		quoted := []string{}
+		for _, name := range command.FlagNames() {
+			quoted = append(quoted, bashQuote(name))
+		}
+		fmt.Fprintf(buffer, "            %s) suggestions=(%s) ;;\n", strings.Join(names(command), "|"), strings.Join(quoted, " "))
+	}
+	fmt.Fprintln(buffer, "        esac")
+	fmt.Fprintln(buffer, "        compadd -a suggestions")
+	fmt.Fprintln(buffer, "        return")
+	fmt.Fprintln(buffer, "    fi")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, `    suggestions=( ${(f)"$(cf __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"} )`)
+	fmt.Fprintln(buffer, "    compadd -a suggestions")
+	fmt.Fprintln(buffer, "}")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, "compdef _cf_complete cf")
+
+	return buffer.String()
+}
+
+func fishScript(commands []Command) string {
+	buffer := new(bytes.Buffer)
+
+	fmt.Fprintln(buffer, "# fish completion for cf")
+	fmt.Fprintln(buffer, "# Generated by: cf completion fish")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, "function __cf_complete")
+	fmt.Fprintln(buffer, "    set -l words (commandline -opc)")
+	fmt.Fprintln(buffer, "    set -l current (commandline -ct)")
+	fmt.Fprintln(buffer, "    string match -q -- '-*' \"$current\"; and return")
+	fmt.Fprintln(buffer, "    set -e words[1]")
+	fmt.Fprintln(buffer, "    cf __complete $words \"$current\" 2>/dev/null")
+	fmt.Fprintln(buffer, "end")
+	fmt.Fprintln(buffer, "")
+	fmt.Fprintln(buffer, "complete -c cf -f")
+
+	for _, command := range commands {
+		for _, name := range names(command) {
+			fmt.Fprintf(buffer, "complete -c cf -n '__fish_use_subcommand' -a %s -d %s\n", fishQuote(name), fishQuote(summary(command.Description)))
+		}
+	}
+
+	for _, command := range commands {
+		condition := fishQuote("__fish_seen_subcommand_from " + strings.Join(names(command), " "))
+		for _, flag := range command.Flags {
+			options := []string{}
+			for _, name := range flag.Names {
+				switch {
+				case strings.HasPrefix(name, "--"):
+					options = append(options, "-l "+fishQuote(strings.TrimPrefix(name, "--")))
+				case len(name) == 2:
+					options = append(options, "-s "+fishQuote(strings.TrimPrefix(name, "-")))
+				default:
+					options = append(options, "-o "+fishQuote(strings.TrimPrefix(name, "-")))
+				}
+			}
+			fmt.Fprintf(buffer, "complete -c cf -n %s %s -d %s\n", condition, strings.Join(options, " "), fishQuote(summary(flag.Usage)))
+		}
+	}
+
+	fmt.Fprintln(buffer, "complete -c cf -n 'not __fish_use_subcommand' -a '(__cf_complete)'")
+
+	return buffer.String()
+}
+
+func commandNames(commands []Command) []string {
+	all := []string{}
+	for _, command := range commands {
+		all = append(all, names(command)...)
+	}
+	return all
+}
+
+func names(command Command) []string {
+	if command.ShortName == "" {
+		return []string{command.Name}
+	}
+	return []string{command.Name, command.ShortName}
+}
+
+// summary is the first line of a description, which is all that fits next
+// to a suggestion.
+func summary(description string) string {
+	return strings.TrimSpace(strings.SplitN(description, "\n", 2)[0])
+}
+
+// bashQuote single quotes a word for bash and zsh.
+func bashQuote(word string) string {
+	return "'" + strings.Replace(word, "'", `'\''`, -1) + "'"
+}
+
+// fishQuote single quotes a word for fish, which escapes quotes and
+// backslashes inside single quotes.
+func fishQuote(word string) string {
+	word = strings.Replace(word, `\`, `\\`, -1)
+	return "'" + strings.Replace(word, "'", `\'`, -1) + "'"
+}
@@ -0,0 +1,70 @@
+package completion_test
+
+import (
+	"bytes"
+
+	. "github.com/cloudfoundry/cli/cf/completion"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteScript", func() {
+	var (
+		commands []Command
+		output   *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		commands = []Command{
+			{
+				Name:        "push",
+				ShortName:   "p",
+				Description: "Push a new app or sync changes to an existing app",
+				Flags: []Flag{
+					{Names: []string{"--no-start"}, Usage: "Do not start an app after pushing"},
+					{Names: []string{"-b"}, Usage: "Custom buildpack by name", TakesValue: true},
+				},
+			},
+			{
+				Name:        "quote",
+				Description: "It's quoted",
+			},
+		}
+		output = new(bytes.Buffer)
+	})
+
+	It("writes a bash script with the commands and their flags", func() {
+		Expect(WriteScript(output, "bash", commands)).To(Succeed())
+
+		Expect(output.String()).To(ContainSubstring("compgen -W 'push p quote'"))
+		Expect(output.String()).To(ContainSubstring("push|p) flags='--no-start -b' ;;"))
+		Expect(output.String()).To(ContainSubstring(`cf __complete "${COMP_WORDS[@]:1:COMP_CWORD}"`))
+		Expect(output.String()).To(ContainSubstring("complete -F _cf_complete cf"))
+	})
+
+	It("writes a zsh script with the descriptions of the commands", func() {
+		Expect(WriteScript(output, "zsh", commands)).To(Succeed())
+
+		Expect(output.String()).To(HavePrefix("#compdef cf\n"))
+		Expect(output.String()).To(ContainSubstring("'p:Push a new app or sync changes to an existing app'"))
+		Expect(output.String()).To(ContainSubstring(`'quote:It'\''s quoted'`))
+		Expect(output.String()).To(ContainSubstring("push|p) suggestions=('--no-start' '-b') ;;"))
+		Expect(output.String()).To(ContainSubstring("compdef _cf_complete cf"))
+	})
+
+	It("writes a fish script with the descriptions of the commands and flags", func() {
+		Expect(WriteScript(output, "fish", commands)).To(Succeed())
+
+		Expect(output.String()).To(ContainSubstring(`complete -c cf -n '__fish_use_subcommand' -a 'quote' -d 'It\'s quoted'`))
+		Expect(output.String()).To(ContainSubstring("complete -c cf -n '__fish_seen_subcommand_from push p' -l 'no-start' -d 'Do not start an app after pushing'"))
+		Expect(output.String()).To(ContainSubstring("complete -c cf -n '__fish_seen_subcommand_from push p' -s 'b' -d 'Custom buildpack by name'"))
+		Expect(output.String()).To(ContainSubstring("complete -c cf -n 'not __fish_use_subcommand' -a '(__cf_complete)'"))
+	})
+
+	It("fails for other shells", func() {
+		err := WriteScript(output, "tcsh", commands)
+		Expect(err).To(MatchError("Unsupported shell tcsh. Supported shells are: bash, zsh, fish"))
+		Expect(output.String()).To(BeEmpty())
+	})
+})
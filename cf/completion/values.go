@@ -0,0 +1,181 @@
+package completion
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/organizations"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+)
+
+const CacheTTL = 30 * time.Second
+
+type repositoryValueSource struct {
+	config           coreconfig.Reader
+	appSummaries     api.AppSummaryRepository
+	serviceSummaries api.ServiceSummaryRepository
+	spaceRepo        spaces.SpaceRepository
+	orgRepo          organizations.OrganizationRepository
+}
+
+// NewRepositoryValueSource looks values up from the Cloud Controller for the
+// current target. Apps and services need a targeted space, spaces need a
+// targeted org, and nothing is looked up when not logged in.
+func NewRepositoryValueSource(config coreconfig.Reader, repoLocator api.RepositoryLocator) ValueSource {
+	return repositoryValueSource{
+		config:           config,
+		appSummaries:     repoLocator.GetAppSummaryRepository(),
+		serviceSummaries: repoLocator.GetServiceSummaryRepository(),
+		spaceRepo:        repoLocator.GetSpaceRepository(),
+		orgRepo:          repoLocator.GetOrganizationRepository(),
+	}
+}
+
+func (source repositoryValueSource) Values(kind ValueKind) ([]string, error) {
+	names := []string{}
+	if !source.config.IsLoggedIn() {
+		return names, nil
+	}
+
+	switch kind {
+	case AppNames:
+		if !source.config.HasSpace() {
+			return names, nil
+		}
+		apps, err := source.appSummaries.GetSummariesInCurrentSpace()
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			names = append(names, app.Name)
+		}
+	case ServiceNames:
+		if !source.config.HasSpace() {
+			return names, nil
+		}
+		instances, err := source.serviceSummaries.GetSummariesInCurrentSpace()
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			names = append(names, instance.Name)
+		}
+	case SpaceNames:
+		if !source.config.HasOrganization() {
+			return names, nil
+		}
+		err := source.spaceRepo.ListSpaces(func(space models.Space) bool {
+			names = append(names, space.Name)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	case OrgNames:
+		orgs, err := source.orgRepo.ListOrgs(0)
+		if err != nil {
+			return nil, err
+		}
+		for _, org := range orgs {
+			names = append(names, org.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// TargetKey identifies the target whose values are cached, so that changing
+// the API, user, org or space never shows values of the previous target.
+func TargetKey(config coreconfig.Reader) string {
+	return strings.Join([]string{
+		config.APIEndpoint(),
+		config.Username(),
+		config.OrganizationFields().GUID,
+		config.SpaceFields().GUID,
+	}, " ")
+}
+
+type cachedValueSource struct {
+	source ValueSource
+	path   string
+	key    string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+type cacheEntry struct {
+	Values  []string
+	Expires time.Time
+}
+
+// NewCachedValueSource keeps the values looked up by source in the file at
+// path for ttl, since completing a single word can take several lookups and
+// a round trip to the Cloud Controller for each is noticeable.
+func NewCachedValueSource(source ValueSource, path string, key string, ttl time.Duration, now func() time.Time) ValueSource {
+	return cachedValueSource{
+		source: source,
+		path:   path,
+		key:    key,
+		ttl:    ttl,
+		now:    now,
+	}
+}
+
+func (cache cachedValueSource) Values(kind ValueKind) ([]string, error) {
+	entries := cache.read()
+	key := cache.key + " " + string(kind)
+
+	if entry, found := entries[key]; found && cache.now().Before(entry.Expires) {
+		return entry.Values, nil
+	}
+
+	values, err := cache.source.Values(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, entry := range entries {
+		if !cache.now().Before(entry.Expires) {
+			delete(entries, k)
+		}
+	}
+	entries[key] = cacheEntry{Values: values, Expires: cache.now().Add(cache.ttl)}
+	cache.write(entries)
+
+	return values, nil
+}
+
+func (cache cachedValueSource) read() map[string]cacheEntry {
+	entries := map[string]cacheEntry{}
+
+	contents, err := ioutil.ReadFile(cache.path)
+	if err != nil {
+		return entries
+	}
+
+	err = json.Unmarshal(contents, &entries)
+	if err != nil {
+		return map[string]cacheEntry{}
+	}
+	return entries
+}
+
+func (cache cachedValueSource) write(entries map[string]cacheEntry) {
+	contents, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(cache.path), 0700)
+	if err != nil {
+		return
+	}
+	ioutil.WriteFile(cache.path, contents, 0600)
+}
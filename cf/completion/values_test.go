@@ -0,0 +1,188 @@
+package completion_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/organizations/organizationsfakes"
+	"github.com/cloudfoundry/cli/cf/api/spaces/spacesfakes"
+	. "github.com/cloudfoundry/cli/cf/completion"
+	"github.com/cloudfoundry/cli/cf/completion/completionfakes"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Values", func() {
+	Describe("NewRepositoryValueSource", func() {
+		var (
+			config       coreconfig.Repository
+			appSummaries *apifakes.FakeAppSummaryRepository
+			services     *apifakes.FakeServiceSummaryRepository
+			spaceRepo    *spacesfakes.FakeSpaceRepository
+			orgRepo      *organizationsfakes.FakeOrganizationRepository
+			source       ValueSource
+		)
+
+		BeforeEach(func() {
+			config = testconfig.NewRepositoryWithDefaults()
+
+			appSummaries = new(apifakes.FakeAppSummaryRepository)
+			appSummaries.GetSummariesInCurrentSpaceReturns([]models.Application{
+				{ApplicationFields: models.ApplicationFields{Name: "app-1"}},
+				{ApplicationFields: models.ApplicationFields{Name: "app-2"}},
+			}, nil)
+
+			services = new(apifakes.FakeServiceSummaryRepository)
+			services.GetSummariesInCurrentSpaceReturns([]models.ServiceInstance{
+				{ServiceInstanceFields: models.ServiceInstanceFields{Name: "db"}},
+			}, nil)
+
+			spaceRepo = new(spacesfakes.FakeSpaceRepository)
+			spaceRepo.ListSpacesStub = func(callback func(models.Space) bool) error {
+				callback(models.Space{SpaceFields: models.SpaceFields{Name: "dev"}})
+				callback(models.Space{SpaceFields: models.SpaceFields{Name: "prod"}})
+				return nil
+			}
+
+			orgRepo = new(organizationsfakes.FakeOrganizationRepository)
+			orgRepo.ListOrgsReturns([]models.Organization{
+				{OrganizationFields: models.OrganizationFields{Name: "my-org"}},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			repoLocator := api.RepositoryLocator{}.
+				SetAppSummaryRepository(appSummaries).
+				SetServiceSummaryRepository(services).
+				SetSpaceRepository(spaceRepo).
+				SetOrganizationRepository(orgRepo)
+			source = NewRepositoryValueSource(config, repoLocator)
+		})
+
+		It("looks up the names of apps, services, spaces and orgs", func() {
+			Expect(source.Values(AppNames)).To(Equal([]string{"app-1", "app-2"}))
+			Expect(source.Values(ServiceNames)).To(Equal([]string{"db"}))
+			Expect(source.Values(SpaceNames)).To(Equal([]string{"dev", "prod"}))
+			Expect(source.Values(OrgNames)).To(Equal([]string{"my-org"}))
+			Expect(orgRepo.ListOrgsArgsForCall(0)).To(Equal(0))
+		})
+
+		It("returns the errors of the lookups", func() {
+			appSummaries.GetSummariesInCurrentSpaceReturns(nil, errors.New("boom"))
+
+			_, err := source.Values(AppNames)
+			Expect(err).To(MatchError("boom"))
+		})
+
+		Context("when no space is targeted", func() {
+			BeforeEach(func() {
+				config.SetSpaceFields(models.SpaceFields{})
+			})
+
+			It("looks up no apps or services", func() {
+				Expect(source.Values(AppNames)).To(BeEmpty())
+				Expect(source.Values(ServiceNames)).To(BeEmpty())
+				Expect(appSummaries.GetSummariesInCurrentSpaceCallCount()).To(Equal(0))
+				Expect(services.GetSummariesInCurrentSpaceCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when not logged in", func() {
+			BeforeEach(func() {
+				config = testconfig.NewRepository()
+			})
+
+			It("looks up nothing", func() {
+				Expect(source.Values(OrgNames)).To(BeEmpty())
+				Expect(orgRepo.ListOrgsCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("TargetKey", func() {
+		It("changes with the targeted space", func() {
+			config := testconfig.NewRepositoryWithDefaults()
+			key := TargetKey(config)
+
+			config.SetSpaceFields(models.SpaceFields{GUID: "other-space-guid"})
+			Expect(TargetKey(config)).NotTo(Equal(key))
+		})
+	})
+
+	Describe("NewCachedValueSource", func() {
+		var (
+			dir    string
+			path   string
+			now    time.Time
+			source *completionfakes.FakeValueSource
+			cached ValueSource
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "completion-cache")
+			Expect(err).NotTo(HaveOccurred())
+			path = filepath.Join(dir, ".cf", "completion_cache.json")
+
+			now = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+			source = new(completionfakes.FakeValueSource)
+			source.ValuesReturns([]string{"app-1"}, nil)
+
+			cached = NewCachedValueSource(source, path, "target", 30*time.Second, func() time.Time { return now })
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("keeps values in the cache file until they expire", func() {
+			Expect(cached.Values(AppNames)).To(Equal([]string{"app-1"}))
+			Expect(path).To(BeAnExistingFile())
+
+			now = now.Add(29 * time.Second)
+			Expect(cached.Values(AppNames)).To(Equal([]string{"app-1"}))
+			Expect(source.ValuesCallCount()).To(Equal(1))
+
+			now = now.Add(time.Second)
+			source.ValuesReturns([]string{"app-2"}, nil)
+			Expect(cached.Values(AppNames)).To(Equal([]string{"app-2"}))
+			Expect(source.ValuesCallCount()).To(Equal(2))
+		})
+
+		It("keeps the values of each kind and target apart", func() {
+			cached.Values(AppNames)
+			cached.Values(SpaceNames)
+			Expect(source.ValuesCallCount()).To(Equal(2))
+
+			other := NewCachedValueSource(source, path, "other-target", 30*time.Second, func() time.Time { return now })
+			other.Values(AppNames)
+			Expect(source.ValuesCallCount()).To(Equal(3))
+		})
+
+		It("does not cache failed lookups", func() {
+			source.ValuesReturns(nil, errors.New("boom"))
+			_, err := cached.Values(AppNames)
+			Expect(err).To(MatchError("boom"))
+
+			source.ValuesReturns([]string{"app-1"}, nil)
+			Expect(cached.Values(AppNames)).To(Equal([]string{"app-1"}))
+			Expect(source.ValuesCallCount()).To(Equal(2))
+		})
+
+		It("ignores a cache file it cannot read", func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte("not json"), 0600)).To(Succeed())
+
+			Expect(cached.Values(AppNames)).To(Equal([]string{"app-1"}))
+		})
+	})
+})
@@ -2,10 +2,20 @@ package coreconfig
 
 import (
 	"encoding/json"
+	"sort"
 
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
 	"github.com/cloudfoundry/cli/cf/models"
 )
 
+// DefaultProfileName is the profile of a config that has never switched
+// profiles, including every config written before profiles existed.
+const DefaultProfileName = "default"
+
+// ClientCredentialsGrantType is the UAAGrantType of a client that logged in
+// with its own id and secret instead of a user's credentials.
+const ClientCredentialsGrantType = "client_credentials"
+
 type AuthPromptType string
 
 const (
@@ -30,14 +40,60 @@ type Data struct {
 	AccessToken              string
 	SSHOAuthClient           string
 	RefreshToken             string
+	UAAGrantType             string `json:",omitempty"`
+	UAAOAuthClient           string `json:",omitempty"`
+	UAAOAuthClientSecret     string `json:",omitempty"`
 	OrganizationFields       models.OrganizationFields
 	SpaceFields              models.SpaceFields
 	SSLDisabled              bool
+	CACertFile               string `json:",omitempty"`
+	ClientCertFile           string `json:",omitempty"`
+	ClientKeyFile            string `json:",omitempty"`
 	AsyncTimeout             uint
+	Retries                  *int `json:",omitempty"`
+	RetryMaxWait             uint `json:",omitempty"`
 	Trace                    string
 	ColorEnabled             string
 	Locale                   string
 	PluginRepos              []models.PluginRepo
+	Aliases                  map[string]string `json:",omitempty"`
+	MinCLIVersion            string
+	MinRecommendedCLIVersion string
+	CurrentProfile           string    `json:",omitempty"`
+	Profiles                 []Profile `json:",omitempty"`
+	CredentialHelper         string    `json:",omitempty"`
+
+	// activeProfile is the profile used by this invocation of the CLI when it
+	// is not CurrentProfile.
+	activeProfile string
+}
+
+// Profile is everything that belongs to one Cloud Foundry: its endpoints, the
+// tokens of the user logged in to it and the targeted org and space.
+//
+// The fields of Data hold the active profile, so that configs stay readable
+// by versions of the CLI without profiles; Profiles holds all the others.
+type Profile struct {
+	Name                     string
+	Target                   string
+	APIVersion               string
+	AuthorizationEndpoint    string
+	LoggregatorEndPoint      string
+	DopplerEndPoint          string
+	UaaEndpoint              string
+	RoutingAPIEndpoint       string
+	AccessToken              string
+	SSHOAuthClient           string
+	RefreshToken             string
+	UAAGrantType             string `json:",omitempty"`
+	UAAOAuthClient           string `json:",omitempty"`
+	UAAOAuthClientSecret     string `json:",omitempty"`
+	OrganizationFields       models.OrganizationFields
+	SpaceFields              models.SpaceFields
+	SSLDisabled              bool
+	CACertFile               string `json:",omitempty"`
+	ClientCertFile           string `json:",omitempty"`
+	ClientKeyFile            string `json:",omitempty"`
 	MinCLIVersion            string
 	MinRecommendedCLIVersion string
 }
@@ -49,7 +105,26 @@ func NewData() (data *Data) {
 
 func (d *Data) JSONMarshalV3() (output []byte, err error) {
 	d.ConfigVersion = 3
-	return json.MarshalIndent(d, "", "  ")
+
+	if d.activeProfileName() == d.currentProfileName() && d.CredentialHelper == "" {
+		return json.MarshalIndent(d, "", "  ")
+	}
+
+	// Only this invocation uses another profile, so the current profile is
+	// the one written to the fields of Data.
+	persisted := *d
+	persisted.Profiles = append([]Profile{}, d.Profiles...)
+	persisted.activate(d.currentProfileName())
+
+	// Tokens kept by a credential helper are never written to the config.
+	if d.CredentialHelper != "" {
+		persisted.setTokens(credentials.Tokens{})
+		for i := range persisted.Profiles {
+			persisted.Profiles[i].setTokens(credentials.Tokens{})
+		}
+	}
+
+	return json.MarshalIndent(persisted, "", "  ")
 }
 
 func (d *Data) JSONUnmarshalV3(input []byte) (err error) {
@@ -65,3 +140,172 @@ func (d *Data) JSONUnmarshalV3(input []byte) (err error) {
 
 	return
 }
+
+func (d *Data) currentProfileName() string {
+	if d.CurrentProfile == "" {
+		return DefaultProfileName
+	}
+	return d.CurrentProfile
+}
+
+func (d *Data) activeProfileName() string {
+	if d.activeProfile == "" {
+		return d.currentProfileName()
+	}
+	return d.activeProfile
+}
+
+func (d *Data) findProfile(name string) int {
+	for i, profile := range d.Profiles {
+		if profile.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *Data) hasProfile(name string) bool {
+	return name == d.activeProfileName() || d.findProfile(name) >= 0
+}
+
+// allProfiles returns every profile, including the active one, sorted by
+// name.
+func (d *Data) allProfiles() []Profile {
+	profiles := append([]Profile{d.profile()}, d.Profiles...)
+	sort.Sort(byProfileName(profiles))
+	return profiles
+}
+
+// activate makes the named profile the active one, creating it if it does
+// not exist, and keeps the previously active profile in Profiles.
+func (d *Data) activate(name string) {
+	if name == d.activeProfileName() {
+		return
+	}
+
+	next := Profile{Name: name}
+	if i := d.findProfile(name); i >= 0 {
+		next = d.Profiles[i]
+		d.Profiles = append(d.Profiles[:i], d.Profiles[i+1:]...)
+	}
+
+	d.Profiles = append(d.Profiles, d.profile())
+	sort.Sort(byProfileName(d.Profiles))
+
+	d.setProfile(next)
+	d.activeProfile = name
+}
+
+// useProfile makes the named profile the current one, so that it is also
+// used by later invocations of the CLI.
+func (d *Data) useProfile(name string) {
+	d.activate(name)
+	d.CurrentProfile = name
+	d.activeProfile = ""
+}
+
+func (d *Data) deleteProfile(name string) bool {
+	if name == d.currentProfileName() {
+		return false
+	}
+	i := d.findProfile(name)
+	if i < 0 {
+		return false
+	}
+	d.Profiles = append(d.Profiles[:i], d.Profiles[i+1:]...)
+	return true
+}
+
+// setProfileTokens sets the tokens of a profile that is not the active one.
+func (d *Data) setProfileTokens(name string, tokens credentials.Tokens) {
+	if i := d.findProfile(name); i >= 0 {
+		d.Profiles[i].setTokens(tokens)
+	}
+}
+
+// tokens are the secrets of the active profile, which a credential helper
+// keeps instead of the config.
+func (d *Data) tokens() credentials.Tokens {
+	return credentials.Tokens{
+		AccessToken:  d.AccessToken,
+		RefreshToken: d.RefreshToken,
+		ClientSecret: d.UAAOAuthClientSecret,
+	}
+}
+
+func (d *Data) setTokens(tokens credentials.Tokens) {
+	d.AccessToken = tokens.AccessToken
+	d.RefreshToken = tokens.RefreshToken
+	d.UAAOAuthClientSecret = tokens.ClientSecret
+}
+
+func (p Profile) tokens() credentials.Tokens {
+	return credentials.Tokens{
+		AccessToken:  p.AccessToken,
+		RefreshToken: p.RefreshToken,
+		ClientSecret: p.UAAOAuthClientSecret,
+	}
+}
+
+func (p *Profile) setTokens(tokens credentials.Tokens) {
+	p.AccessToken = tokens.AccessToken
+	p.RefreshToken = tokens.RefreshToken
+	p.UAAOAuthClientSecret = tokens.ClientSecret
+}
+
+func (d *Data) profile() Profile {
+	return Profile{
+		Name:                     d.activeProfileName(),
+		Target:                   d.Target,
+		APIVersion:               d.APIVersion,
+		AuthorizationEndpoint:    d.AuthorizationEndpoint,
+		LoggregatorEndPoint:      d.LoggregatorEndPoint,
+		DopplerEndPoint:          d.DopplerEndPoint,
+		UaaEndpoint:              d.UaaEndpoint,
+		RoutingAPIEndpoint:       d.RoutingAPIEndpoint,
+		AccessToken:              d.AccessToken,
+		SSHOAuthClient:           d.SSHOAuthClient,
+		RefreshToken:             d.RefreshToken,
+		UAAGrantType:             d.UAAGrantType,
+		UAAOAuthClient:           d.UAAOAuthClient,
+		UAAOAuthClientSecret:     d.UAAOAuthClientSecret,
+		OrganizationFields:       d.OrganizationFields,
+		SpaceFields:              d.SpaceFields,
+		SSLDisabled:              d.SSLDisabled,
+		CACertFile:               d.CACertFile,
+		ClientCertFile:           d.ClientCertFile,
+		ClientKeyFile:            d.ClientKeyFile,
+		MinCLIVersion:            d.MinCLIVersion,
+		MinRecommendedCLIVersion: d.MinRecommendedCLIVersion,
+	}
+}
+
+func (d *Data) setProfile(profile Profile) {
+	d.Target = profile.Target
+	d.APIVersion = profile.APIVersion
+	d.AuthorizationEndpoint = profile.AuthorizationEndpoint
+	d.LoggregatorEndPoint = profile.LoggregatorEndPoint
+	d.DopplerEndPoint = profile.DopplerEndPoint
+	d.UaaEndpoint = profile.UaaEndpoint
+	d.RoutingAPIEndpoint = profile.RoutingAPIEndpoint
+	d.AccessToken = profile.AccessToken
+	d.SSHOAuthClient = profile.SSHOAuthClient
+	d.RefreshToken = profile.RefreshToken
+	d.UAAGrantType = profile.UAAGrantType
+	d.UAAOAuthClient = profile.UAAOAuthClient
+	d.UAAOAuthClientSecret = profile.UAAOAuthClientSecret
+	d.OrganizationFields = profile.OrganizationFields
+	d.SpaceFields = profile.SpaceFields
+	d.SSLDisabled = profile.SSLDisabled
+	d.CACertFile = profile.CACertFile
+	d.ClientCertFile = profile.ClientCertFile
+	d.ClientKeyFile = profile.ClientKeyFile
+	d.MinCLIVersion = profile.MinCLIVersion
+	d.MinRecommendedCLIVersion = profile.MinRecommendedCLIVersion
+}
+
+type byProfileName []Profile
+
+func (s byProfileName) Len() int           { return len(s) }
+func (s byProfileName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byProfileName) Less(i, j int) bool { return s[i].Name < s[j].Name }
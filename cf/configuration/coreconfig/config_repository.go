@@ -1,11 +1,14 @@
 package coreconfig
 
 import (
+	"errors"
 	"strings"
 	"sync"
 
 	"github.com/blang/semver"
 	"github.com/cloudfoundry/cli/cf/configuration"
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/cf/models"
 )
 
@@ -15,8 +18,17 @@ type ConfigRepository struct {
 	initOnce  *sync.Once
 	persistor configuration.Persistor
 	onError   func(error)
+	profile   string
+
+	credentialHelpers  CredentialHelperFactory
+	credentialHelper   credentials.Helper
+	credentialsProfile string
+	storedTokens       credentials.Tokens
 }
 
+// CredentialHelperFactory returns the credential helper with the given name.
+type CredentialHelperFactory func(name string) credentials.Helper
+
 type CCInfo struct {
 	APIVersion               string `json:"api_version"`
 	AuthorizationEndpoint    string `json:"authorization_endpoint"`
@@ -28,10 +40,24 @@ type CCInfo struct {
 }
 
 func NewRepositoryFromFilepath(filepath string, errorHandler func(error)) Repository {
+	return NewRepositoryForProfile(filepath, "", nil, errorHandler)
+}
+
+// NewRepositoryForProfile reads the config like NewRepositoryFromFilepath,
+// but uses the named profile instead of the current one. An empty name uses
+// the current profile.
+//
+// When the config names a credential helper, the tokens are read from and
+// written to the helper returned by credentialHelpers instead of the config.
+// Without credentialHelpers such a repository has no tokens.
+func NewRepositoryForProfile(filepath string, profile string, credentialHelpers CredentialHelperFactory, errorHandler func(error)) Repository {
 	if errorHandler == nil {
 		return nil
 	}
-	return NewRepositoryFromPersistor(configuration.NewDiskPersistor(filepath), errorHandler)
+	repo := NewRepositoryFromPersistor(configuration.NewDiskPersistor(filepath), errorHandler).(*ConfigRepository)
+	repo.profile = profile
+	repo.credentialHelpers = credentialHelpers
+	return repo
 }
 
 func NewRepositoryFromPersistor(persistor configuration.Persistor, errorHandler func(error)) Repository {
@@ -66,6 +92,9 @@ type Reader interface {
 	AccessToken() string
 	SSHOAuthClient() string
 	RefreshToken() string
+	UAAGrantType() string
+	UAAOAuthClient() string
+	UAAOAuthClientSecret() string
 
 	OrganizationFields() models.OrganizationFields
 	HasOrganization() bool
@@ -78,12 +107,17 @@ type Reader interface {
 	UserEmail() string
 	IsLoggedIn() bool
 	IsSSLDisabled() bool
+	CACertFile() string
+	ClientCertFile() string
+	ClientKeyFile() string
 	IsMinAPIVersion(semver.Version) bool
 	IsMinCLIVersion(string) bool
 	MinCLIVersion() string
 	MinRecommendedCLIVersion() string
 
 	AsyncTimeout() uint
+	Retries() int
+	RetryMaxWait() uint
 	Trace() string
 
 	ColorEnabled() string
@@ -91,6 +125,13 @@ type Reader interface {
 	Locale() string
 
 	PluginRepos() []models.PluginRepo
+
+	Aliases() map[string]string
+
+	ProfileName() string
+	Profiles() []Profile
+
+	CredentialHelper() string
 }
 
 //go:generate counterfeiter . ReadWriter
@@ -109,15 +150,27 @@ type ReadWriter interface {
 	SetAccessToken(string)
 	SetSSHOAuthClient(string)
 	SetRefreshToken(string)
+	SetUAAGrantType(string)
+	SetUAAOAuthClient(string)
+	SetUAAOAuthClientSecret(string)
 	SetOrganizationFields(models.OrganizationFields)
 	SetSpaceFields(models.SpaceFields)
 	SetSSLDisabled(bool)
+	SetCACertFile(string)
+	SetClientCertificate(certFile string, keyFile string)
 	SetAsyncTimeout(uint)
+	SetRetries(int)
+	SetRetryMaxWait(uint)
 	SetTrace(string)
 	SetColorEnabled(string)
 	SetLocale(string)
 	SetPluginRepo(models.PluginRepo)
 	UnSetPluginRepo(int)
+	SetAlias(name string, command string)
+	DeleteAlias(string)
+	UseProfile(string)
+	DeleteProfile(string)
+	SetCredentialHelper(string)
 }
 
 type Repository interface {
@@ -133,6 +186,14 @@ func (c *ConfigRepository) init() {
 		if err != nil {
 			c.onError(err)
 		}
+
+		if c.profile != "" {
+			if !c.data.hasProfile(c.profile) {
+				c.onError(errors.New(T("Profile {{.Name}} does not exist. Use 'cf profile use {{.Name}}' to create it.", map[string]interface{}{"Name": c.profile})))
+				return
+			}
+			c.data.activate(c.profile)
+		}
 	})
 }
 
@@ -151,12 +212,96 @@ func (c *ConfigRepository) write(cb func()) {
 
 	cb()
 
+	c.storeCredentials()
+
 	err := c.persistor.Save(c.data)
 	if err != nil {
 		c.onError(err)
 	}
 }
 
+// readCredentials is read for callbacks that use the tokens, which may first
+// have to be read from the credential helper.
+func (c *ConfigRepository) readCredentials(cb func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.init()
+
+	c.loadCredentials()
+
+	cb()
+}
+
+// CREDENTIALS
+
+func (c *ConfigRepository) getCredentialHelper() credentials.Helper {
+	if c.data.CredentialHelper == "" || c.credentialHelpers == nil {
+		return nil
+	}
+	if c.credentialHelper == nil {
+		c.credentialHelper = c.credentialHelpers(c.data.CredentialHelper)
+	}
+	return c.credentialHelper
+}
+
+// loadCredentials reads the tokens of the active profile from the credential
+// helper, the first time they are used. Tokens still in the config, such as
+// ones written by an older CLI, win and are moved to the helper on the next
+// write.
+func (c *ConfigRepository) loadCredentials() {
+	helper := c.getCredentialHelper()
+	profile := c.data.activeProfileName()
+	if helper == nil || c.credentialsProfile == profile {
+		return
+	}
+
+	tokens, err := helper.Get(profile)
+	if err != nil {
+		c.onError(err)
+		return
+	}
+
+	c.credentialsProfile = profile
+	c.storedTokens = tokens
+	if c.data.tokens().IsEmpty() {
+		c.data.setTokens(tokens)
+	}
+}
+
+// storeCredentials gives the tokens of the active profile to the credential
+// helper when they changed, before the config is written without them.
+func (c *ConfigRepository) storeCredentials() {
+	helper := c.getCredentialHelper()
+	if helper == nil {
+		return
+	}
+
+	profile := c.data.activeProfileName()
+	if c.credentialsProfile != profile {
+		if c.data.tokens().IsEmpty() {
+			return
+		}
+		c.loadCredentials()
+	}
+
+	tokens := c.data.tokens()
+	if tokens == c.storedTokens {
+		return
+	}
+
+	var err error
+	if tokens.IsEmpty() {
+		err = helper.Erase(profile)
+	} else {
+		err = helper.Store(profile, tokens)
+	}
+	if err != nil {
+		c.onError(err)
+		return
+	}
+	c.storedTokens = tokens
+}
+
 // CLOSERS
 
 func (c *ConfigRepository) Close() {
@@ -231,7 +376,7 @@ func (c *ConfigRepository) HasAPIEndpoint() (hasEndpoint bool) {
 }
 
 func (c *ConfigRepository) AccessToken() (accessToken string) {
-	c.read(func() {
+	c.readCredentials(func() {
 		accessToken = c.data.AccessToken
 	})
 	return
@@ -245,12 +390,33 @@ func (c *ConfigRepository) SSHOAuthClient() (clientID string) {
 }
 
 func (c *ConfigRepository) RefreshToken() (refreshToken string) {
-	c.read(func() {
+	c.readCredentials(func() {
 		refreshToken = c.data.RefreshToken
 	})
 	return
 }
 
+func (c *ConfigRepository) UAAGrantType() (grantType string) {
+	c.read(func() {
+		grantType = c.data.UAAGrantType
+	})
+	return
+}
+
+func (c *ConfigRepository) UAAOAuthClient() (clientID string) {
+	c.read(func() {
+		clientID = c.data.UAAOAuthClient
+	})
+	return
+}
+
+func (c *ConfigRepository) UAAOAuthClientSecret() (clientSecret string) {
+	c.readCredentials(func() {
+		clientSecret = c.data.UAAOAuthClientSecret
+	})
+	return
+}
+
 func (c *ConfigRepository) OrganizationFields() (org models.OrganizationFields) {
 	c.read(func() {
 		org = c.data.OrganizationFields
@@ -266,28 +432,34 @@ func (c *ConfigRepository) SpaceFields() (space models.SpaceFields) {
 }
 
 func (c *ConfigRepository) UserEmail() (email string) {
-	c.read(func() {
+	c.readCredentials(func() {
 		email = NewTokenInfo(c.data.AccessToken).Email
 	})
 	return
 }
 
 func (c *ConfigRepository) UserGUID() (guid string) {
-	c.read(func() {
+	c.readCredentials(func() {
 		guid = NewTokenInfo(c.data.AccessToken).UserGUID
 	})
 	return
 }
 
+// Username is the name of the logged in user, or the id of the client that
+// logged in with client credentials.
 func (c *ConfigRepository) Username() (name string) {
-	c.read(func() {
+	c.readCredentials(func() {
+		if c.data.UAAGrantType == ClientCredentialsGrantType {
+			name = c.data.UAAOAuthClient
+			return
+		}
 		name = NewTokenInfo(c.data.AccessToken).Username
 	})
 	return
 }
 
 func (c *ConfigRepository) IsLoggedIn() (loggedIn bool) {
-	c.read(func() {
+	c.readCredentials(func() {
 		loggedIn = c.data.AccessToken != ""
 	})
 	return
@@ -307,6 +479,27 @@ func (c *ConfigRepository) HasSpace() (hasSpace bool) {
 	return
 }
 
+func (c *ConfigRepository) CACertFile() (path string) {
+	c.read(func() {
+		path = c.data.CACertFile
+	})
+	return
+}
+
+func (c *ConfigRepository) ClientCertFile() (path string) {
+	c.read(func() {
+		path = c.data.ClientCertFile
+	})
+	return
+}
+
+func (c *ConfigRepository) ClientKeyFile() (path string) {
+	c.read(func() {
+		path = c.data.ClientKeyFile
+	})
+	return
+}
+
 func (c *ConfigRepository) IsSSLDisabled() (isSSLDisabled bool) {
 	c.read(func() {
 		isSSLDisabled = c.data.SSLDisabled
@@ -371,6 +564,25 @@ func (c *ConfigRepository) AsyncTimeout() (timeout uint) {
 	return
 }
 
+// Retries is the number of times a request that failed for a transient reason
+// is retried, or -1 when it has not been configured.
+func (c *ConfigRepository) Retries() (retries int) {
+	retries = -1
+	c.read(func() {
+		if c.data.Retries != nil {
+			retries = *c.data.Retries
+		}
+	})
+	return
+}
+
+func (c *ConfigRepository) RetryMaxWait() (seconds uint) {
+	c.read(func() {
+		seconds = c.data.RetryMaxWait
+	})
+	return
+}
+
 func (c *ConfigRepository) Trace() (trace string) {
 	c.read(func() {
 		trace = c.data.Trace
@@ -399,12 +611,49 @@ func (c *ConfigRepository) PluginRepos() (repos []models.PluginRepo) {
 	return
 }
 
+// Aliases returns the commands that user defined aliases expand to, by name.
+func (c *ConfigRepository) Aliases() (aliases map[string]string) {
+	aliases = map[string]string{}
+	c.read(func() {
+		for name, command := range c.data.Aliases {
+			aliases[name] = command
+		}
+	})
+	return
+}
+
+// ProfileName returns the name of the profile in use.
+func (c *ConfigRepository) ProfileName() (name string) {
+	c.read(func() {
+		name = c.data.activeProfileName()
+	})
+	return
+}
+
+func (c *ConfigRepository) Profiles() (profiles []Profile) {
+	c.readCredentials(func() {
+		profiles = c.data.allProfiles()
+	})
+	return
+}
+
+func (c *ConfigRepository) CredentialHelper() (name string) {
+	c.read(func() {
+		name = c.data.CredentialHelper
+	})
+	return
+}
+
 // SETTERS
 
 func (c *ConfigRepository) ClearSession() {
 	c.write(func() {
+		c.loadCredentials()
 		c.data.AccessToken = ""
 		c.data.RefreshToken = ""
+		c.data.UAAGrantType = ""
+		c.data.UAAOAuthClient = ""
+		c.data.UAAOAuthClientSecret = ""
 		c.data.OrganizationFields = models.OrganizationFields{}
 		c.data.SpaceFields = models.SpaceFields{}
 	})
@@ -466,6 +715,7 @@ func (c *ConfigRepository) SetRoutingAPIEndpoint(routingAPIEndpoint string) {
 
 func (c *ConfigRepository) SetAccessToken(token string) {
 	c.write(func() {
+		c.loadCredentials()
 		c.data.AccessToken = token
 	})
 }
@@ -478,10 +728,30 @@ func (c *ConfigRepository) SetSSHOAuthClient(clientID string) {
 
 func (c *ConfigRepository) SetRefreshToken(token string) {
 	c.write(func() {
+		c.loadCredentials()
 		c.data.RefreshToken = token
 	})
 }
 
+func (c *ConfigRepository) SetUAAGrantType(grantType string) {
+	c.write(func() {
+		c.data.UAAGrantType = grantType
+	})
+}
+
+func (c *ConfigRepository) SetUAAOAuthClient(clientID string) {
+	c.write(func() {
+		c.data.UAAOAuthClient = clientID
+	})
+}
+
+func (c *ConfigRepository) SetUAAOAuthClientSecret(clientSecret string) {
+	c.write(func() {
+		c.loadCredentials()
+		c.data.UAAOAuthClientSecret = clientSecret
+	})
+}
+
 func (c *ConfigRepository) SetOrganizationFields(org models.OrganizationFields) {
 	c.write(func() {
 		c.data.OrganizationFields = org
@@ -500,12 +770,43 @@ func (c *ConfigRepository) SetSSLDisabled(disabled bool) {
 	})
 }
 
+func (c *ConfigRepository) SetCACertFile(path string) {
+	c.write(func() {
+		c.data.CACertFile = path
+	})
+}
+
+func (c *ConfigRepository) SetClientCertificate(certFile string, keyFile string) {
+	c.write(func() {
+		c.data.ClientCertFile = certFile
+		c.data.ClientKeyFile = keyFile
+	})
+}
+
 func (c *ConfigRepository) SetAsyncTimeout(timeout uint) {
 	c.write(func() {
 		c.data.AsyncTimeout = timeout
 	})
 }
 
+// SetRetries sets the number of retries; a negative number restores the
+// default.
+func (c *ConfigRepository) SetRetries(retries int) {
+	c.write(func() {
+		if retries < 0 {
+			c.data.Retries = nil
+		} else {
+			c.data.Retries = &retries
+		}
+	})
+}
+
+func (c *ConfigRepository) SetRetryMaxWait(seconds uint) {
+	c.write(func() {
+		c.data.RetryMaxWait = seconds
+	})
+}
+
 func (c *ConfigRepository) SetTrace(value string) {
 	c.write(func() {
 		c.data.Trace = value
@@ -535,3 +836,100 @@ func (c *ConfigRepository) UnSetPluginRepo(index int) {
 		c.data.PluginRepos = append(c.data.PluginRepos[:index], c.data.PluginRepos[index+1:]...)
 	})
 }
+
+func (c *ConfigRepository) SetAlias(name string, command string) {
+	c.write(func() {
+		if c.data.Aliases == nil {
+			c.data.Aliases = map[string]string{}
+		}
+		c.data.Aliases[name] = command
+	})
+}
+
+func (c *ConfigRepository) DeleteAlias(name string) {
+	c.write(func() {
+		delete(c.data.Aliases, name)
+	})
+}
+
+// UseProfile switches to the named profile, creating an empty one if it does
+// not exist yet.
+func (c *ConfigRepository) UseProfile(name string) {
+	c.write(func() {
+		c.data.useProfile(name)
+	})
+}
+
+// DeleteProfile forgets the named profile. The profile in use cannot be
+// deleted.
+func (c *ConfigRepository) DeleteProfile(name string) {
+	c.write(func() {
+		if !c.data.deleteProfile(name) {
+			return
+		}
+
+		if helper := c.getCredentialHelper(); helper != nil {
+			err := helper.Erase(name)
+			if err != nil {
+				c.onError(err)
+			}
+		}
+	})
+}
+
+// SetCredentialHelper moves the tokens of every profile to the named
+// credential helper, or back into the config when name is empty.
+func (c *ConfigRepository) SetCredentialHelper(name string) {
+	c.write(func() {
+		if name == c.data.CredentialHelper {
+			return
+		}
+
+		c.loadCredentials()
+		active := c.data.activeProfileName()
+		previousHelper := c.getCredentialHelper()
+
+		tokens := map[string]credentials.Tokens{}
+		for _, profile := range c.data.allProfiles() {
+			profileTokens := profile.tokens()
+			if previousHelper != nil && profile.Name != active {
+				var err error
+				profileTokens, err = previousHelper.Get(profile.Name)
+				if err != nil {
+					c.onError(err)
+					return
+				}
+			}
+			tokens[profile.Name] = profileTokens
+		}
+
+		c.data.CredentialHelper = name
+		c.credentialHelper = nil
+		nextHelper := c.getCredentialHelper()
+
+		for profile, profileTokens := range tokens {
+			if nextHelper == nil {
+				if profile != active {
+					c.data.setProfileTokens(profile, profileTokens)
+				}
+			} else if !profileTokens.IsEmpty() {
+				err := nextHelper.Store(profile, profileTokens)
+				if err != nil {
+					c.onError(err)
+					return
+				}
+			}
+
+			if previousHelper != nil {
+				err := previousHelper.Erase(profile)
+				if err != nil {
+					c.onError(err)
+					return
+				}
+			}
+		}
+
+		c.credentialsProfile = active
+		c.storedTokens = tokens[active]
+	})
+}
@@ -1,6 +1,7 @@
 package coreconfig_test
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -9,6 +10,8 @@ import (
 	"github.com/cloudfoundry/cli/cf/configuration"
 	"github.com/cloudfoundry/cli/cf/configuration/configurationfakes"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+	"github.com/cloudfoundry/cli/cf/configuration/credentials/credentialsfakes"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/testhelpers/maker"
 
@@ -89,6 +92,15 @@ var _ = Describe("Configuration Repository", func() {
 		config.SetRefreshToken("the-token")
 		Expect(config.RefreshToken()).To(Equal("the-token"))
 
+		config.SetUAAGrantType("client_credentials")
+		Expect(config.UAAGrantType()).To(Equal("client_credentials"))
+
+		config.SetUAAOAuthClient("client-id")
+		Expect(config.UAAOAuthClient()).To(Equal("client-id"))
+
+		config.SetUAAOAuthClientSecret("client-secret")
+		Expect(config.UAAOAuthClientSecret()).To(Equal("client-secret"))
+
 		organization := maker.NewOrgFields(maker.Overrides{"name": "the-org"})
 		config.SetOrganizationFields(organization)
 		Expect(config.OrganizationFields()).To(Equal(organization))
@@ -103,10 +115,27 @@ var _ = Describe("Configuration Repository", func() {
 		config.SetLocale("en_US")
 		Expect(config.Locale()).To(Equal("en_US"))
 
+		Expect(config.Retries()).To(Equal(-1))
+		config.SetRetries(0)
+		Expect(config.Retries()).To(Equal(0))
+		config.SetRetries(-1)
+		Expect(config.Retries()).To(Equal(-1))
+
+		config.SetRetryMaxWait(45)
+		Expect(config.RetryMaxWait()).To(Equal(uint(45)))
+
 		config.SetPluginRepo(models.PluginRepo{Name: "repo", URL: "nowhere.com"})
 		Expect(config.PluginRepos()[0].Name).To(Equal("repo"))
 		Expect(config.PluginRepos()[0].URL).To(Equal("nowhere.com"))
 
+		Expect(config.Aliases()).To(BeEmpty())
+		config.SetAlias("deploy-prod", "push -f manifests/prod.yml")
+		Expect(config.Aliases()).To(Equal(map[string]string{"deploy-prod": "push -f manifests/prod.yml"}))
+		config.Aliases()["other"] = "apps"
+		Expect(config.Aliases()).To(HaveLen(1))
+		config.DeleteAlias("deploy-prod")
+		Expect(config.Aliases()).To(BeEmpty())
+
 		s, _ := semver.Make("3.1")
 		Expect(config.IsMinAPIVersion(s)).To(Equal(false))
 
@@ -117,6 +146,27 @@ var _ = Describe("Configuration Repository", func() {
 		Expect(config.MinRecommendedCLIVersion()).To(Equal("6.9.0"))
 	})
 
+	Describe("client credentials", func() {
+		BeforeEach(func() {
+			config.SetAccessToken("bearer client-token")
+			config.SetUAAGrantType(coreconfig.ClientCredentialsGrantType)
+			config.SetUAAOAuthClient("my-client")
+			config.SetUAAOAuthClientSecret("my-secret")
+		})
+
+		It("uses the client id as the username", func() {
+			Expect(config.Username()).To(Equal("my-client"))
+		})
+
+		It("forgets the client credentials when the session is cleared", func() {
+			config.ClearSession()
+
+			Expect(config.UAAGrantType()).To(BeEmpty())
+			Expect(config.UAAOAuthClient()).To(BeEmpty())
+			Expect(config.UAAOAuthClientSecret()).To(BeEmpty())
+		})
+	})
+
 	Describe("HasAPIEndpoint", func() {
 		Context("when both endpoint and version are set", func() {
 			BeforeEach(func() {
@@ -249,6 +299,322 @@ var _ = Describe("Configuration Repository", func() {
 		})
 	})
 
+	Describe("profiles", func() {
+		var (
+			tmpDir     string
+			configPath string
+		)
+
+		newConfig := func(profile string) coreconfig.Repository {
+			return coreconfig.NewRepositoryForProfile(configPath, profile, nil, func(err error) {
+				panic(err)
+			})
+		}
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "test-config")
+			Expect(err).NotTo(HaveOccurred())
+			configPath = filepath.Join(tmpDir, "config.json")
+
+			config = newConfig("")
+			config.SetAPIEndpoint("https://api.dev.example.com")
+			config.SetAccessToken("dev-token")
+			config.SetSpaceFields(models.SpaceFields{GUID: "dev-space-guid", Name: "dev-space"})
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		It("uses the default profile for a config that never switched profiles", func() {
+			Expect(config.ProfileName()).To(Equal(coreconfig.DefaultProfileName))
+			Expect(config.Profiles()).To(HaveLen(1))
+			Expect(config.Profiles()[0].Name).To(Equal(coreconfig.DefaultProfileName))
+			Expect(config.Profiles()[0].Target).To(Equal("https://api.dev.example.com"))
+		})
+
+		It("starts a new profile without any endpoint, login or target", func() {
+			config.UseProfile("prod")
+
+			Expect(config.ProfileName()).To(Equal("prod"))
+			Expect(config.APIEndpoint()).To(BeEmpty())
+			Expect(config.AccessToken()).To(BeEmpty())
+			Expect(config.HasSpace()).To(BeFalse())
+		})
+
+		It("keeps each profile's endpoint, login and target", func() {
+			config.UseProfile("prod")
+			config.SetAPIEndpoint("https://api.prod.example.com")
+			config.SetAccessToken("prod-token")
+			config.SetSSLDisabled(true)
+
+			config.UseProfile(coreconfig.DefaultProfileName)
+			Expect(config.APIEndpoint()).To(Equal("https://api.dev.example.com"))
+			Expect(config.AccessToken()).To(Equal("dev-token"))
+			Expect(config.SpaceFields().Name).To(Equal("dev-space"))
+			Expect(config.IsSSLDisabled()).To(BeFalse())
+
+			config.UseProfile("prod")
+			Expect(config.APIEndpoint()).To(Equal("https://api.prod.example.com"))
+			Expect(config.AccessToken()).To(Equal("prod-token"))
+			Expect(config.IsSSLDisabled()).To(BeTrue())
+		})
+
+		It("keeps settings that are not part of a profile when switching", func() {
+			config.SetLocale("fr_FR")
+			config.SetAlias("deploy", "push")
+			config.UseProfile("prod")
+
+			Expect(config.Locale()).To(Equal("fr_FR"))
+			Expect(config.Aliases()).To(HaveKeyWithValue("deploy", "push"))
+		})
+
+		It("remembers the current profile", func() {
+			config.UseProfile("prod")
+			config.SetAPIEndpoint("https://api.prod.example.com")
+
+			config = newConfig("")
+			Expect(config.ProfileName()).To(Equal("prod"))
+			Expect(config.APIEndpoint()).To(Equal("https://api.prod.example.com"))
+
+			names := []string{}
+			for _, profile := range config.Profiles() {
+				names = append(names, profile.Name)
+			}
+			Expect(names).To(Equal([]string{"default", "prod"}))
+		})
+
+		It("keeps the current profile in the top level of the config file for older CLIs", func() {
+			config.UseProfile("prod")
+			config.SetAPIEndpoint("https://api.prod.example.com")
+
+			data := coreconfig.NewData()
+			contents, err := ioutil.ReadFile(configPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data.JSONUnmarshalV3(contents)).To(Succeed())
+
+			Expect(data.Target).To(Equal("https://api.prod.example.com"))
+			Expect(data.CurrentProfile).To(Equal("prod"))
+			Expect(data.Profiles).To(HaveLen(1))
+			Expect(data.Profiles[0].Name).To(Equal("default"))
+			Expect(data.Profiles[0].Target).To(Equal("https://api.dev.example.com"))
+		})
+
+		Describe("NewRepositoryForProfile", func() {
+			BeforeEach(func() {
+				config.UseProfile("prod")
+				config.SetAPIEndpoint("https://api.prod.example.com")
+				config.UseProfile(coreconfig.DefaultProfileName)
+			})
+
+			It("uses the named profile without making it the current one", func() {
+				config = newConfig("prod")
+				Expect(config.ProfileName()).To(Equal("prod"))
+				Expect(config.APIEndpoint()).To(Equal("https://api.prod.example.com"))
+
+				config.SetAccessToken("refreshed-prod-token")
+
+				config = newConfig("")
+				Expect(config.ProfileName()).To(Equal(coreconfig.DefaultProfileName))
+				Expect(config.APIEndpoint()).To(Equal("https://api.dev.example.com"))
+				Expect(config.AccessToken()).To(Equal("dev-token"))
+
+				config.UseProfile("prod")
+				Expect(config.AccessToken()).To(Equal("refreshed-prod-token"))
+			})
+
+			It("reports an error for a profile that does not exist", func() {
+				var reportedErr error
+				config = coreconfig.NewRepositoryForProfile(configPath, "staging", nil, func(err error) {
+					reportedErr = err
+				})
+				config.APIEndpoint()
+
+				Expect(reportedErr).To(HaveOccurred())
+				Expect(reportedErr.Error()).To(ContainSubstring("Profile staging does not exist"))
+			})
+		})
+
+		Describe("DeleteProfile", func() {
+			It("forgets the profile", func() {
+				config.UseProfile("prod")
+				config.UseProfile(coreconfig.DefaultProfileName)
+
+				config.DeleteProfile("prod")
+				Expect(config.Profiles()).To(HaveLen(1))
+
+				config.UseProfile("prod")
+				Expect(config.APIEndpoint()).To(BeEmpty())
+			})
+
+			It("does not delete the current profile", func() {
+				config.DeleteProfile(coreconfig.DefaultProfileName)
+
+				Expect(config.APIEndpoint()).To(Equal("https://api.dev.example.com"))
+				Expect(config.Profiles()).To(HaveLen(1))
+			})
+		})
+	})
+
+	Describe("credential helpers", func() {
+		var (
+			tmpDir     string
+			configPath string
+			helper     *credentialsfakes.FakeHelper
+			stored     map[string]credentials.Tokens
+		)
+
+		newConfig := func() coreconfig.Repository {
+			return coreconfig.NewRepositoryForProfile(configPath, "", func(name string) credentials.Helper {
+				Expect(name).To(Equal("keychain"))
+				return helper
+			}, func(err error) {
+				panic(err)
+			})
+		}
+
+		readConfigFile := func() string {
+			contents, err := ioutil.ReadFile(configPath)
+			Expect(err).NotTo(HaveOccurred())
+			return string(contents)
+		}
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "test-config")
+			Expect(err).NotTo(HaveOccurred())
+			configPath = filepath.Join(tmpDir, "config.json")
+
+			stored = map[string]credentials.Tokens{}
+			helper = new(credentialsfakes.FakeHelper)
+			helper.GetStub = func(profile string) (credentials.Tokens, error) {
+				return stored[profile], nil
+			}
+			helper.StoreStub = func(profile string, tokens credentials.Tokens) error {
+				stored[profile] = tokens
+				return nil
+			}
+			helper.EraseStub = func(profile string) error {
+				delete(stored, profile)
+				return nil
+			}
+
+			config = newConfig()
+			config.SetAPIEndpoint("https://api.dev.example.com")
+			config.SetAccessToken("dev-access-token")
+			config.SetRefreshToken("dev-refresh-token")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		It("moves the client secret out of the config", func() {
+			config.SetUAAGrantType(coreconfig.ClientCredentialsGrantType)
+			config.SetUAAOAuthClient("my-client")
+			config.SetUAAOAuthClientSecret("my-client-secret")
+
+			config.SetCredentialHelper("keychain")
+
+			Expect(readConfigFile()).To(ContainSubstring("my-client"))
+			Expect(readConfigFile()).NotTo(ContainSubstring("my-client-secret"))
+			Expect(stored["default"].ClientSecret).To(Equal("my-client-secret"))
+			Expect(newConfig().UAAOAuthClientSecret()).To(Equal("my-client-secret"))
+		})
+
+		It("moves the tokens of every profile out of the config", func() {
+			config.UseProfile("prod")
+			config.SetAccessToken("prod-access-token")
+			config.UseProfile(coreconfig.DefaultProfileName)
+
+			config.SetCredentialHelper("keychain")
+
+			Expect(readConfigFile()).NotTo(ContainSubstring("access-token"))
+			Expect(readConfigFile()).NotTo(ContainSubstring("refresh-token"))
+			Expect(stored).To(Equal(map[string]credentials.Tokens{
+				"default": {AccessToken: "dev-access-token", RefreshToken: "dev-refresh-token"},
+				"prod":    {AccessToken: "prod-access-token"},
+			}))
+		})
+
+		Context("when a credential helper is configured", func() {
+			BeforeEach(func() {
+				config.SetCredentialHelper("keychain")
+				config = newConfig()
+			})
+
+			It("reads the tokens from the helper", func() {
+				Expect(config.AccessToken()).To(Equal("dev-access-token"))
+				Expect(config.RefreshToken()).To(Equal("dev-refresh-token"))
+				Expect(helper.GetArgsForCall(helper.GetCallCount() - 1)).To(Equal("default"))
+			})
+
+			It("does not use the helper when no token is needed", func() {
+				getCalls := helper.GetCallCount()
+
+				Expect(config.APIEndpoint()).To(Equal("https://api.dev.example.com"))
+				config.SetLocale("fr_FR")
+
+				Expect(helper.GetCallCount()).To(Equal(getCalls))
+			})
+
+			It("gives new tokens to the helper", func() {
+				config.SetAccessToken("new-access-token")
+
+				Expect(stored["default"]).To(Equal(credentials.Tokens{AccessToken: "new-access-token", RefreshToken: "dev-refresh-token"}))
+				Expect(readConfigFile()).NotTo(ContainSubstring("access-token"))
+			})
+
+			It("erases the tokens when logging out", func() {
+				config.ClearSession()
+
+				Expect(stored).NotTo(HaveKey("default"))
+				Expect(newConfig().IsLoggedIn()).To(BeFalse())
+			})
+
+			It("keeps the tokens of each profile", func() {
+				config.UseProfile("prod")
+				Expect(config.AccessToken()).To(BeEmpty())
+				config.SetAccessToken("prod-access-token")
+
+				config.UseProfile(coreconfig.DefaultProfileName)
+				Expect(config.AccessToken()).To(Equal("dev-access-token"))
+
+				config.UseProfile("prod")
+				Expect(config.AccessToken()).To(Equal("prod-access-token"))
+			})
+
+			It("erases the tokens of a deleted profile", func() {
+				config.UseProfile("prod")
+				config.SetAccessToken("prod-access-token")
+				config.UseProfile(coreconfig.DefaultProfileName)
+
+				config.DeleteProfile("prod")
+				Expect(stored).NotTo(HaveKey("prod"))
+			})
+
+			It("moves the tokens back into the config when the helper is cleared", func() {
+				config.UseProfile("prod")
+				config.SetAccessToken("prod-access-token")
+				config.UseProfile(coreconfig.DefaultProfileName)
+
+				config.SetCredentialHelper("")
+
+				Expect(stored).To(BeEmpty())
+				Expect(readConfigFile()).To(ContainSubstring("dev-access-token"))
+				Expect(readConfigFile()).To(ContainSubstring("prod-access-token"))
+			})
+
+			It("reports errors from the helper", func() {
+				helper.GetStub = nil
+				helper.GetReturns(credentials.Tokens{}, errors.New("keychain is locked"))
+
+				Expect(func() { config.AccessToken() }).To(Panic())
+			})
+		})
+	})
+
 	Describe("IsMinCLIVersion", func() {
 		It("returns true when the actual version is BUILT_FROM_SOURCE", func() {
 			Expect(config.IsMinCLIVersion("BUILT_FROM_SOURCE")).To(BeTrue())
@@ -188,6 +188,18 @@ type FakeReadWriter struct {
 	pluginReposReturns     struct {
 		result1 []models.PluginRepo
 	}
+	ProfileNameStub        func() string
+	profileNameMutex       sync.RWMutex
+	profileNameArgsForCall []struct{}
+	profileNameReturns     struct {
+		result1 string
+	}
+	ProfilesStub        func() []coreconfig.Profile
+	profilesMutex       sync.RWMutex
+	profilesArgsForCall []struct{}
+	profilesReturns     struct {
+		result1 []coreconfig.Profile
+	}
 	ClearSessionStub          func()
 	clearSessionMutex         sync.RWMutex
 	clearSessionArgsForCall   []struct{}
@@ -296,6 +308,128 @@ type FakeReadWriter struct {
 	unSetPluginRepoArgsForCall []struct {
 		arg1 int
 	}
+	UseProfileStub        func(string)
+	useProfileMutex       sync.RWMutex
+	useProfileArgsForCall []struct {
+		arg1 string
+	}
+	DeleteProfileStub        func(string)
+	deleteProfileMutex       sync.RWMutex
+	deleteProfileArgsForCall []struct {
+		arg1 string
+	}
+	CredentialHelperStub        func() string
+	credentialHelperMutex       sync.RWMutex
+	credentialHelperArgsForCall []struct{}
+	credentialHelperReturns     struct {
+		result1 string
+	}
+	SetCredentialHelperStub        func(string)
+	setCredentialHelperMutex       sync.RWMutex
+	setCredentialHelperArgsForCall []struct {
+		arg1 string
+	}
+	UAAGrantTypeStub        func() string
+	uAAGrantTypeMutex       sync.RWMutex
+	uAAGrantTypeArgsForCall []struct{}
+	uAAGrantTypeReturns     struct {
+		result1 string
+	}
+	UAAOAuthClientStub        func() string
+	uAAOAuthClientMutex       sync.RWMutex
+	uAAOAuthClientArgsForCall []struct{}
+	uAAOAuthClientReturns     struct {
+		result1 string
+	}
+	UAAOAuthClientSecretStub        func() string
+	uAAOAuthClientSecretMutex       sync.RWMutex
+	uAAOAuthClientSecretArgsForCall []struct{}
+	uAAOAuthClientSecretReturns     struct {
+		result1 string
+	}
+	SetUAAGrantTypeStub        func(string)
+	setUAAGrantTypeMutex       sync.RWMutex
+	setUAAGrantTypeArgsForCall []struct {
+		arg1 string
+	}
+	SetUAAOAuthClientStub        func(string)
+	setUAAOAuthClientMutex       sync.RWMutex
+	setUAAOAuthClientArgsForCall []struct {
+		arg1 string
+	}
+	SetUAAOAuthClientSecretStub        func(string)
+	setUAAOAuthClientSecretMutex       sync.RWMutex
+	setUAAOAuthClientSecretArgsForCall []struct {
+		arg1 string
+	}
+	RetriesStub        func() int
+	retriesMutex       sync.RWMutex
+	retriesArgsForCall []struct{}
+	retriesReturns     struct {
+		result1 int
+	}
+	RetryMaxWaitStub        func() uint
+	retryMaxWaitMutex       sync.RWMutex
+	retryMaxWaitArgsForCall []struct{}
+	retryMaxWaitReturns     struct {
+		result1 uint
+	}
+	SetRetriesStub        func(int)
+	setRetriesMutex       sync.RWMutex
+	setRetriesArgsForCall []struct {
+		arg1 int
+	}
+	SetRetryMaxWaitStub        func(uint)
+	setRetryMaxWaitMutex       sync.RWMutex
+	setRetryMaxWaitArgsForCall []struct {
+		arg1 uint
+	}
+	CACertFileStub        func() string
+	cACertFileMutex       sync.RWMutex
+	cACertFileArgsForCall []struct{}
+	cACertFileReturns     struct {
+		result1 string
+	}
+	ClientCertFileStub        func() string
+	clientCertFileMutex       sync.RWMutex
+	clientCertFileArgsForCall []struct{}
+	clientCertFileReturns     struct {
+		result1 string
+	}
+	ClientKeyFileStub        func() string
+	clientKeyFileMutex       sync.RWMutex
+	clientKeyFileArgsForCall []struct{}
+	clientKeyFileReturns     struct {
+		result1 string
+	}
+	SetClientCertificateStub        func(certFile string, keyFile string)
+	setClientCertificateMutex       sync.RWMutex
+	setClientCertificateArgsForCall []struct {
+		certFile string
+		keyFile  string
+	}
+	SetAliasStub        func(name string, command string)
+	setAliasMutex       sync.RWMutex
+	setAliasArgsForCall []struct {
+		name    string
+		command string
+	}
+	SetCACertFileStub        func(string)
+	setCACertFileMutex       sync.RWMutex
+	setCACertFileArgsForCall []struct {
+		arg1 string
+	}
+	AliasesStub        func() map[string]string
+	aliasesMutex       sync.RWMutex
+	aliasesArgsForCall []struct{}
+	aliasesReturns     struct {
+		result1 map[string]string
+	}
+	DeleteAliasStub        func(string)
+	deleteAliasMutex       sync.RWMutex
+	deleteAliasArgsForCall []struct {
+		arg1 string
+	}
 }
 
 func (fake *FakeReadWriter) APIEndpoint() string {
@@ -1010,6 +1144,54 @@ func (fake *FakeReadWriter) PluginReposReturns(result1 []models.PluginRepo) {
 	}{result1}
 }
 
+func (fake *FakeReadWriter) ProfileName() string {
+	fake.profileNameMutex.Lock()
+	fake.profileNameArgsForCall = append(fake.profileNameArgsForCall, struct{}{})
+	fake.profileNameMutex.Unlock()
+	if fake.ProfileNameStub != nil {
+		return fake.ProfileNameStub()
+	} else {
+		return fake.profileNameReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) ProfileNameCallCount() int {
+	fake.profileNameMutex.RLock()
+	defer fake.profileNameMutex.RUnlock()
+	return len(fake.profileNameArgsForCall)
+}
+
+func (fake *FakeReadWriter) ProfileNameReturns(result1 string) {
+	fake.ProfileNameStub = nil
+	fake.profileNameReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) Profiles() []coreconfig.Profile {
+	fake.profilesMutex.Lock()
+	fake.profilesArgsForCall = append(fake.profilesArgsForCall, struct{}{})
+	fake.profilesMutex.Unlock()
+	if fake.ProfilesStub != nil {
+		return fake.ProfilesStub()
+	} else {
+		return fake.profilesReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) ProfilesCallCount() int {
+	fake.profilesMutex.RLock()
+	defer fake.profilesMutex.RUnlock()
+	return len(fake.profilesArgsForCall)
+}
+
+func (fake *FakeReadWriter) ProfilesReturns(result1 []coreconfig.Profile) {
+	fake.ProfilesStub = nil
+	fake.profilesReturns = struct {
+		result1 []coreconfig.Profile
+	}{result1}
+}
+
 func (fake *FakeReadWriter) ClearSession() {
 	fake.clearSessionMutex.Lock()
 	fake.clearSessionArgsForCall = append(fake.clearSessionArgsForCall, struct{}{})
@@ -1508,4 +1690,522 @@ func (fake *FakeReadWriter) UnSetPluginRepoArgsForCall(i int) int {
 	return fake.unSetPluginRepoArgsForCall[i].arg1
 }
 
+func (fake *FakeReadWriter) UseProfile(arg1 string) {
+	fake.useProfileMutex.Lock()
+	fake.useProfileArgsForCall = append(fake.useProfileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.useProfileMutex.Unlock()
+	if fake.UseProfileStub != nil {
+		fake.UseProfileStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) UseProfileCallCount() int {
+	fake.useProfileMutex.RLock()
+	defer fake.useProfileMutex.RUnlock()
+	return len(fake.useProfileArgsForCall)
+}
+
+func (fake *FakeReadWriter) UseProfileArgsForCall(i int) string {
+	fake.useProfileMutex.RLock()
+	defer fake.useProfileMutex.RUnlock()
+	return fake.useProfileArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) DeleteProfile(arg1 string) {
+	fake.deleteProfileMutex.Lock()
+	fake.deleteProfileArgsForCall = append(fake.deleteProfileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.deleteProfileMutex.Unlock()
+	if fake.DeleteProfileStub != nil {
+		fake.DeleteProfileStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) DeleteProfileCallCount() int {
+	fake.deleteProfileMutex.RLock()
+	defer fake.deleteProfileMutex.RUnlock()
+	return len(fake.deleteProfileArgsForCall)
+}
+
+func (fake *FakeReadWriter) DeleteProfileArgsForCall(i int) string {
+	fake.deleteProfileMutex.RLock()
+	defer fake.deleteProfileMutex.RUnlock()
+	return fake.deleteProfileArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) CredentialHelper() string {
+	fake.credentialHelperMutex.Lock()
+	fake.credentialHelperArgsForCall = append(fake.credentialHelperArgsForCall, struct{}{})
+	fake.credentialHelperMutex.Unlock()
+	if fake.CredentialHelperStub != nil {
+		return fake.CredentialHelperStub()
+	} else {
+		return fake.credentialHelperReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) CredentialHelperCallCount() int {
+	fake.credentialHelperMutex.RLock()
+	defer fake.credentialHelperMutex.RUnlock()
+	return len(fake.credentialHelperArgsForCall)
+}
+
+func (fake *FakeReadWriter) CredentialHelperReturns(result1 string) {
+	fake.CredentialHelperStub = nil
+	fake.credentialHelperReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) SetCredentialHelper(arg1 string) {
+	fake.setCredentialHelperMutex.Lock()
+	fake.setCredentialHelperArgsForCall = append(fake.setCredentialHelperArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setCredentialHelperMutex.Unlock()
+	if fake.SetCredentialHelperStub != nil {
+		fake.SetCredentialHelperStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetCredentialHelperCallCount() int {
+	fake.setCredentialHelperMutex.RLock()
+	defer fake.setCredentialHelperMutex.RUnlock()
+	return len(fake.setCredentialHelperArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetCredentialHelperArgsForCall(i int) string {
+	fake.setCredentialHelperMutex.RLock()
+	defer fake.setCredentialHelperMutex.RUnlock()
+	return fake.setCredentialHelperArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) UAAGrantType() string {
+	fake.uAAGrantTypeMutex.Lock()
+	fake.uAAGrantTypeArgsForCall = append(fake.uAAGrantTypeArgsForCall, struct{}{})
+	fake.uAAGrantTypeMutex.Unlock()
+	if fake.UAAGrantTypeStub != nil {
+		return fake.UAAGrantTypeStub()
+	} else {
+		return fake.uAAGrantTypeReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) UAAGrantTypeCallCount() int {
+	fake.uAAGrantTypeMutex.RLock()
+	defer fake.uAAGrantTypeMutex.RUnlock()
+	return len(fake.uAAGrantTypeArgsForCall)
+}
+
+func (fake *FakeReadWriter) UAAGrantTypeReturns(result1 string) {
+	fake.UAAGrantTypeStub = nil
+	fake.uAAGrantTypeReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) UAAOAuthClient() string {
+	fake.uAAOAuthClientMutex.Lock()
+	fake.uAAOAuthClientArgsForCall = append(fake.uAAOAuthClientArgsForCall, struct{}{})
+	fake.uAAOAuthClientMutex.Unlock()
+	if fake.UAAOAuthClientStub != nil {
+		return fake.UAAOAuthClientStub()
+	} else {
+		return fake.uAAOAuthClientReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientCallCount() int {
+	fake.uAAOAuthClientMutex.RLock()
+	defer fake.uAAOAuthClientMutex.RUnlock()
+	return len(fake.uAAOAuthClientArgsForCall)
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientReturns(result1 string) {
+	fake.UAAOAuthClientStub = nil
+	fake.uAAOAuthClientReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientSecret() string {
+	fake.uAAOAuthClientSecretMutex.Lock()
+	fake.uAAOAuthClientSecretArgsForCall = append(fake.uAAOAuthClientSecretArgsForCall, struct{}{})
+	fake.uAAOAuthClientSecretMutex.Unlock()
+	if fake.UAAOAuthClientSecretStub != nil {
+		return fake.UAAOAuthClientSecretStub()
+	} else {
+		return fake.uAAOAuthClientSecretReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientSecretCallCount() int {
+	fake.uAAOAuthClientSecretMutex.RLock()
+	defer fake.uAAOAuthClientSecretMutex.RUnlock()
+	return len(fake.uAAOAuthClientSecretArgsForCall)
+}
+
+func (fake *FakeReadWriter) UAAOAuthClientSecretReturns(result1 string) {
+	fake.UAAOAuthClientSecretStub = nil
+	fake.uAAOAuthClientSecretReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) SetUAAGrantType(arg1 string) {
+	fake.setUAAGrantTypeMutex.Lock()
+	fake.setUAAGrantTypeArgsForCall = append(fake.setUAAGrantTypeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setUAAGrantTypeMutex.Unlock()
+	if fake.SetUAAGrantTypeStub != nil {
+		fake.SetUAAGrantTypeStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetUAAGrantTypeCallCount() int {
+	fake.setUAAGrantTypeMutex.RLock()
+	defer fake.setUAAGrantTypeMutex.RUnlock()
+	return len(fake.setUAAGrantTypeArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetUAAGrantTypeArgsForCall(i int) string {
+	fake.setUAAGrantTypeMutex.RLock()
+	defer fake.setUAAGrantTypeMutex.RUnlock()
+	return fake.setUAAGrantTypeArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClient(arg1 string) {
+	fake.setUAAOAuthClientMutex.Lock()
+	fake.setUAAOAuthClientArgsForCall = append(fake.setUAAOAuthClientArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setUAAOAuthClientMutex.Unlock()
+	if fake.SetUAAOAuthClientStub != nil {
+		fake.SetUAAOAuthClientStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientCallCount() int {
+	fake.setUAAOAuthClientMutex.RLock()
+	defer fake.setUAAOAuthClientMutex.RUnlock()
+	return len(fake.setUAAOAuthClientArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientArgsForCall(i int) string {
+	fake.setUAAOAuthClientMutex.RLock()
+	defer fake.setUAAOAuthClientMutex.RUnlock()
+	return fake.setUAAOAuthClientArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientSecret(arg1 string) {
+	fake.setUAAOAuthClientSecretMutex.Lock()
+	fake.setUAAOAuthClientSecretArgsForCall = append(fake.setUAAOAuthClientSecretArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setUAAOAuthClientSecretMutex.Unlock()
+	if fake.SetUAAOAuthClientSecretStub != nil {
+		fake.SetUAAOAuthClientSecretStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientSecretCallCount() int {
+	fake.setUAAOAuthClientSecretMutex.RLock()
+	defer fake.setUAAOAuthClientSecretMutex.RUnlock()
+	return len(fake.setUAAOAuthClientSecretArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetUAAOAuthClientSecretArgsForCall(i int) string {
+	fake.setUAAOAuthClientSecretMutex.RLock()
+	defer fake.setUAAOAuthClientSecretMutex.RUnlock()
+	return fake.setUAAOAuthClientSecretArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) Retries() int {
+	fake.retriesMutex.Lock()
+	fake.retriesArgsForCall = append(fake.retriesArgsForCall, struct{}{})
+	fake.retriesMutex.Unlock()
+	if fake.RetriesStub != nil {
+		return fake.RetriesStub()
+	} else {
+		return fake.retriesReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) RetriesCallCount() int {
+	fake.retriesMutex.RLock()
+	defer fake.retriesMutex.RUnlock()
+	return len(fake.retriesArgsForCall)
+}
+
+func (fake *FakeReadWriter) RetriesReturns(result1 int) {
+	fake.RetriesStub = nil
+	fake.retriesReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeReadWriter) RetryMaxWait() uint {
+	fake.retryMaxWaitMutex.Lock()
+	fake.retryMaxWaitArgsForCall = append(fake.retryMaxWaitArgsForCall, struct{}{})
+	fake.retryMaxWaitMutex.Unlock()
+	if fake.RetryMaxWaitStub != nil {
+		return fake.RetryMaxWaitStub()
+	} else {
+		return fake.retryMaxWaitReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) RetryMaxWaitCallCount() int {
+	fake.retryMaxWaitMutex.RLock()
+	defer fake.retryMaxWaitMutex.RUnlock()
+	return len(fake.retryMaxWaitArgsForCall)
+}
+
+func (fake *FakeReadWriter) RetryMaxWaitReturns(result1 uint) {
+	fake.RetryMaxWaitStub = nil
+	fake.retryMaxWaitReturns = struct {
+		result1 uint
+	}{result1}
+}
+
+func (fake *FakeReadWriter) SetRetries(arg1 int) {
+	fake.setRetriesMutex.Lock()
+	fake.setRetriesArgsForCall = append(fake.setRetriesArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.setRetriesMutex.Unlock()
+	if fake.SetRetriesStub != nil {
+		fake.SetRetriesStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetRetriesCallCount() int {
+	fake.setRetriesMutex.RLock()
+	defer fake.setRetriesMutex.RUnlock()
+	return len(fake.setRetriesArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetRetriesArgsForCall(i int) int {
+	fake.setRetriesMutex.RLock()
+	defer fake.setRetriesMutex.RUnlock()
+	return fake.setRetriesArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) SetRetryMaxWait(arg1 uint) {
+	fake.setRetryMaxWaitMutex.Lock()
+	fake.setRetryMaxWaitArgsForCall = append(fake.setRetryMaxWaitArgsForCall, struct {
+		arg1 uint
+	}{arg1})
+	fake.setRetryMaxWaitMutex.Unlock()
+	if fake.SetRetryMaxWaitStub != nil {
+		fake.SetRetryMaxWaitStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetRetryMaxWaitCallCount() int {
+	fake.setRetryMaxWaitMutex.RLock()
+	defer fake.setRetryMaxWaitMutex.RUnlock()
+	return len(fake.setRetryMaxWaitArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetRetryMaxWaitArgsForCall(i int) uint {
+	fake.setRetryMaxWaitMutex.RLock()
+	defer fake.setRetryMaxWaitMutex.RUnlock()
+	return fake.setRetryMaxWaitArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) CACertFile() string {
+	fake.cACertFileMutex.Lock()
+	fake.cACertFileArgsForCall = append(fake.cACertFileArgsForCall, struct{}{})
+	fake.cACertFileMutex.Unlock()
+	if fake.CACertFileStub != nil {
+		return fake.CACertFileStub()
+	} else {
+		return fake.cACertFileReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) CACertFileCallCount() int {
+	fake.cACertFileMutex.RLock()
+	defer fake.cACertFileMutex.RUnlock()
+	return len(fake.cACertFileArgsForCall)
+}
+
+func (fake *FakeReadWriter) CACertFileReturns(result1 string) {
+	fake.CACertFileStub = nil
+	fake.cACertFileReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) ClientCertFile() string {
+	fake.clientCertFileMutex.Lock()
+	fake.clientCertFileArgsForCall = append(fake.clientCertFileArgsForCall, struct{}{})
+	fake.clientCertFileMutex.Unlock()
+	if fake.ClientCertFileStub != nil {
+		return fake.ClientCertFileStub()
+	} else {
+		return fake.clientCertFileReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) ClientCertFileCallCount() int {
+	fake.clientCertFileMutex.RLock()
+	defer fake.clientCertFileMutex.RUnlock()
+	return len(fake.clientCertFileArgsForCall)
+}
+
+func (fake *FakeReadWriter) ClientCertFileReturns(result1 string) {
+	fake.ClientCertFileStub = nil
+	fake.clientCertFileReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) ClientKeyFile() string {
+	fake.clientKeyFileMutex.Lock()
+	fake.clientKeyFileArgsForCall = append(fake.clientKeyFileArgsForCall, struct{}{})
+	fake.clientKeyFileMutex.Unlock()
+	if fake.ClientKeyFileStub != nil {
+		return fake.ClientKeyFileStub()
+	} else {
+		return fake.clientKeyFileReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) ClientKeyFileCallCount() int {
+	fake.clientKeyFileMutex.RLock()
+	defer fake.clientKeyFileMutex.RUnlock()
+	return len(fake.clientKeyFileArgsForCall)
+}
+
+func (fake *FakeReadWriter) ClientKeyFileReturns(result1 string) {
+	fake.ClientKeyFileStub = nil
+	fake.clientKeyFileReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) SetCACertFile(arg1 string) {
+	fake.setCACertFileMutex.Lock()
+	fake.setCACertFileArgsForCall = append(fake.setCACertFileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.setCACertFileMutex.Unlock()
+	if fake.SetCACertFileStub != nil {
+		fake.SetCACertFileStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) SetCACertFileCallCount() int {
+	fake.setCACertFileMutex.RLock()
+	defer fake.setCACertFileMutex.RUnlock()
+	return len(fake.setCACertFileArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetCACertFileArgsForCall(i int) string {
+	fake.setCACertFileMutex.RLock()
+	defer fake.setCACertFileMutex.RUnlock()
+	return fake.setCACertFileArgsForCall[i].arg1
+}
+
+func (fake *FakeReadWriter) SetClientCertificate(certFile string, keyFile string) {
+	fake.setClientCertificateMutex.Lock()
+	fake.setClientCertificateArgsForCall = append(fake.setClientCertificateArgsForCall, struct {
+		certFile string
+		keyFile  string
+	}{certFile, keyFile})
+	fake.setClientCertificateMutex.Unlock()
+	if fake.SetClientCertificateStub != nil {
+		fake.SetClientCertificateStub(certFile, keyFile)
+	}
+}
+
+func (fake *FakeReadWriter) SetClientCertificateCallCount() int {
+	fake.setClientCertificateMutex.RLock()
+	defer fake.setClientCertificateMutex.RUnlock()
+	return len(fake.setClientCertificateArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetClientCertificateArgsForCall(i int) (string, string) {
+	fake.setClientCertificateMutex.RLock()
+	defer fake.setClientCertificateMutex.RUnlock()
+	return fake.setClientCertificateArgsForCall[i].certFile, fake.setClientCertificateArgsForCall[i].keyFile
+}
+
+func (fake *FakeReadWriter) SetAlias(name string, command string) {
+	fake.setAliasMutex.Lock()
+	fake.setAliasArgsForCall = append(fake.setAliasArgsForCall, struct {
+		name    string
+		command string
+	}{name, command})
+	fake.setAliasMutex.Unlock()
+	if fake.SetAliasStub != nil {
+		fake.SetAliasStub(name, command)
+	}
+}
+
+func (fake *FakeReadWriter) SetAliasCallCount() int {
+	fake.setAliasMutex.RLock()
+	defer fake.setAliasMutex.RUnlock()
+	return len(fake.setAliasArgsForCall)
+}
+
+func (fake *FakeReadWriter) SetAliasArgsForCall(i int) (string, string) {
+	fake.setAliasMutex.RLock()
+	defer fake.setAliasMutex.RUnlock()
+	return fake.setAliasArgsForCall[i].name, fake.setAliasArgsForCall[i].command
+}
+
+func (fake *FakeReadWriter) Aliases() map[string]string {
+	fake.aliasesMutex.Lock()
+	fake.aliasesArgsForCall = append(fake.aliasesArgsForCall, struct{}{})
+	fake.aliasesMutex.Unlock()
+	if fake.AliasesStub != nil {
+		return fake.AliasesStub()
+	} else {
+		return fake.aliasesReturns.result1
+	}
+}
+
+func (fake *FakeReadWriter) AliasesCallCount() int {
+	fake.aliasesMutex.RLock()
+	defer fake.aliasesMutex.RUnlock()
+	return len(fake.aliasesArgsForCall)
+}
+
+func (fake *FakeReadWriter) AliasesReturns(result1 map[string]string) {
+	fake.AliasesStub = nil
+	fake.aliasesReturns = struct {
+		result1 map[string]string
+	}{result1}
+}
+
+func (fake *FakeReadWriter) DeleteAlias(arg1 string) {
+	fake.deleteAliasMutex.Lock()
+	fake.deleteAliasArgsForCall = append(fake.deleteAliasArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.deleteAliasMutex.Unlock()
+	if fake.DeleteAliasStub != nil {
+		fake.DeleteAliasStub(arg1)
+	}
+}
+
+func (fake *FakeReadWriter) DeleteAliasCallCount() int {
+	fake.deleteAliasMutex.RLock()
+	defer fake.deleteAliasMutex.RUnlock()
+	return len(fake.deleteAliasArgsForCall)
+}
+
+func (fake *FakeReadWriter) DeleteAliasArgsForCall(i int) string {
+	fake.deleteAliasMutex.RLock()
+	defer fake.deleteAliasMutex.RUnlock()
+	return fake.deleteAliasArgsForCall[i].arg1
+}
+
 var _ coreconfig.ReadWriter = new(FakeReadWriter)
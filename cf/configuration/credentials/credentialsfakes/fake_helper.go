@@ -0,0 +1,137 @@
+// This file was generated by counterfeiter
+package credentialsfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+)
+
+type FakeHelper struct {
+	GetStub        func(profile string) (credentials.Tokens, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		profile string
+	}
+	getReturns struct {
+		result1 credentials.Tokens
+		result2 error
+	}
+	StoreStub        func(profile string, tokens credentials.Tokens) error
+	storeMutex       sync.RWMutex
+	storeArgsForCall []struct {
+		profile string
+		tokens  credentials.Tokens
+	}
+	storeReturns struct {
+		result1 error
+	}
+	EraseStub        func(profile string) error
+	eraseMutex       sync.RWMutex
+	eraseArgsForCall []struct {
+		profile string
+	}
+	eraseReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeHelper) Get(profile string) (credentials.Tokens, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		profile string
+	}{profile})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(profile)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2
+	}
+}
+
+func (fake *FakeHelper) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeHelper) GetArgsForCall(i int) string {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].profile
+}
+
+func (fake *FakeHelper) GetReturns(result1 credentials.Tokens, result2 error) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 credentials.Tokens
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHelper) Store(profile string, tokens credentials.Tokens) error {
+	fake.storeMutex.Lock()
+	fake.storeArgsForCall = append(fake.storeArgsForCall, struct {
+		profile string
+		tokens  credentials.Tokens
+	}{profile, tokens})
+	fake.storeMutex.Unlock()
+	if fake.StoreStub != nil {
+		return fake.StoreStub(profile, tokens)
+	} else {
+		return fake.storeReturns.result1
+	}
+}
+
+func (fake *FakeHelper) StoreCallCount() int {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	return len(fake.storeArgsForCall)
+}
+
+func (fake *FakeHelper) StoreArgsForCall(i int) (string, credentials.Tokens) {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	return fake.storeArgsForCall[i].profile, fake.storeArgsForCall[i].tokens
+}
+
+func (fake *FakeHelper) StoreReturns(result1 error) {
+	fake.StoreStub = nil
+	fake.storeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHelper) Erase(profile string) error {
+	fake.eraseMutex.Lock()
+	fake.eraseArgsForCall = append(fake.eraseArgsForCall, struct {
+		profile string
+	}{profile})
+	fake.eraseMutex.Unlock()
+	if fake.EraseStub != nil {
+		return fake.EraseStub(profile)
+	} else {
+		return fake.eraseReturns.result1
+	}
+}
+
+func (fake *FakeHelper) EraseCallCount() int {
+	fake.eraseMutex.RLock()
+	defer fake.eraseMutex.RUnlock()
+	return len(fake.eraseArgsForCall)
+}
+
+func (fake *FakeHelper) EraseArgsForCall(i int) string {
+	fake.eraseMutex.RLock()
+	defer fake.eraseMutex.RUnlock()
+	return fake.eraseArgsForCall[i].profile
+}
+
+func (fake *FakeHelper) EraseReturns(result1 error) {
+	fake.EraseStub = nil
+	fake.eraseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ credentials.Helper = new(FakeHelper)
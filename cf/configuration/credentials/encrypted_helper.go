@@ -0,0 +1,251 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+const (
+	saltLength          = 16
+	keyLength           = 32
+	keyDerivationRounds = 100000
+)
+
+// EncryptedHelper keeps the tokens of every profile in one file, each
+// encrypted with AES-256-GCM under a key derived from a passphrase with
+// PBKDF2-HMAC-SHA256. The passphrase itself is never written anywhere.
+type EncryptedHelper struct {
+	path       string
+	passphrase func() (string, error)
+
+	mutex sync.Mutex
+	salt  []byte
+	key   []byte
+}
+
+type encryptedFile struct {
+	Salt     []byte            `json:"salt"`
+	Profiles map[string][]byte `json:"profiles"`
+}
+
+func NewEncryptedHelper(path string, passphrase func() (string, error)) *EncryptedHelper {
+	return &EncryptedHelper{
+		path:       path,
+		passphrase: passphrase,
+	}
+}
+
+func (helper *EncryptedHelper) Get(profile string) (Tokens, error) {
+	helper.mutex.Lock()
+	defer helper.mutex.Unlock()
+
+	var tokens Tokens
+
+	file, err := helper.read()
+	if err != nil {
+		return tokens, err
+	}
+
+	sealed, found := file.Profiles[profile]
+	if !found {
+		return tokens, nil
+	}
+
+	plaintext, err := helper.open(file, sealed)
+	if err != nil {
+		return tokens, err
+	}
+
+	err = json.Unmarshal(plaintext, &tokens)
+	return tokens, err
+}
+
+func (helper *EncryptedHelper) Store(profile string, tokens Tokens) error {
+	helper.mutex.Lock()
+	defer helper.mutex.Unlock()
+
+	file, err := helper.read()
+	if err != nil {
+		return err
+	}
+
+	// Make sure the passphrase is the one the other profiles were stored
+	// with, so the file never mixes keys.
+	for _, sealed := range file.Profiles {
+		_, err = helper.open(file, sealed)
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := helper.seal(&file, plaintext)
+	if err != nil {
+		return err
+	}
+
+	file.Profiles[profile] = sealed
+	return helper.write(file)
+}
+
+func (helper *EncryptedHelper) Erase(profile string) error {
+	helper.mutex.Lock()
+	defer helper.mutex.Unlock()
+
+	file, err := helper.read()
+	if err != nil {
+		return err
+	}
+
+	if _, found := file.Profiles[profile]; !found {
+		return nil
+	}
+
+	delete(file.Profiles, profile)
+	return helper.write(file)
+}
+
+func (helper *EncryptedHelper) read() (encryptedFile, error) {
+	file := encryptedFile{Profiles: map[string][]byte{}}
+
+	contents, err := ioutil.ReadFile(helper.path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return file, err
+	}
+
+	err = json.Unmarshal(contents, &file)
+	if err != nil {
+		return file, errors.New(T("Could not read credentials file {{.Path}}: {{.Err}}",
+			map[string]interface{}{"Path": helper.path, "Err": err.Error()}))
+	}
+	if file.Profiles == nil {
+		file.Profiles = map[string][]byte{}
+	}
+	return file, nil
+}
+
+func (helper *EncryptedHelper) write(file encryptedFile) error {
+	contents, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(helper.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(helper.path, contents, 0600)
+}
+
+func (helper *EncryptedHelper) seal(file *encryptedFile, plaintext []byte) ([]byte, error) {
+	if len(file.Salt) == 0 {
+		file.Salt = make([]byte, saltLength)
+		if _, err := io.ReadFull(rand.Reader, file.Salt); err != nil {
+			return nil, err
+		}
+	}
+
+	aead, err := helper.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (helper *EncryptedHelper) open(file encryptedFile, sealed []byte) ([]byte, error) {
+	aead, err := helper.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New(T("Could not decrypt credentials in {{.Path}}", map[string]interface{}{"Path": helper.path}))
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New(T("Could not decrypt credentials in {{.Path}}. The passphrase may be wrong.", map[string]interface{}{"Path": helper.path}))
+	}
+	return plaintext, nil
+}
+
+// cipher asks for the passphrase and derives the key the first time it is
+// needed for a salt.
+func (helper *EncryptedHelper) cipher(salt []byte) (cipher.AEAD, error) {
+	if helper.key == nil || !hmac.Equal(helper.salt, salt) {
+		passphrase, err := helper.passphrase()
+		if err != nil {
+			return nil, err
+		}
+
+		helper.salt = salt
+		helper.key = pbkdf2SHA256([]byte(passphrase), salt, keyDerivationRounds, keyLength)
+	}
+
+	block, err := aes.NewCipher(helper.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 derives a key from a password as described in RFC 2898,
+// section 5.2, using HMAC-SHA256 as the pseudorandom function.
+func pbkdf2SHA256(password, salt []byte, rounds, length int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := make([]byte, 0, length)
+
+	blockIndex := make([]byte, 4)
+	for block := uint32(1); len(key) < length; block++ {
+		binary.BigEndian.PutUint32(blockIndex, block)
+
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(blockIndex)
+		u := prf.Sum(nil)
+
+		t := make([]byte, len(u))
+		copy(t, u)
+		for i := 1; i < rounds; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+
+		key = append(key, t...)
+	}
+
+	return key[:length]
+}
@@ -0,0 +1,127 @@
+package credentials_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cli/cf/configuration/credentials"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EncryptedHelper", func() {
+	var (
+		tmpDir          string
+		credentialsPath string
+		passphrase      string
+		passphraseCalls int
+		helper          *EncryptedHelper
+	)
+
+	newHelper := func() *EncryptedHelper {
+		return NewEncryptedHelper(credentialsPath, func() (string, error) {
+			passphraseCalls++
+			return passphrase, nil
+		})
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "credentials")
+		Expect(err).NotTo(HaveOccurred())
+
+		credentialsPath = filepath.Join(tmpDir, "credentials.json")
+		passphrase = "open sesame"
+		passphraseCalls = 0
+		helper = newHelper()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("returns no tokens when nothing was stored", func() {
+		tokens, err := helper.Get("default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens.IsEmpty()).To(BeTrue())
+		Expect(passphraseCalls).To(Equal(0))
+	})
+
+	It("stores the tokens of each profile encrypted", func() {
+		Expect(helper.Store("default", Tokens{AccessToken: "bearer default-access", RefreshToken: "default-refresh"})).To(Succeed())
+		Expect(helper.Store("staging", Tokens{AccessToken: "bearer staging-access"})).To(Succeed())
+
+		contents, err := ioutil.ReadFile(credentialsPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).NotTo(ContainSubstring("access"))
+		Expect(string(contents)).NotTo(ContainSubstring("refresh"))
+
+		info, err := os.Stat(credentialsPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		helper = newHelper()
+		tokens, err := helper.Get("default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal(Tokens{AccessToken: "bearer default-access", RefreshToken: "default-refresh"}))
+
+		tokens, err = helper.Get("staging")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal(Tokens{AccessToken: "bearer staging-access"}))
+	})
+
+	It("asks for the passphrase only once", func() {
+		Expect(helper.Store("default", Tokens{AccessToken: "bearer access"})).To(Succeed())
+		_, err := helper.Get("default")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(passphraseCalls).To(Equal(1))
+	})
+
+	It("erases the tokens of a profile", func() {
+		Expect(helper.Store("default", Tokens{AccessToken: "bearer access"})).To(Succeed())
+		Expect(helper.Erase("default")).To(Succeed())
+
+		tokens, err := helper.Get("default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens.IsEmpty()).To(BeTrue())
+	})
+
+	Context("when the passphrase is wrong", func() {
+		BeforeEach(func() {
+			Expect(helper.Store("default", Tokens{AccessToken: "bearer access"})).To(Succeed())
+
+			passphrase = "wrong"
+			helper = newHelper()
+		})
+
+		It("fails to get the tokens", func() {
+			_, err := helper.Get("default")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("The passphrase may be wrong"))
+		})
+
+		It("refuses to store tokens under another key", func() {
+			err := helper.Store("staging", Tokens{AccessToken: "bearer other"})
+			Expect(err).To(HaveOccurred())
+
+			passphrase = "open sesame"
+			helper = newHelper()
+			tokens, err := helper.Get("default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens.AccessToken).To(Equal("bearer access"))
+		})
+	})
+
+	It("fails when the passphrase cannot be read", func() {
+		helper = NewEncryptedHelper(credentialsPath, func() (string, error) {
+			return "", errors.New("no passphrase")
+		})
+
+		err := helper.Store("default", Tokens{AccessToken: "bearer access"})
+		Expect(err).To(MatchError("no passphrase"))
+	})
+})
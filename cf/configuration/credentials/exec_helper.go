@@ -0,0 +1,100 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+// ExecHelper runs the cf-credential-NAME executable, like the credential
+// helpers of git and docker. The action is its only argument and a JSON
+// request is written to its stdin:
+//
+//	cf-credential-NAME get    {"profile": "default"}
+//	cf-credential-NAME store  {"profile": "default", "access_token": "...", "refresh_token": "...", "client_secret": "..."}
+//	cf-credential-NAME erase  {"profile": "default"}
+//
+// get prints the stored tokens in the same form as store is given them, or
+// nothing when no tokens are stored. Empty tokens are left out. An action
+// fails when the executable exits non-zero, with what it printed to stderr as
+// the error.
+type ExecHelper struct {
+	name string
+}
+
+type execHelperRequest struct {
+	Profile string `json:"profile"`
+	Tokens
+}
+
+func NewExecHelper(name string) ExecHelper {
+	return ExecHelper{name: name}
+}
+
+func (helper ExecHelper) Get(profile string) (Tokens, error) {
+	var tokens Tokens
+
+	output, err := helper.run("get", execHelperRequest{Profile: profile})
+	if err != nil {
+		return tokens, err
+	}
+
+	if len(bytes.TrimSpace(output)) == 0 {
+		return tokens, nil
+	}
+
+	err = json.Unmarshal(output, &tokens)
+	if err != nil {
+		return tokens, errors.New(T("Invalid response from credential helper {{.Executable}}: {{.Err}}",
+			map[string]interface{}{"Executable": helperExecutable(helper.name), "Err": err.Error()}))
+	}
+	return tokens, nil
+}
+
+func (helper ExecHelper) Store(profile string, tokens Tokens) error {
+	_, err := helper.run("store", execHelperRequest{Profile: profile, Tokens: tokens})
+	return err
+}
+
+func (helper ExecHelper) Erase(profile string) error {
+	_, err := helper.run("erase", execHelperRequest{Profile: profile})
+	return err
+}
+
+func (helper ExecHelper) run(action string, request execHelperRequest) ([]byte, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.Command(helperExecutable(helper.name), action)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, errors.New(T("Credential helper {{.Executable}} failed to {{.Action}} credentials: {{.Err}}",
+			map[string]interface{}{"Executable": helperExecutable(helper.name), "Action": action, "Err": message}))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// ExecutablePrefix is the start of the name of credential helper executables.
+const ExecutablePrefix = "cf-credential-"
+
+func helperExecutable(name string) string {
+	return ExecutablePrefix + name
+}
@@ -0,0 +1,125 @@
+package credentials_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/cloudfoundry/cli/cf/configuration/credentials"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeHelperScript records each request in a file named after the action and
+// answers get with the contents of the "tokens" file.
+const fakeHelperScript = `#!/bin/sh
+dir=$(dirname "$0")
+cat > "$dir/$1.request"
+case "$1" in
+  get) cat "$dir/tokens" 2>/dev/null ;;
+  store) [ -f "$dir/fail" ] && { echo "keychain is locked" >&2; exit 1; } ;;
+esac
+exit 0
+`
+
+var _ = Describe("ExecHelper", func() {
+	var (
+		binDir  string
+		oldPath string
+		helper  ExecHelper
+	)
+
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("the fake credential helper is a shell script")
+		}
+
+		var err error
+		binDir, err = ioutil.TempDir("", "credential-helper")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(binDir, "cf-credential-fake"), []byte(fakeHelperScript), 0755)
+		Expect(err).NotTo(HaveOccurred())
+
+		oldPath = os.Getenv("PATH")
+		os.Setenv("PATH", binDir+string(os.PathListSeparator)+oldPath)
+
+		helper = NewExecHelper("fake")
+	})
+
+	AfterEach(func() {
+		if runtime.GOOS == "windows" {
+			return
+		}
+		os.Setenv("PATH", oldPath)
+		os.RemoveAll(binDir)
+	})
+
+	readRequest := func(action string) string {
+		contents, err := ioutil.ReadFile(filepath.Join(binDir, action+".request"))
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	It("gets the tokens printed by the helper", func() {
+		err := ioutil.WriteFile(filepath.Join(binDir, "tokens"), []byte(`{"access_token":"bearer access","refresh_token":"refresh"}`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		tokens, err := helper.Get("staging")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal(Tokens{AccessToken: "bearer access", RefreshToken: "refresh"}))
+		Expect(readRequest("get")).To(MatchJSON(`{"profile":"staging"}`))
+	})
+
+	It("returns no tokens when the helper prints nothing", func() {
+		tokens, err := helper.Get("staging")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens.IsEmpty()).To(BeTrue())
+	})
+
+	It("fails when the helper prints something other than tokens", func() {
+		err := ioutil.WriteFile(filepath.Join(binDir, "tokens"), []byte(`not json`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = helper.Get("staging")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid response from credential helper cf-credential-fake"))
+	})
+
+	It("passes the tokens to store", func() {
+		Expect(helper.Store("staging", Tokens{AccessToken: "bearer access", RefreshToken: "refresh"})).To(Succeed())
+		Expect(readRequest("store")).To(MatchJSON(`{"profile":"staging","access_token":"bearer access","refresh_token":"refresh"}`))
+	})
+
+	It("asks the helper to erase the tokens", func() {
+		Expect(helper.Erase("staging")).To(Succeed())
+		Expect(readRequest("erase")).To(MatchJSON(`{"profile":"staging"}`))
+	})
+
+	It("fails with what the helper printed to stderr", func() {
+		err := ioutil.WriteFile(filepath.Join(binDir, "fail"), []byte{}, 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = helper.Store("staging", Tokens{AccessToken: "bearer access"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Credential helper cf-credential-fake failed to store credentials: keychain is locked"))
+	})
+
+	Describe("Validate", func() {
+		It("accepts the built-in helper", func() {
+			Expect(Validate(EncryptedHelperName)).To(Succeed())
+		})
+
+		It("accepts a helper in the PATH", func() {
+			Expect(Validate("fake")).To(Succeed())
+		})
+
+		It("rejects a helper that is not in the PATH", func() {
+			err := Validate("missing")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Could not find the credential helper cf-credential-missing in your PATH"))
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package credentials
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+// EncryptedHelperName is the name of the built-in helper, which keeps tokens
+// in a file encrypted with a key derived from a passphrase.
+const EncryptedHelperName = "encrypted"
+
+// Tokens are the credentials of the user or client logged in to one profile.
+type Tokens struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+func (tokens Tokens) IsEmpty() bool {
+	return tokens.AccessToken == "" && tokens.RefreshToken == "" && tokens.ClientSecret == ""
+}
+
+// Helper keeps the tokens of each profile somewhere other than config.json.
+//
+//go:generate counterfeiter . Helper
+type Helper interface {
+	// Get returns the tokens stored for the profile, which are empty when
+	// nothing was stored.
+	Get(profile string) (Tokens, error)
+	Store(profile string, tokens Tokens) error
+	Erase(profile string) error
+}
+
+// NewHelper returns the helper with the given name: the built-in encrypted
+// helper, which keeps its file in configDir, or the cf-credential-NAME
+// executable. passphrase is asked for the passphrase of the encrypted helper
+// the first time it is needed.
+func NewHelper(name string, configDir string, passphrase func() (string, error)) Helper {
+	if name == EncryptedHelperName {
+		return NewEncryptedHelper(filepath.Join(configDir, "credentials.json"), passphrase)
+	}
+	return NewExecHelper(name)
+}
+
+// Validate checks that a helper with the given name can be used.
+func Validate(name string) error {
+	if name == EncryptedHelperName {
+		return nil
+	}
+
+	_, err := exec.LookPath(helperExecutable(name))
+	if err != nil {
+		return errors.New(T("Could not find the credential helper {{.Executable}} in your PATH", map[string]interface{}{"Executable": helperExecutable(name)}))
+	}
+	return nil
+}
@@ -14,6 +14,13 @@ type PluginMetadata struct {
 	Location string
 	Version  plugin.VersionType
 	Commands []plugin.Command
+	// AuthenticatedRPC is set for plugins built with a version of the plugin
+	// package that talks to the CLI over the authenticated transport
+	AuthenticatedRPC bool
+	// LegacyRPC is set for plugins built before the authenticated transport.
+	// Plugins with neither set were installed before the CLI recorded it.
+	LegacyRPC bool
+	Hooks     []plugin.Hook
 }
 
 func NewData() *PluginData {
@@ -105,6 +105,8 @@ func newAppPresenter() (presenter appPresenter) {
 				}, {
 					presentCommand("api"),
 					presentCommand("auth"),
+					presentCommand("profiles"),
+					presentCommand("profile"),
 				},
 			},
 		}, {
@@ -139,6 +141,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentCommand("copy-source"),
 				}, {
 					presentCommand("create-app-manifest"),
+					presentCommand("validate-manifest"),
 				}, {
 					presentCommand("get-health-check"),
 					presentCommand("set-health-check"),
@@ -360,8 +363,10 @@ func newAppPresenter() (presenter appPresenter) {
 				{
 					presentCommand("curl"),
 					presentCommand("config"),
+					presentCommand("alias"),
 					presentCommand("oauth-token"),
 					presentCommand("ssh-code"),
+					presentCommand("completion"),
 				},
 			},
 		}, {
@@ -381,6 +386,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentCommand("plugins"),
 					presentCommand("install-plugin"),
 					presentCommand("uninstall-plugin"),
+					presentCommand("update-plugin"),
 				},
 			},
 		}, {
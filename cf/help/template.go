@@ -16,17 +16,28 @@ func GetHelpTemplate() string {
 {{range .}}   {{.Name}} {{.Description}}
 {{end}}{{end}}{{end}}
 {{.Title "` + T("ENVIRONMENT VARIABLES:") + `"}}
+   CF_CA_CERT=path/to/ca.pem          ` + T("Trust these CA certificates when connecting to Cloud Foundry") + `
+   CF_CLIENT_CERT=path/to/cert.pem    ` + T("Present this client certificate when connecting to Cloud Foundry") + `
+   CF_CLIENT_KEY=path/to/key.pem      ` + T("Key of the client certificate, if it is not in the certificate file") + `
    CF_COLOR=false                     ` + T("Do not colorize output") + `
+   CF_CREDENTIAL_PASSPHRASE=secret    ` + T("Passphrase of the encrypted credential helper") + `
    CF_HOME=path/to/dir/               ` + T("Override path to default config directory") + `
    CF_PLUGIN_HOME=path/to/dir/        ` + T("Override path to default plugin config directory") + `
+   CF_PROFILE=NAME                    ` + T("Use this profile instead of the current one") + `
+   CF_RETRIES=2                       ` + T("Times to retry API requests that fail for a transient reason") + `
+   CF_RETRY_MAX_WAIT=30               ` + T("Max wait time between retries of API requests, in seconds") + `
    CF_STAGING_TIMEOUT=15              ` + T("Max wait time for buildpack staging, in minutes") + `
    CF_STARTUP_TIMEOUT=5               ` + T("Max wait time for app instance startup, in minutes") + `
    CF_TRACE=true                      ` + T("Print API request diagnostics to stdout") + `
    CF_TRACE=path/to/trace.log         ` + T("Append API request diagnostics to a log file") + `
+   CF_TRACE=path/to/trace.har         ` + T("Record API requests and responses in an HTTP Archive file") + `
    https_proxy=proxy.example.com:8080 ` + T("Enable HTTP proxying for API requests") + `
 
 {{.Title "` + T("GLOBAL OPTIONS:") + `"}}
    --help, -h                         ` + T("Show help") + `
    -v                                 ` + T("Print API request diagnostics to stdout") + `
+   --profile NAME                     ` + T("Use this profile instead of the current one") + `
+   --output json|yaml|table           ` + T("Print listings as a JSON or YAML document instead of a table") + `
+   --trace-format text|har            ` + T("Write CF_TRACE files as text or as an HTTP Archive (Default: har for .har files)") + `
 `
 }
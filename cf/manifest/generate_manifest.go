@@ -25,30 +25,43 @@ type AppManifest interface {
 	HealthCheckTimeout(string, int)
 	Instances(string, int)
 	Domain(string, string, string)
+	Route(string, string, string, string, int)
+	HealthCheckType(string, string)
+	DockerImage(string, string)
+	Path(string, string)
 	GetContents() []models.Application
 	Stack(string, string)
 	AppPorts(string, []int)
+	Variables([]VariableReference)
 	Save(f io.Writer) error
 }
 
 type ManifestApplication struct {
-	Name       string                 `yaml:"name"`
-	Instances  int                    `yaml:"instances,omitempty"`
-	Memory     string                 `yaml:"memory,omitempty"`
-	DiskQuota  string                 `yaml:"disk_quota,omitempty"`
-	AppPorts   []int                  `yaml:"app-ports,omitempty"`
-	Host       string                 `yaml:"host,omitempty"`
-	Hosts      []string               `yaml:"hosts,omitempty"`
-	Domain     string                 `yaml:"domain,omitempty"`
-	Domains    []string               `yaml:"domains,omitempty"`
-	NoHostname bool                   `yaml:"no-hostname,omitempty"`
-	NoRoute    bool                   `yaml:"no-route,omitempty"`
-	Buildpack  string                 `yaml:"buildpack,omitempty"`
-	Command    string                 `yaml:"command,omitempty"`
-	Env        map[string]interface{} `yaml:"env,omitempty"`
-	Services   []string               `yaml:"services,omitempty"`
-	Stack      string                 `yaml:"stack,omitempty"`
-	Timeout    int                    `yaml:"timeout,omitempty"`
+	Name            string                 `yaml:"name"`
+	Instances       int                    `yaml:"instances,omitempty"`
+	Memory          string                 `yaml:"memory,omitempty"`
+	DiskQuota       string                 `yaml:"disk_quota,omitempty"`
+	AppPorts        []int                  `yaml:"app-ports,omitempty"`
+	Host            string                 `yaml:"host,omitempty"`
+	Hosts           []string               `yaml:"hosts,omitempty"`
+	Domain          string                 `yaml:"domain,omitempty"`
+	Domains         []string               `yaml:"domains,omitempty"`
+	Routes          []ManifestRoute        `yaml:"routes,omitempty"`
+	NoHostname      bool                   `yaml:"no-hostname,omitempty"`
+	NoRoute         bool                   `yaml:"no-route,omitempty"`
+	Buildpack       string                 `yaml:"buildpack,omitempty"`
+	Command         string                 `yaml:"command,omitempty"`
+	DockerImage     string                 `yaml:"docker-image,omitempty"`
+	Path            string                 `yaml:"path,omitempty"`
+	Env             map[string]interface{} `yaml:"env,omitempty"`
+	Services        []string               `yaml:"services,omitempty"`
+	Stack           string                 `yaml:"stack,omitempty"`
+	HealthCheckType string                 `yaml:"health-check-type,omitempty"`
+	Timeout         int                    `yaml:"timeout,omitempty"`
+}
+
+type ManifestRoute struct {
+	Route string `yaml:"route"`
 }
 
 type ManifestApplications struct {
@@ -56,7 +69,9 @@ type ManifestApplications struct {
 }
 
 type appManifest struct {
-	contents []models.Application
+	contents  []models.Application
+	paths     map[string]string
+	variables []VariableReference
 }
 
 func NewGenerator() AppManifest {
@@ -109,15 +124,39 @@ func (m *appManifest) Service(appName string, name string) {
 }
 
 func (m *appManifest) Domain(appName string, host string, domain string) {
+	m.Route(appName, host, domain, "", 0)
+}
+
+func (m *appManifest) Route(appName string, host string, domain string, path string, port int) {
 	i := m.findOrCreateApplication(appName)
 	m.contents[i].Routes = append(m.contents[i].Routes, models.RouteSummary{
 		Host: host,
 		Domain: models.DomainFields{
 			Name: domain,
 		},
+		Path: path,
+		Port: port,
 	})
 }
 
+func (m *appManifest) HealthCheckType(appName string, healthCheckType string) {
+	i := m.findOrCreateApplication(appName)
+	m.contents[i].HealthCheckType = healthCheckType
+}
+
+func (m *appManifest) DockerImage(appName string, dockerImage string) {
+	i := m.findOrCreateApplication(appName)
+	m.contents[i].DockerImage = dockerImage
+}
+
+func (m *appManifest) Path(appName string, path string) {
+	m.findOrCreateApplication(appName)
+	if m.paths == nil {
+		m.paths = map[string]string{}
+	}
+	m.paths[appName] = path
+}
+
 func (m *appManifest) EnvironmentVars(appName string, key, value string) {
 	i := m.findOrCreateApplication(appName)
 	m.contents[i].EnvironmentVars[key] = value
@@ -128,6 +167,10 @@ func (m *appManifest) AppPorts(appName string, appPorts []int) {
 	m.contents[i].AppPorts = appPorts
 }
 
+func (m *appManifest) Variables(references []VariableReference) {
+	m.variables = references
+}
+
 func (m *appManifest) GetContents() []models.Application {
 	return m.contents
 }
@@ -155,23 +198,29 @@ func generateAppMap(app models.Application) (ManifestApplication, error) {
 	}
 
 	m := ManifestApplication{
-		Name:      app.Name,
-		Services:  services,
-		Buildpack: app.BuildpackURL,
-		Memory:    fmt.Sprintf("%dM", app.Memory),
-		Command:   app.Command,
-		Env:       app.EnvironmentVars,
-		Timeout:   app.HealthCheckTimeout,
-		Instances: app.InstanceCount,
-		DiskQuota: fmt.Sprintf("%dM", app.DiskQuota),
-		Stack:     app.Stack.Name,
-		AppPorts:  app.AppPorts,
+		Name:            app.Name,
+		Services:        services,
+		Buildpack:       app.BuildpackURL,
+		Memory:          fmt.Sprintf("%dM", app.Memory),
+		Command:         app.Command,
+		DockerImage:     app.DockerImage,
+		Env:             app.EnvironmentVars,
+		HealthCheckType: app.HealthCheckType,
+		Timeout:         app.HealthCheckTimeout,
+		Instances:       app.InstanceCount,
+		DiskQuota:       fmt.Sprintf("%dM", app.DiskQuota),
+		Stack:           app.Stack.Name,
+		AppPorts:        app.AppPorts,
 	}
 
-	switch len(app.Routes) {
-	case 0:
+	switch {
+	case len(app.Routes) == 0:
 		m.NoRoute = true
-	case 1:
+	case !isHostsByDomains(app.Routes):
+		for _, route := range app.Routes {
+			m.Routes = append(m.Routes, ManifestRoute{Route: route.URL()})
+		}
+	case len(app.Routes) == 1:
 		const noHostname = ""
 
 		m.Domain = app.Routes[0].Domain.Name
@@ -185,10 +234,10 @@ func generateAppMap(app models.Application) (ManifestApplication, error) {
 	default:
 		hosts, domains := separateHostsAndDomains(app.Routes)
 
-		switch len(hosts) {
-		case 0:
+		switch {
+		case len(hosts) == 0, hosts[0] == "":
 			m.NoHostname = true
-		case 1:
+		case len(hosts) == 1:
 			m.Host = hosts[0]
 		default:
 			m.Hosts = hosts
@@ -215,6 +264,7 @@ func (m *appManifest) Save(f io.Writer) error {
 				"Error": mapErr.Error(),
 			}))
 		}
+		appMap.Path = m.paths[app.Name]
 		apps.Applications = append(apps.Applications, appMap)
 	}
 
@@ -223,6 +273,13 @@ func (m *appManifest) Save(f io.Writer) error {
 		return err
 	}
 
+	if len(m.variables) > 0 {
+		contents, err = parameterizeYAML(contents, m.variables)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = f.Write(contents)
 	if err != nil {
 		return err
@@ -277,3 +334,37 @@ func separateHostsAndDomains(routes []models.RouteSummary) ([]string, []string)
 
 	return hosts, domains
 }
+
+// isHostsByDomains reports whether routes can be written as host(s) and
+// domain(s) without changing their meaning: push maps every host to every
+// domain, so that is only possible when the routes are exactly those
+// combinations, have no paths or ports, and either all or none of them have a
+// hostname.
+func isHostsByDomains(routes []models.RouteSummary) bool {
+	hosts, domains := separateHostsAndDomains(routes)
+
+	existing := map[string]bool{}
+	for _, route := range routes {
+		if route.Path != "" || route.Port != 0 {
+			return false
+		}
+		if (route.Host == "") != (routes[0].Host == "") {
+			return false
+		}
+		existing[route.Host+"."+route.Domain.Name] = true
+	}
+
+	if len(existing) != len(hosts)*len(domains) {
+		return false
+	}
+
+	for _, host := range hosts {
+		for _, domain := range domains {
+			if !existing[host+"."+domain] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
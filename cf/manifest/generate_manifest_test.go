@@ -6,6 +6,7 @@ import (
 	"bytes"
 
 	. "github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/generic"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -247,6 +248,129 @@ var _ = Describe("generate_manifest", func() {
 				Expect(application.NoHostname).To(BeFalse())
 			})
 
+			It("generates a manifest containing no-hostname for several domains without hostnames", func() {
+				m.Domain("app1", "", "test1.com")
+				m.Domain("app1", "", "test2.com")
+				err := m.Save(f)
+				Expect(err).NotTo(HaveOccurred())
+
+				application := getYaml(f).Applications[0]
+
+				Expect(application.NoHostname).To(BeTrue())
+				Expect(application.Domains).To(ConsistOf("test1.com", "test2.com"))
+				Expect(application.Host).To(BeEmpty())
+				Expect(application.Hosts).To(BeEmpty())
+				Expect(application.Routes).To(BeEmpty())
+			})
+
+			It("generates routes when the hosts are not on every domain", func() {
+				m.Domain("app1", "foo1", "test1.com")
+				m.Domain("app1", "foo2", "test2.com")
+				err := m.Save(f)
+				Expect(err).NotTo(HaveOccurred())
+
+				application := getYaml(f).Applications[0]
+
+				Expect(application.Routes).To(Equal([]ManifestRoute{
+					{Route: "foo1.test1.com"},
+					{Route: "foo2.test2.com"},
+				}))
+				Expect(application.Host).To(BeEmpty())
+				Expect(application.Hosts).To(BeEmpty())
+				Expect(application.Domain).To(BeEmpty())
+				Expect(application.Domains).To(BeEmpty())
+				Expect(application.NoHostname).To(BeFalse())
+			})
+
+			It("generates routes when only some routes have a hostname", func() {
+				m.Domain("app1", "foo", "test.com")
+				m.Domain("app1", "", "test.com")
+				err := m.Save(f)
+				Expect(err).NotTo(HaveOccurred())
+
+				application := getYaml(f).Applications[0]
+
+				Expect(application.Routes).To(Equal([]ManifestRoute{
+					{Route: "foo.test.com"},
+					{Route: "test.com"},
+				}))
+				Expect(application.NoHostname).To(BeFalse())
+			})
+
+			It("generates routes for route paths and TCP ports", func() {
+				m.Route("app1", "foo", "test.com", "/api", 0)
+				m.Route("app1", "", "tcp.test.com", "", 1234)
+				err := m.Save(f)
+				Expect(err).NotTo(HaveOccurred())
+
+				application := getYaml(f).Applications[0]
+
+				Expect(application.Routes).To(Equal([]ManifestRoute{
+					{Route: "foo.test.com/api"},
+					{Route: "tcp.test.com:1234"},
+				}))
+				Expect(application.Host).To(BeEmpty())
+				Expect(application.Domain).To(BeEmpty())
+			})
+
+			It("includes the health check type, docker image and path", func() {
+				m.HealthCheckType("app1", "port")
+				m.DockerImage("app1", "user/my-image")
+				m.Path("app1", "./app1")
+				err := m.Save(f)
+				Expect(err).NotTo(HaveOccurred())
+
+				application := getYaml(f).Applications[0]
+
+				Expect(application.HealthCheckType).To(Equal("port"))
+				Expect(application.DockerImage).To(Equal("user/my-image"))
+				Expect(application.Path).To(Equal("./app1"))
+			})
+
+			It("generates a manifest that pushes the same settings it was generated from", func() {
+				m.Route("app1", "foo", "test1.com", "", 0)
+				m.Route("app1", "", "test2.com", "/api", 0)
+				m.Route("app1", "", "tcp.test.com", "", 1234)
+				m.BuildpackURL("app1", "go_buildpack")
+				m.StartCommand("app1", "./app1 --serve")
+				m.HealthCheckType("app1", "port")
+				m.HealthCheckTimeout("app1", 90)
+				m.Service("app1", "my-db")
+				m.EnvironmentVars("app1", "FOO", "bar")
+				m.Path("app1", "/apps/app1")
+				err := m.Save(f)
+				Expect(err).NotTo(HaveOccurred())
+
+				data := map[interface{}]interface{}{}
+				err = yaml.Unmarshal(f.Bytes(), &data)
+				Expect(err).NotTo(HaveOccurred())
+
+				parsed := NewManifest("/some/path/manifest.yml", generic.NewMap(data))
+				Expect(parsed.Validate()).To(BeEmpty())
+
+				apps, err := parsed.Applications()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(apps).To(HaveLen(1))
+
+				app := apps[0]
+				Expect(*app.Name).To(Equal("app1"))
+				Expect(*app.Memory).To(Equal(int64(1024)))
+				Expect(*app.DiskQuota).To(Equal(int64(1024)))
+				Expect(*app.InstanceCount).To(Equal(2))
+				Expect(*app.StackName).To(Equal("stack-name"))
+				Expect(*app.BuildpackURL).To(Equal("go_buildpack"))
+				Expect(*app.Command).To(Equal("./app1 --serve"))
+				Expect(*app.HealthCheckType).To(Equal("port"))
+				Expect(*app.HealthCheckTimeout).To(Equal(90))
+				Expect(*app.ServicesToBind).To(Equal([]string{"my-db"}))
+				Expect(*app.EnvironmentVars).To(Equal(map[string]interface{}{"FOO": "bar"}))
+				Expect(*app.Path).To(Equal("/apps/app1"))
+				Expect(*app.Routes).To(Equal([]string{"foo.test1.com", "test2.com/api", "tcp.test.com:1234"}))
+				Expect(app.Hosts).To(BeNil())
+				Expect(app.Domains).To(BeNil())
+				Expect(app.NoRoute).To(BeFalse())
+			})
+
 			Context("when the application contains environment vars", func() {
 				BeforeEach(func() {
 					m.EnvironmentVars("app1", "foo", "foo-value")
@@ -264,6 +388,59 @@ var _ = Describe("generate_manifest", func() {
 					}))
 				})
 			})
+
+			Context("when variables have been set", func() {
+				BeforeEach(func() {
+					m.Domain("app1", "app1-staging", "example.com")
+					m.EnvironmentVars("app1", "DATABASE_URL", "postgres://staging-db")
+					m.EnvironmentVars("app1", "REPLICAS", "2")
+					m.Variables([]VariableReference{
+						{Property: "env.DATABASE_URL", Template: "((database-url))", Value: "postgres://staging-db"},
+						{Property: "host", Template: "((name))-((space))", Value: "app1-staging"},
+						{Property: "instances", Template: "((instances))", Value: 2},
+					})
+				})
+
+				It("replaces the values of the properties variables were interpolated into", func() {
+					err := m.Save(f)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(f.String()).To(ContainSubstring("host: ((name))-((space))"))
+					Expect(f.String()).To(ContainSubstring("instances: ((instances))"))
+					Expect(f.String()).To(ContainSubstring("DATABASE_URL: ((database-url))"))
+					Expect(f.String()).To(ContainSubstring("name: app1"))
+				})
+
+				It("leaves other properties with the same value alone", func() {
+					err := m.Save(f)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(f.String()).To(ContainSubstring(`REPLICAS: "2"`))
+				})
+
+				It("round-trips through manifest interpolation", func() {
+					err := m.Save(f)
+					Expect(err).NotTo(HaveOccurred())
+
+					data := map[interface{}]interface{}{}
+					err = yaml.Unmarshal(f.Bytes(), &data)
+					Expect(err).NotTo(HaveOccurred())
+
+					parsed := NewManifest("/some/path/manifest.yml", generic.NewMap(data))
+					parsed.Variables = map[string]interface{}{
+						"name":         "app1",
+						"space":        "prod",
+						"database-url": "postgres://prod-db",
+						"instances":    4,
+					}
+
+					apps, err := parsed.Applications()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(*apps[0].Hosts).To(Equal([]string{"app1-prod"}))
+					Expect(*apps[0].InstanceCount).To(Equal(4))
+					Expect((*apps[0].EnvironmentVars)["DATABASE_URL"]).To(Equal("postgres://prod-db"))
+				})
+			})
 		})
 
 		It("returns an error when stack has not been set", func() {
@@ -313,23 +490,27 @@ type YManifest struct {
 }
 
 type YApplication struct {
-	Name       string                 `yaml:"name"`
-	Services   []string               `yaml:"services"`
-	Buildpack  string                 `yaml:"buildpack"`
-	Memory     string                 `yaml:"memory"`
-	Command    string                 `yaml:"command"`
-	Env        map[string]interface{} `yaml:"env"`
-	Timeout    int                    `yaml:"timeout"`
-	Instances  int                    `yaml:"instances"`
-	Host       string                 `yaml:"host"`
-	Hosts      []string               `yaml:"hosts"`
-	Domain     string                 `yaml:"domain"`
-	Domains    []string               `yaml:"domains"`
-	NoHostname bool                   `yaml:"no-hostname"`
-	NoRoute    bool                   `yaml:"no-route"`
-	DiskQuota  string                 `yaml:"disk_quota"`
-	Stack      string                 `yaml:"stack"`
-	AppPorts   []int                  `yaml:"app-ports"`
+	Name            string                 `yaml:"name"`
+	Services        []string               `yaml:"services"`
+	Buildpack       string                 `yaml:"buildpack"`
+	Memory          string                 `yaml:"memory"`
+	Command         string                 `yaml:"command"`
+	Env             map[string]interface{} `yaml:"env"`
+	Timeout         int                    `yaml:"timeout"`
+	Instances       int                    `yaml:"instances"`
+	Host            string                 `yaml:"host"`
+	Hosts           []string               `yaml:"hosts"`
+	Domain          string                 `yaml:"domain"`
+	Domains         []string               `yaml:"domains"`
+	Routes          []ManifestRoute        `yaml:"routes"`
+	NoHostname      bool                   `yaml:"no-hostname"`
+	NoRoute         bool                   `yaml:"no-route"`
+	DiskQuota       string                 `yaml:"disk_quota"`
+	Stack           string                 `yaml:"stack"`
+	AppPorts        []int                  `yaml:"app-ports"`
+	HealthCheckType string                 `yaml:"health-check-type"`
+	DockerImage     string                 `yaml:"docker-image"`
+	Path            string                 `yaml:"path"`
 }
 
 func getYaml(f *bytes.Buffer) YManifest {
@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type location struct {
+	line   int
+	column int
+}
+
+type locationFrame struct {
+	indent int
+	path   string
+	item   bool
+}
+
+var (
+	listItemRegex = regexp.MustCompile(`^-(\s+|$)`)
+	mapKeyRegex   = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s#'"][^:#]*?)\s*:(\s|$)`)
+)
+
+// indexLocations finds the line and column of every key and list item in a
+// block-style YAML document, keyed by paths such as "applications[0].env.FOO".
+// Flow-style collections are not indexed; lookups for paths inside them fall
+// back to the nearest indexed parent.
+func indexLocations(lines []string) map[string]location {
+	locations := map[string]location{}
+	itemCounts := map[string]int{}
+	var stack []locationFrame
+
+	for lineIndex, line := range lines {
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "#") || strings.HasPrefix(content, "---") || strings.HasPrefix(content, "...") {
+			continue
+		}
+		column := len(line) - len(content)
+
+		for {
+			match := listItemRegex.FindString(content)
+			if match == "" {
+				break
+			}
+
+			for len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if top.indent < column || (top.indent == column && !top.item) {
+					break
+				}
+				stack = stack[:len(stack)-1]
+			}
+
+			parent := framePath(stack)
+			path := fmt.Sprintf("%s[%d]", parent, itemCounts[parent])
+			itemCounts[parent]++
+
+			locations[path] = location{line: lineIndex + 1, column: column + 1}
+			stack = append(stack, locationFrame{indent: column, path: path, item: true})
+
+			content = content[len(match):]
+			column += len(match)
+		}
+
+		match := mapKeyRegex.FindStringSubmatch(content)
+		if match == nil {
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= column {
+			stack = stack[:len(stack)-1]
+		}
+
+		key := strings.Trim(match[1], `"'`)
+		path := key
+		if parent := framePath(stack); parent != "" {
+			path = parent + "." + key
+		}
+
+		if _, found := locations[path]; !found {
+			locations[path] = location{line: lineIndex + 1, column: column + 1}
+		}
+		stack = append(stack, locationFrame{indent: column, path: path})
+	}
+
+	return locations
+}
+
+func framePath(stack []locationFrame) string {
+	if len(stack) == 0 {
+		return ""
+	}
+	return stack[len(stack)-1].path
+}
+
+func findLocation(locations map[string]location, path string) (location, bool) {
+	for path != "" {
+		if loc, found := locations[path]; found {
+			return loc, true
+		}
+
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return location{}, false
+}
@@ -17,16 +17,43 @@ import (
 )
 
 type Manifest struct {
-	Path string
-	Data generic.Map
+	Path      string
+	Data      generic.Map
+	Variables map[string]interface{}
+
+	sources []manifestSource
 }
 
 func NewEmptyManifest() (m *Manifest) {
 	return &Manifest{Data: generic.NewMap()}
 }
 
+// Merge overlays another manifest onto this one, following the same rules as
+// inherit. Relative paths in the overlay are resolved from its own directory.
+func (m *Manifest) Merge(overlay *Manifest) error {
+	dir, err := filepath.Abs(filepath.Dir(overlay.Path))
+	if err != nil {
+		return err
+	}
+	resolvePaths(overlay.Data, dir)
+
+	data, err := mergeManifestData(m.Data, overlay.Data)
+	if err != nil {
+		return err
+	}
+
+	m.Data = data
+	m.sources = append(m.sources, overlay.sources...)
+	return nil
+}
+
 func (m Manifest) Applications() ([]models.AppParams, error) {
-	rawData, err := expandProperties(m.Data, generator.NewWordGenerator())
+	interpolatedData, err := m.interpolateVariables(m.Data, nil)
+	if err != nil {
+		return []models.AppParams{}, err
+	}
+
+	rawData, err := expandProperties(interpolatedData, generator.NewWordGenerator())
 	if err != nil {
 		return []models.AppParams{}, err
 	}
@@ -198,9 +225,20 @@ func mapToAppParams(basePath string, yamlMap generic.Map) (models.AppParams, err
 	appParams.NoHostname = boolVal(yamlMap, "no-hostname", &errs)
 	appParams.UseRandomRoute = boolVal(yamlMap, "random-route", &errs)
 	appParams.ServicesToBind = sliceOrEmptyVal(yamlMap, "services", &errs)
+	appParams.DependsOn = sliceOrEmptyVal(yamlMap, "depends-on", &errs)
 	appParams.EnvironmentVars = envVarOrEmptyMap(yamlMap, &errs)
 	appParams.HealthCheckType = stringVal(yamlMap, "health-check-type", &errs)
 	appParams.AppPorts = intSliceVal(yamlMap, "app-ports", &errs)
+	appParams.DockerImage = stringVal(yamlMap, "docker-image", &errs)
+	appParams.Routes = routesVal(yamlMap, &errs)
+
+	if appParams.Routes != nil {
+		for _, key := range []string{"host", "hosts", "domain", "domains", "no-hostname", "random-route"} {
+			if yamlMap.Has(key) {
+				errs = append(errs, fmt.Errorf(T("{{.PropertyName}} cannot be used together with routes", map[string]interface{}{"PropertyName": key})))
+			}
+		}
+	}
 
 	if appParams.Path != nil {
 		path := *appParams.Path
@@ -424,6 +462,41 @@ func intSliceVal(yamlMap generic.Map, key string, errs *[]error) *[]int {
 	return &intSlice
 }
 
+// routesVal reads the routes property, a list of maps each holding a single
+// route such as "host.example.com/path" or "tcp.example.com:1234".
+func routesVal(yamlMap generic.Map, errs *[]error) *[]string {
+	key := "routes"
+	if !yamlMap.Has(key) {
+		return nil
+	}
+
+	err := fmt.Errorf(T("Expected {{.PropertyName}} to be a list of routes, such as '- route: host.example.com/path'.", map[string]interface{}{"PropertyName": key}))
+
+	input, ok := yamlMap.Get(key).([]interface{})
+	if !ok {
+		*errs = append(*errs, err)
+		return nil
+	}
+
+	routes := []string{}
+	for _, item := range input {
+		if !generic.IsMappable(item) {
+			*errs = append(*errs, err)
+			return nil
+		}
+
+		route, ok := generic.NewMap(item).Get("route").(string)
+		if !ok || route == "" {
+			*errs = append(*errs, err)
+			return nil
+		}
+
+		routes = append(routes, route)
+	}
+
+	return &routes
+}
+
 func envVarOrEmptyMap(yamlMap generic.Map, errs *[]error) *map[string]interface{} {
 	key := "env"
 	switch envVars := yamlMap.Get(key).(type) {
@@ -1,11 +1,13 @@
 package manifest
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/cloudfoundry/cli/cf/errors"
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -35,7 +37,7 @@ func (repo ManifestDiskRepository) ReadManifest(inputPath string) (*Manifest, er
 
 	m.Path = manifestPath
 
-	mapp, err := repo.readAllYAMLFiles(manifestPath)
+	mapp, err := repo.readAllYAMLFiles(manifestPath, m)
 	if err != nil {
 		return m, err
 	}
@@ -45,14 +47,24 @@ func (repo ManifestDiskRepository) ReadManifest(inputPath string) (*Manifest, er
 	return m, nil
 }
 
-func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap generic.Map, err error) {
+func (repo ManifestDiskRepository) readAllYAMLFiles(path string, m *Manifest) (mergedMap generic.Map, err error) {
 	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
-	mapp, err := parseManifest(file)
+	contents, err := ioutil.ReadAll(file)
+	if err != nil {
+		return
+	}
+
+	m.sources = append(m.sources, manifestSource{
+		path:  path,
+		lines: strings.Split(string(contents), "\n"),
+	})
+
+	mapp, err := parseManifest(bytes.NewReader(contents))
 	if err != nil {
 		return
 	}
@@ -72,12 +84,12 @@ func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap gene
 		inheritedPath = filepath.Join(filepath.Dir(path), inheritedPath)
 	}
 
-	inheritedMap, err := repo.readAllYAMLFiles(inheritedPath)
+	inheritedMap, err := repo.readAllYAMLFiles(inheritedPath, m)
 	if err != nil {
 		return
 	}
 
-	mergedMap = generic.DeepMerge(inheritedMap, mapp)
+	mergedMap, err = mergeManifestData(inheritedMap, mapp)
 	return
 }
 
@@ -155,6 +155,23 @@ var _ = Describe("ManifestDiskRepository", func() {
 		Expect(services).To(Equal([]string{"base-service", "foo-service"}))
 	})
 
+	It("merges inherited applications that have the same name", func() {
+		m, err := repo.ReadManifest("../../fixtures/manifests/overlay/prod.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		applications, err := m.Applications()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applications).To(HaveLen(2))
+
+		Expect(*applications[0].Name).To(Equal("web"))
+		Expect(*applications[0].InstanceCount).To(Equal(4))
+		Expect(*applications[0].Hosts).To(Equal([]string{"web"}))
+		Expect(*applications[0].Memory).To(Equal(int64(256)))
+		Expect(*applications[0].ServicesToBind).To(Equal([]string{"logging", "prod-db"}))
+
+		Expect(*applications[1].Name).To(Equal("worker"))
+	})
+
 	It("supports yml merges", func() {
 		m, err := repo.ReadManifest("../../fixtures/manifests/merge-manifest.yml")
 		Expect(err).NotTo(HaveOccurred())
@@ -174,4 +191,44 @@ var _ = Describe("ManifestDiskRepository", func() {
 		Expect(*applications[2].InstanceCount).To(Equal(3))
 		Expect(*applications[2].Memory).To(Equal(int64(256)))
 	})
+	Describe("manifests with ((var)) variables", func() {
+		var m *Manifest
+
+		BeforeEach(func() {
+			var err error
+			m, err = repo.ReadManifest("../../fixtures/manifests/vars")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("interpolates variables in the manifest and the manifests it inherits", func() {
+			variables, err := ReadVariables(
+				[]string{"../../fixtures/manifests/vars/vars.yml"},
+				[]string{"database-url=postgres://db", "env=prod"},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			m.Variables = variables
+
+			applications, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(*applications[0].Name).To(Equal("my-app"))
+			Expect(*applications[0].InstanceCount).To(Equal(2))
+			Expect(*applications[0].Hosts).To(Equal([]string{"my-app-prod"}))
+			Expect((*applications[0].EnvironmentVars)["DATABASE_URL"]).To(Equal("postgres://db"))
+		})
+
+		It("reports the file and line of unresolved variables", func() {
+			m.Variables = map[string]interface{}{"app-name": "my-app"}
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+
+			manifestPath := filepath.Clean("../../fixtures/manifests/vars/manifest.yml")
+			basePath := filepath.Join(filepath.Dir(manifestPath), "base.yml")
+			Expect(err.Error()).To(ContainSubstring("((database-url)) at " + basePath + ":4"))
+			Expect(err.Error()).To(ContainSubstring("((env)) at " + manifestPath + ":6"))
+			Expect(err.Error()).To(ContainSubstring("((instances)) at " + manifestPath + ":5"))
+			Expect(err.Error()).NotTo(ContainSubstring("((app-name))"))
+		})
+	})
 })
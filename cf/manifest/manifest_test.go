@@ -342,6 +342,134 @@ var _ = Describe("Manifests", func() {
 		})
 	})
 
+	Describe("((var)) variables", func() {
+		It("substitutes variables, keeping the type of values that are only a variable", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name":      "((name))",
+						"instances": "((instances))",
+						"host":      "((name))-((space))",
+						"env": map[interface{}]interface{}{
+							"DEBUG": "((debug))",
+						},
+					},
+				},
+			}))
+			m.Variables = map[string]interface{}{
+				"name":      "my-app",
+				"instances": 3,
+				"space":     "dev",
+				"debug":     true,
+			}
+
+			apps, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*apps[0].Name).To(Equal("my-app"))
+			Expect(*apps[0].InstanceCount).To(Equal(3))
+			Expect(*apps[0].Hosts).To(Equal([]string{"my-app-dev"}))
+			Expect((*apps[0].EnvironmentVars)["DEBUG"]).To(Equal(true))
+		})
+
+		It("returns an error listing every unresolved variable", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name": "((name))",
+						"host": "((host))",
+					},
+				},
+			}))
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("((host))"))
+			Expect(err.Error()).To(ContainSubstring("((name))"))
+		})
+
+		It("does not change the manifest data", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"name": "((name))",
+			}))
+			m.Variables = map[string]interface{}{"name": "my-app"}
+
+			_, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Data.Get("name")).To(Equal("((name))"))
+		})
+	})
+
+	Describe("VariableReferences", func() {
+		It("returns the properties variables are interpolated into", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"instances": "((instances))",
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name":   "my-app",
+						"host":   "((name))-((space))",
+						"memory": "1G",
+						"env": map[interface{}]interface{}{
+							"REPLICAS": 1,
+							"DEBUG":    "((debug))",
+						},
+					},
+				},
+			}))
+			m.Variables = map[string]interface{}{
+				"name":      "my-app",
+				"instances": 1,
+				"space":     "dev",
+				"debug":     true,
+			}
+
+			references, err := m.VariableReferences()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(references).To(Equal([]manifest.VariableReference{
+				{Property: "env.DEBUG", Template: "((debug))", Value: true},
+				{Property: "host", Template: "((name))-((space))", Value: "my-app-dev"},
+				{Property: "instances", Template: "((instances))", Value: 1},
+			}))
+		})
+
+		It("returns an error for unresolved variables", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"host": "((host))",
+			}))
+
+			_, err := m.VariableReferences()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("((host))"))
+		})
+	})
+
+	Describe("ReadVariables", func() {
+		It("lets --var values override vars files", func() {
+			variables, err := manifest.ReadVariables(
+				[]string{"../../fixtures/manifests/vars/vars.yml"},
+				[]string{"env=prod", "instances=5", "url=http://example.com?a=b"},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variables).To(Equal(map[string]interface{}{
+				"app-name":  "my-app",
+				"env":       "prod",
+				"instances": 5,
+				"url":       "http://example.com?a=b",
+			}))
+		})
+
+		It("returns an error when a variable is not NAME=VALUE", func() {
+			_, err := manifest.ReadVariables([]string{}, []string{"no-value"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no-value"))
+		})
+
+		It("returns an error when a vars file cannot be read", func() {
+			_, err := manifest.ReadVariables([]string{"../../fixtures/manifests/vars/missing.yml"}, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("missing.yml"))
+		})
+	})
+
 	It("sets the command and buildpack to blank when their values are null in the manifest", func() {
 		m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
 			"applications": []interface{}{
@@ -507,4 +635,77 @@ var _ = Describe("Manifests", func() {
 			Expect(*app[0].ServicesToBind).To(Equal([]string{"service-1", "service-2"}))
 		})
 	})
+
+	Describe("parsing dependencies", func() {
+		It("can read a list of app names", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"depends-on": []interface{}{"app-1", "app-2"},
+			}))
+
+			app, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(*app[0].DependsOn).To(Equal([]string{"app-1", "app-2"}))
+		})
+
+		It("returns an error when depends-on is not a list of strings", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"depends-on": "app-1",
+			}))
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected depends-on to be a list of strings."))
+		})
+	})
+
+	Describe("parsing routes", func() {
+		It("can read a list of routes", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"routes": []interface{}{
+					map[interface{}]interface{}{"route": "my-app.example.com/api"},
+					map[interface{}]interface{}{"route": "tcp.example.com:1234"},
+				},
+			}))
+
+			app, err := m.Applications()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(*app[0].Routes).To(Equal([]string{"my-app.example.com/api", "tcp.example.com:1234"}))
+		})
+
+		It("returns an error when a route is not a map with a route", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"routes": []interface{}{"my-app.example.com"},
+			}))
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected routes to be a list of routes"))
+		})
+
+		It("returns an error when routes are combined with hosts or domains", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"domain": "example.com",
+				"routes": []interface{}{
+					map[interface{}]interface{}{"route": "my-app.example.com"},
+				},
+			}))
+
+			_, err := m.Applications()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("domain cannot be used together with routes"))
+		})
+	})
+
+	It("parses the docker image", func() {
+		m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+			"docker-image": "user/my-image",
+		}))
+
+		app, err := m.Applications()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(*app[0].DockerImage).To(Equal("user/my-image"))
+	})
 })
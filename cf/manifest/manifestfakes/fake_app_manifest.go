@@ -66,6 +66,33 @@ type FakeAppManifest struct {
 		arg2 string
 		arg3 string
 	}
+	RouteStub        func(string, string, string, string, int)
+	routeMutex       sync.RWMutex
+	routeArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 int
+	}
+	HealthCheckTypeStub        func(string, string)
+	healthCheckTypeMutex       sync.RWMutex
+	healthCheckTypeArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	DockerImageStub        func(string, string)
+	dockerImageMutex       sync.RWMutex
+	dockerImageArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	PathStub        func(string, string)
+	pathMutex       sync.RWMutex
+	pathArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
 	GetContentsStub        func() []models.Application
 	getContentsMutex       sync.RWMutex
 	getContentsArgsForCall []struct{}
@@ -84,6 +111,11 @@ type FakeAppManifest struct {
 		arg1 string
 		arg2 []int
 	}
+	VariablesStub        func([]manifest.VariableReference)
+	variablesMutex       sync.RWMutex
+	variablesArgsForCall []struct {
+		arg1 []manifest.VariableReference
+	}
 	SaveStub        func(f io.Writer) error
 	saveMutex       sync.RWMutex
 	saveArgsForCall []struct {
@@ -312,6 +344,105 @@ func (fake *FakeAppManifest) DomainArgsForCall(i int) (string, string, string) {
 	return fake.domainArgsForCall[i].arg1, fake.domainArgsForCall[i].arg2, fake.domainArgsForCall[i].arg3
 }
 
+func (fake *FakeAppManifest) Route(arg1 string, arg2 string, arg3 string, arg4 string, arg5 int) {
+	fake.routeMutex.Lock()
+	fake.routeArgsForCall = append(fake.routeArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 int
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.routeMutex.Unlock()
+	if fake.RouteStub != nil {
+		fake.RouteStub(arg1, arg2, arg3, arg4, arg5)
+	}
+}
+
+func (fake *FakeAppManifest) RouteCallCount() int {
+	fake.routeMutex.RLock()
+	defer fake.routeMutex.RUnlock()
+	return len(fake.routeArgsForCall)
+}
+
+func (fake *FakeAppManifest) RouteArgsForCall(i int) (string, string, string, string, int) {
+	fake.routeMutex.RLock()
+	defer fake.routeMutex.RUnlock()
+	return fake.routeArgsForCall[i].arg1, fake.routeArgsForCall[i].arg2, fake.routeArgsForCall[i].arg3, fake.routeArgsForCall[i].arg4, fake.routeArgsForCall[i].arg5
+}
+
+func (fake *FakeAppManifest) HealthCheckType(arg1 string, arg2 string) {
+	fake.healthCheckTypeMutex.Lock()
+	fake.healthCheckTypeArgsForCall = append(fake.healthCheckTypeArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.healthCheckTypeMutex.Unlock()
+	if fake.HealthCheckTypeStub != nil {
+		fake.HealthCheckTypeStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppManifest) HealthCheckTypeCallCount() int {
+	fake.healthCheckTypeMutex.RLock()
+	defer fake.healthCheckTypeMutex.RUnlock()
+	return len(fake.healthCheckTypeArgsForCall)
+}
+
+func (fake *FakeAppManifest) HealthCheckTypeArgsForCall(i int) (string, string) {
+	fake.healthCheckTypeMutex.RLock()
+	defer fake.healthCheckTypeMutex.RUnlock()
+	return fake.healthCheckTypeArgsForCall[i].arg1, fake.healthCheckTypeArgsForCall[i].arg2
+}
+
+func (fake *FakeAppManifest) DockerImage(arg1 string, arg2 string) {
+	fake.dockerImageMutex.Lock()
+	fake.dockerImageArgsForCall = append(fake.dockerImageArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.dockerImageMutex.Unlock()
+	if fake.DockerImageStub != nil {
+		fake.DockerImageStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppManifest) DockerImageCallCount() int {
+	fake.dockerImageMutex.RLock()
+	defer fake.dockerImageMutex.RUnlock()
+	return len(fake.dockerImageArgsForCall)
+}
+
+func (fake *FakeAppManifest) DockerImageArgsForCall(i int) (string, string) {
+	fake.dockerImageMutex.RLock()
+	defer fake.dockerImageMutex.RUnlock()
+	return fake.dockerImageArgsForCall[i].arg1, fake.dockerImageArgsForCall[i].arg2
+}
+
+func (fake *FakeAppManifest) Path(arg1 string, arg2 string) {
+	fake.pathMutex.Lock()
+	fake.pathArgsForCall = append(fake.pathArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.pathMutex.Unlock()
+	if fake.PathStub != nil {
+		fake.PathStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppManifest) PathCallCount() int {
+	fake.pathMutex.RLock()
+	defer fake.pathMutex.RUnlock()
+	return len(fake.pathArgsForCall)
+}
+
+func (fake *FakeAppManifest) PathArgsForCall(i int) (string, string) {
+	fake.pathMutex.RLock()
+	defer fake.pathMutex.RUnlock()
+	return fake.pathArgsForCall[i].arg1, fake.pathArgsForCall[i].arg2
+}
+
 func (fake *FakeAppManifest) GetContents() []models.Application {
 	fake.getContentsMutex.Lock()
 	fake.getContentsArgsForCall = append(fake.getContentsArgsForCall, struct{}{})
@@ -384,6 +515,29 @@ func (fake *FakeAppManifest) AppPortsArgsForCall(i int) (string, []int) {
 	return fake.appPortsArgsForCall[i].arg1, fake.appPortsArgsForCall[i].arg2
 }
 
+func (fake *FakeAppManifest) Variables(arg1 []manifest.VariableReference) {
+	fake.variablesMutex.Lock()
+	fake.variablesArgsForCall = append(fake.variablesArgsForCall, struct {
+		arg1 []manifest.VariableReference
+	}{arg1})
+	fake.variablesMutex.Unlock()
+	if fake.VariablesStub != nil {
+		fake.VariablesStub(arg1)
+	}
+}
+
+func (fake *FakeAppManifest) VariablesCallCount() int {
+	fake.variablesMutex.RLock()
+	defer fake.variablesMutex.RUnlock()
+	return len(fake.variablesArgsForCall)
+}
+
+func (fake *FakeAppManifest) VariablesArgsForCall(i int) []manifest.VariableReference {
+	fake.variablesMutex.RLock()
+	defer fake.variablesMutex.RUnlock()
+	return fake.variablesArgsForCall[i].arg1
+}
+
 func (fake *FakeAppManifest) Save(f io.Writer) error {
 	fake.saveMutex.Lock()
 	fake.saveArgsForCall = append(fake.saveArgsForCall, struct {
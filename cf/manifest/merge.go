@@ -0,0 +1,199 @@
+package manifest
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/generic"
+)
+
+// Route properties are replaced rather than merged: an overlay that sets any
+// property of a group replaces every property of that group in the manifest it
+// is merged onto, so an environment's overlay fully decides its own routes.
+var replacedPropertyGroups = [][]interface{}{
+	{"host", "hosts", "no-hostname", "random-route", "routes"},
+	{"domain", "domains", "routes"},
+}
+
+// mergeManifestData deep-merges overlay onto base, as used by both inherit and
+// several -f flags on push. The rules are:
+//
+//   - maps such as env are merged key by key, with values in overlay winning
+//   - lists of strings such as services are concatenated, dropping duplicates
+//   - host, hosts, no-hostname and random-route are replaced as a group, and
+//     so are domain and domains; routes replaces both groups
+//   - applications are matched by name; a matching application is merged with
+//     these same rules, and any other application is added to the end
+//   - every other value in overlay replaces the one in base, and so does null
+//
+// A map or list in overlay can only be merged onto a value of the same kind;
+// anything else is an error.
+func mergeManifestData(base, overlay generic.Map) (generic.Map, error) {
+	applications := []interface{}{"applications"}
+
+	kept := withoutReplacedProperties(base.Except(applications), overlay)
+	err := checkMergeable(kept, overlay.Except(applications))
+	if err != nil {
+		return nil, err
+	}
+
+	merged := generic.DeepMerge(kept, overlay.Except(applications))
+	removeDuplicatedStrings(merged)
+
+	if overlay.Has("applications") {
+		baseApps, baseOK := base.Get("applications").([]interface{})
+		overlayApps, overlayOK := overlay.Get("applications").([]interface{})
+		if baseOK && overlayOK {
+			apps, err := mergeApplications(baseApps, overlayApps)
+			if err != nil {
+				return nil, err
+			}
+			merged.Set("applications", apps)
+		} else {
+			merged.Set("applications", overlay.Get("applications"))
+		}
+	} else if base.Has("applications") {
+		merged.Set("applications", base.Get("applications"))
+	}
+
+	return merged, nil
+}
+
+func mergeApplications(baseApps, overlayApps []interface{}) ([]interface{}, error) {
+	merged := make([]interface{}, len(baseApps))
+	copy(merged, baseApps)
+
+	for _, overlayApp := range overlayApps {
+		index := findApplication(merged, overlayApp)
+		if index < 0 {
+			merged = append(merged, overlayApp)
+			continue
+		}
+
+		app, err := mergeManifestData(generic.NewMap(merged[index]), generic.NewMap(overlayApp))
+		if err != nil {
+			return nil, err
+		}
+		merged[index] = app
+	}
+
+	return merged, nil
+}
+
+// checkMergeable makes sure that every map and list in overlay is merged onto
+// a map or list in base, looking into maps merged key by key. Nulls on either
+// side are simply replaced.
+func checkMergeable(base, overlay generic.Map) error {
+	var err error
+	generic.Each(overlay, func(key, value interface{}) {
+		baseValue := base.Get(key)
+		if err != nil || value == nil || baseValue == nil {
+			return
+		}
+
+		switch {
+		case generic.IsMappable(value):
+			if !generic.IsMappable(baseValue) {
+				err = mismatchError(key)
+				return
+			}
+			err = checkMergeable(generic.NewMap(baseValue), generic.NewMap(value))
+		case generic.IsSliceable(value):
+			if _, ok := baseValue.([]interface{}); !ok {
+				err = mismatchError(key)
+			}
+		}
+	})
+	return err
+}
+
+func mismatchError(key interface{}) error {
+	return errors.New(T("Cannot merge manifests: {{.PropertyName}} is a list or map in one manifest but not in another", map[string]interface{}{"PropertyName": key}))
+}
+
+func findApplication(apps []interface{}, app interface{}) int {
+	if !generic.IsMappable(app) {
+		return -1
+	}
+
+	name, ok := generic.NewMap(app).Get("name").(string)
+	if !ok {
+		return -1
+	}
+
+	for index, candidate := range apps {
+		if generic.IsMappable(candidate) && generic.NewMap(candidate).Get("name") == name {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func withoutReplacedProperties(base, overlay generic.Map) generic.Map {
+	var replaced []interface{}
+	for _, group := range replacedPropertyGroups {
+		for _, key := range group {
+			if overlay.Has(key) {
+				replaced = append(replaced, group...)
+				break
+			}
+		}
+	}
+
+	return base.Except(replaced)
+}
+
+func removeDuplicatedStrings(data generic.Map) {
+	generic.Each(data, func(key, value interface{}) {
+		values, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+
+		seen := map[string]bool{}
+		unique := []interface{}{}
+		for _, item := range values {
+			str, ok := item.(string)
+			if !ok {
+				return
+			}
+			if !seen[str] {
+				seen[str] = true
+				unique = append(unique, str)
+			}
+		}
+
+		data.Set(key, unique)
+	})
+}
+
+// resolvePaths makes the relative paths in data, of the manifest itself and of
+// its applications, relative to dir instead, so that they keep pointing at the
+// same place once merged into a manifest in another directory. Paths that
+// start with a variable are left alone, since it may hold an absolute path.
+func resolvePaths(data generic.Map, dir string) {
+	resolvePath(data, dir)
+
+	apps, ok := data.Get("applications").([]interface{})
+	if !ok {
+		return
+	}
+	for i, app := range apps {
+		if generic.IsMappable(app) {
+			appMap := generic.NewMap(app)
+			resolvePath(appMap, dir)
+			apps[i] = appMap
+		}
+	}
+}
+
+func resolvePath(data generic.Map, dir string) {
+	path, ok := data.Get("path").(string)
+	if !ok || filepath.IsAbs(path) || strings.HasPrefix(path, "((") {
+		return
+	}
+	data.Set("path", filepath.Join(dir, path))
+}
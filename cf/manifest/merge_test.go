@@ -0,0 +1,167 @@
+package manifest_test
+
+import (
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/generic"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("merging manifests", func() {
+	var base, overlay map[interface{}]interface{}
+
+	BeforeEach(func() {
+		base = map[interface{}]interface{}{
+			"memory":   "256M",
+			"services": []interface{}{"logging", "db"},
+			"env": map[interface{}]interface{}{
+				"LOG_LEVEL": "info",
+				"REGION":    "us",
+			},
+			"applications": []interface{}{
+				map[interface{}]interface{}{
+					"name":      "web",
+					"instances": 1,
+					"hosts":     []interface{}{"web", "www"},
+					"domain":    "example.com",
+				},
+				map[interface{}]interface{}{
+					"name": "worker",
+				},
+			},
+		}
+		overlay = map[interface{}]interface{}{}
+	})
+
+	merge := func() generic.Map {
+		m := NewManifest("/some/path/manifest.yml", generic.NewMap(base))
+		err := m.Merge(NewManifest("/some/path/prod.yml", generic.NewMap(overlay)))
+		Expect(err).NotTo(HaveOccurred())
+		return m.Data
+	}
+
+	app := func(data generic.Map, index int) generic.Map {
+		return generic.NewMap(data.Get("applications").([]interface{})[index])
+	}
+
+	It("replaces scalar values", func() {
+		overlay["memory"] = "1G"
+
+		Expect(merge().Get("memory")).To(Equal("1G"))
+	})
+
+	It("merges maps key by key", func() {
+		overlay["env"] = map[interface{}]interface{}{"LOG_LEVEL": "warn"}
+
+		env := generic.NewMap(merge().Get("env"))
+		Expect(env.Get("LOG_LEVEL")).To(Equal("warn"))
+		Expect(env.Get("REGION")).To(Equal("us"))
+	})
+
+	It("concatenates services without duplicates", func() {
+		overlay["services"] = []interface{}{"db", "cache"}
+
+		Expect(merge().Get("services")).To(Equal([]interface{}{"logging", "db", "cache"}))
+	})
+
+	It("merges applications with the same name and adds new ones", func() {
+		overlay["applications"] = []interface{}{
+			map[interface{}]interface{}{"name": "web", "instances": 4},
+			map[interface{}]interface{}{"name": "scheduler"},
+		}
+
+		data := merge()
+		Expect(data.Get("applications")).To(HaveLen(3))
+		Expect(app(data, 0).Get("instances")).To(Equal(4))
+		Expect(app(data, 0).Get("domain")).To(Equal("example.com"))
+		Expect(app(data, 1).Get("name")).To(Equal("worker"))
+		Expect(app(data, 2).Get("name")).To(Equal("scheduler"))
+	})
+
+	It("keeps the applications of the base when the overlay has none", func() {
+		Expect(merge().Get("applications")).To(HaveLen(2))
+	})
+
+	It("replaces hosts when the overlay sets a host", func() {
+		overlay["applications"] = []interface{}{
+			map[interface{}]interface{}{"name": "web", "host": "web-prod"},
+		}
+
+		web := app(merge(), 0)
+		Expect(web.Get("host")).To(Equal("web-prod"))
+		Expect(web.Has("hosts")).To(BeFalse())
+		Expect(web.Get("domain")).To(Equal("example.com"))
+	})
+
+	It("replaces domains when the overlay sets domains", func() {
+		overlay["applications"] = []interface{}{
+			map[interface{}]interface{}{"name": "web", "domains": []interface{}{"prod.example.com"}},
+		}
+
+		web := app(merge(), 0)
+		Expect(web.Get("domains")).To(Equal([]interface{}{"prod.example.com"}))
+		Expect(web.Has("domain")).To(BeFalse())
+		Expect(web.Get("hosts")).To(Equal([]interface{}{"web", "www"}))
+	})
+
+	It("replaces hosts and domains when the overlay sets routes", func() {
+		overlay["applications"] = []interface{}{
+			map[interface{}]interface{}{
+				"name":   "web",
+				"routes": []interface{}{map[interface{}]interface{}{"route": "web.prod.example.com"}},
+			},
+		}
+
+		web := app(merge(), 0)
+		Expect(web.Get("routes")).To(HaveLen(1))
+		Expect(web.Has("hosts")).To(BeFalse())
+		Expect(web.Has("domain")).To(BeFalse())
+	})
+
+	It("replaces values with null", func() {
+		overlay["env"] = nil
+
+		data := merge()
+		Expect(data.Has("env")).To(BeTrue())
+		Expect(data.Get("env")).To(BeNil())
+	})
+
+	It("fails when a list is merged onto a single value", func() {
+		base["stack"] = "cflinuxfs2"
+		overlay["stack"] = []interface{}{"cflinuxfs3"}
+
+		m := NewManifest("/some/path/manifest.yml", generic.NewMap(base))
+		err := m.Merge(NewManifest("/some/path/prod.yml", generic.NewMap(overlay)))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("stack"))
+	})
+
+	It("fails when a map is merged onto a single value in an application", func() {
+		overlay["applications"] = []interface{}{
+			map[interface{}]interface{}{"name": "web", "instances": map[interface{}]interface{}{"count": 2}},
+		}
+
+		m := NewManifest("/some/path/manifest.yml", generic.NewMap(base))
+		err := m.Merge(NewManifest("/some/path/prod.yml", generic.NewMap(overlay)))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("instances"))
+	})
+
+	It("resolves the relative paths of the overlay from its own directory", func() {
+		overlayDir, err := filepath.Abs(filepath.Join("other", "dir"))
+		Expect(err).NotTo(HaveOccurred())
+
+		base["path"] = "base-dir"
+		overlay["applications"] = []interface{}{
+			map[interface{}]interface{}{"name": "web", "path": "web-dir"},
+		}
+
+		m := NewManifest("/some/path/manifest.yml", generic.NewMap(base))
+		err = m.Merge(NewManifest(filepath.Join(overlayDir, "prod.yml"), generic.NewMap(overlay)))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.Data.Get("path")).To(Equal("base-dir"))
+		Expect(app(m.Data, 0).Get("path")).To(Equal(filepath.Join(overlayDir, "web-dir")))
+	})
+})
@@ -0,0 +1,387 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/generic"
+	"gopkg.in/yaml.v2"
+)
+
+type propertyType int
+
+const (
+	stringProperty propertyType = iota
+	stringOrNullProperty
+	bytesProperty
+	intProperty
+	boolProperty
+	stringListProperty
+	intListProperty
+	mapProperty
+	routeListProperty
+)
+
+// appProperties are the properties an application, or the top level of a
+// manifest, may set. They mirror the fields read by mapToAppParams.
+var appProperties = map[string]propertyType{
+	"buildpack":         stringOrNullProperty,
+	"command":           stringOrNullProperty,
+	"depends-on":        stringListProperty,
+	"disk_quota":        bytesProperty,
+	"docker-image":      stringProperty,
+	"domain":            stringProperty,
+	"domains":           stringListProperty,
+	"env":               mapProperty,
+	"health-check-type": stringProperty,
+	"host":              stringProperty,
+	"hosts":             stringListProperty,
+	"instances":         intProperty,
+	"memory":            bytesProperty,
+	"name":              stringProperty,
+	"no-hostname":       boolProperty,
+	"no-route":          boolProperty,
+	"path":              stringProperty,
+	"random-route":      boolProperty,
+	"routes":            routeListProperty,
+	"services":          stringListProperty,
+	"stack":             stringProperty,
+	"timeout":           intProperty,
+	"app-ports":         intListProperty,
+}
+
+// deprecatedProperties were understood by earlier versions of the CLI and are
+// now ignored, mapped to what should be used instead.
+var deprecatedProperties = map[string]string{
+	"framework": "buildpack",
+	"runtime":   "buildpack",
+	"url":       "host and domain",
+}
+
+var (
+	yamlErrorLineRegex = regexp.MustCompile(`line (\d+)`)
+	yamlAnchorRegex    = regexp.MustCompile(`(^|[\s:\[{,-])&[^\s\[\]{},]+`)
+)
+
+type ValidationError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+	Warning bool
+}
+
+func (err ValidationError) Error() string {
+	location := err.File
+	if err.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", err.File, err.Line, err.Column)
+	}
+	if location == "" {
+		return err.Message
+	}
+	return location + ": " + err.Message
+}
+
+type ValidationErrors []ValidationError
+
+// Errors returns the problems that make the manifest unusable, leaving out
+// warnings.
+func (errs ValidationErrors) Errors() ValidationErrors {
+	var result ValidationErrors
+	for _, err := range errs {
+		if !err.Warning {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+// Warnings returns the problems that are reported but do not stop the
+// manifest from being used.
+func (errs ValidationErrors) Warnings() ValidationErrors {
+	var result ValidationErrors
+	for _, err := range errs {
+		if err.Warning {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+// Validate checks every file the manifest was read from, or its data when it
+// was not read from disk, without contacting the Cloud Controller.
+func (m Manifest) Validate() ValidationErrors {
+	if len(m.sources) == 0 {
+		v := &validator{}
+		v.validateManifest(m.Data)
+		return v.errs
+	}
+
+	var errs ValidationErrors
+	for _, source := range m.sources {
+		errs = append(errs, validateSource(source)...)
+	}
+	return errs
+}
+
+func validateSource(source manifestSource) ValidationErrors {
+	v := &validator{
+		file:      source.path,
+		lines:     source.lines,
+		locations: indexLocations(source.lines),
+	}
+
+	data := make(map[interface{}]interface{})
+	err := yaml.Unmarshal([]byte(strings.Join(source.lines, "\n")), &data)
+	if err != nil {
+		validationErr := ValidationError{File: source.path, Message: err.Error()}
+		if match := yamlErrorLineRegex.FindStringSubmatch(err.Error()); match != nil {
+			validationErr.Line, _ = strconv.Atoi(match[1])
+			validationErr.Column = 1
+		}
+		return ValidationErrors{validationErr}
+	}
+
+	v.validateManifest(generic.NewMap(data))
+	return v.errs
+}
+
+type validator struct {
+	file      string
+	lines     []string
+	locations map[string]location
+	errs      ValidationErrors
+}
+
+func (v *validator) report(path string, warning bool, message string) {
+	err := ValidationError{File: v.file, Message: message, Warning: warning}
+	if loc, found := findLocation(v.locations, path); found {
+		err.Line = loc.line
+		err.Column = loc.column
+	}
+	v.errs = append(v.errs, err)
+}
+
+func (v *validator) validateManifest(data generic.Map) {
+	v.validateProperties("", data.Except([]interface{}{"applications", "inherit"}))
+
+	if data.Has("inherit") {
+		if _, ok := data.Get("inherit").(string); !ok {
+			v.report("inherit", false, T("inherit must be a string value"))
+		}
+	}
+
+	if !data.Has("applications") {
+		return
+	}
+
+	apps, ok := data.Get("applications").([]interface{})
+	if !ok {
+		v.report("applications", false, T("Expected applications to be a list"))
+		return
+	}
+
+	names := map[string]bool{}
+	for index, app := range apps {
+		path := fmt.Sprintf("applications[%d]", index)
+		if !generic.IsMappable(app) {
+			v.report(path, false, T("Expected application to be a list of key/value pairs"))
+			continue
+		}
+
+		appMap := generic.NewMap(app)
+		v.validateProperties(path, appMap)
+
+		if name, ok := appMap.Get("name").(string); ok {
+			if names[name] {
+				v.report(path+".name", false, T("Application '{{.Name}}' is defined more than once", map[string]interface{}{"Name": name}))
+			}
+			names[name] = true
+		}
+	}
+}
+
+func (v *validator) validateProperties(parent string, data generic.Map) {
+	var keys []string
+	generic.Each(data, func(key, _ interface{}) {
+		keys = append(keys, coerceToString(key))
+	})
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := key
+		if parent != "" {
+			path = parent + "." + key
+		}
+		value := data.Get(key)
+
+		if replacement, found := deprecatedProperties[key]; found {
+			v.report(path, true, T("'{{.PropertyName}}' is deprecated and will be ignored. Use {{.Replacement}} instead.",
+				map[string]interface{}{"PropertyName": key, "Replacement": replacement}))
+			continue
+		}
+
+		expectedType, found := appProperties[key]
+		if !found {
+			if parent == "" && v.definesAnchors(key) {
+				continue
+			}
+			v.report(path, true, T("Unknown property '{{.PropertyName}}' will be ignored", map[string]interface{}{"PropertyName": key}))
+			continue
+		}
+
+		if message := v.validateValue(path, key, expectedType, value); message != "" {
+			v.report(path, false, message)
+		}
+
+		if key != "routes" && data.Has("routes") && isRouteProperty(key) {
+			v.report(path, false, T("{{.PropertyName}} cannot be used together with routes", map[string]interface{}{"PropertyName": key}))
+		}
+	}
+}
+
+// definesAnchors tells whether the top-level key holds YAML anchors, such as
+// a block of settings that applications merge in with <<: *ANCHOR.
+func (v *validator) definesAnchors(key string) bool {
+	loc, found := v.locations[key]
+	if !found {
+		return false
+	}
+
+	for index := loc.line - 1; index < len(v.lines); index++ {
+		line := v.lines[index]
+		content := strings.TrimSpace(line)
+		if index >= loc.line && content != "" && !strings.HasPrefix(content, "#") && !strings.HasPrefix(line, " ") {
+			break
+		}
+		if yamlAnchorRegex.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRouteProperty(key string) bool {
+	for _, group := range replacedPropertyGroups {
+		for _, property := range group {
+			if property == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *validator) validateValue(path, key string, expectedType propertyType, value interface{}) string {
+	if value == nil {
+		if expectedType == stringOrNullProperty {
+			return ""
+		}
+		return T("{{.PropertyName}} should not be null", map[string]interface{}{"PropertyName": key})
+	}
+
+	if str, ok := value.(string); ok {
+		if variableRegex.MatchString(str) {
+			return ""
+		}
+		if match := propertyRegex.FindString(str); match != "" && match != "${random-word}" {
+			return T("Property '{{.PropertyName}}' found in manifest. This feature is no longer supported. Please remove it and try again.",
+				map[string]interface{}{"PropertyName": match})
+		}
+	}
+
+	switch expectedType {
+	case stringProperty, stringOrNullProperty:
+		if _, ok := value.(string); !ok {
+			return T("{{.PropertyName}} must be a string value", map[string]interface{}{"PropertyName": key})
+		}
+	case bytesProperty:
+		if _, err := formatters.ToMegabytes(coerceToString(value)); err != nil {
+			return T("Invalid value for '{{.PropertyName}}': {{.StringVal}}\n{{.Error}}",
+				map[string]interface{}{"PropertyName": key, "StringVal": coerceToString(value), "Error": err.Error()})
+		}
+	case intProperty:
+		switch value := value.(type) {
+		case int, int64:
+		case string:
+			if _, err := strconv.Atoi(value); err != nil {
+				return T("Expected {{.PropertyName}} to be a number, but it was a {{.PropertyType}}.",
+					map[string]interface{}{"PropertyName": key, "PropertyType": value})
+			}
+		default:
+			return T("Expected {{.PropertyName}} to be a number, but it was a {{.PropertyType}}.",
+				map[string]interface{}{"PropertyName": key, "PropertyType": value})
+		}
+	case boolProperty:
+		switch value := value.(type) {
+		case bool:
+		case string:
+			if value != "true" && value != "false" {
+				return T("Expected {{.PropertyName}} to be a boolean.", map[string]interface{}{"PropertyName": key})
+			}
+		default:
+			return T("Expected {{.PropertyName}} to be a boolean.", map[string]interface{}{"PropertyName": key})
+		}
+	case stringListProperty:
+		values, ok := value.([]interface{})
+		if !ok {
+			return T("Expected {{.PropertyName}} to be a list of strings.", map[string]interface{}{"PropertyName": key})
+		}
+		for index, item := range values {
+			if _, ok := item.(string); !ok {
+				v.report(fmt.Sprintf("%s[%d]", path, index), false,
+					T("Expected {{.PropertyName}} to be a list of strings.", map[string]interface{}{"PropertyName": key}))
+			}
+		}
+	case intListProperty:
+		values, ok := value.([]interface{})
+		if !ok {
+			return T("Expected {{.PropertyName}} to be a list of integers.", map[string]interface{}{"PropertyName": key})
+		}
+		for index, item := range values {
+			if _, ok := item.(int); !ok {
+				v.report(fmt.Sprintf("%s[%d]", path, index), false,
+					T("Expected {{.PropertyName}} to be a list of integers.", map[string]interface{}{"PropertyName": key}))
+			}
+		}
+	case routeListProperty:
+		values, ok := value.([]interface{})
+		if !ok {
+			return T("Expected {{.PropertyName}} to be a list of routes, such as '- route: host.example.com/path'.", map[string]interface{}{"PropertyName": key})
+		}
+		for index, item := range values {
+			itemPath := fmt.Sprintf("%s[%d]", path, index)
+			if !generic.IsMappable(item) {
+				v.report(itemPath, false,
+					T("Expected {{.PropertyName}} to be a list of routes, such as '- route: host.example.com/path'.", map[string]interface{}{"PropertyName": key}))
+				continue
+			}
+			generic.Each(generic.NewMap(item), func(name, routeValue interface{}) {
+				if coerceToString(name) != "route" {
+					v.report(itemPath+"."+coerceToString(name), false,
+						T("Unknown property '{{.PropertyName}}'", map[string]interface{}{"PropertyName": name}))
+				} else if _, ok := routeValue.(string); !ok {
+					v.report(itemPath+".route", false, T("{{.PropertyName}} must be a string value", map[string]interface{}{"PropertyName": "route"}))
+				}
+			})
+		}
+	case mapProperty:
+		if !generic.IsMappable(value) {
+			return T("Expected {{.Name}} to be a set of key => value, but it was a {{.Type}}.",
+				map[string]interface{}{"Name": key, "Type": value})
+		}
+		generic.Each(generic.NewMap(value), func(name, envValue interface{}) {
+			if envValue == nil {
+				v.report(path+"."+coerceToString(name), false,
+					T("env var '{{.PropertyName}}' should not be null", map[string]interface{}{"PropertyName": name}))
+			}
+		})
+	}
+
+	return ""
+}
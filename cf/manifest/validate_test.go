@@ -0,0 +1,137 @@
+package manifest_test
+
+import (
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/generic"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	Context("when the manifest was read from disk", func() {
+		var (
+			path string
+			errs ValidationErrors
+		)
+
+		BeforeEach(func() {
+			path = filepath.Clean("../../fixtures/manifests/invalid/manifest.yml")
+			m, err := NewManifestDiskRepository().ReadManifest(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			errs = m.Validate()
+		})
+
+		It("reports each problem with its line and column", func() {
+			messages := []string{}
+			for _, err := range errs.Errors() {
+				messages = append(messages, err.Error())
+			}
+
+			Expect(messages).To(ConsistOf(
+				path+":5:3: Expected instances to be a number, but it was a lots.",
+				HavePrefix(path+":6:3: Invalid value for 'disk_quota': 1 banana"),
+				path+":10:3: Expected services to be a list of strings.",
+				path+":14:5: env var 'TOKEN' should not be null",
+				path+":15:3: Application 'web' is defined more than once",
+				path+":16:3: Expected no-route to be a boolean.",
+			))
+		})
+
+		It("reports unknown and deprecated properties as warnings", func() {
+			warnings := errs.Warnings()
+			Expect(warnings).To(HaveLen(2))
+			Expect(warnings[0].Error()).To(Equal(path + ":2:1: Unknown property 'memroy' will be ignored"))
+			Expect(warnings[1].Line).To(Equal(7))
+			Expect(warnings[1].Column).To(Equal(3))
+			Expect(warnings[1].Message).To(ContainSubstring("'framework' is deprecated"))
+		})
+
+		It("ignores top-level properties that only hold YAML anchors", func() {
+			m, err := NewManifestDiskRepository().ReadManifest("../../fixtures/manifests/merge-manifest.yml")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Validate()).To(BeEmpty())
+		})
+
+		It("validates the manifests it inherits", func() {
+			m, err := NewManifestDiskRepository().ReadManifest("../../fixtures/manifests/inherited-manifest.yml")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Validate()).To(BeEmpty())
+		})
+	})
+
+	Context("when the manifest was not read from disk", func() {
+		It("reports problems without a location", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name":   "web",
+						"memory": 256,
+						"hosts":  "not-a-list",
+					},
+				},
+			}))
+
+			errs := m.Validate()
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Error()).To(Equal("Expected hosts to be a list of strings."))
+			Expect(errs[1].Error()).To(HavePrefix("Invalid value for 'memory': 256"))
+		})
+
+		It("accepts ((var)) variables and nulls for command and buildpack", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name":      "web",
+						"instances": "((instances))",
+						"command":   nil,
+						"buildpack": nil,
+					},
+				},
+			}))
+
+			Expect(m.Validate()).To(BeEmpty())
+		})
+
+		It("rejects old-style properties", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"host": "${app-name}",
+			}))
+
+			errs := m.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Message).To(ContainSubstring("'${app-name}'"))
+		})
+
+		It("accepts routes and the docker image", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"docker-image": "user/my-image",
+				"routes": []interface{}{
+					map[interface{}]interface{}{"route": "my-app.example.com/api"},
+				},
+			}))
+
+			Expect(m.Validate()).To(BeEmpty())
+		})
+
+		It("rejects malformed routes and routes combined with hosts", func() {
+			m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+				"host": "my-app",
+				"routes": []interface{}{
+					"my-app.example.com",
+					map[interface{}]interface{}{"url": "my-app.example.com"},
+				},
+			}))
+
+			errs := m.Validate()
+			Expect(errs).To(HaveLen(3))
+			Expect(errs[0].Message).To(Equal("host cannot be used together with routes"))
+			Expect(errs[1].Message).To(ContainSubstring("Expected routes to be a list of routes"))
+			Expect(errs[2].Message).To(Equal("Unknown property 'url'"))
+		})
+	})
+})
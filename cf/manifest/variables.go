@@ -0,0 +1,251 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/generic"
+	"gopkg.in/yaml.v2"
+)
+
+var variableRegex = regexp.MustCompile(`\(\(([\w.-]+)\)\)`)
+
+type manifestSource struct {
+	path  string
+	lines []string
+}
+
+// ReadVariables loads the variables used to interpolate ((var)) references in
+// a manifest. Each vars file must be a YAML map; later files override earlier
+// ones, and NAME=VALUE pairs given with --var override all of them.
+func ReadVariables(varsFiles []string, vars []string) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+
+	for _, path := range varsFiles {
+		bytes, err := ioutil.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf(T("Error reading vars file {{.Path}}: {{.Error}}",
+				map[string]interface{}{"Path": path, "Error": err.Error()}))
+		}
+
+		fileVars := map[string]interface{}{}
+		err = yaml.Unmarshal(bytes, &fileVars)
+		if err != nil {
+			return nil, fmt.Errorf(T("Error reading vars file {{.Path}}: {{.Error}}",
+				map[string]interface{}{"Path": path, "Error": err.Error()}))
+		}
+
+		for name, value := range fileVars {
+			variables[name] = value
+		}
+	}
+
+	for _, pair := range vars {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf(T("Invalid variable '{{.Variable}}'. Expected NAME=VALUE",
+				map[string]interface{}{"Variable": pair}))
+		}
+
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(parts[1]), &value); err != nil || value == nil {
+			value = parts[1]
+		}
+		variables[parts[0]] = value
+	}
+
+	return variables, nil
+}
+
+// VariableReference records a manifest property whose value was interpolated
+// from ((var)) references: Template is the value as written in the manifest
+// and Value is what it was interpolated to.
+type VariableReference struct {
+	Property string
+	Template string
+	Value    interface{}
+}
+
+// VariableReferences returns the properties of the manifest that its
+// variables are interpolated into. Properties are named by their keys joined
+// with dots, leaving out list indices and the applications key, so that
+// inherited and per-app properties share a name.
+func (m Manifest) VariableReferences() ([]VariableReference, error) {
+	references := []VariableReference{}
+	_, err := m.interpolateVariables(m.Data, &references)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(byProperty(references))
+	return references, nil
+}
+
+func (m Manifest) interpolateVariables(data generic.Map, references *[]VariableReference) (generic.Map, error) {
+	unresolved := map[string]bool{}
+	output := interpolate(data, "", m.Variables, unresolved, references)
+
+	if len(unresolved) > 0 {
+		var names []string
+		for name := range unresolved {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		message := T("Unresolved variables found in manifest:")
+		for _, name := range names {
+			message += "\n  ((" + name + "))"
+			if location := m.locateVariable(name); location != "" {
+				message += " " + T("at {{.Location}}", map[string]interface{}{"Location": location})
+			}
+		}
+		return nil, errors.New(message)
+	}
+
+	return output.(generic.Map), nil
+}
+
+func interpolate(input interface{}, property string, variables map[string]interface{}, unresolved map[string]bool, references *[]VariableReference) interface{} {
+	switch input := input.(type) {
+	case string:
+		if !variableRegex.MatchString(input) {
+			return input
+		}
+
+		output := interpolateString(input, variables, unresolved)
+		if references != nil {
+			*references = append(*references, VariableReference{Property: property, Template: input, Value: output})
+		}
+		return output
+	case []interface{}:
+		outputSlice := make([]interface{}, len(input))
+		for index, item := range input {
+			outputSlice[index] = interpolate(item, property, variables, unresolved, references)
+		}
+		return outputSlice
+	case map[interface{}]interface{}:
+		outputMap := make(map[interface{}]interface{})
+		for key, value := range input {
+			outputMap[key] = interpolate(value, childProperty(property, key), variables, unresolved, references)
+		}
+		return outputMap
+	case generic.Map:
+		outputMap := generic.NewMap()
+		generic.Each(input, func(key, value interface{}) {
+			outputMap.Set(key, interpolate(value, childProperty(property, key), variables, unresolved, references))
+		})
+		return outputMap
+	default:
+		return input
+	}
+}
+
+func interpolateString(input string, variables map[string]interface{}, unresolved map[string]bool) interface{} {
+	match := variableRegex.FindStringSubmatch(input)
+	if match != nil && match[0] == input {
+		value, ok := variables[match[1]]
+		if !ok {
+			unresolved[match[1]] = true
+			return input
+		}
+		return value
+	}
+
+	return variableRegex.ReplaceAllStringFunc(input, func(reference string) string {
+		name := variableRegex.FindStringSubmatch(reference)[1]
+		value, ok := variables[name]
+		if !ok {
+			unresolved[name] = true
+			return reference
+		}
+		return coerceToString(value)
+	})
+}
+
+func childProperty(property string, key interface{}) string {
+	name := fmt.Sprint(key)
+	if property == "" {
+		if name == "applications" {
+			return ""
+		}
+		return name
+	}
+	return property + "." + name
+}
+
+type byProperty []VariableReference
+
+func (r byProperty) Len() int      { return len(r) }
+func (r byProperty) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byProperty) Less(i, j int) bool {
+	if r[i].Property != r[j].Property {
+		return r[i].Property < r[j].Property
+	}
+	return r[i].Template < r[j].Template
+}
+
+func (m Manifest) locateVariable(name string) string {
+	reference := "((" + name + "))"
+	for _, source := range m.sources {
+		for index, line := range source.lines {
+			if strings.Contains(line, reference) {
+				return fmt.Sprintf("%s:%d", source.path, index+1)
+			}
+		}
+	}
+	return ""
+}
+
+func parameterizeYAML(contents []byte, references []VariableReference) ([]byte, error) {
+	var document yaml.MapSlice
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(parameterize(document, "", references))
+}
+
+// parameterize is the inverse of interpolate: the value of a property that a
+// variable was interpolated into is replaced by the template it was
+// interpolated from, when it still has the interpolated value, so that a
+// generated manifest can be pushed again with a different set of variables.
+// Other values are left alone, even if they happen to equal a variable.
+func parameterize(input interface{}, property string, references []VariableReference) interface{} {
+	switch input := input.(type) {
+	case []interface{}:
+		outputSlice := make([]interface{}, len(input))
+		for index, item := range input {
+			outputSlice[index] = parameterize(item, property, references)
+		}
+		return outputSlice
+	case map[interface{}]interface{}:
+		outputMap := make(map[interface{}]interface{})
+		for key, value := range input {
+			outputMap[key] = parameterize(value, childProperty(property, key), references)
+		}
+		return outputMap
+	case yaml.MapSlice:
+		outputSlice := make(yaml.MapSlice, len(input))
+		for index, item := range input {
+			outputSlice[index] = yaml.MapItem{Key: item.Key, Value: parameterize(item.Value, childProperty(property, item.Key), references)}
+		}
+		return outputSlice
+	case nil:
+		return input
+	default:
+		value := coerceToString(input)
+		for _, reference := range references {
+			if reference.Property == property && value != "" && coerceToString(reference.Value) == value {
+				return reference.Template
+			}
+		}
+		return input
+	}
+}
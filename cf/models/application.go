@@ -77,6 +77,7 @@ type AppParams struct {
 	BuildpackURL       *string
 	Command            *string
 	DiskQuota          *int64
+	DependsOn          *[]string
 	Domains            *[]string
 	EnvironmentVars    *map[string]interface{}
 	GUID               *string
@@ -87,6 +88,7 @@ type AppParams struct {
 	EnableSSH          *bool
 	Hosts              *[]string
 	RoutePath          *string
+	Routes             *[]string
 	InstanceCount      *int
 	Memory             *int64
 	Name               *string
@@ -117,6 +119,9 @@ func (app *AppParams) Merge(other *AppParams) {
 	if other.DiskQuota != nil {
 		app.DiskQuota = other.DiskQuota
 	}
+	if other.DependsOn != nil {
+		app.DependsOn = other.DependsOn
+	}
 	if other.DockerImage != nil {
 		app.DockerImage = other.DockerImage
 	}
@@ -156,6 +161,12 @@ func (app *AppParams) Merge(other *AppParams) {
 	if other.RoutePath != nil {
 		app.RoutePath = other.RoutePath
 	}
+	if other.Hosts != nil || other.Domains != nil || other.RoutePath != nil || other.NoHostname || other.UseRandomRoute {
+		app.Routes = nil
+	}
+	if other.Routes != nil {
+		app.Routes = other.Routes
+	}
 	if other.ServicesToBind != nil {
 		app.ServicesToBind = other.ServicesToBind
 	}
@@ -13,6 +13,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf"
@@ -27,6 +28,7 @@ const (
 	JOB_FINISHED             = "finished"
 	JOB_FAILED               = "failed"
 	DEFAULT_POLLING_THROTTLE = 5 * time.Second
+	DEFAULT_PAGE_WORKERS     = 5
 )
 
 type JobResource struct {
@@ -60,10 +62,13 @@ type Gateway struct {
 	errHandler      apiErrorHandler
 	PollingEnabled  bool
 	PollingThrottle time.Duration
+	PageWorkers     int
 	trustedCerts    []tls.Certificate
 	config          coreconfig.Reader
 	warnings        *[]string
+	warningsMutex   *sync.Mutex
 	Clock           func() time.Time
+	Sleep           func(time.Duration)
 	transport       *http.Transport
 	ui              terminal.UI
 	logger          trace.Printer
@@ -74,8 +79,11 @@ func newGateway(errHandler apiErrorHandler, config coreconfig.Reader, ui termina
 		errHandler:      errHandler,
 		config:          config,
 		PollingThrottle: DEFAULT_POLLING_THROTTLE,
+		PageWorkers:     DEFAULT_PAGE_WORKERS,
 		warnings:        &[]string{},
+		warningsMutex:   new(sync.Mutex),
 		Clock:           time.Now,
+		Sleep:           time.Sleep,
 		ui:              ui,
 		logger:          logger,
 	}
@@ -148,11 +156,9 @@ func (gateway Gateway) ListPaginatedResources(
 	cb func(interface{}) bool,
 ) error {
 	for path != "" {
-		pagination := NewPaginatedResources(resource)
-
-		apiErr := gateway.GetResource(fmt.Sprintf("%s%s", target, path), &pagination)
-		if apiErr != nil {
-			return apiErr
+		pagination, err := gateway.getPage(target, path, resource)
+		if err != nil {
+			return err
 		}
 
 		resources, err := pagination.Resources()
@@ -166,6 +172,11 @@ func (gateway Gateway) ListPaginatedResources(
 			}
 		}
 
+		// once the number of pages is known, the rest are fetched together
+		if paths := pagination.remainingPagePaths(); len(paths) > 1 {
+			return gateway.listPagesConcurrently(target, paths, resource, cb)
+		}
+
 		path = pagination.NextURL
 	}
 
@@ -332,6 +343,9 @@ func (gateway Gateway) PerformPollingRequestForJSONResponse(endpoint string, req
 }
 
 func (gateway Gateway) Warnings() []string {
+	gateway.warningsMutex.Lock()
+	defer gateway.warningsMutex.Unlock()
+
 	return *gateway.warnings
 }
 
@@ -401,7 +415,7 @@ func (gateway Gateway) doRequestHandlingAuth(request *Request) (rawResponse *htt
 }
 
 func (gateway Gateway) doRequestAndHandlerError(request *Request) (rawResponse *http.Response, err error) {
-	rawResponse, err = gateway.doRequest(request.HTTPReq)
+	rawResponse, err = gateway.doRequestWithRetries(request)
 	if err != nil {
 		err = WrapNetworkErrors(request.HTTPReq.URL.Host, err)
 		return
@@ -417,24 +431,58 @@ func (gateway Gateway) doRequestAndHandlerError(request *Request) (rawResponse *
 	return
 }
 
-func (gateway Gateway) doRequest(request *http.Request) (response *http.Response, err error) {
+func (gateway Gateway) doRequestWithRetries(request *Request) (response *http.Response, err error) {
 	if gateway.transport == nil {
-		makeHTTPTransport(&gateway)
+		err = makeHTTPTransport(&gateway)
+		if err != nil {
+			return
+		}
 	}
 
-	httpClient := NewHTTPClient(gateway.transport, NewRequestDumper(gateway.logger))
+	policy := NewRetryPolicy(gateway.config.Retries(), gateway.config.RetryMaxWait())
 
-	httpClient.DumpRequest(request)
+	for attempt := 0; ; attempt++ {
+		response, err = gateway.doRequest(request.HTTPReq)
+
+		delay, retry := policy.ShouldRetry(request, response, err, attempt)
+		if !retry {
+			return
+		}
+
+		if request.SeekableBody != nil {
+			if _, seekErr := request.SeekableBody.Seek(0, 0); seekErr != nil {
+				return
+			}
+			request.HTTPReq.Body = ioutil.NopCloser(request.SeekableBody)
+		}
 
-	for i := 0; i < 3; i++ {
-		response, err = httpClient.Do(request)
-		if response == nil && err != nil {
-			continue
-		} else {
-			break
+		reason := fmt.Sprint(err)
+		if err == nil {
+			reason = response.Status
+			response.Body.Close()
 		}
+
+		gateway.logger.Printf("\n%s %s\n", terminal.HeaderColor(T("RETRYING:")),
+			T("{{.Method}} {{.URL}} in {{.Delay}} (retry {{.Retry}} of {{.MaxRetries}}) after: {{.Reason}}",
+				map[string]interface{}{
+					"Method":     request.HTTPReq.Method,
+					"URL":        request.HTTPReq.URL,
+					"Delay":      delay,
+					"Retry":      attempt + 1,
+					"MaxRetries": policy.MaxRetries,
+					"Reason":     reason,
+				}))
+
+		gateway.Sleep(delay)
 	}
+}
+
+func (gateway Gateway) doRequest(request *http.Request) (response *http.Response, err error) {
+	httpClient := NewHTTPClient(gateway.transport, NewRequestDumper(gateway.logger))
 
+	httpClient.DumpRequest(request)
+
+	response, err = httpClient.Do(request)
 	if err != nil {
 		return
 	}
@@ -443,23 +491,34 @@ func (gateway Gateway) doRequest(request *http.Request) (response *http.Response
 
 	header := http.CanonicalHeaderKey("X-Cf-Warnings")
 	raw_warnings := response.Header[header]
+	gateway.warningsMutex.Lock()
 	for _, raw_warning := range raw_warnings {
 		warning, _ := url.QueryUnescape(raw_warning)
 		*gateway.warnings = append(*gateway.warnings, warning)
 	}
+	gateway.warningsMutex.Unlock()
 
 	return
 }
 
-func makeHTTPTransport(gateway *Gateway) {
+func makeHTTPTransport(gateway *Gateway) error {
+	tlsConfig, err := NewTLSConfigForConfig(gateway.trustedCerts, gateway.config)
+	if err != nil {
+		return err
+	}
+
 	gateway.transport = &http.Transport{
 		Dial:            (&net.Dialer{Timeout: 5 * time.Second}).Dial,
-		TLSClientConfig: NewTLSConfig(gateway.trustedCerts, gateway.config.IsSSLDisabled()),
+		TLSClientConfig: tlsConfig,
 		Proxy:           http.ProxyFromEnvironment,
 	}
+	return nil
 }
 
 func (gateway *Gateway) SetTrustedCerts(certificates []tls.Certificate) {
 	gateway.trustedCerts = certificates
+
+	// a bad certificate file is reported by the first request instead
+	gateway.transport = nil
 	makeHTTPTransport(gateway)
 }
@@ -2,12 +2,15 @@ package net_test
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
@@ -67,6 +70,7 @@ var _ = Describe("Gateway", func() {
 
 		BeforeEach(func() {
 			client = new(netfakes.FakeHTTPClientInterface)
+			ccGateway.Sleep = func(time.Duration) {}
 
 			oldNewHTTPClient = NewHTTPClient
 			NewHTTPClient = func(tr *http.Transport, dumper RequestDumper) HTTPClientInterface {
@@ -99,6 +103,151 @@ var _ = Describe("Gateway", func() {
 		})
 	})
 
+	Describe("retrying transient failures", func() {
+		var (
+			printer *tracefakes.FakePrinter
+			sleeps  []time.Duration
+		)
+
+		BeforeEach(func() {
+			ccServer = ghttp.NewServer()
+			config.SetAPIEndpoint(ccServer.URL())
+
+			printer = new(tracefakes.FakePrinter)
+			ccGateway = NewCloudControllerGateway(config, clock, &testterm.FakeUI{}, printer)
+			sleeps = []time.Duration{}
+			ccGateway.Sleep = func(delay time.Duration) {
+				sleeps = append(sleeps, delay)
+			}
+		})
+
+		AfterEach(func() {
+			ccServer.Close()
+			os.Unsetenv("CF_RETRIES")
+		})
+
+		It("retries idempotent requests that fail with a 502, 503 or 429 and backs off between retries", func() {
+			ccServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, `{}`),
+				ghttp.RespondWith(http.StatusBadGateway, `{}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			)
+
+			request, err := ccGateway.NewRequest("GET", ccServer.URL()+"/v2/apps", "BEARER my-access-token", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ccGateway.PerformRequest(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ccServer.ReceivedRequests()).To(HaveLen(3))
+
+			Expect(sleeps).To(HaveLen(2))
+			Expect(sleeps[0]).To(BeNumerically(">=", 500*time.Millisecond))
+			Expect(sleeps[0]).To(BeNumerically("<=", time.Second))
+			Expect(sleeps[1]).To(BeNumerically(">=", time.Second))
+			Expect(sleeps[1]).To(BeNumerically("<=", 2*time.Second))
+		})
+
+		It("gives up after the configured number of retries", func() {
+			config.SetRetries(1)
+			ccServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, `{}`),
+				ghttp.RespondWith(http.StatusServiceUnavailable, `{}`),
+			)
+
+			request, _ := ccGateway.NewRequest("GET", ccServer.URL()+"/v2/apps", "BEARER my-access-token", nil)
+			_, err := ccGateway.PerformRequest(request)
+			Expect(err).To(HaveOccurred())
+			Expect(ccServer.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("takes the number of retries from CF_RETRIES over the config", func() {
+			config.SetRetries(3)
+			os.Setenv("CF_RETRIES", "0")
+			ccServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, `{}`))
+
+			request, _ := ccGateway.NewRequest("GET", ccServer.URL()+"/v2/apps", "BEARER my-access-token", nil)
+			_, err := ccGateway.PerformRequest(request)
+			Expect(err).To(HaveOccurred())
+			Expect(ccServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("waits for as long as Retry-After asks", func() {
+			ccServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, `{}`, http.Header{"Retry-After": []string{"7"}}),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			)
+
+			request, _ := ccGateway.NewRequest("GET", ccServer.URL()+"/v2/apps", "BEARER my-access-token", nil)
+			_, err := ccGateway.PerformRequest(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sleeps).To(Equal([]time.Duration{7 * time.Second}))
+		})
+
+		It("does not retry when Retry-After is longer than the maximum wait", func() {
+			config.SetRetryMaxWait(5)
+			ccServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, `{}`, http.Header{"Retry-After": []string{"7"}}),
+			)
+
+			request, _ := ccGateway.NewRequest("GET", ccServer.URL()+"/v2/apps", "BEARER my-access-token", nil)
+			_, err := ccGateway.PerformRequest(request)
+			Expect(err).To(HaveOccurred())
+			Expect(ccServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("does not retry requests that are not idempotent", func() {
+			ccServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, `{}`))
+
+			request, _ := ccGateway.NewRequest("POST", ccServer.URL()+"/v2/apps", "BEARER my-access-token", strings.NewReader(`{"name":"app"}`))
+			_, err := ccGateway.PerformRequest(request)
+			Expect(err).To(HaveOccurred())
+			Expect(ccServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("sends the whole body again when retrying an upload", func() {
+			fileToUpload, err := ioutil.TempFile("", "test-gateway")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(fileToUpload.Name())
+			defer fileToUpload.Close()
+			strings.NewReader("expected body").WriteTo(fileToUpload)
+
+			var bodies []string
+			recordBody := func(writer http.ResponseWriter, request *http.Request) {
+				body, _ := ioutil.ReadAll(request.Body)
+				bodies = append(bodies, string(body))
+			}
+			ccServer.AppendHandlers(
+				ghttp.CombineHandlers(recordBody, ghttp.RespondWith(http.StatusBadGateway, `{}`)),
+				ghttp.CombineHandlers(recordBody, ghttp.RespondWith(http.StatusCreated, `{}`)),
+			)
+
+			request, err := ccGateway.NewRequestForFile("PUT", ccServer.URL()+"/v2/apps/app-guid/bits", "BEARER my-access-token", fileToUpload)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ccGateway.PerformRequest(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bodies).To(Equal([]string{"expected body", "expected body"}))
+		})
+
+		It("shows each retry in the trace output", func() {
+			ccServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, `{}`, http.Header{"Retry-After": []string{"3"}}),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			)
+
+			request, _ := ccGateway.NewRequest("GET", ccServer.URL()+"/v2/apps", "BEARER my-access-token", nil)
+			_, err := ccGateway.PerformRequest(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			var traced []string
+			for i := 0; i < printer.PrintfCallCount(); i++ {
+				format, args := printer.PrintfArgsForCall(i)
+				traced = append(traced, fmt.Sprintf(format, args...))
+			}
+			Expect(strings.Join(traced, "")).To(ContainSubstring("GET " + ccServer.URL() + "/v2/apps in 3s (retry 1 of 2) after: 503 Service Unavailable"))
+		})
+	})
+
 	Describe("NewRequest", func() {
 		var (
 			request *Request
@@ -584,6 +733,69 @@ var _ = Describe("Gateway", func() {
 
 	})
 
+	Describe("CA certificate and client certificate files", func() {
+		var (
+			apiServer      *httptest.Server
+			certDir        string
+			peerCertCounts []int
+		)
+
+		BeforeEach(func() {
+			peerCertCounts = []int{}
+			apiServer = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				peerCertCounts = append(peerCertCounts, len(r.TLS.PeerCertificates))
+				fmt.Fprintln(w, `{}`)
+			}))
+			apiServer.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+			apiServer.StartTLS()
+
+			var err error
+			certDir, err = ioutil.TempDir("", "gateway-certs")
+			Expect(err).NotTo(HaveOccurred())
+
+			serverCert := apiServer.TLS.Certificates[0]
+			writePEM(filepath.Join(certDir, "ca.pem"), "CERTIFICATE", serverCert.Certificate[0])
+			writePEM(filepath.Join(certDir, "client.pem"), "CERTIFICATE", serverCert.Certificate[0])
+			key, err := x509.MarshalPKCS8PrivateKey(serverCert.PrivateKey)
+			Expect(err).NotTo(HaveOccurred())
+			writePEM(filepath.Join(certDir, "client-key.pem"), "PRIVATE KEY", key)
+		})
+
+		AfterEach(func() {
+			apiServer.Close()
+			os.RemoveAll(certDir)
+		})
+
+		It("trusts the certificates in the CA certificate file", func() {
+			config.SetCACertFile(filepath.Join(certDir, "ca.pem"))
+
+			request, _ := ccGateway.NewRequest("GET", apiServer.URL+"/v2/foo", "the-access-token", nil)
+			_, apiErr := ccGateway.PerformRequest(request)
+			Expect(apiErr).NotTo(HaveOccurred())
+			Expect(peerCertCounts).To(Equal([]int{0}))
+		})
+
+		It("presents the client certificate", func() {
+			config.SetCACertFile(filepath.Join(certDir, "ca.pem"))
+			config.SetClientCertificate(filepath.Join(certDir, "client.pem"), filepath.Join(certDir, "client-key.pem"))
+
+			request, _ := ccGateway.NewRequest("GET", apiServer.URL+"/v2/foo", "the-access-token", nil)
+			_, apiErr := ccGateway.PerformRequest(request)
+			Expect(apiErr).NotTo(HaveOccurred())
+			Expect(peerCertCounts).To(Equal([]int{1}))
+		})
+
+		It("fails without contacting the server when the CA certificate file cannot be read", func() {
+			config.SetCACertFile(filepath.Join(certDir, "missing.pem"))
+
+			request, _ := ccGateway.NewRequest("GET", apiServer.URL+"/v2/foo", "the-access-token", nil)
+			_, apiErr := ccGateway.PerformRequest(request)
+			Expect(apiErr).To(HaveOccurred())
+			Expect(apiErr.Error()).To(ContainSubstring("Could not read CA certificate file"))
+			Expect(peerCertCounts).To(BeEmpty())
+		})
+	})
+
 	Describe("collecting warnings", func() {
 		var (
 			apiServer  *httptest.Server
@@ -640,6 +852,11 @@ var _ = Describe("Gateway", func() {
 	})
 })
 
+func writePEM(path string, blockType string, bytes []byte) {
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes}), 0600)
+	Expect(err).NotTo(HaveOccurred())
+}
+
 func getHost(urlString string) string {
 	url, err := url.Parse(urlString)
 	if err != nil {
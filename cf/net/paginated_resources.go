@@ -2,7 +2,12 @@ package net
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/url"
 	"reflect"
+	"strconv"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
 )
 
 func NewPaginatedResources(exampleResource interface{}) PaginatedResources {
@@ -13,6 +18,7 @@ func NewPaginatedResources(exampleResource interface{}) PaginatedResources {
 
 type PaginatedResources struct {
 	NextURL        string          `json:"next_url"`
+	TotalPages     int             `json:"total_pages"`
 	ResourcesBytes json.RawMessage `json:"resources"`
 	resourceType   reflect.Type
 }
@@ -28,3 +34,108 @@ func (pr PaginatedResources) Resources() ([]interface{}, error) {
 	}
 	return contents, err
 }
+
+// remainingPagePaths returns the paths of the next page and every page after
+// it, built from next_url and total_pages. It returns nil when next_url does
+// not say which page it is for.
+func (pr PaginatedResources) remainingPagePaths() []string {
+	if pr.NextURL == "" {
+		return nil
+	}
+
+	nextURL, err := url.Parse(pr.NextURL)
+	if err != nil {
+		return nil
+	}
+
+	query := nextURL.Query()
+	nextPage, err := strconv.Atoi(query.Get("page"))
+	if err != nil || nextPage < 1 || nextPage > pr.TotalPages {
+		return nil
+	}
+
+	var paths []string
+	for page := nextPage; page <= pr.TotalPages; page++ {
+		query.Set("page", strconv.Itoa(page))
+		nextURL.RawQuery = query.Encode()
+		paths = append(paths, nextURL.String())
+	}
+	return paths
+}
+
+type pageResult struct {
+	resources []interface{}
+	err       error
+}
+
+func (gateway Gateway) getPage(target, path string, resource interface{}) (PaginatedResources, error) {
+	pagination := NewPaginatedResources(resource)
+	err := gateway.GetResource(fmt.Sprintf("%s%s", target, path), &pagination)
+	return pagination, err
+}
+
+func (gateway Gateway) listPage(target, path string, resource interface{}) ([]interface{}, error) {
+	pagination, err := gateway.getPage(target, path, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := pagination.Resources()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", T("Error parsing JSON"), err.Error())
+	}
+	return resources, nil
+}
+
+// listPagesConcurrently fetches the pages with up to PageWorkers requests at a
+// time, and calls cb for their resources in order, on the calling goroutine,
+// until it returns false or a page fails. No new pages are fetched after that.
+func (gateway Gateway) listPagesConcurrently(target string, paths []string, resource interface{}, cb func(interface{}) bool) error {
+	results := make([]chan pageResult, len(paths))
+	for i := range results {
+		results[i] = make(chan pageResult, 1)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	pages := make(chan int)
+	go func() {
+		defer close(pages)
+		for i := range paths {
+			select {
+			case pages <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	workers := gateway.PageWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for page := range pages {
+				resources, err := gateway.listPage(target, paths[page], resource)
+				results[page] <- pageResult{resources: resources, err: err}
+			}
+		}()
+	}
+
+	for _, result := range results {
+		page := <-result
+		if page.err != nil {
+			return page.err
+		}
+
+		for _, resource := range page.resources {
+			if !cb(resource) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package net_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	. "github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/trace/tracefakes"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type pagedThing struct {
+	Name string
+}
+
+// fakePagedCC serves totalPages pages of two things each from /v2/things,
+// taking latency to answer every request.
+type fakePagedCC struct {
+	totalPages int
+	latency    time.Duration
+	failPage   int
+
+	mutex    sync.Mutex
+	requests []string
+	inFlight int
+	maxLoad  int
+}
+
+func (cc *fakePagedCC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cc.mutex.Lock()
+	cc.requests = append(cc.requests, r.URL.RequestURI())
+	cc.inFlight++
+	if cc.inFlight > cc.maxLoad {
+		cc.maxLoad = cc.inFlight
+	}
+	cc.mutex.Unlock()
+
+	defer func() {
+		cc.mutex.Lock()
+		cc.inFlight--
+		cc.mutex.Unlock()
+	}()
+
+	time.Sleep(cc.latency)
+
+	page := 1
+	if r.URL.Query().Get("page") != "" {
+		page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	}
+
+	if page == cc.failPage {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"code": 10001, "description": "page failed"}`)
+		return
+	}
+
+	nextURL := "null"
+	if page < cc.totalPages {
+		nextURL = fmt.Sprintf(`"/v2/things?page=%d&results-per-page=2"`, page+1)
+	}
+	fmt.Fprintf(w, `{
+		"total_pages": %d,
+		"next_url": %s,
+		"resources": [{"Name": "thing-%d-a"}, {"Name": "thing-%d-b"}]
+	}`, cc.totalPages, nextURL, page, page)
+}
+
+func newPagedGateway(config coreconfig.Reader) Gateway {
+	return NewCloudControllerGateway(config, time.Now, &testterm.FakeUI{}, new(tracefakes.FakePrinter))
+}
+
+var _ = Describe("ListPaginatedResources", func() {
+	var (
+		cc      *fakePagedCC
+		server  *httptest.Server
+		gateway Gateway
+		names   []string
+	)
+
+	collect := func(resource interface{}) bool {
+		names = append(names, resource.(pagedThing).Name)
+		return true
+	}
+
+	BeforeEach(func() {
+		cc = &fakePagedCC{totalPages: 6, latency: 10 * time.Millisecond}
+		server = httptest.NewServer(cc)
+		gateway = newPagedGateway(testconfig.NewRepository())
+		gateway.PageWorkers = 3
+		names = []string{}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("calls back for every resource of every page in order", func() {
+		err := gateway.ListPaginatedResources(server.URL, "/v2/things", pagedThing{}, collect)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(names).To(HaveLen(12))
+		for page := 1; page <= 6; page++ {
+			Expect(names[2*page-2]).To(Equal(fmt.Sprintf("thing-%d-a", page)))
+			Expect(names[2*page-1]).To(Equal(fmt.Sprintf("thing-%d-b", page)))
+		}
+		Expect(cc.requests).To(HaveLen(6))
+	})
+
+	It("fetches the remaining pages concurrently, up to the number of workers", func() {
+		err := gateway.ListPaginatedResources(server.URL, "/v2/things", pagedThing{}, collect)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.maxLoad).To(Equal(3))
+	})
+
+	It("does not fetch more pages when the first page is enough", func() {
+		err := gateway.ListPaginatedResources(server.URL, "/v2/things", pagedThing{}, func(resource interface{}) bool {
+			return false
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.requests).To(HaveLen(1))
+	})
+
+	It("stops calling back once the callback returns false", func() {
+		err := gateway.ListPaginatedResources(server.URL, "/v2/things", pagedThing{}, func(resource interface{}) bool {
+			names = append(names, resource.(pagedThing).Name)
+			return len(names) < 5
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(Equal([]string{"thing-1-a", "thing-1-b", "thing-2-a", "thing-2-b", "thing-3-a"}))
+	})
+
+	It("returns the error of a failed page after calling back for the pages before it", func() {
+		cc.failPage = 4
+
+		err := gateway.ListPaginatedResources(server.URL, "/v2/things", pagedThing{}, collect)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("page failed"))
+		Expect(names).To(HaveLen(6))
+	})
+
+	It("follows next_url one page at a time when it has no page number", func() {
+		server.Close()
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("token") == "" {
+				fmt.Fprint(w, `{"total_pages": 3, "next_url": "/v2/things?token=abc", "resources": [{"Name": "first"}]}`)
+			} else {
+				fmt.Fprint(w, `{"total_pages": 3, "next_url": null, "resources": [{"Name": "second"}]}`)
+			}
+		}))
+
+		err := gateway.ListPaginatedResources(server.URL, "/v2/things", pagedThing{}, collect)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(Equal([]string{"first", "second"}))
+	})
+})
+
+func benchmarkListPaginatedResources(b *testing.B, workers int) {
+	server := httptest.NewServer(&fakePagedCC{totalPages: 20, latency: 5 * time.Millisecond})
+	defer server.Close()
+
+	gateway := newPagedGateway(testconfig.NewRepository())
+	gateway.PageWorkers = workers
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := gateway.ListPaginatedResources(server.URL, "/v2/things", pagedThing{}, func(interface{}) bool { return true })
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListPaginatedResourcesOneWorker(b *testing.B) {
+	benchmarkListPaginatedResources(b, 1)
+}
+
+func BenchmarkListPaginatedResourcesDefaultWorkers(b *testing.B) {
+	benchmarkListPaginatedResources(b, DEFAULT_PAGE_WORKERS)
+}
@@ -0,0 +1,162 @@
+package net
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	DEFAULT_MAX_RETRIES     = 2
+	DEFAULT_RETRY_MIN_DELAY = 1 * time.Second
+	DEFAULT_RETRY_MAX_DELAY = 30 * time.Second
+)
+
+// RetryPolicy decides whether a request that failed for a transient reason is
+// sent again, and how long to wait before doing so.
+type RetryPolicy struct {
+	MaxRetries int
+	MinDelay   time.Duration
+	MaxDelay   time.Duration
+}
+
+var idempotentMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"PUT":     true,
+	"DELETE":  true,
+}
+
+var retryableStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+}
+
+// NewRetryPolicy returns the default policy, overridden first by the
+// settings in the config and then by the CF_RETRIES and CF_RETRY_MAX_WAIT
+// environment variables.
+func NewRetryPolicy(retries int, maxWait uint) RetryPolicy {
+	policy := RetryPolicy{
+		MaxRetries: DEFAULT_MAX_RETRIES,
+		MinDelay:   DEFAULT_RETRY_MIN_DELAY,
+		MaxDelay:   DEFAULT_RETRY_MAX_DELAY,
+	}
+
+	if retries >= 0 {
+		policy.MaxRetries = retries
+	}
+	if maxWait > 0 {
+		policy.MaxDelay = time.Duration(maxWait) * time.Second
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("CF_RETRIES")); err == nil && value >= 0 {
+		policy.MaxRetries = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("CF_RETRY_MAX_WAIT")); err == nil && value > 0 {
+		policy.MaxDelay = time.Duration(value) * time.Second
+	}
+
+	if policy.MinDelay > policy.MaxDelay {
+		policy.MinDelay = policy.MaxDelay
+	}
+
+	return policy
+}
+
+// ShouldRetry reports whether the request should be sent again after the
+// given attempt, counted from zero, and how long to wait first. Connection
+// failures and 429, 502 and 503 responses are retried for idempotent methods;
+// requests that could not connect at all are retried for any method.
+func (policy RetryPolicy) ShouldRetry(request *Request, response *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= policy.MaxRetries {
+		return 0, false
+	}
+
+	if err != nil {
+		if response != nil {
+			return 0, false
+		}
+		if !isDialError(err) && !isRetryableRequest(request) {
+			return 0, false
+		}
+		return policy.backoff(attempt), true
+	}
+
+	if !retryableStatusCodes[response.StatusCode] || !isRetryableRequest(request) {
+		return 0, false
+	}
+
+	if delay, found := retryAfter(response, time.Now()); found {
+		if delay > policy.MaxDelay {
+			return 0, false
+		}
+		return delay, true
+	}
+
+	return policy.backoff(attempt), true
+}
+
+// backoff doubles the delay with every attempt, up to MaxDelay, and picks a
+// random delay between half and all of it so that clients failing together
+// do not retry together.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.MinDelay
+	for i := 0; i < attempt && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// isRetryableRequest is true for idempotent methods whose body, if any, can
+// be sent again.
+func isRetryableRequest(request *Request) bool {
+	if !idempotentMethods[request.HTTPReq.Method] {
+		return false
+	}
+	return request.HTTPReq.Body == nil || request.SeekableBody != nil
+}
+
+func isDialError(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	opErr, ok := err.(*net.OpError)
+	return ok && opErr.Op == "dial"
+}
+
+// retryAfter parses the Retry-After header, which holds either a number of
+// seconds or an HTTP date.
+func retryAfter(response *http.Response, now time.Time) (time.Duration, bool) {
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,86 @@
+package net_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	. "github.com/cloudfoundry/cli/cf/net"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RetryPolicy", func() {
+	AfterEach(func() {
+		os.Unsetenv("CF_RETRIES")
+		os.Unsetenv("CF_RETRY_MAX_WAIT")
+	})
+
+	Describe("NewRetryPolicy", func() {
+		It("uses the defaults when nothing is configured", func() {
+			policy := NewRetryPolicy(-1, 0)
+			Expect(policy.MaxRetries).To(Equal(DEFAULT_MAX_RETRIES))
+			Expect(policy.MaxDelay).To(Equal(DEFAULT_RETRY_MAX_DELAY))
+		})
+
+		It("uses the values from the config", func() {
+			policy := NewRetryPolicy(0, 10)
+			Expect(policy.MaxRetries).To(Equal(0))
+			Expect(policy.MaxDelay).To(Equal(10 * time.Second))
+		})
+
+		It("prefers the environment over the config", func() {
+			os.Setenv("CF_RETRIES", "5")
+			os.Setenv("CF_RETRY_MAX_WAIT", "60")
+
+			policy := NewRetryPolicy(1, 10)
+			Expect(policy.MaxRetries).To(Equal(5))
+			Expect(policy.MaxDelay).To(Equal(60 * time.Second))
+		})
+
+		It("ignores invalid values in the environment", func() {
+			os.Setenv("CF_RETRIES", "lots")
+
+			policy := NewRetryPolicy(1, 0)
+			Expect(policy.MaxRetries).To(Equal(1))
+		})
+	})
+
+	Describe("ShouldRetry", func() {
+		var (
+			policy  RetryPolicy
+			request *Request
+		)
+
+		BeforeEach(func() {
+			policy = RetryPolicy{MaxRetries: 10, MinDelay: time.Second, MaxDelay: 4 * time.Second}
+			httpReq, _ := http.NewRequest("GET", "https://example.com/v2/apps", nil)
+			request = &Request{HTTPReq: httpReq}
+		})
+
+		It("never waits longer than the maximum delay", func() {
+			delay, retry := policy.ShouldRetry(request, nil, errors.New("connection reset by peer"), 6)
+			Expect(retry).To(BeTrue())
+			Expect(delay).To(BeNumerically(">=", 2*time.Second))
+			Expect(delay).To(BeNumerically("<=", 4*time.Second))
+		})
+
+		It("understands Retry-After given as a date", func() {
+			response := &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": []string{time.Now().Add(3 * time.Second).UTC().Format(http.TimeFormat)}},
+			}
+
+			delay, retry := policy.ShouldRetry(request, response, nil, 0)
+			Expect(retry).To(BeTrue())
+			Expect(delay).To(BeNumerically(">", time.Second))
+			Expect(delay).To(BeNumerically("<=", 3*time.Second))
+		})
+
+		It("does not retry other errors returned by the server", func() {
+			_, retry := policy.ShouldRetry(request, &http.Response{StatusCode: http.StatusInternalServerError}, nil, 0)
+			Expect(retry).To(BeFalse())
+		})
+	})
+})
@@ -3,6 +3,12 @@ package net
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
 )
 
 func NewTLSConfig(trustedCerts []tls.Certificate, disableSSL bool) (TLSConfig *tls.Config) {
@@ -23,3 +29,86 @@ func NewTLSConfig(trustedCerts []tls.Certificate, disableSSL bool) (TLSConfig *t
 
 	return
 }
+
+// CertificateFiles are the PEM files used to verify servers and to identify
+// the CLI to them.
+type CertificateFiles struct {
+	CACert     string
+	ClientCert string
+	ClientKey  string
+}
+
+// NewCertificateFiles takes the files from the config, overridden by the
+// CF_CA_CERT, CF_CLIENT_CERT and CF_CLIENT_KEY environment variables.
+func NewCertificateFiles(config coreconfig.Reader) CertificateFiles {
+	files := CertificateFiles{
+		CACert:     config.CACertFile(),
+		ClientCert: config.ClientCertFile(),
+		ClientKey:  config.ClientKeyFile(),
+	}
+
+	if path := os.Getenv("CF_CA_CERT"); path != "" {
+		files.CACert = path
+	}
+	if path := os.Getenv("CF_CLIENT_CERT"); path != "" {
+		files.ClientCert = path
+		files.ClientKey = os.Getenv("CF_CLIENT_KEY")
+	}
+
+	return files
+}
+
+// NewTLSConfigForConfig is NewTLSConfig with the CA bundle and client
+// certificate from the config added.
+func NewTLSConfigForConfig(trustedCerts []tls.Certificate, config coreconfig.Reader) (*tls.Config, error) {
+	tlsConfig := NewTLSConfig(trustedCerts, config.IsSSLDisabled())
+	err := NewCertificateFiles(config).AddTo(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return tlsConfig, nil
+}
+
+// AddTo adds the certificates in the CA bundle to the roots trusted by
+// tlsConfig, on top of the system roots, and makes tlsConfig present the
+// client certificate. The client key may be left out when it is in the same
+// file as the certificate.
+func (files CertificateFiles) AddTo(tlsConfig *tls.Config) error {
+	if files.CACert != "" {
+		pemCerts, err := ioutil.ReadFile(files.CACert)
+		if err != nil {
+			return errors.New(T("Could not read CA certificate file {{.Path}}: {{.Err}}",
+				map[string]interface{}{"Path": files.CACert, "Err": err.Error()}))
+		}
+
+		certPool := tlsConfig.RootCAs
+		if certPool == nil {
+			certPool, err = x509.SystemCertPool()
+			if err != nil {
+				certPool = x509.NewCertPool()
+			}
+		}
+
+		if !certPool.AppendCertsFromPEM(pemCerts) {
+			return errors.New(T("No PEM encoded certificates found in {{.Path}}",
+				map[string]interface{}{"Path": files.CACert}))
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if files.ClientCert != "" {
+		keyFile := files.ClientKey
+		if keyFile == "" {
+			keyFile = files.ClientCert
+		}
+
+		certificate, err := tls.LoadX509KeyPair(files.ClientCert, keyFile)
+		if err != nil {
+			return errors.New(T("Could not load client certificate {{.Path}}: {{.Err}}",
+				map[string]interface{}{"Path": files.ClientCert, "Err": err.Error()}))
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return nil
+}
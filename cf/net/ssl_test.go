@@ -0,0 +1,86 @@
+package net_test
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cli/cf/net"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CertificateFiles", func() {
+	AfterEach(func() {
+		os.Unsetenv("CF_CA_CERT")
+		os.Unsetenv("CF_CLIENT_CERT")
+		os.Unsetenv("CF_CLIENT_KEY")
+	})
+
+	Describe("NewCertificateFiles", func() {
+		It("takes the files from the config", func() {
+			config := testconfig.NewRepository()
+			config.SetCACertFile("/config/ca.pem")
+			config.SetClientCertificate("/config/cert.pem", "/config/key.pem")
+
+			Expect(NewCertificateFiles(config)).To(Equal(CertificateFiles{
+				CACert:     "/config/ca.pem",
+				ClientCert: "/config/cert.pem",
+				ClientKey:  "/config/key.pem",
+			}))
+		})
+
+		It("prefers the environment over the config", func() {
+			config := testconfig.NewRepository()
+			config.SetCACertFile("/config/ca.pem")
+			config.SetClientCertificate("/config/cert.pem", "/config/key.pem")
+			os.Setenv("CF_CA_CERT", "/env/ca.pem")
+			os.Setenv("CF_CLIENT_CERT", "/env/cert-and-key.pem")
+
+			Expect(NewCertificateFiles(config)).To(Equal(CertificateFiles{
+				CACert:     "/env/ca.pem",
+				ClientCert: "/env/cert-and-key.pem",
+			}))
+		})
+	})
+
+	Describe("AddTo", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "certificate-files")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("does nothing when there are no files", func() {
+			tlsConfig := &tls.Config{}
+			Expect(CertificateFiles{}.AddTo(tlsConfig)).To(Succeed())
+			Expect(tlsConfig.RootCAs).To(BeNil())
+			Expect(tlsConfig.Certificates).To(BeEmpty())
+		})
+
+		It("fails when the CA certificate file has no certificates", func() {
+			path := filepath.Join(dir, "ca.pem")
+			ioutil.WriteFile(path, []byte("not a certificate"), 0600)
+
+			err := CertificateFiles{CACert: path}.AddTo(&tls.Config{})
+			Expect(err).To(MatchError("No PEM encoded certificates found in " + path))
+		})
+
+		It("fails when the client certificate cannot be loaded", func() {
+			path := filepath.Join(dir, "cert.pem")
+			ioutil.WriteFile(path, []byte("not a certificate"), 0600)
+
+			err := CertificateFiles{ClientCert: path}.AddTo(&tls.Config{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Could not load client certificate " + path))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package terminal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"gopkg.in/yaml.v2"
+)
+
+// OutputFormat selects how listing commands render their results. The
+// default, TableOutput, is the human readable table; the structured
+// formats emit a document built from the models backing each row.
+type OutputFormat string
+
+const (
+	TableOutput OutputFormat = "table"
+	JSONOutput  OutputFormat = "json"
+	YAMLOutput  OutputFormat = "yaml"
+)
+
+// ParseOutputFormat converts the value given to --output into an
+// OutputFormat. The empty string selects TableOutput.
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch OutputFormat(strings.ToLower(value)) {
+	case "", TableOutput:
+		return TableOutput, nil
+	case JSONOutput:
+		return JSONOutput, nil
+	case YAMLOutput:
+		return YAMLOutput, nil
+	}
+
+	return TableOutput, errors.New(T("Invalid output format '{{.Format}}'. Valid formats are: json, yaml, table",
+		map[string]interface{}{"Format": value}))
+}
+
+// IsStructured reports whether the format produces a machine-readable
+// document instead of a table.
+func (f OutputFormat) IsStructured() bool {
+	return f == JSONOutput || f == YAMLOutput
+}
+
+// Marshal renders records as a document in the receiving format. Records
+// go through JSON first so that JSON and YAML output share key names and
+// ordering, independent of how the YAML encoder would treat the models.
+func (f OutputFormat) Marshal(records []interface{}) (string, error) {
+	if records == nil {
+		records = []interface{}{}
+	}
+
+	document, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	switch f {
+	case JSONOutput:
+		return string(document), nil
+	case YAMLOutput:
+		var generic interface{}
+		err = json.Unmarshal(document, &generic)
+		if err != nil {
+			return "", err
+		}
+
+		document, err = yaml.Marshal(generic)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(document), "\n"), nil
+	}
+
+	return "", errors.New("Cannot marshal records for table output")
+}
+
+// writerPrinter is a Printer on top of a plain io.Writer. Structured UIs
+// use it to keep status messages off stdout.
+type writerPrinter struct {
+	writer io.Writer
+}
+
+func (p writerPrinter) Print(a ...interface{}) (n int, err error) {
+	return fmt.Fprint(p.writer, a...)
+}
+
+func (p writerPrinter) Printf(format string, a ...interface{}) (n int, err error) {
+	return fmt.Fprintf(p.writer, format, a...)
+}
+
+func (p writerPrinter) Println(a ...interface{}) (n int, err error) {
+	return fmt.Fprintln(p.writer, a...)
+}
@@ -0,0 +1,45 @@
+package terminal_test
+
+import (
+	. "github.com/cloudfoundry/cli/cf/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OutputFormat", func() {
+	Describe("ParseOutputFormat", func() {
+		It("defaults to table output", func() {
+			format, err := ParseOutputFormat("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(format).To(Equal(TableOutput))
+		})
+
+		It("accepts the known formats regardless of case", func() {
+			format, err := ParseOutputFormat("JSON")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(format).To(Equal(JSONOutput))
+
+			format, err = ParseOutputFormat("yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(format).To(Equal(YAMLOutput))
+
+			format, err = ParseOutputFormat("table")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(format).To(Equal(TableOutput))
+		})
+
+		It("returns an error for unknown formats", func() {
+			_, err := ParseOutputFormat("xml")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid output format 'xml'"))
+		})
+	})
+
+	Describe("IsStructured", func() {
+		It("is true for json and yaml", func() {
+			Expect(JSONOutput.IsStructured()).To(BeTrue())
+			Expect(YAMLOutput.IsStructured()).To(BeTrue())
+			Expect(TableOutput.IsStructured()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+// prefixedOutputMutex keeps lines said through different prefixed UIs from
+// interleaving mid-message.
+var prefixedOutputMutex sync.Mutex
+
+// prefixedUI says every line of output with a prefix in front of it, so that
+// several operations running at once can share a terminal and still be told
+// apart. Prompts and everything else are left to the wrapped UI.
+type prefixedUI struct {
+	UI
+	prefix string
+}
+
+func NewPrefixedUI(ui UI, prefix string) UI {
+	return &prefixedUI{UI: ui, prefix: prefix}
+}
+
+func (ui *prefixedUI) Say(message string, args ...interface{}) {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+
+	prefixedOutputMutex.Lock()
+	defer prefixedOutputMutex.Unlock()
+
+	for _, line := range strings.Split(message, "\n") {
+		ui.UI.Say("%s%s", ui.prefix, line)
+	}
+}
+
+func (ui *prefixedUI) PrintPaginator(rows []string, err error) {
+	if err != nil {
+		ui.Failed(err.Error())
+		return
+	}
+
+	for _, row := range rows {
+		ui.Say(row)
+	}
+}
+
+func (ui *prefixedUI) Warn(message string, args ...interface{}) {
+	ui.Say(WarningColor(fmt.Sprintf(message, args...)))
+}
+
+func (ui *prefixedUI) Ok() {
+	ui.Say(SuccessColor(T("OK")))
+}
+
+func (ui *prefixedUI) Failed(message string, args ...interface{}) {
+	ui.Say(FailureColor(T("FAILED")))
+	ui.Say(fmt.Sprintf(message, args...))
+
+	panic(QuietPanic)
+}
+
+func (ui *prefixedUI) Table(headers []string) *UITable {
+	table := ui.UI.Table(headers)
+	table.UI = ui
+	return table
+}
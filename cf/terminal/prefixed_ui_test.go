@@ -0,0 +1,69 @@
+package terminal_test
+
+import (
+	. "github.com/cloudfoundry/cli/cf/terminal"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrefixedUI", func() {
+	var (
+		fakeUI *testterm.FakeUI
+		ui     UI
+	)
+
+	BeforeEach(func() {
+		fakeUI = new(testterm.FakeUI)
+		ui = NewPrefixedUI(fakeUI, "[my-app] ")
+	})
+
+	It("prefixes every line it says", func() {
+		ui.Say("Hello %s\nand goodbye", "world")
+
+		Expect(fakeUI.Outputs).To(Equal([]string{
+			"[my-app] Hello world",
+			"[my-app] and goodbye",
+		}))
+	})
+
+	It("does not format messages without arguments", func() {
+		ui.Say("100%")
+
+		Expect(fakeUI.Outputs).To(Equal([]string{"[my-app] 100%"}))
+	})
+
+	It("prefixes OK and warnings", func() {
+		ui.Ok()
+		ui.Warn("careful")
+
+		Expect(fakeUI.Outputs).To(ContainSubstrings(
+			[]string{"[my-app]", "OK"},
+			[]string{"[my-app]", "careful"},
+		))
+	})
+
+	It("prefixes failures before panicking", func() {
+		Expect(func() {
+			ui.Failed("it broke: %s", "badly")
+		}).To(Panic())
+
+		Expect(fakeUI.Outputs).To(ContainSubstrings(
+			[]string{"[my-app]", "FAILED"},
+			[]string{"[my-app]", "it broke: badly"},
+		))
+	})
+
+	It("prefixes the rows of tables", func() {
+		table := ui.Table([]string{"name", "state"})
+		table.Add("web", "running")
+		table.Print()
+
+		Expect(fakeUI.Outputs).To(ContainSubstrings(
+			[]string{"[my-app]", "name", "state"},
+			[]string{"[my-app]", "web", "running"},
+		))
+	})
+})
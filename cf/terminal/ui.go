@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -52,14 +53,33 @@ type terminalUI struct {
 	stdin   io.Reader
 	printer Printer
 	logger  trace.Printer
+
+	outputFormat    OutputFormat
+	documentPrinter Printer
 }
 
 func NewUI(r io.Reader, printer Printer, logger trace.Printer) UI {
-	return &terminalUI{
-		stdin:   r,
-		printer: printer,
-		logger:  logger,
+	return NewUIWithOutputFormat(r, printer, logger, TableOutput)
+}
+
+// NewUIWithOutputFormat creates a UI whose tables render in the given
+// format. For structured formats the printer only receives the documents
+// produced by tables; everything else the UI says goes to stderr, so that
+// stdout can be piped straight into a parser.
+func NewUIWithOutputFormat(r io.Reader, printer Printer, logger trace.Printer, format OutputFormat) UI {
+	ui := &terminalUI{
+		stdin:        r,
+		printer:      printer,
+		logger:       logger,
+		outputFormat: format,
+	}
+
+	if format.IsStructured() {
+		ui.documentPrinter = printer
+		ui.printer = writerPrinter{writer: os.Stderr}
 	}
+
+	return ui
 }
 
 func (ui *terminalUI) PrintPaginator(rows []string, err error) {
@@ -173,6 +193,10 @@ func (ui *terminalUI) PanicQuietly() {
 func (ui *terminalUI) ShowConfiguration(config coreconfig.Reader) {
 	table := ui.Table([]string{"", ""})
 
+	if config.ProfileName() != coreconfig.DefaultProfileName {
+		table.Add(T("Profile:"), EntityNameColor(config.ProfileName()))
+	}
+
 	if config.HasAPIEndpoint() {
 		table.Add(
 			T("API endpoint:"),
@@ -240,24 +264,59 @@ func (ui *terminalUI) LoadingIndication() {
 
 func (ui *terminalUI) Table(headers []string) *UITable {
 	return &UITable{
-		UI:    ui,
-		Table: NewTable(headers),
+		UI:           ui,
+		Table:        NewTable(headers),
+		OutputFormat: ui.outputFormat,
+		Output:       ui.documentPrinter,
 	}
 }
 
+// UITable is a table bound to the UI it prints to. Besides the rows of
+// text it collects the records backing them, which are what gets printed
+// when a structured OutputFormat is in effect. Structured documents are
+// written to Output, or said through the UI when Output is nil.
 type UITable struct {
-	UI    UI
-	Table *Table
+	UI           UI
+	Table        *Table
+	OutputFormat OutputFormat
+	Output       Printer
+
+	records []interface{}
 }
 
 func (u *UITable) Add(row ...string) {
 	u.Table.Add(row...)
 }
 
+// AddRecord extends the table by another row and remembers the record
+// the row was rendered from. Rows added with Add alone show up in
+// structured output keyed by the headers of the table, unless the table
+// has records.
+func (u *UITable) AddRecord(record interface{}, row ...string) {
+	u.records = append(u.records, record)
+	u.Table.Add(row...)
+}
+
+// PrintNoRecords is used instead of Print when a listing came back
+// empty. The message is said as usual, and structured output still gets
+// an empty document so that consumers always have something to parse.
+func (u *UITable) PrintNoRecords(message string) {
+	u.UI.Say(message)
+
+	if u.OutputFormat.IsStructured() {
+		u.Print()
+	}
+}
+
 // Print formats the table and then prints it to the UI specified at
 // the time of the construction. Afterwards the table is cleared,
 // becoming ready for another round of rows and printing.
 func (u *UITable) Print() {
+	if u.OutputFormat.IsStructured() {
+		u.printDocument()
+		return
+	}
+
 	result := &bytes.Buffer{}
 	t := u.Table
 
@@ -283,6 +342,54 @@ func (u *UITable) Print() {
 	}
 }
 
+// printDocument renders the collected records in the structured output
+// format, or the rows when no records were collected. Like Print, it
+// clears the table afterwards.
+func (u *UITable) printDocument() {
+	records := u.records
+	if len(records) == 0 {
+		records = u.rowRecords()
+	}
+
+	document, err := u.OutputFormat.Marshal(records)
+	u.records = nil
+	u.Table.rows = [][]string{}
+
+	if err != nil {
+		u.UI.Failed(err.Error())
+		return
+	}
+
+	if u.Output == nil {
+		u.UI.Say("%s", document)
+		return
+	}
+
+	u.Output.Printf("%s\n", document)
+}
+
+// rowRecords turns the rows of the table into records keyed by the
+// headers, for tables filled with Add only. Colors are removed, and
+// columns without a header are keyed by their position.
+func (u *UITable) rowRecords() []interface{} {
+	records := []interface{}{}
+	for _, row := range u.Table.rows {
+		record := map[string]string{}
+		for i, value := range row {
+			key := ""
+			if i < len(u.Table.headers) {
+				key = strings.TrimSpace(Decolorize(u.Table.headers[i]))
+			}
+			if key == "" {
+				key = fmt.Sprintf("column %d", i+1)
+			}
+			record[key] = strings.TrimSpace(Decolorize(value))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
 func (ui *terminalUI) NotifyUpdateIfNeeded(config coreconfig.Reader) {
 	if !config.IsMinCLIVersion(cf.Version) {
 		ui.Say("")
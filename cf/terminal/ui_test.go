@@ -278,6 +278,22 @@ var _ = Describe("UI", func() {
 				Expect(output).To(ContainSubstrings([]string{"User:", "my-user-email"}))
 			})
 
+			It("does not mention the default profile", func() {
+				Expect(output).NotTo(ContainSubstrings([]string{"Profile:"}))
+			})
+
+			Context("when another profile is in use", func() {
+				BeforeEach(func() {
+					config.UseProfile("staging")
+					config.SetAPIEndpoint("https://api.staging.example.org")
+					config.SetAPIVersion("☃☃☃")
+				})
+
+				It("tells the user which profile is in use", func() {
+					Expect(output).To(ContainSubstrings([]string{"Profile:", "staging"}))
+				})
+			})
+
 			Context("when an org is targeted", func() {
 				BeforeEach(func() {
 					config.SetOrganizationFields(models.OrganizationFields{
@@ -401,6 +417,92 @@ var _ = Describe("UI", func() {
 		})
 	})
 
+	Describe("Printing tables in a structured output format", func() {
+		type record struct {
+			Name  string
+			Count int
+		}
+
+		It("prints the records added to the table as a JSON document", func() {
+			output := io_helpers.CaptureOutput(func() {
+				ui := NewUIWithOutputFormat(os.Stdin, NewTeePrinter(), fakeLogger, JSONOutput)
+				table := ui.Table([]string{"name", "count"})
+				table.AddRecord(record{Name: "foo", Count: 1}, "foo", "1")
+				table.AddRecord(record{Name: "bar", Count: 2}, "bar", "2")
+				table.Print()
+			})
+
+			Expect(strings.Join(output, "\n")).To(MatchJSON(`[{"Name":"foo","Count":1},{"Name":"bar","Count":2}]`))
+		})
+
+		It("prints the records added to the table as a YAML document", func() {
+			output := io_helpers.CaptureOutput(func() {
+				ui := NewUIWithOutputFormat(os.Stdin, NewTeePrinter(), fakeLogger, YAMLOutput)
+				table := ui.Table([]string{"name", "count"})
+				table.AddRecord(record{Name: "foo", Count: 1}, "foo", "1")
+				table.Print()
+			})
+
+			Expect(output).To(Equal([]string{"- Count: 1", "  Name: foo", ""}))
+		})
+
+		It("leaves out rows that were added without a record", func() {
+			output := io_helpers.CaptureOutput(func() {
+				ui := NewUIWithOutputFormat(os.Stdin, NewTeePrinter(), fakeLogger, JSONOutput)
+				table := ui.Table([]string{"name"})
+				table.AddRecord(record{Name: "foo"}, "foo")
+				table.Add("continuation")
+				table.Print()
+			})
+
+			Expect(strings.Join(output, "\n")).To(MatchJSON(`[{"Name":"foo","Count":0}]`))
+		})
+
+		It("prints the rows keyed by the headers when the table has no records", func() {
+			output := io_helpers.CaptureOutput(func() {
+				ui := NewUIWithOutputFormat(os.Stdin, NewTeePrinter(), fakeLogger, JSONOutput)
+				table := ui.Table([]string{"name", "", "count"})
+				table.Add(EntityNameColor("foo"), "x", "1")
+				table.Add("bar", "y", "2")
+				table.Print()
+			})
+
+			Expect(strings.Join(output, "\n")).To(MatchJSON(`[{"name":"foo","column 2":"x","count":"1"},{"name":"bar","column 2":"y","count":"2"}]`))
+		})
+
+		It("keeps everything but the document off stdout", func() {
+			output := io_helpers.CaptureOutput(func() {
+				ui := NewUIWithOutputFormat(os.Stdin, NewTeePrinter(), fakeLogger, JSONOutput)
+				ui.Say("Getting things...")
+				ui.Ok()
+				table := ui.Table([]string{"name"})
+				table.AddRecord(record{Name: "foo"}, "foo")
+				table.Print()
+			})
+
+			Expect(output).NotTo(ContainSubstrings([]string{"Getting things..."}, []string{"OK"}))
+			Expect(output).NotTo(ContainSubstrings([]string{"name"}))
+		})
+
+		It("prints an empty document when there are no records", func() {
+			output := io_helpers.CaptureOutput(func() {
+				ui := NewUIWithOutputFormat(os.Stdin, NewTeePrinter(), fakeLogger, JSONOutput)
+				ui.Table([]string{"name"}).PrintNoRecords("No things found")
+			})
+
+			Expect(strings.Join(output, "\n")).To(MatchJSON(`[]`))
+		})
+
+		It("only says the message for an empty table in table format", func() {
+			output := io_helpers.CaptureOutput(func() {
+				ui := NewUI(os.Stdin, NewTeePrinter(), fakeLogger)
+				ui.Table([]string{"name"}).PrintNoRecords("No things found")
+			})
+
+			Expect(output).To(Equal([]string{"No things found", ""}))
+		})
+	})
+
 	Describe("NotifyUpdateIfNeeded", func() {
 
 		var (
@@ -0,0 +1,324 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+const HARFormat = "har"
+
+// HTTPRecorder is implemented by printers that keep requests and responses as
+// structured data rather than printing them as text.
+type HTTPRecorder interface {
+	RecordRequest(*http.Request)
+	RecordResponse(*http.Response)
+}
+
+// HARPrinter records requests and responses in an HTTP Archive file. Text
+// written to it is ignored. Each run of the CLI starts a new archive. Entries
+// are appended to the file as responses are recorded, followed by the
+// requests still waiting for a response, so that the file is a complete
+// archive even when the CLI exits early.
+type HARPrinter struct {
+	file *os.File
+
+	mutex   sync.Mutex
+	written int64
+	entries int
+	pending []*pendingEntry
+}
+
+type pendingEntry struct {
+	request *http.Request
+	started time.Time
+	entry   harEntry
+}
+
+var (
+	harPrintersMutex sync.Mutex
+	harPrinters      = map[string]*HARPrinter{}
+)
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NewHARPrinter returns a printer that records into a new archive at path,
+// replacing any earlier archive, and fails when the archive cannot be
+// written. Printers for the same path share the archive.
+func NewHARPrinter(path string) (*HARPrinter, error) {
+	harPrintersMutex.Lock()
+	defer harPrintersMutex.Unlock()
+
+	if printer, found := harPrinters[path]; found {
+		return printer, nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	creator, err := json.Marshal(harCreator{Name: cf.Name, Version: cf.Version})
+	if err != nil {
+		return nil, err
+	}
+
+	header := `{"log":{"version":"1.2","creator":` + string(creator) + `,"entries":[`
+	printer := &HARPrinter{file: file, written: int64(len(header))}
+	_, err = file.WriteAt([]byte(header), 0)
+	if err == nil {
+		err = printer.writeTail()
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	harPrinters[path] = printer
+	return printer, nil
+}
+
+func (p *HARPrinter) Print(v ...interface{})                 {}
+func (p *HARPrinter) Printf(format string, v ...interface{}) {}
+func (p *HARPrinter) Println(v ...interface{})               {}
+func (p *HARPrinter) WritesToConsole() bool                  { return false }
+
+// RecordRequest adds an entry for the request. Until its response is
+// recorded, the entry has a status of 0, as for requests that failed.
+func (p *HARPrinter) RecordRequest(req *http.Request) {
+	started := time.Now()
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         Sanitize(req.URL.String()),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    req.ContentLength,
+		},
+		Response: harResponse{
+			Headers:     []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+	}
+
+	query := req.URL.Query()
+	for _, name := range sortedKeys(query) {
+		for _, value := range query[name] {
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: Sanitize(value)})
+		}
+	}
+
+	if req.Body != nil {
+		entry.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type")}
+		if strings.Contains(entry.Request.PostData.MimeType, "multipart/form-data") {
+			entry.Request.PostData.Text = T("[MULTIPART/FORM-DATA CONTENT HIDDEN]")
+		} else {
+			var body []byte
+			body, req.Body = readBody(req.Body)
+			entry.Request.PostData.Text = Sanitize(string(body))
+			entry.Request.BodySize = int64(len(body))
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.pending = append(p.pending, &pendingEntry{request: req, started: started, entry: entry})
+	p.writeTail()
+}
+
+// RecordResponse completes the entry of the request the response is for.
+func (p *HARPrinter) RecordResponse(res *http.Response) {
+	var body []byte
+	body, res.Body = readBody(res.Body)
+
+	response := harResponse{
+		Status:      res.StatusCode,
+		StatusText:  http.StatusText(res.StatusCode),
+		HTTPVersion: res.Proto,
+		Headers:     harHeaders(res.Header),
+		Cookies:     []harNameValue{},
+		Content: harContent{
+			Size:     int64(len(body)),
+			MimeType: res.Header.Get("Content-Type"),
+			Text:     Sanitize(string(body)),
+		},
+		RedirectURL: res.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for index, pending := range p.pending {
+		if pending.request != res.Request {
+			continue
+		}
+		p.pending = append(p.pending[:index], p.pending[index+1:]...)
+
+		elapsed := float64(time.Since(pending.started)) / float64(time.Millisecond)
+		entry := pending.entry
+		entry.Response = response
+		entry.Time = elapsed
+		entry.Timings = harTimings{Wait: elapsed}
+
+		contents, err := p.marshalEntry(entry, p.entries)
+		if err != nil {
+			return
+		}
+		if _, err = p.file.WriteAt(contents, p.written); err != nil {
+			return
+		}
+		p.written += int64(len(contents))
+		p.entries++
+
+		p.writeTail()
+		return
+	}
+}
+
+// writeTail writes the requests still waiting for a response, with a status
+// of 0 as for requests that failed, and closes the archive after the entries
+// written so far.
+func (p *HARPrinter) writeTail() error {
+	var tail []byte
+	for index, pending := range p.pending {
+		contents, err := p.marshalEntry(pending.entry, p.entries+index)
+		if err != nil {
+			return err
+		}
+		tail = append(tail, contents...)
+	}
+	tail = append(tail, "\n]}}\n"...)
+
+	_, err := p.file.WriteAt(tail, p.written)
+	if err != nil {
+		return err
+	}
+	return p.file.Truncate(p.written + int64(len(tail)))
+}
+
+func (p *HARPrinter) marshalEntry(entry harEntry, index int) ([]byte, error) {
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	separator := "\n"
+	if index > 0 {
+		separator = ",\n"
+	}
+	return append([]byte(separator), contents...), nil
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for _, name := range sortedKeys(header) {
+		for _, value := range header[name] {
+			line := Sanitize(name + ": " + value)
+			headers = append(headers, harNameValue{Name: name, Value: strings.TrimPrefix(line, name+": ")})
+		}
+	}
+	return headers
+}
+
+func sortedKeys(values map[string][]string) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readBody reads all of body and returns a copy to put in its place.
+func readBody(body io.ReadCloser) ([]byte, io.ReadCloser) {
+	if body == nil {
+		return nil, nil
+	}
+
+	contents, _ := ioutil.ReadAll(body)
+	body.Close()
+	return contents, ioutil.NopCloser(bytes.NewReader(contents))
+}
@@ -0,0 +1,183 @@
+package trace_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/trace"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HARPrinter", func() {
+	var (
+		dir     string
+		path    string
+		server  *httptest.Server
+		printer *HARPrinter
+	)
+
+	type harDocument struct {
+		Log struct {
+			Version string
+			Creator struct{ Name string }
+			Entries []struct {
+				Time    float64
+				Request struct {
+					Method      string
+					URL         string
+					Headers     []struct{ Name, Value string }
+					QueryString []struct{ Name, Value string }
+					PostData    struct{ Text string }
+				}
+				Response struct {
+					Status  int
+					Content struct{ Text string }
+				}
+			}
+		}
+	}
+
+	readHAR := func() harDocument {
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		var document harDocument
+		Expect(json.Unmarshal(contents, &document)).To(Succeed())
+		return document
+	}
+
+	do := func(req *http.Request) {
+		printer.RecordRequest(req)
+		res, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		printer.RecordResponse(res)
+		res.Body.Close()
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "har-printer")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "traces", "trace.har")
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"received":%q,"access_token":"secret-token"}`, string(body))
+		}))
+
+		printer, err = NewHARPrinter(path)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(dir)
+	})
+
+	It("writes an empty archive when it is created", func() {
+		document := readHAR()
+		Expect(document.Log.Version).To(Equal("1.2"))
+		Expect(document.Log.Creator.Name).To(Equal("cf"))
+		Expect(document.Log.Entries).To(BeEmpty())
+	})
+
+	It("records each request with its response", func() {
+		req, _ := http.NewRequest("POST", server.URL+"/v2/apps?q=name:foo", strings.NewReader(`{"name":"foo"}`))
+		do(req)
+
+		document := readHAR()
+		Expect(document.Log.Entries).To(HaveLen(1))
+
+		entry := document.Log.Entries[0]
+		Expect(entry.Request.Method).To(Equal("POST"))
+		Expect(entry.Request.URL).To(Equal(server.URL + "/v2/apps?q=name:foo"))
+		Expect(entry.Request.QueryString).To(HaveLen(1))
+		Expect(entry.Request.QueryString[0].Value).To(Equal("name:foo"))
+		Expect(entry.Request.PostData.Text).To(Equal(`{"name":"foo"}`))
+		Expect(entry.Response.Status).To(Equal(http.StatusCreated))
+		Expect(entry.Response.Content.Text).To(ContainSubstring(`"received":"{\"name\":\"foo\"}"`))
+		Expect(entry.Time).To(BeNumerically(">", 0))
+	})
+
+	It("hides private data", func() {
+		req, _ := http.NewRequest("GET", server.URL+"/v2/info", nil)
+		req.Header.Set("Authorization", "bearer secret-token")
+		do(req)
+
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).NotTo(ContainSubstring("secret-token"))
+		Expect(string(contents)).To(ContainSubstring(PRIVATE_DATA_PLACEHOLDER()))
+	})
+
+	It("records requests waiting for a response with a status of 0", func() {
+		req, _ := http.NewRequest("GET", server.URL+"/v2/info", nil)
+		do(req)
+		req, _ = http.NewRequest("GET", server.URL+"/v2/apps", nil)
+		printer.RecordRequest(req)
+
+		document := readHAR()
+		Expect(document.Log.Entries).To(HaveLen(2))
+		Expect(document.Log.Entries[0].Response.Status).To(Equal(http.StatusCreated))
+		Expect(document.Log.Entries[1].Request.URL).To(HaveSuffix("/v2/apps"))
+		Expect(document.Log.Entries[1].Response.Status).To(Equal(0))
+	})
+
+	It("appends entries in the order their responses are recorded", func() {
+		first, _ := http.NewRequest("GET", server.URL+"/v2/first", nil)
+		second, _ := http.NewRequest("GET", server.URL+"/v2/second", nil)
+		printer.RecordRequest(first)
+		printer.RecordRequest(second)
+
+		res, err := http.DefaultClient.Do(second)
+		Expect(err).NotTo(HaveOccurred())
+		printer.RecordResponse(res)
+		res.Body.Close()
+
+		document := readHAR()
+		Expect(document.Log.Entries).To(HaveLen(2))
+		Expect(document.Log.Entries[0].Request.URL).To(HaveSuffix("/v2/second"))
+		Expect(document.Log.Entries[0].Response.Status).To(Equal(http.StatusCreated))
+		Expect(document.Log.Entries[1].Request.URL).To(HaveSuffix("/v2/first"))
+		Expect(document.Log.Entries[1].Response.Status).To(Equal(0))
+	})
+
+	It("shares the archive between printers for the same path", func() {
+		req, _ := http.NewRequest("GET", server.URL+"/v2/info", nil)
+		do(req)
+
+		otherPrinter, err := NewHARPrinter(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(otherPrinter == printer).To(BeTrue())
+		Expect(readHAR().Log.Entries).To(HaveLen(1))
+	})
+
+	It("replaces an archive left by an earlier run", func() {
+		otherPath := filepath.Join(dir, "earlier.har")
+		Expect(ioutil.WriteFile(otherPath, []byte(`{"log":{"entries":[{"request":{"url":"earlier"}}]}}`), 0600)).To(Succeed())
+
+		_, err := NewHARPrinter(otherPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		contents, err := ioutil.ReadFile(otherPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).NotTo(ContainSubstring("earlier"))
+
+		var document harDocument
+		Expect(json.Unmarshal(contents, &document)).To(Succeed())
+		Expect(document.Log.Entries).To(BeEmpty())
+	})
+
+	It("ignores text", func() {
+		printer.Printf("some %s", "text")
+		Expect(readHAR().Log.Entries).To(BeEmpty())
+	})
+})
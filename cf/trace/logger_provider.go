@@ -1,7 +1,10 @@
 package trace
 
 import (
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
 
@@ -9,6 +12,12 @@ import (
 )
 
 func NewLogger(verbose bool, cf_trace, config_trace string) Printer {
+	return NewLoggerWithFormat(verbose, "", cf_trace, config_trace)
+}
+
+// NewLoggerWithFormat is NewLogger with trace files written in the given
+// format. Files ending in .har are always written as HTTP Archives.
+func NewLoggerWithFormat(verbose bool, format, cf_trace, config_trace string) Printer {
 	LoggingToStdout = verbose
 
 	var printers []Printer
@@ -20,10 +29,17 @@ func NewLogger(verbose bool, cf_trace, config_trace string) Printer {
 		LoggingToStdout = LoggingToStdout || b
 
 		if path != "" && err != nil {
-			file, err := fileutils.Open(path)
+			var printer Printer
+			if format == HARFormat || strings.EqualFold(filepath.Ext(path), ".har") {
+				printer, err = NewHARPrinter(path)
+			} else {
+				var file *os.File
+				file, err = fileutils.Open(path)
+				printer = NewWriterPrinter(file, false)
+			}
 
 			if err == nil {
-				printers = append(printers, NewWriterPrinter(file, false))
+				printers = append(printers, printer)
 			} else {
 				stdoutLogger.Printf(T("CF_TRACE ERROR CREATING LOG FILE {{.Path}}:\n{{.Err}}",
 					map[string]interface{}{"Path": path, "Err": err}))
@@ -3,6 +3,7 @@ package trace_test
 import (
 	"bytes"
 	"io/ioutil"
+	"path/filepath"
 	"runtime"
 
 	. "github.com/cloudfoundry/cli/cf/trace"
@@ -201,4 +202,41 @@ var _ = Describe("NewLogger", func() {
 			Expect(stdoutContents).To(ContainSubstring("Hello World"))
 		}
 	})
+
+	Describe("HTTP Archive trace files", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "trace-har")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("writes files ending in .har as HTTP Archives", func() {
+			path := filepath.Join(dir, "trace.har")
+			logger := NewLogger(false, path, "")
+			logger.Print("Hello World")
+
+			_, ok := logger.(HTTPRecorder)
+			Expect(ok).To(BeTrue())
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(MatchRegexp(`"entries":\s*\[\s*\]`))
+			Expect(string(contents)).NotTo(ContainSubstring("Hello World"))
+		})
+
+		It("writes any file as an HTTP Archive when the format is har", func() {
+			path := filepath.Join(dir, "trace.json")
+			NewLoggerWithFormat(false, HARFormat, "", path)
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(MatchRegexp(`"entries":\s*\[\s*\]`))
+		})
+	})
 })
@@ -0,0 +1,51 @@
+/**
+	* A plugin that hooks the core version command, and the alias command
+	* after it ran. The pre hook vetoes the command when HOOKS_VETO is set.
+**/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cloudfoundry/cli/plugin"
+	"github.com/cloudfoundry/cli/plugin/models"
+)
+
+type Hooks struct {
+}
+
+func (c *Hooks) Run(cliConnection plugin.CliConnection, args []string) {
+}
+
+func (c *Hooks) RunHook(cliConnection plugin.CliConnection, hook plugin_models.HookContext) error {
+	if hook.Stage == string(plugin.PreHook) && os.Getenv("HOOKS_VETO") != "" {
+		return errors.New(os.Getenv("HOOKS_VETO"))
+	}
+
+	if hook.Failed {
+		fmt.Printf("%s-hook of failed %s\n", hook.Stage, hook.Command)
+		return nil
+	}
+
+	fmt.Printf("%s-hook of %s\n", hook.Stage, hook.Command)
+	return nil
+}
+
+func (c *Hooks) GetMetadata() plugin.PluginMetadata {
+	return plugin.PluginMetadata{
+		Name:     "Hooks",
+		Commands: []plugin.Command{},
+		Hooks: []plugin.Hook{
+			{Stage: plugin.PreHook, Command: "version"},
+			{Stage: plugin.PostHook, Command: "version"},
+			{Stage: plugin.PostHook, Command: "alias"},
+		},
+	}
+}
+
+func main() {
+	plugin.Start(new(Hooks))
+}
@@ -48,6 +48,26 @@ func init() {
 			Expect(mergedMap).To(Equal(expectedMap))
 		})
 
+		It("deep merges nulls by replacing them or the values they are merged onto", func() {
+			map1 := NewMap(map[interface{}]interface{}{
+				"key1": "val1",
+				"key2": nil,
+			})
+
+			map2 := NewMap(map[interface{}]interface{}{
+				"key1": nil,
+				"key2": []interface{}{"val2"},
+			})
+
+			expectedMap := NewMap(map[interface{}]interface{}{
+				"key1": nil,
+				"key2": []interface{}{"val2"},
+			})
+
+			mergedMap := DeepMerge(map1, map2)
+			Expect(mergedMap).To(Equal(expectedMap))
+		})
+
 		Describe("IsMappable", func() {
 			It("returns true for generic.Map", func() {
 				m := NewMap()
@@ -20,7 +20,7 @@ func DeepMerge(maps ...Map) Map {
 
 func mergeReducer(key, val interface{}, reduced Map) Map {
 	switch {
-	case reduced.Has(key) == false:
+	case reduced.Has(key) == false, val == nil, reduced.Get(key) == nil:
 		reduced.Set(key, val)
 		return reduced
 
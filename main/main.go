@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	netrpc "net/rpc"
 	"os"
 	"runtime"
 	"strings"
 
+	"github.com/cloudfoundry/cli/cf/actors/pathplugin"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/confighelpers"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
@@ -18,6 +21,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/trace"
 	"github.com/cloudfoundry/cli/commandsloader"
 	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/plugin"
 	"github.com/cloudfoundry/cli/plugin/rpc"
 )
 
@@ -27,6 +31,40 @@ func main() {
 	traceEnv := os.Getenv("CF_TRACE")
 	traceLogger := trace.NewLogger(false, traceEnv, "")
 
+	//failures before the command dependencies are set up, such as a bad
+	//--profile or config file, exit like any other failed command
+	defer handlePanics(terminal.NewTeePrinter(), traceLogger)
+
+	//handles the global `--profile NAME`; the profile is passed on through the
+	//environment so that plugins and the commands they run use it too
+	newArgs, profile, err := handleProfile(os.Args)
+	if err != nil {
+		ui := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
+		ui.Failed(T("Incorrect Usage") + "\n\n" + err.Error())
+	}
+	os.Args = newArgs
+	if profile != "" {
+		os.Setenv("CF_PROFILE", profile)
+	}
+
+	//handles the global `--trace-format FORMAT`, which writes trace files as
+	//HTTP Archives when FORMAT is har
+	newArgs, traceFormat, err := handleTraceFormat(os.Args)
+	if err != nil {
+		ui := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
+		ui.Failed(T("Incorrect Usage") + "\n\n" + err.Error())
+	}
+	os.Args = newArgs
+
+	//handles the global `--output FORMAT`, which core commands that do not
+	//have an --output flag of their own print their results in
+	newArgs, output, err := handleOutput(os.Args)
+	if err != nil {
+		ui := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
+		ui.Failed(T("Incorrect Usage") + "\n\n" + err.Error())
+	}
+	os.Args = newArgs
+
 	//handle `cf -v` for cf version
 	if len(os.Args) == 2 && (os.Args[1] == "-v" || os.Args[1] == "--version") {
 		os.Args[1] = "version"
@@ -37,13 +75,23 @@ func main() {
 		os.Args = []string{os.Args[0], "help"}
 	}
 
-	//handles `cf [COMMAND] -h ...`
-	//rearrange args to `cf help COMMAND` and let `command help` to print out usage
-	os.Args = append([]string{os.Args[0]}, handleHelp(os.Args[1:])...)
+	//the words passed to the hidden `cf __complete` are the partial command
+	//line being completed, so -h and -v in them are left alone, and nothing
+	//is traced while completing since the output goes to the shell
+	completing := os.Args[1] == "__complete"
+	if completing {
+		traceEnv = ""
+	}
 
-	newArgs, isVerbose := handleVerbose(os.Args)
-	os.Args = newArgs
-	traceLogger = trace.NewLogger(isVerbose, traceEnv, "")
+	isVerbose := false
+	if !completing {
+		//handles `cf [COMMAND] -h ...`
+		//rearrange args to `cf help COMMAND` and let `command help` to print out usage
+		os.Args = append([]string{os.Args[0]}, handleHelp(os.Args[1:])...)
+
+		os.Args, isVerbose = handleVerbose(os.Args)
+	}
+	traceLogger = trace.NewLoggerWithFormat(isVerbose, traceFormat, traceEnv, "")
 
 	errFunc := func(err error) {
 		if err != nil {
@@ -56,11 +104,20 @@ func main() {
 	config := coreconfig.NewRepositoryFromFilepath(confighelpers.DefaultFilePath(), errFunc)
 	defer config.Close()
 
-	traceConfigVal := config.Trace()
+	traceConfigVal := ""
+	if !completing {
+		traceConfigVal = config.Trace()
+	}
 
-	traceLogger = trace.NewLogger(isVerbose, traceEnv, traceConfigVal)
+	traceLogger = trace.NewLoggerWithFormat(isVerbose, traceFormat, traceEnv, traceConfigVal)
 
-	deps := commandregistry.NewDependency(traceLogger)
+	//completing must not prompt for the passphrase of stored credentials
+	var deps commandregistry.Dependency
+	if completing {
+		deps = commandregistry.NewNonInteractiveDependency(traceLogger)
+	} else {
+		deps = commandregistry.NewDependency(traceLogger)
+	}
 	defer handlePanics(deps.TeePrinter, deps.Logger)
 	defer deps.Config.Close()
 
@@ -74,15 +131,21 @@ func main() {
 		os.Exit(0)
 	}
 
-	warningProducers := []net.WarningProducer{}
-	for _, warningProducer := range deps.Gateways {
-		warningProducers = append(warningProducers, warningProducer)
-	}
-
-	warningsCollector := net.NewWarningsCollector(deps.UI, warningProducers...)
-
 	commandsloader.Load()
 
+	//handles user defined aliases, which expand before both core and plugin
+	//commands are looked up but never replace a core command
+	if !completing {
+		expanded, err := cmdRegistry.ExpandAlias(os.Args[1:], deps.Config.Aliases())
+		if err != nil {
+			deps.UI.Failed(err.Error())
+		}
+		if len(expanded) == 0 {
+			expanded = []string{"help"}
+		}
+		os.Args = append([]string{os.Args[0]}, expanded...)
+	}
+
 	//run core command
 	cmdName := os.Args[1]
 	cmd := cmdRegistry.FindCommand(cmdName)
@@ -92,12 +155,24 @@ func main() {
 		flagContext.SkipFlagParsing(meta.SkipFlagParsing)
 
 		cmdArgs := os.Args[2:]
+
+		//a command with its own --output flag gets the one given before it
+		if output != "" {
+			if _, ok := meta.Flags["output"]; ok || meta.SkipFlagParsing {
+				cmdArgs = append([]string{"--output", output}, cmdArgs...)
+			} else if outputFormat, _ := terminal.ParseOutputFormat(output); outputFormat.IsStructured() {
+				deps.UI = terminal.NewUIWithOutputFormat(os.Stdin, deps.TeePrinter, deps.Logger, outputFormat)
+			}
+		}
+
 		err := flagContext.Parse(cmdArgs...)
 		if err != nil {
 			usage := cmdRegistry.CommandUsage(cmdName)
 			deps.UI.Failed(T("Incorrect Usage") + "\n\n" + err.Error() + "\n\n" + usage)
 		}
 
+		warningsCollector := newWarningsCollector(deps)
+
 		cmd = cmd.SetDependency(deps, false)
 		cmdRegistry.SetCommand(cmd)
 
@@ -111,13 +186,42 @@ func main() {
 			}
 		}
 
+		//plugins hooking the command run before and after it
+		var rpcService *rpc.CliRpcService
+		if rpc.HasHooks(deps.PluginConfig.Plugins(), meta.Name) {
+			//the command may have registered its own rpc service already
+			netrpc.DefaultServer = netrpc.NewServer()
+
+			rpcService, err = rpc.NewRpcService(deps.TeePrinter, deps.TeePrinter, deps.Config, deps.RepoLocator, rpc.NewCommandRunner(), deps.Logger)
+			if err != nil {
+				deps.UI.Failed(T("Error initializing RPC service: ") + err.Error())
+			}
+		}
+
+		runHooks(deps, rpcService, plugin.PreHook, meta, flagContext, false)
+
+		//commands fail by panicking, post hooks then run on the way out
+		commandSucceeded := false
+		defer func() {
+			if !commandSucceeded {
+				runHooks(deps, rpcService, plugin.PostHook, meta, flagContext, true)
+			}
+		}()
+
 		cmd.Execute(flagContext)
+		commandSucceeded = true
+
+		runHooks(deps, rpcService, plugin.PostHook, meta, flagContext, false)
 
 		warningsCollector.PrintWarnings()
 
 		os.Exit(0)
 	}
 
+	if output != "" {
+		deps.UI.Failed(T("Incorrect Usage") + "\n\n" + T("The --output flag is not supported by {{.Command}}", map[string]interface{}{"Command": cmdName}))
+	}
+
 	//non core command, try plugin command
 	rpcService, err := rpc.NewRpcService(deps.TeePrinter, deps.TeePrinter, deps.Config, deps.RepoLocator, rpc.NewCommandRunner(), deps.Logger)
 	if err != nil {
@@ -128,14 +232,61 @@ func main() {
 	pluginConfig := pluginconfig.NewPluginConfig(func(err error) {
 		deps.UI.Failed(fmt.Sprintf("Error read/writing plugin config: %s, ", err.Error()))
 	})
-	pluginList := pluginConfig.Plugins()
+	ran := rpc.RunMethodIfExists(rpcService, os.Args[1:], pluginConfig)
+	if ran {
+		return
+	}
 
-	ran := rpc.RunMethodIfExists(rpcService, os.Args[1:], pluginList)
-	if !ran {
+	//neither core nor plugin command, try a cf-NAME executable on PATH
+	pathPlugin, found := pathplugin.Find(os.Args[1])
+	if !found {
 		deps.UI.Say("'" + os.Args[1] + T("' is not a registered command. See 'cf help'"))
 		os.Exit(1)
 	}
 
+	accessToken := deps.Config.AccessToken()
+	if deps.Config.IsLoggedIn() {
+		refreshedToken, err := deps.RepoLocator.GetAuthenticationRepository().RefreshAuthToken()
+		if err == nil {
+			accessToken = refreshedToken
+		}
+	}
+
+	exitStatus, err := pathplugin.Run(pathPlugin, os.Args[2:], pathplugin.Env(deps.Config, accessToken))
+	if err != nil {
+		deps.UI.Failed(T("Error running {{.Location}}: {{.Error}}", map[string]interface{}{"Location": pathPlugin.Location, "Error": err.Error()}))
+	}
+	os.Exit(exitStatus)
+}
+
+//runHooks runs the plugins hooking the command at stage; a failing pre hook
+//stops the command, failing post hooks are reported as warnings. Post hooks
+//are told whether the command failed.
+func runHooks(deps commandregistry.Dependency, rpcService *rpc.CliRpcService, stage plugin.HookStage, meta commandregistry.CommandMetadata, flagContext flags.FlagContext, commandFailed bool) {
+	if rpcService == nil {
+		return
+	}
+
+	hook := rpc.NewHookContext(stage, meta, flagContext, deps.Config)
+	hook.Failed = commandFailed
+	for _, hookErr := range rpc.RunHooks(rpcService, hook, deps.PluginConfig) {
+		if stage == plugin.PreHook {
+			deps.UI.Failed(T("Plugin {{.PluginName}} stopped the command {{.Command}}:\n{{.Error}}",
+				map[string]interface{}{"PluginName": hookErr.PluginName, "Command": meta.Name, "Error": hookErr.Err.Error()}))
+		}
+
+		deps.UI.Warn(T("The hook of plugin {{.PluginName}} after the command {{.Command}} failed: {{.Error}}",
+			map[string]interface{}{"PluginName": hookErr.PluginName, "Command": meta.Name, "Error": hookErr.Err.Error()}))
+	}
+}
+
+func newWarningsCollector(deps commandregistry.Dependency) net.WarningsCollector {
+	warningProducers := []net.WarningProducer{}
+	for _, warningProducer := range deps.Gateways {
+		warningProducers = append(warningProducers, warningProducer)
+	}
+
+	return net.NewWarningsCollector(deps.UI, warningProducers...)
 }
 
 func handlePanics(printer terminal.Printer, logger trace.Printer) {
@@ -187,6 +338,143 @@ func handleHelp(args []string) []string {
 	}
 }
 
+func handleOutput(args []string) ([]string, string, error) {
+	args, output, err := handleGlobalFlag(args, "output")
+	if err != nil || output == "" {
+		return args, output, err
+	}
+
+	_, err = terminal.ParseOutputFormat(output)
+	if err != nil {
+		return args, "", err
+	}
+	return args, output, nil
+}
+
+func handleProfile(args []string) ([]string, string, error) {
+	return handleGlobalFlag(args, "profile")
+}
+
+func handleTraceFormat(args []string) ([]string, string, error) {
+	args, format, err := handleGlobalFlag(args, "trace-format")
+	if err != nil {
+		return args, "", err
+	}
+
+	switch format {
+	case "", "text", trace.HARFormat:
+		return args, format, nil
+	default:
+		return args, "", errors.New(T("Invalid value for flag: --trace-format {{.Format}}. Use 'text' or 'har'.", map[string]interface{}{"Format": format}))
+	}
+}
+
+//globalFlagsWithValues are the global flags handled before the command is
+//run that take a value
+var globalFlagsWithValues = []string{"--profile", "--trace-format", "--output"}
+
+//handleGlobalFlag removes `--NAME VALUE` or `--NAME=VALUE` from args and
+//returns its value. The flag is taken from before the command name, or from
+//the arguments of a core command that does not have a flag of the same name,
+//up to a `--`. The arguments of plugin commands and of executables on PATH
+//are left alone, as those may define the flag themselves.
+func handleGlobalFlag(args []string, name string) ([]string, string, error) {
+	flag := "--" + name
+
+	i := 1
+	for ; i < len(args) && strings.HasPrefix(args[i], "-"); i++ {
+		if newArgs, value, found, err := removeFlag(args, i, flag); found || err != nil {
+			return newArgs, value, err
+		}
+
+		if isGlobalFlagWithValue(args[i]) {
+			i++
+		}
+	}
+
+	if i >= len(args) {
+		return args, "", nil
+	}
+
+	//the words completed by `cf __complete` are a command line of their own
+	if args[i] == "__complete" {
+		words, value, err := handleGlobalFlag(args[i:], name)
+		return append(args[:i:i], words...), value, err
+	}
+
+	if !takesGlobalFlag(args[i], name) {
+		return args, "", nil
+	}
+
+	for i++; i < len(args) && args[i] != "--"; i++ {
+		if newArgs, value, found, err := removeFlag(args, i, flag); found || err != nil {
+			return newArgs, value, err
+		}
+	}
+
+	return args, "", nil
+}
+
+//removeFlag removes the flag at args[i], with its value, if it is there
+func removeFlag(args []string, i int, flag string) ([]string, string, bool, error) {
+	arg := args[i]
+	if arg == flag {
+		if i+1 >= len(args) || args[i+1] == "" {
+			return args, "", true, errors.New(T("No value provided for flag: {{.Flag}}", map[string]interface{}{"Flag": flag}))
+		}
+		return append(args[:i:i], args[i+2:]...), args[i+1], true, nil
+	}
+
+	if strings.HasPrefix(arg, flag+"=") {
+		value := strings.TrimPrefix(arg, flag+"=")
+		if value == "" {
+			return args, "", true, errors.New(T("No value provided for flag: {{.Flag}}", map[string]interface{}{"Flag": flag}))
+		}
+		return append(args[:i:i], args[i+1:]...), value, true, nil
+	}
+
+	return args, "", false, nil
+}
+
+func isGlobalFlagWithValue(arg string) bool {
+	for _, flag := range globalFlagsWithValues {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+//takesGlobalFlag tells whether the command, or the command a user defined
+//alias expands to, is a core command that leaves the global flag to the CLI
+func takesGlobalFlag(cmdName string, name string) bool {
+	expanded, err := cmdRegistry.ExpandAlias([]string{cmdName}, userAliases())
+	if err != nil || len(expanded) == 0 {
+		return false
+	}
+
+	cmd := cmdRegistry.FindCommand(expanded[0])
+	if cmd == nil {
+		return false
+	}
+
+	meta := cmd.MetaData()
+	if meta.SkipFlagParsing {
+		return false
+	}
+	_, hasFlag := meta.Flags[name]
+	return !hasFlag
+}
+
+//userAliases are the aliases of the profile in CF_PROFILE, read before the
+//command dependencies are set up
+func userAliases() map[string]string {
+	config := coreconfig.NewRepositoryForProfile(confighelpers.DefaultFilePath(), os.Getenv("CF_PROFILE"), nil, func(error) {})
+	defer config.Close()
+
+	return config.Aliases()
+}
+
 func handleVerbose(args []string) ([]string, bool) {
 	var verbose bool
 	idx := -1
@@ -22,6 +22,7 @@ func TestMain(t *testing.T) {
 	pluginbuilder.BuildTestBinary(filepath.Join("..", "fixtures", "plugins"), "call_core_cmd")
 	pluginbuilder.BuildTestBinary(filepath.Join("..", "fixtures", "plugins"), "input")
 	pluginbuilder.BuildTestBinary(filepath.Join("..", "fixtures", "plugins"), "panics")
+	pluginbuilder.BuildTestBinary(filepath.Join("..", "fixtures", "plugins"), "hooks")
 
 	//compile plugin examples to ensure they're up to date
 	pluginbuilder.BuildTestBinary(filepath.Join("..", "plugin_examples"), "basic_plugin")
@@ -2,11 +2,17 @@ package main_test
 
 import (
 	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"time"
 
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/credentials"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
@@ -114,6 +120,162 @@ var _ = Describe("main", func() {
 		})
 	})
 
+	Describe("Selects the output format with --output", func() {
+		It("rejects unknown formats", func() {
+			result := Cf("apps", "--output", "xml")
+			Eventually(result.Out).Should(Say("Invalid output format 'xml'"))
+			Eventually(result).Should(Exit(1))
+		})
+
+		It("takes the format from before the command", func() {
+			result := Cf("--output", "xml", "apps")
+			Eventually(result.Out).Should(Say("Invalid output format 'xml'"))
+			Eventually(result).Should(Exit(1))
+		})
+
+		It("leaves the arguments after -- to the command", func() {
+			result := Cf("apps", "--", "--output", "xml")
+			Eventually(result).Should(Exit(1))
+			Expect(result.Out).NotTo(Say("Invalid output format"))
+		})
+
+		It("fails for commands that are not core commands", func() {
+			result := Cf("--output", "json", "test_1_cmd1")
+			Eventually(result.Out).Should(Say("The --output flag is not supported by test_1_cmd1"))
+			Eventually(result).Should(Exit(1))
+		})
+	})
+
+	Describe("Selects a profile with --profile", func() {
+		var cfHome string
+
+		BeforeEach(func() {
+			var err error
+			cfHome, err = ioutil.TempDir("", "cf-home")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(cfHome, ".cf"), 0700)).To(Succeed())
+			config := `{
+				"ConfigVersion": 3,
+				"Target": "https://api.dev.example.com",
+				"Profiles": [{"Name": "staging", "Target": "https://api.staging.example.com"}],
+				"Aliases": {"where": "api"}
+			}`
+			Expect(ioutil.WriteFile(filepath.Join(cfHome, ".cf", "config.json"), []byte(config), 0600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(cfHome)
+		})
+
+		It("uses the given profile for the command", func() {
+			result := CfWith_CF_HOME(cfHome, "--profile", "staging", "api")
+			Eventually(result.Out).Should(Say("https://api.staging.example.com"))
+			Eventually(result).Should(Exit(0))
+
+			result = CfWith_CF_HOME(cfHome, "api")
+			Eventually(result.Out).Should(Say("https://api.dev.example.com"))
+			Eventually(result).Should(Exit(0))
+		})
+
+		It("takes the profile from after other global flags", func() {
+			result := CfWith_CF_HOME(cfHome, "--trace-format", "text", "--profile", "staging", "api")
+			Eventually(result.Out).Should(Say("https://api.staging.example.com"))
+			Eventually(result).Should(Exit(0))
+		})
+
+		It("takes the profile from after an alias", func() {
+			result := CfWith_CF_HOME(cfHome, "where", "--profile", "staging")
+			Eventually(result.Out).Should(Say("https://api.staging.example.com"))
+			Eventually(result).Should(Exit(0))
+		})
+
+		It("fails when the profile does not exist", func() {
+			result := CfWith_CF_HOME(cfHome, "api", "--profile=nope")
+			Eventually(result.Out).Should(Say("Profile nope does not exist"))
+			Eventually(result).Should(Exit(1))
+		})
+
+		It("fails when no profile is given", func() {
+			result := CfWith_CF_HOME(cfHome, "api", "--profile")
+			Eventually(result.Out).Should(Say("No value provided for flag: --profile"))
+			Eventually(result).Should(Exit(1))
+		})
+	})
+
+	Describe("Chooses the trace file format with --trace-format", func() {
+		var cfHome string
+
+		BeforeEach(func() {
+			var err error
+			cfHome, err = ioutil.TempDir("", "cf-home")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(cfHome, ".cf"), 0700)).To(Succeed())
+			config := fmt.Sprintf(`{"ConfigVersion": 3, "Trace": %q, "Aliases": {"where": "api"}}`, filepath.Join(cfHome, "trace.json"))
+			Expect(ioutil.WriteFile(filepath.Join(cfHome, ".cf", "config.json"), []byte(config), 0600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(cfHome)
+		})
+
+		It("writes the trace file as an HTTP Archive", func() {
+			result := CfWith_CF_HOME(cfHome, "api", "--trace-format", "har")
+			Eventually(result).Should(Exit(0))
+
+			contents, err := ioutil.ReadFile(filepath.Join(cfHome, "trace.json"))
+			Expect(err).NotTo(HaveOccurred())
+			var archive struct {
+				Log struct{ Entries []interface{} }
+			}
+			Expect(json.Unmarshal(contents, &archive)).To(Succeed())
+			Expect(archive.Log.Entries).To(BeEmpty())
+		})
+
+		It("takes the format from after an alias", func() {
+			result := CfWith_CF_HOME(cfHome, "where", "--trace-format", "xml")
+			Eventually(result.Out).Should(Say("Invalid value for flag: --trace-format xml"))
+			Eventually(result).Should(Exit(1))
+		})
+
+		It("fails when the format is unknown", func() {
+			result := CfWith_CF_HOME(cfHome, "api", "--trace-format", "xml")
+			Eventually(result.Out).Should(Say("Invalid value for flag: --trace-format xml"))
+			Eventually(result).Should(Exit(1))
+		})
+	})
+
+	Describe("Completes commands with __complete", func() {
+		var cfHome string
+
+		BeforeEach(func() {
+			var err error
+			cfHome, err = ioutil.TempDir("", "cf-home")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(cfHome, ".cf"), 0700)).To(Succeed())
+			config := `{"ConfigVersion": 3, "Target": "https://api.example.com", "CredentialHelper": "encrypted"}`
+			Expect(ioutil.WriteFile(filepath.Join(cfHome, ".cf", "config.json"), []byte(config), 0600)).To(Succeed())
+
+			helper := credentials.NewEncryptedHelper(filepath.Join(cfHome, ".cf", "credentials.json"), func() (string, error) {
+				return "secret", nil
+			})
+			Expect(helper.Store(coreconfig.DefaultProfileName, credentials.Tokens{AccessToken: "bearer some-token"})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(cfHome)
+		})
+
+		It("does not ask for the passphrase of stored credentials", func() {
+			result := CfWith_CF_HOME(cfHome, "__complete", "target", "-o", "")
+			Eventually(result).Should(Exit(0))
+			Expect(result.Out).NotTo(Say("Passphrase"))
+			Expect(result.Out).NotTo(Say("FAILED"))
+		})
+	})
+
 	Describe("Shows debug information with -b or --build", func() {
 		It("prints the golang version if '--build' flag is provided", func() {
 			output := Cf("--build").Wait(1 * time.Second)
@@ -256,6 +418,88 @@ var _ = Describe("main", func() {
 			session := Cf("exit1").Wait(5 * time.Second)
 			Eventually(session).Should(Exit(1))
 		})
+		Describe("cf-NAME executables on PATH", func() {
+			var (
+				pathDir string
+				oldPath string
+			)
+
+			BeforeEach(func() {
+				var err error
+				pathDir, err = ioutil.TempDir("", "main-path-plugins")
+				Expect(err).NotTo(HaveOccurred())
+
+				script := "#!/bin/sh\necho \"hello $1 from $CF_API_ENDPOINT\"\nexit 4\n"
+				Expect(ioutil.WriteFile(filepath.Join(pathDir, "cf-hello"), []byte(script), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(pathDir, "cf-version"), []byte(script), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(pathDir, "cf-args"), []byte("#!/bin/sh\necho \"args: $*\"\n"), 0755)).To(Succeed())
+
+				oldPath = os.Getenv("PATH")
+				os.Setenv("PATH", pathDir+string(os.PathListSeparator)+oldPath)
+			})
+
+			AfterEach(func() {
+				os.Setenv("PATH", oldPath)
+				os.RemoveAll(pathDir)
+			})
+
+			It("runs the executable with the arguments and exits with its exit status", func() {
+				session := Cf("hello", "world").Wait(5 * time.Second)
+				Eventually(session).Should(Exit(4))
+				Expect(session.Out).To(Say("hello world from"))
+			})
+
+			It("leaves global flags in the arguments to the executable", func() {
+				session := Cf("args", "--profile", "mine", "--trace-format=har").Wait(5 * time.Second)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("args: --profile mine --trace-format=har"))
+			})
+
+			It("does not run the executable when a core command has the same name", func() {
+				session := Cf("version").Wait(5 * time.Second)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).NotTo(Say("hello"))
+			})
+		})
+
+		Describe("hooks", func() {
+			AfterEach(func() {
+				os.Unsetenv("HOOKS_VETO")
+			})
+
+			It("runs the plugins hooking a core command before and after it", func() {
+				session := Cf("version").Wait(5 * time.Second)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("pre-hook of version"))
+				Expect(session.Out).To(Say("version"))
+				Expect(session.Out).To(Say("post-hook of version"))
+			})
+
+			It("does not run the command when a pre hook fails", func() {
+				os.Setenv("HOOKS_VETO", "no versions today")
+
+				session := Cf("version").Wait(5 * time.Second)
+				Eventually(session).Should(Exit(1))
+				Expect(session.Out).To(Say("Plugin Hooks stopped the command version:"))
+				Expect(session.Out).To(Say("no versions today"))
+				Expect(session.Out).NotTo(Say("post-hook of version"))
+			})
+
+			It("runs the hooks of core commands that plugins run", func() {
+				os.Setenv("HOOKS_VETO", "not from plugins either")
+
+				session := Cf("core-command", "version").Wait(5 * time.Second)
+				Eventually(session).Should(Exit(0))
+				Expect(session.Out).To(Say("PLUGIN ERROR: Error from CliCommand:  Plugin Hooks stopped the command version: not from plugins either"))
+			})
+
+			It("runs post hooks when the command fails", func() {
+				session := Cf("alias", "set", "broken", "apps | cat").Wait(5 * time.Second)
+				Eventually(session).Should(Exit(1))
+				Expect(session.Out).To(Say("FAILED"))
+				Expect(session.Out).To(Say("post-hook of failed alias"))
+			})
+		})
 	})
 
 })
@@ -285,7 +529,7 @@ func CfWithIo(command string, args string) *Session {
 
 func CfWith_CF_HOME(cfHome string, args ...string) *Session {
 	cmd := exec.Command(buildPath, args...)
-	cmd.Env = append(cmd.Env, "CF_HOME="+cfHome)
+	cmd.Env = append(os.Environ(), "CF_HOME="+cfHome)
 	session, err := Start(cmd, GinkgoWriter, GinkgoWriter)
 	Expect(err).NotTo(HaveOccurred())
 
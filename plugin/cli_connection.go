@@ -9,23 +9,44 @@ import (
 	"time"
 
 	"github.com/cloudfoundry/cli/plugin/models"
+	"github.com/cloudfoundry/cli/plugin/rpcauth"
 )
 
 type cliConnection struct {
-	cliServerPort string
+	network string
+	address string
+	secret  string
 }
 
 func NewCliConnection(cliServerPort string) *cliConnection {
 	return &cliConnection{
-		cliServerPort: cliServerPort,
+		network: "tcp",
+		address: "127.0.0.1:" + cliServerPort,
+	}
+}
+
+// newAuthenticatedCliConnection connects to the CLI at the address it passed
+// in the environment, sending the secret it passed with every call.
+func newAuthenticatedCliConnection(network string, address string, secret string) *cliConnection {
+	return &cliConnection{
+		network: network,
+		address: address,
+		secret:  secret,
 	}
 }
 
 func (c *cliConnection) withClientDo(f func(client *rpc.Client) error) error {
-	client, err := rpc.Dial("tcp", "127.0.0.1:"+c.cliServerPort)
+	conn, err := net.Dial(c.network, c.address)
 	if err != nil {
 		return err
 	}
+
+	var client *rpc.Client
+	if c.secret == "" {
+		client = rpc.NewClient(conn)
+	} else {
+		client = rpc.NewClientWithCodec(rpcauth.NewClientCodec(conn, c.secret))
+	}
 	defer client.Close()
 
 	return f(client)
@@ -50,6 +71,38 @@ func (c *cliConnection) sendPluginMetadataToCliServer(metadata PluginMetadata) {
 	os.Exit(0)
 }
 
+func (c *cliConnection) runHook(cmd Plugin) {
+	var hook plugin_models.HookContext
+
+	err := c.withClientDo(func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetHookContext", "", &hook)
+	})
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var result plugin_models.HookResult
+	if hookPlugin, ok := cmd.(HookPlugin); ok {
+		if hookErr := hookPlugin.RunHook(c, hook); hookErr != nil {
+			result = plugin_models.HookResult{Failed: true, Message: hookErr.Error()}
+		}
+	}
+
+	var success bool
+	err = c.withClientDo(func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.SetHookResult", result, &success)
+	})
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
 func (c *cliConnection) isMinCliVersion(version string) bool {
 	var result bool
 
@@ -109,12 +162,58 @@ func (c *cliConnection) callCliCommand(silently bool, args ...string) ([]string,
 	return cmdOutput, nil
 }
 
+// CliCommandStream runs a command without printing its output to the
+// terminal, handing each line to output as soon as the command prints it.
+// It returns once the command is done or was cancelled with CancelCliCommand.
+func (c *cliConnection) CliCommandStream(output func(line string), args ...string) error {
+	return c.withClientForVersionDo(3, "CliCommandStream", func(client *rpc.Client) error {
+		var started bool
+
+		err := client.Call("CliRpcCmd.StartCoreCommandStream", args, &started)
+		if err != nil {
+			return err
+		}
+
+		if !started {
+			return errors.New("Error executing cli core command")
+		}
+
+		for {
+			var result plugin_models.CoreCommandOutput
+
+			err = client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &result)
+			if err != nil {
+				return err
+			}
+
+			for _, line := range result.Lines {
+				output(line)
+			}
+
+			if result.Done {
+				return nil
+			}
+		}
+	})
+}
+
+// CancelCliCommand makes the running CliCommandStream return with an error.
+// It can be called from another goroutine or from the output function. The
+// command keeps running in the CLI, and another command can only be streamed
+// once it has returned.
+func (c *cliConnection) CancelCliCommand() error {
+	return c.withClientForVersionDo(3, "CancelCliCommand", func(client *rpc.Client) error {
+		var cancelled bool
+		return client.Call("CliRpcCmd.CancelCoreCommandStream", true, &cancelled)
+	})
+}
+
 func (c *cliConnection) pingCLI() {
 	//call back to cf saying we have been setup
 	var connErr error
 	var conn net.Conn
 	for i := 0; i < 5; i++ {
-		conn, connErr = net.Dial("tcp", "127.0.0.1:"+c.cliServerPort)
+		conn, connErr = net.Dial(c.network, c.address)
 		if connErr != nil {
 			time.Sleep(200 * time.Millisecond)
 		} else {
@@ -381,3 +480,117 @@ func (c *cliConnection) GetService(serviceInstance string) (plugin_models.GetSer
 
 	return result, err
 }
+
+// withClientForVersionDo is withClientDo for the methods added in a version
+// of the RPC API, failing with an error when the CLI running the plugin is too
+// old to offer them.
+func (c *cliConnection) withClientForVersionDo(version int, method string, f func(client *rpc.Client) error) error {
+	return c.withClientDo(func(client *rpc.Client) error {
+		var cliVersion int
+
+		err := client.Call("CliRpcCmd.RpcApiVersion", "", &cliVersion)
+		if _, ok := err.(rpc.ServerError); ok {
+			// CLIs from before the RPC API was versioned do not know the method
+			cliVersion = 1
+		} else if err != nil {
+			return err
+		}
+
+		if cliVersion < version {
+			return fmt.Errorf("%s requires a newer version of the cf CLI (plugin RPC API version %d, this CLI offers version %d)", method, version, cliVersion)
+		}
+
+		return f(client)
+	})
+}
+
+func (c *cliConnection) GetRoutes() ([]plugin_models.GetRoutes_Model, error) {
+	var result []plugin_models.GetRoutes_Model
+
+	err := c.withClientForVersionDo(2, "GetRoutes", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetRoutes", "", &result)
+	})
+
+	return result, err
+}
+
+func (c *cliConnection) GetDomains() ([]plugin_models.GetDomains_Model, error) {
+	var result []plugin_models.GetDomains_Model
+
+	err := c.withClientForVersionDo(2, "GetDomains", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetDomains", "", &result)
+	})
+
+	return result, err
+}
+
+func (c *cliConnection) GetServiceKeys(serviceInstance string) ([]plugin_models.GetServiceKeys_Model, error) {
+	var result []plugin_models.GetServiceKeys_Model
+
+	err := c.withClientForVersionDo(2, "GetServiceKeys", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetServiceKeys", serviceInstance, &result)
+	})
+
+	return result, err
+}
+
+func (c *cliConnection) GetServiceBindings(serviceInstance string) ([]plugin_models.GetServiceBindings_Model, error) {
+	var result []plugin_models.GetServiceBindings_Model
+
+	err := c.withClientForVersionDo(2, "GetServiceBindings", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetServiceBindings", serviceInstance, &result)
+	})
+
+	return result, err
+}
+
+func (c *cliConnection) GetAppEvents(appName string) ([]plugin_models.GetAppEvents_Model, error) {
+	var result []plugin_models.GetAppEvents_Model
+
+	err := c.withClientForVersionDo(2, "GetAppEvents", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetAppEvents", appName, &result)
+	})
+
+	return result, err
+}
+
+func (c *cliConnection) GetSecurityGroups() ([]plugin_models.GetSecurityGroups_Model, error) {
+	var result []plugin_models.GetSecurityGroups_Model
+
+	err := c.withClientForVersionDo(2, "GetSecurityGroups", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetSecurityGroups", "", &result)
+	})
+
+	return result, err
+}
+
+func (c *cliConnection) GetQuotas() ([]plugin_models.GetQuotas_Model, error) {
+	var result []plugin_models.GetQuotas_Model
+
+	err := c.withClientForVersionDo(2, "GetQuotas", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetQuotas", "", &result)
+	})
+
+	return result, err
+}
+
+func (c *cliConnection) GetStacks() ([]plugin_models.GetStacks_Model, error) {
+	var result []plugin_models.GetStacks_Model
+
+	err := c.withClientForVersionDo(2, "GetStacks", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.GetStacks", "", &result)
+	})
+
+	return result, err
+}
+
+func (c *cliConnection) CCRequest(method string, path string, body string) (plugin_models.CCResponse, error) {
+	var result plugin_models.CCResponse
+
+	args := plugin_models.CCRequest_Args{Method: method, Path: path, Body: body}
+	err := c.withClientForVersionDo(2, "CCRequest", func(client *rpc.Client) error {
+		return client.Call("CliRpcCmd.CCRequest", args, &result)
+	})
+
+	return result, err
+}
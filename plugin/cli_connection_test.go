@@ -0,0 +1,153 @@
+package plugin_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/cli/plugin"
+	"github.com/cloudfoundry/cli/plugin/models"
+	"github.com/cloudfoundry/cli/testhelpers/rpcserver"
+	"github.com/cloudfoundry/cli/testhelpers/rpcserver/rpcserverfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CliConnection", func() {
+	var (
+		err         error
+		ts          *rpcserver.TestServer
+		rpcHandlers *rpcserverfakes.FakeHandlers
+		connection  plugin.CliConnection
+	)
+
+	BeforeEach(func() {
+		rpcHandlers = new(rpcserverfakes.FakeHandlers)
+		rpcHandlers.GetStacksStub = func(_ string, retVal *[]plugin_models.GetStacks_Model) error {
+			*retVal = []plugin_models.GetStacks_Model{{Guid: "stack-guid", Name: "cflinuxfs2"}}
+			return nil
+		}
+
+		ts, err = rpcserver.NewTestRpcServer(rpcHandlers)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ts.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		connection = plugin.NewCliConnection(ts.Port())
+	})
+
+	AfterEach(func() {
+		ts.Stop()
+	})
+
+	Describe("methods added in a later version of the RPC API", func() {
+		Context("when the CLI offers the version of the method", func() {
+			BeforeEach(func() {
+				rpcHandlers.RpcApiVersionStub = func(_ string, retVal *int) error {
+					*retVal = plugin.RpcApiVersion
+					return nil
+				}
+			})
+
+			It("calls the method", func() {
+				stacks, err := connection.GetStacks()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stacks).To(Equal([]plugin_models.GetStacks_Model{{Guid: "stack-guid", Name: "cflinuxfs2"}}))
+				Expect(rpcHandlers.GetStacksCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the CLI offers an older version", func() {
+			BeforeEach(func() {
+				rpcHandlers.RpcApiVersionStub = func(_ string, retVal *int) error {
+					*retVal = 1
+					return nil
+				}
+			})
+
+			It("returns an error without calling the method", func() {
+				_, err := connection.GetStacks()
+				Expect(err).To(MatchError("GetStacks requires a newer version of the cf CLI (plugin RPC API version 2, this CLI offers version 1)"))
+				Expect(rpcHandlers.GetStacksCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the CLI does not know the version method", func() {
+			BeforeEach(func() {
+				rpcHandlers.RpcApiVersionReturns(errors.New("rpc: can't find method CliRpcCmd.RpcApiVersion"))
+			})
+
+			It("treats the CLI as offering the first version", func() {
+				_, err := connection.GetStacks()
+				Expect(err).To(MatchError(ContainSubstring("this CLI offers version 1")))
+				Expect(rpcHandlers.GetStacksCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe(".CliCommandStream", func() {
+		BeforeEach(func() {
+			rpcHandlers.RpcApiVersionStub = func(_ string, retVal *int) error {
+				*retVal = plugin.RpcApiVersion
+				return nil
+			}
+			rpcHandlers.StartCoreCommandStreamStub = func(_ []string, retVal *bool) error {
+				*retVal = true
+				return nil
+			}
+		})
+
+		It("hands the output of the command to the output function until it is done", func() {
+			outputs := []plugin_models.CoreCommandOutput{
+				{Lines: []string{"Getting apps..."}},
+				{Lines: []string{"OK", ""}},
+				{Done: true},
+			}
+			rpcHandlers.GetCoreCommandStreamOutputStub = func(_ bool, retVal *plugin_models.CoreCommandOutput) error {
+				*retVal = outputs[0]
+				outputs = outputs[1:]
+				return nil
+			}
+
+			var lines []string
+			err := connection.CliCommandStream(func(line string) {
+				lines = append(lines, line)
+			}, "apps")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lines).To(Equal([]string{"Getting apps...", "OK", ""}))
+
+			args, _ := rpcHandlers.StartCoreCommandStreamArgsForCall(0)
+			Expect(args).To(Equal([]string{"apps"}))
+		})
+
+		It("returns an error when the command does not exist", func() {
+			rpcHandlers.StartCoreCommandStreamStub = func(_ []string, retVal *bool) error {
+				*retVal = false
+				return nil
+			}
+
+			err := connection.CliCommandStream(func(string) {}, "not-a-command")
+			Expect(err).To(MatchError("Error executing cli core command"))
+			Expect(rpcHandlers.GetCoreCommandStreamOutputCallCount()).To(Equal(0))
+		})
+
+		It("returns the error of the command", func() {
+			rpcHandlers.GetCoreCommandStreamOutputReturns(errors.New("Command cancelled"))
+
+			err := connection.CliCommandStream(func(string) {}, "logs", "my-app")
+			Expect(err).To(MatchError("Command cancelled"))
+		})
+	})
+
+	Describe(".CancelCliCommand", func() {
+		It("cancels the streamed command", func() {
+			rpcHandlers.RpcApiVersionStub = func(_ string, retVal *int) error {
+				*retVal = plugin.RpcApiVersion
+				return nil
+			}
+
+			err := connection.CancelCliCommand()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rpcHandlers.CancelCoreCommandStreamCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package plugin_models
+
+type CCRequest_Args struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+type CCResponse struct {
+	StatusCode int
+	Header     map[string][]string
+	Body       string
+}
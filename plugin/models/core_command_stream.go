@@ -0,0 +1,6 @@
+package plugin_models
+
+type CoreCommandOutput struct {
+	Lines []string
+	Done  bool
+}
@@ -0,0 +1,11 @@
+package plugin_models
+
+import "time"
+
+type GetAppEvents_Model struct {
+	Guid        string
+	Name        string
+	Timestamp   time.Time
+	Description string
+	ActorName   string
+}
@@ -0,0 +1,9 @@
+package plugin_models
+
+type GetDomains_Model struct {
+	Guid                   string
+	Name                   string
+	OwningOrganizationGuid string
+	Shared                 bool
+	RouterGroupType        string
+}
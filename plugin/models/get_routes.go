@@ -0,0 +1,26 @@
+package plugin_models
+
+type GetRoutes_Model struct {
+	Guid   string
+	Host   string
+	Path   string
+	Port   int
+	Domain GetRoutes_Domain
+	Space  GetRoutes_Space
+	Apps   []GetRoutes_App
+}
+
+type GetRoutes_Domain struct {
+	Guid string
+	Name string
+}
+
+type GetRoutes_Space struct {
+	Guid string
+	Name string
+}
+
+type GetRoutes_App struct {
+	Guid string
+	Name string
+}
@@ -0,0 +1,17 @@
+package plugin_models
+
+import "encoding/gob"
+
+func init() {
+	// credentials and security group rules are decoded JSON, which nests
+	// these types inside interface values
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+type GetServiceKeys_Model struct {
+	Guid                string
+	Name                string
+	ServiceInstanceGuid string
+	Credentials         map[string]interface{}
+}
@@ -0,0 +1,7 @@
+package plugin_models
+
+type GetStacks_Model struct {
+	Guid        string
+	Name        string
+	Description string
+}
@@ -0,0 +1,18 @@
+package plugin_models
+
+type HookContext struct {
+	Stage       string
+	Command     string
+	Args        []string
+	Flags       map[string]interface{} //flags set for the command, holding bool, int, float64, string or []string values
+	Org         Organization
+	Space       Space
+	ApiEndpoint string
+	Username    string
+	Failed      bool //for post hooks, whether the command failed
+}
+
+type HookResult struct {
+	Failed  bool
+	Message string
+}
@@ -2,6 +2,17 @@ package plugin
 
 import "github.com/cloudfoundry/cli/plugin/models"
 
+/**
+	Version of the RPC API the CLI offers to plugins. Version 1 is the API up
+	to GetService; version 2 adds routes, domains, service keys, service
+	bindings, app events, security groups, quotas, stacks and CCRequest;
+	version 3 adds CliCommandStream and CancelCliCommand; version 4 adds
+	hooks. Methods only ever get added, so plugins built for an older version
+	keep working, and methods of a newer version fail with an error on older
+	CLIs.
+**/
+const RpcApiVersion = 4
+
 /**
 	Command interface needs to be implemented for a runnable plugin of `cf`
 **/
@@ -10,6 +21,17 @@ type Plugin interface {
 	GetMetadata() PluginMetadata
 }
 
+/**
+	HookPlugin needs to be implemented by plugins declaring Hooks in their
+	metadata. RunHook is called with the command being run, its flags and
+	the target. An error returned by a pre hook stops the command, and is
+	shown to the user; an error returned by a post hook is shown as a warning.
+**/
+type HookPlugin interface {
+	Plugin
+	RunHook(cliConnection CliConnection, hook plugin_models.HookContext) error
+}
+
 //go:generate counterfeiter . CliConnection
 /**
 	List of commands avaiable to CliConnection variable passed into run
@@ -17,6 +39,8 @@ type Plugin interface {
 type CliConnection interface {
 	CliCommandWithoutTerminalOutput(args ...string) ([]string, error)
 	CliCommand(args ...string) ([]string, error)
+	CliCommandStream(output func(line string), args ...string) error
+	CancelCliCommand() error
 	GetCurrentOrg() (plugin_models.Organization, error)
 	GetCurrentSpace() (plugin_models.Space, error)
 	Username() (string, error)
@@ -42,6 +66,15 @@ type CliConnection interface {
 	GetService(string) (plugin_models.GetService_Model, error)
 	GetOrg(string) (plugin_models.GetOrg_Model, error)
 	GetSpace(string) (plugin_models.GetSpace_Model, error)
+	GetRoutes() ([]plugin_models.GetRoutes_Model, error)
+	GetDomains() ([]plugin_models.GetDomains_Model, error)
+	GetServiceKeys(string) ([]plugin_models.GetServiceKeys_Model, error)
+	GetServiceBindings(string) ([]plugin_models.GetServiceBindings_Model, error)
+	GetAppEvents(string) ([]plugin_models.GetAppEvents_Model, error)
+	GetSecurityGroups() ([]plugin_models.GetSecurityGroups_Model, error)
+	GetQuotas() ([]plugin_models.GetQuotas_Model, error)
+	GetStacks() ([]plugin_models.GetStacks_Model, error)
+	CCRequest(method string, path string, body string) (plugin_models.CCResponse, error)
 }
 
 type VersionType struct {
@@ -55,6 +88,7 @@ type PluginMetadata struct {
 	Version       VersionType
 	MinCliVersion VersionType
 	Commands      []Command
+	Hooks         []Hook
 }
 
 type Usage struct {
@@ -68,3 +102,20 @@ type Command struct {
 	HelpText     string
 	UsageDetails Usage //Detail usage to be displayed in `cf help <cmd>`
 }
+
+type HookStage string
+
+const (
+	PreHook  HookStage = "pre"
+	PostHook HookStage = "post"
+)
+
+/**
+	Hook subscribes a plugin to a core command, e.g. {Stage: PreHook, Command: "push"}
+	runs the plugin before every push. Post hooks run after the command whether it
+	succeeded or failed; HookContext.Failed tells which.
+**/
+type Hook struct {
+	Stage   HookStage
+	Command string
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+
+	"github.com/cloudfoundry/cli/plugin/rpcauth"
 )
 
 /**
@@ -11,13 +13,18 @@ import (
 	* os.Args[1] port CF_CLI rpc server is running on
 	* os.Args[2] **OPTIONAL**
 		* SendMetadata - used to fetch the plugin metadata
+		* CLI-MESSAGE-HOOK - used to run a hook of a HookPlugin
+	* CLIs offering the authenticated transport also pass its address and secret in the environment,
+	* which is then used instead of the port
 **/
 func Start(cmd Plugin) {
-	cliConnection := NewCliConnection(os.Args[1])
+	cliConnection := connectToCli(os.Args[1])
 
 	cliConnection.pingCLI()
 	if isMetadataRequest(os.Args) {
 		cliConnection.sendPluginMetadataToCliServer(cmd.GetMetadata())
+	} else if isHookRequest(os.Args) {
+		cliConnection.runHook(cmd)
 	} else {
 		if version := MinCliVersionStr(cmd.GetMetadata().MinCliVersion); version != "" {
 			ok := cliConnection.isMinCliVersion(version)
@@ -31,10 +38,32 @@ func Start(cmd Plugin) {
 	}
 }
 
+func connectToCli(cliServerPort string) *cliConnection {
+	network := os.Getenv(rpcauth.NetworkEnvKey)
+	address := os.Getenv(rpcauth.AddressEnvKey)
+	secret := os.Getenv(rpcauth.SecretEnvKey)
+
+	//keep the secret from the processes the plugin starts
+	os.Unsetenv(rpcauth.NetworkEnvKey)
+	os.Unsetenv(rpcauth.AddressEnvKey)
+	os.Unsetenv(rpcauth.SecretEnvKey)
+
+	if network == "" || address == "" || secret == "" {
+		//older CLIs only offer the port
+		return NewCliConnection(cliServerPort)
+	}
+
+	return newAuthenticatedCliConnection(network, address, secret)
+}
+
 func isMetadataRequest(args []string) bool {
 	return len(args) == 3 && args[2] == "SendMetadata"
 }
 
+func isHookRequest(args []string) bool {
+	return len(args) == 3 && args[2] == "CLI-MESSAGE-HOOK"
+}
+
 func MinCliVersionStr(version VersionType) string {
 	if version.Major == 0 && version.Minor == 0 && version.Build == 0 {
 		return ""
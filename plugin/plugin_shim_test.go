@@ -1,10 +1,13 @@
 package plugin_test
 
 import (
+	"net/rpc"
 	"os/exec"
 	"path/filepath"
 
+	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/plugin"
+	cliRpc "github.com/cloudfoundry/cli/plugin/rpc"
 	"github.com/cloudfoundry/cli/testhelpers/rpcserver"
 	"github.com/cloudfoundry/cli/testhelpers/rpcserver/rpcserverfakes"
 	. "github.com/onsi/ginkgo"
@@ -75,6 +78,43 @@ var _ = Describe("Command", func() {
 		})
 	})
 
+	Describe("negotiating the transport", func() {
+		var rpcService *cliRpc.CliRpcService
+
+		BeforeEach(func() {
+			rpc.DefaultServer = rpc.NewServer()
+
+			var err error
+			rpcService, err = cliRpc.NewRpcService(nil, nil, nil, api.RepositoryLocator{}, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = rpcService.Start()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			rpcService.Stop()
+		})
+
+		It("uses the authenticated transport when the CLI offers it", func() {
+			session, err := Start(rpcService.PluginCommand(validPluginPath, "SendMetadata"), GinkgoWriter, GinkgoWriter)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(session, 2).Should(Exit(0))
+
+			Expect(rpcService.RpcCmd.PluginMetadata.Name).To(Equal("Test1"))
+			Expect(rpcService.RpcCmd.LegacyPlugin).To(BeFalse())
+		})
+
+		It("falls back to the port when the CLI only offers that", func() {
+			session, err := Start(exec.Command(validPluginPath, rpcService.Port(), "SendMetadata"), GinkgoWriter, GinkgoWriter)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(session, 2).Should(Exit(0))
+
+			Expect(rpcService.RpcCmd.PluginMetadata.Name).To(Equal("Test1"))
+			Expect(rpcService.RpcCmd.LegacyPlugin).To(BeTrue())
+		})
+	})
+
 	Describe("MinCliVersionStr", func() {
 		It("returns a string representation of VersionType{}", func() {
 			version := plugin.VersionType{
@@ -27,6 +27,21 @@ type FakeCliConnection struct {
 		result1 []string
 		result2 error
 	}
+	CliCommandStreamStub        func(output func(line string), args ...string) error
+	cliCommandStreamMutex       sync.RWMutex
+	cliCommandStreamArgsForCall []struct {
+		output func(line string)
+		args   []string
+	}
+	cliCommandStreamReturns struct {
+		result1 error
+	}
+	CancelCliCommandStub        func() error
+	cancelCliCommandMutex       sync.RWMutex
+	cancelCliCommandArgsForCall []struct{}
+	cancelCliCommandReturns     struct {
+		result1 error
+	}
 	GetCurrentOrgStub        func() (plugin_models.Organization, error)
 	getCurrentOrgMutex       sync.RWMutex
 	getCurrentOrgArgsForCall []struct{}
@@ -216,6 +231,79 @@ type FakeCliConnection struct {
 		result1 plugin_models.GetSpace_Model
 		result2 error
 	}
+	GetRoutesStub        func() ([]plugin_models.GetRoutes_Model, error)
+	getRoutesMutex       sync.RWMutex
+	getRoutesArgsForCall []struct{}
+	getRoutesReturns     struct {
+		result1 []plugin_models.GetRoutes_Model
+		result2 error
+	}
+	GetDomainsStub        func() ([]plugin_models.GetDomains_Model, error)
+	getDomainsMutex       sync.RWMutex
+	getDomainsArgsForCall []struct{}
+	getDomainsReturns     struct {
+		result1 []plugin_models.GetDomains_Model
+		result2 error
+	}
+	GetServiceKeysStub        func(string) ([]plugin_models.GetServiceKeys_Model, error)
+	getServiceKeysMutex       sync.RWMutex
+	getServiceKeysArgsForCall []struct {
+		arg1 string
+	}
+	getServiceKeysReturns struct {
+		result1 []plugin_models.GetServiceKeys_Model
+		result2 error
+	}
+	GetServiceBindingsStub        func(string) ([]plugin_models.GetServiceBindings_Model, error)
+	getServiceBindingsMutex       sync.RWMutex
+	getServiceBindingsArgsForCall []struct {
+		arg1 string
+	}
+	getServiceBindingsReturns struct {
+		result1 []plugin_models.GetServiceBindings_Model
+		result2 error
+	}
+	GetAppEventsStub        func(string) ([]plugin_models.GetAppEvents_Model, error)
+	getAppEventsMutex       sync.RWMutex
+	getAppEventsArgsForCall []struct {
+		arg1 string
+	}
+	getAppEventsReturns struct {
+		result1 []plugin_models.GetAppEvents_Model
+		result2 error
+	}
+	GetSecurityGroupsStub        func() ([]plugin_models.GetSecurityGroups_Model, error)
+	getSecurityGroupsMutex       sync.RWMutex
+	getSecurityGroupsArgsForCall []struct{}
+	getSecurityGroupsReturns     struct {
+		result1 []plugin_models.GetSecurityGroups_Model
+		result2 error
+	}
+	GetQuotasStub        func() ([]plugin_models.GetQuotas_Model, error)
+	getQuotasMutex       sync.RWMutex
+	getQuotasArgsForCall []struct{}
+	getQuotasReturns     struct {
+		result1 []plugin_models.GetQuotas_Model
+		result2 error
+	}
+	GetStacksStub        func() ([]plugin_models.GetStacks_Model, error)
+	getStacksMutex       sync.RWMutex
+	getStacksArgsForCall []struct{}
+	getStacksReturns     struct {
+		result1 []plugin_models.GetStacks_Model
+		result2 error
+	}
+	CCRequestStub        func(method string, path string, body string) (plugin_models.CCResponse, error)
+	cCRequestMutex       sync.RWMutex
+	cCRequestArgsForCall []struct {
+		method string
+		path   string
+		body   string
+	}
+	cCRequestReturns struct {
+		result1 plugin_models.CCResponse
+		result2 error
+	}
 }
 
 func (fake *FakeCliConnection) CliCommandWithoutTerminalOutput(args ...string) ([]string, error) {
@@ -284,6 +372,63 @@ func (fake *FakeCliConnection) CliCommandReturns(result1 []string, result2 error
 	}{result1, result2}
 }
 
+func (fake *FakeCliConnection) CliCommandStream(output func(line string), args ...string) error {
+	fake.cliCommandStreamMutex.Lock()
+	fake.cliCommandStreamArgsForCall = append(fake.cliCommandStreamArgsForCall, struct {
+		output func(line string)
+		args   []string
+	}{output, args})
+	fake.cliCommandStreamMutex.Unlock()
+	if fake.CliCommandStreamStub != nil {
+		return fake.CliCommandStreamStub(output, args...)
+	} else {
+		return fake.cliCommandStreamReturns.result1
+	}
+}
+
+func (fake *FakeCliConnection) CliCommandStreamCallCount() int {
+	fake.cliCommandStreamMutex.RLock()
+	defer fake.cliCommandStreamMutex.RUnlock()
+	return len(fake.cliCommandStreamArgsForCall)
+}
+
+func (fake *FakeCliConnection) CliCommandStreamArgsForCall(i int) (func(line string), []string) {
+	fake.cliCommandStreamMutex.RLock()
+	defer fake.cliCommandStreamMutex.RUnlock()
+	return fake.cliCommandStreamArgsForCall[i].output, fake.cliCommandStreamArgsForCall[i].args
+}
+
+func (fake *FakeCliConnection) CliCommandStreamReturns(result1 error) {
+	fake.CliCommandStreamStub = nil
+	fake.cliCommandStreamReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCliConnection) CancelCliCommand() error {
+	fake.cancelCliCommandMutex.Lock()
+	fake.cancelCliCommandArgsForCall = append(fake.cancelCliCommandArgsForCall, struct{}{})
+	fake.cancelCliCommandMutex.Unlock()
+	if fake.CancelCliCommandStub != nil {
+		return fake.CancelCliCommandStub()
+	} else {
+		return fake.cancelCliCommandReturns.result1
+	}
+}
+
+func (fake *FakeCliConnection) CancelCliCommandCallCount() int {
+	fake.cancelCliCommandMutex.RLock()
+	defer fake.cancelCliCommandMutex.RUnlock()
+	return len(fake.cancelCliCommandArgsForCall)
+}
+
+func (fake *FakeCliConnection) CancelCliCommandReturns(result1 error) {
+	fake.CancelCliCommandStub = nil
+	fake.cancelCliCommandReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeCliConnection) GetCurrentOrg() (plugin_models.Organization, error) {
 	fake.getCurrentOrgMutex.Lock()
 	fake.getCurrentOrgArgsForCall = append(fake.getCurrentOrgArgsForCall, struct{}{})
@@ -959,4 +1104,263 @@ func (fake *FakeCliConnection) GetSpaceReturns(result1 plugin_models.GetSpace_Mo
 	}{result1, result2}
 }
 
+func (fake *FakeCliConnection) GetRoutes() ([]plugin_models.GetRoutes_Model, error) {
+	fake.getRoutesMutex.Lock()
+	fake.getRoutesArgsForCall = append(fake.getRoutesArgsForCall, struct{}{})
+	fake.getRoutesMutex.Unlock()
+	if fake.GetRoutesStub != nil {
+		return fake.GetRoutesStub()
+	} else {
+		return fake.getRoutesReturns.result1, fake.getRoutesReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) GetRoutesCallCount() int {
+	fake.getRoutesMutex.RLock()
+	defer fake.getRoutesMutex.RUnlock()
+	return len(fake.getRoutesArgsForCall)
+}
+
+func (fake *FakeCliConnection) GetRoutesReturns(result1 []plugin_models.GetRoutes_Model, result2 error) {
+	fake.GetRoutesStub = nil
+	fake.getRoutesReturns = struct {
+		result1 []plugin_models.GetRoutes_Model
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCliConnection) GetDomains() ([]plugin_models.GetDomains_Model, error) {
+	fake.getDomainsMutex.Lock()
+	fake.getDomainsArgsForCall = append(fake.getDomainsArgsForCall, struct{}{})
+	fake.getDomainsMutex.Unlock()
+	if fake.GetDomainsStub != nil {
+		return fake.GetDomainsStub()
+	} else {
+		return fake.getDomainsReturns.result1, fake.getDomainsReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) GetDomainsCallCount() int {
+	fake.getDomainsMutex.RLock()
+	defer fake.getDomainsMutex.RUnlock()
+	return len(fake.getDomainsArgsForCall)
+}
+
+func (fake *FakeCliConnection) GetDomainsReturns(result1 []plugin_models.GetDomains_Model, result2 error) {
+	fake.GetDomainsStub = nil
+	fake.getDomainsReturns = struct {
+		result1 []plugin_models.GetDomains_Model
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCliConnection) GetServiceKeys(arg1 string) ([]plugin_models.GetServiceKeys_Model, error) {
+	fake.getServiceKeysMutex.Lock()
+	fake.getServiceKeysArgsForCall = append(fake.getServiceKeysArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getServiceKeysMutex.Unlock()
+	if fake.GetServiceKeysStub != nil {
+		return fake.GetServiceKeysStub(arg1)
+	} else {
+		return fake.getServiceKeysReturns.result1, fake.getServiceKeysReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) GetServiceKeysCallCount() int {
+	fake.getServiceKeysMutex.RLock()
+	defer fake.getServiceKeysMutex.RUnlock()
+	return len(fake.getServiceKeysArgsForCall)
+}
+
+func (fake *FakeCliConnection) GetServiceKeysArgsForCall(i int) string {
+	fake.getServiceKeysMutex.RLock()
+	defer fake.getServiceKeysMutex.RUnlock()
+	return fake.getServiceKeysArgsForCall[i].arg1
+}
+
+func (fake *FakeCliConnection) GetServiceKeysReturns(result1 []plugin_models.GetServiceKeys_Model, result2 error) {
+	fake.GetServiceKeysStub = nil
+	fake.getServiceKeysReturns = struct {
+		result1 []plugin_models.GetServiceKeys_Model
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCliConnection) GetServiceBindings(arg1 string) ([]plugin_models.GetServiceBindings_Model, error) {
+	fake.getServiceBindingsMutex.Lock()
+	fake.getServiceBindingsArgsForCall = append(fake.getServiceBindingsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getServiceBindingsMutex.Unlock()
+	if fake.GetServiceBindingsStub != nil {
+		return fake.GetServiceBindingsStub(arg1)
+	} else {
+		return fake.getServiceBindingsReturns.result1, fake.getServiceBindingsReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) GetServiceBindingsCallCount() int {
+	fake.getServiceBindingsMutex.RLock()
+	defer fake.getServiceBindingsMutex.RUnlock()
+	return len(fake.getServiceBindingsArgsForCall)
+}
+
+func (fake *FakeCliConnection) GetServiceBindingsArgsForCall(i int) string {
+	fake.getServiceBindingsMutex.RLock()
+	defer fake.getServiceBindingsMutex.RUnlock()
+	return fake.getServiceBindingsArgsForCall[i].arg1
+}
+
+func (fake *FakeCliConnection) GetServiceBindingsReturns(result1 []plugin_models.GetServiceBindings_Model, result2 error) {
+	fake.GetServiceBindingsStub = nil
+	fake.getServiceBindingsReturns = struct {
+		result1 []plugin_models.GetServiceBindings_Model
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCliConnection) GetAppEvents(arg1 string) ([]plugin_models.GetAppEvents_Model, error) {
+	fake.getAppEventsMutex.Lock()
+	fake.getAppEventsArgsForCall = append(fake.getAppEventsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppEventsMutex.Unlock()
+	if fake.GetAppEventsStub != nil {
+		return fake.GetAppEventsStub(arg1)
+	} else {
+		return fake.getAppEventsReturns.result1, fake.getAppEventsReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) GetAppEventsCallCount() int {
+	fake.getAppEventsMutex.RLock()
+	defer fake.getAppEventsMutex.RUnlock()
+	return len(fake.getAppEventsArgsForCall)
+}
+
+func (fake *FakeCliConnection) GetAppEventsArgsForCall(i int) string {
+	fake.getAppEventsMutex.RLock()
+	defer fake.getAppEventsMutex.RUnlock()
+	return fake.getAppEventsArgsForCall[i].arg1
+}
+
+func (fake *FakeCliConnection) GetAppEventsReturns(result1 []plugin_models.GetAppEvents_Model, result2 error) {
+	fake.GetAppEventsStub = nil
+	fake.getAppEventsReturns = struct {
+		result1 []plugin_models.GetAppEvents_Model
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCliConnection) GetSecurityGroups() ([]plugin_models.GetSecurityGroups_Model, error) {
+	fake.getSecurityGroupsMutex.Lock()
+	fake.getSecurityGroupsArgsForCall = append(fake.getSecurityGroupsArgsForCall, struct{}{})
+	fake.getSecurityGroupsMutex.Unlock()
+	if fake.GetSecurityGroupsStub != nil {
+		return fake.GetSecurityGroupsStub()
+	} else {
+		return fake.getSecurityGroupsReturns.result1, fake.getSecurityGroupsReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) GetSecurityGroupsCallCount() int {
+	fake.getSecurityGroupsMutex.RLock()
+	defer fake.getSecurityGroupsMutex.RUnlock()
+	return len(fake.getSecurityGroupsArgsForCall)
+}
+
+func (fake *FakeCliConnection) GetSecurityGroupsReturns(result1 []plugin_models.GetSecurityGroups_Model, result2 error) {
+	fake.GetSecurityGroupsStub = nil
+	fake.getSecurityGroupsReturns = struct {
+		result1 []plugin_models.GetSecurityGroups_Model
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCliConnection) GetQuotas() ([]plugin_models.GetQuotas_Model, error) {
+	fake.getQuotasMutex.Lock()
+	fake.getQuotasArgsForCall = append(fake.getQuotasArgsForCall, struct{}{})
+	fake.getQuotasMutex.Unlock()
+	if fake.GetQuotasStub != nil {
+		return fake.GetQuotasStub()
+	} else {
+		return fake.getQuotasReturns.result1, fake.getQuotasReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) GetQuotasCallCount() int {
+	fake.getQuotasMutex.RLock()
+	defer fake.getQuotasMutex.RUnlock()
+	return len(fake.getQuotasArgsForCall)
+}
+
+func (fake *FakeCliConnection) GetQuotasReturns(result1 []plugin_models.GetQuotas_Model, result2 error) {
+	fake.GetQuotasStub = nil
+	fake.getQuotasReturns = struct {
+		result1 []plugin_models.GetQuotas_Model
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCliConnection) GetStacks() ([]plugin_models.GetStacks_Model, error) {
+	fake.getStacksMutex.Lock()
+	fake.getStacksArgsForCall = append(fake.getStacksArgsForCall, struct{}{})
+	fake.getStacksMutex.Unlock()
+	if fake.GetStacksStub != nil {
+		return fake.GetStacksStub()
+	} else {
+		return fake.getStacksReturns.result1, fake.getStacksReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) GetStacksCallCount() int {
+	fake.getStacksMutex.RLock()
+	defer fake.getStacksMutex.RUnlock()
+	return len(fake.getStacksArgsForCall)
+}
+
+func (fake *FakeCliConnection) GetStacksReturns(result1 []plugin_models.GetStacks_Model, result2 error) {
+	fake.GetStacksStub = nil
+	fake.getStacksReturns = struct {
+		result1 []plugin_models.GetStacks_Model
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCliConnection) CCRequest(method string, path string, body string) (plugin_models.CCResponse, error) {
+	fake.cCRequestMutex.Lock()
+	fake.cCRequestArgsForCall = append(fake.cCRequestArgsForCall, struct {
+		method string
+		path   string
+		body   string
+	}{method, path, body})
+	fake.cCRequestMutex.Unlock()
+	if fake.CCRequestStub != nil {
+		return fake.CCRequestStub(method, path, body)
+	} else {
+		return fake.cCRequestReturns.result1, fake.cCRequestReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) CCRequestCallCount() int {
+	fake.cCRequestMutex.RLock()
+	defer fake.cCRequestMutex.RUnlock()
+	return len(fake.cCRequestArgsForCall)
+}
+
+func (fake *FakeCliConnection) CCRequestArgsForCall(i int) (string, string, string) {
+	fake.cCRequestMutex.RLock()
+	defer fake.cCRequestMutex.RUnlock()
+	return fake.cCRequestArgsForCall[i].method, fake.cCRequestArgsForCall[i].path, fake.cCRequestArgsForCall[i].body
+}
+
+func (fake *FakeCliConnection) CCRequestReturns(result1 plugin_models.CCResponse, result2 error) {
+	fake.CCRequestStub = nil
+	fake.cCRequestReturns = struct {
+		result1 plugin_models.CCResponse
+		result2 error
+	}{result1, result2}
+}
+
 var _ plugin.CliConnection = new(FakeCliConnection)
@@ -1,16 +1,27 @@
 package rpc
 
 import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/blang/semver"
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/flags"
 	"github.com/cloudfoundry/cli/plugin"
 	"github.com/cloudfoundry/cli/plugin/models"
+	"github.com/cloudfoundry/cli/plugin/rpcauth"
 
 	"fmt"
 	"net"
@@ -24,14 +35,25 @@ import (
 )
 
 type CliRpcService struct {
-	listener net.Listener
-	stopCh   chan struct{}
-	Pinged   bool
-	RpcCmd   *CliRpcCmd
+	listener        net.Listener
+	legacyListener  net.Listener
+	socketDir       string
+	secret          string
+	server          *rpc.Server
+	handshakeServer *rpc.Server
+	stopCh          chan struct{}
+	Pinged          bool
+	RpcCmd          *CliRpcCmd
 }
 
 type CliRpcCmd struct {
-	PluginMetadata       *plugin.PluginMetadata
+	PluginMetadata *plugin.PluginMetadata
+	// LegacyPlugin is set when the plugin sent its metadata over the TCP port,
+	// as plugins built before the authenticated transport do, and not over the
+	// authenticated transport
+	LegacyPlugin         bool
+	metadataMutex        sync.Mutex
+	authenticatedPlugin  bool
 	outputCapture        OutputCapture
 	terminalOutputSwitch TerminalOutputSwitch
 	cliConfig            coreconfig.Repository
@@ -39,6 +61,12 @@ type CliRpcCmd struct {
 	newCmdRunner         CommandRunner
 	outputBucket         *bytes.Buffer
 	logger               trace.Printer
+	streamMutex          sync.Mutex
+	stream               *coreCommandStream
+	hookMutex            sync.Mutex
+	servingHook          bool
+	hookContext          plugin_models.HookContext
+	hookResult           *plugin_models.HookResult
 }
 
 //go:generate counterfeiter . TerminalOutputSwitch
@@ -60,6 +88,20 @@ func NewRpcService(
 	repoLocator api.RepositoryLocator,
 	newCmdRunner CommandRunner,
 	logger trace.Printer,
+) (*CliRpcService, error) {
+	return newRpcService(rpc.DefaultServer, outputCapture, terminalOutputSwitch, cliConfig, repoLocator, newCmdRunner, logger)
+}
+
+// newRpcService creates a service serving the API with server, so that
+// services can be created while another serves a plugin with the default one.
+func newRpcService(
+	server *rpc.Server,
+	outputCapture OutputCapture,
+	terminalOutputSwitch TerminalOutputSwitch,
+	cliConfig coreconfig.Repository,
+	repoLocator api.RepositoryLocator,
+	newCmdRunner CommandRunner,
+	logger trace.Printer,
 ) (*CliRpcService, error) {
 	rpcService := &CliRpcService{
 		RpcCmd: &CliRpcCmd{
@@ -74,7 +116,14 @@ func NewRpcService(
 		},
 	}
 
-	err := rpc.Register(rpcService.RpcCmd)
+	err := server.Register(rpcService.RpcCmd)
+	if err != nil {
+		return nil, err
+	}
+	rpcService.server = server
+
+	rpcService.handshakeServer = rpc.NewServer()
+	err = rpcService.handshakeServer.RegisterName("CliRpcCmd", &legacyHandshake{cmd: rpcService.RpcCmd})
 	if err != nil {
 		return nil, err
 	}
@@ -85,38 +134,158 @@ func NewRpcService(
 func (cli *CliRpcService) Stop() {
 	close(cli.stopCh)
 	cli.listener.Close()
+	cli.legacyListener.Close()
+	if cli.socketDir != "" {
+		os.RemoveAll(cli.socketDir)
+	}
 }
 
+// Port is the TCP port plugins built before the authenticated transport
+// connect to. It is passed to plugins as their first argument.
 func (cli *CliRpcService) Port() string {
-	return strconv.Itoa(cli.listener.Addr().(*net.TCPAddr).Port)
+	return strconv.Itoa(cli.legacyListener.Addr().(*net.TCPAddr).Port)
 }
 
+// Network and Address tell where the authenticated API is served: a unix
+// socket only the user can access, or a localhost TCP port on systems
+// without unix sockets.
+func (cli *CliRpcService) Network() string {
+	return cli.listener.Addr().Network()
+}
+
+func (cli *CliRpcService) Address() string {
+	return cli.listener.Addr().String()
+}
+
+// Secret is the secret every call to the authenticated API must carry. A new
+// one is generated each time the service starts.
+func (cli *CliRpcService) Secret() string {
+	return cli.secret
+}
+
+// Start serves the API to plugins over the authenticated transport. The TCP
+// port only accepts the metadata of plugins built before the authenticated
+// transport, for the CLI to find out that they need StartWithLegacyTransport.
 func (cli *CliRpcService) Start() error {
+	return cli.start(cli.handshakeServer)
+}
+
+// StartWithLegacyTransport serves the API like Start, and also offers it
+// without authentication on the TCP port, for plugins built before the
+// authenticated transport.
+func (cli *CliRpcService) StartWithLegacyTransport() error {
+	return cli.start(cli.server)
+}
+
+func (cli *CliRpcService) start(legacyServer *rpc.Server) error {
 	var err error
 
 	cli.stopCh = make(chan struct{})
+	cli.RpcCmd.metadataMutex.Lock()
+	cli.RpcCmd.LegacyPlugin = false
+	cli.RpcCmd.authenticatedPlugin = false
+	cli.RpcCmd.metadataMutex.Unlock()
 
-	cli.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	cli.secret, err = rpcauth.NewSecret()
 	if err != nil {
 		return err
 	}
 
-	go func() {
-		for {
-			conn, err := cli.listener.Accept()
-			if err != nil {
-				select {
-				case <-cli.stopCh:
-					return
-				default:
-					fmt.Println(err)
-				}
-			} else {
-				go rpc.ServeConn(conn)
+	cli.listener, cli.socketDir, err = listenPrivately()
+	if err != nil {
+		return err
+	}
+
+	cli.legacyListener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		cli.listener.Close()
+		os.RemoveAll(cli.socketDir)
+		return err
+	}
+
+	secret := cli.secret
+	go serve(cli.listener, cli.stopCh, func(conn io.ReadWriteCloser) {
+		cli.server.ServeCodec(rpcauth.NewServerCodec(conn, secret))
+	})
+	go serve(cli.legacyListener, cli.stopCh, legacyServer.ServeConn)
+
+	return nil
+}
+
+// serve accepts connections until stopCh is closed. The listener and stopCh
+// are those of one start, as the service may be started again before the
+// previous listeners are done.
+func serve(listener net.Listener, stopCh chan struct{}, serveConn func(io.ReadWriteCloser)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-stopCh:
+				return
+			default:
+				fmt.Println(err)
 			}
+		} else {
+			go serveConn(conn)
 		}
-	}()
+	}
+}
 
+// PluginCommand returns the command running the plugin at location with args,
+// passing it the TCP port as first argument and the authenticated transport
+// in its environment.
+func (cli *CliRpcService) PluginCommand(location string, args ...string) *exec.Cmd {
+	cmd := exec.Command(location, append([]string{cli.Port()}, args...)...)
+	cmd.Env = append(os.Environ(),
+		rpcauth.NetworkEnvKey+"="+cli.Network(),
+		rpcauth.AddressEnvKey+"="+cli.Address(),
+		rpcauth.SecretEnvKey+"="+cli.secret,
+	)
+	return cmd
+}
+
+// listenPrivately listens on a unix socket only the user can access, in a
+// new temporary directory, falling back to a localhost TCP port where unix
+// sockets are not available.
+func listenPrivately() (net.Listener, string, error) {
+	dir, err := ioutil.TempDir("", "cf-plugin-rpc")
+	if err != nil {
+		return nil, "", err
+	}
+
+	socketPath := filepath.Join(dir, "rpc.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err == nil {
+		err = os.Chmod(socketPath, 0600)
+		if err == nil {
+			return listener, dir, nil
+		}
+		listener.Close()
+	}
+	os.RemoveAll(dir)
+
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	return listener, "", err
+}
+
+// legacyHandshake is what the TCP port offers while the CLI fetches the
+// metadata of a plugin, so that plugins built before the authenticated
+// transport can still be installed. Any local process can reach the port, so
+// it is ignored once the plugin has sent its metadata over the authenticated
+// transport.
+type legacyHandshake struct {
+	cmd *CliRpcCmd
+}
+
+func (h *legacyHandshake) SetPluginMetadata(pluginMetadata plugin.PluginMetadata, retVal *bool) error {
+	h.cmd.metadataMutex.Lock()
+	defer h.cmd.metadataMutex.Unlock()
+
+	if !h.cmd.authenticatedPlugin {
+		h.cmd.LegacyPlugin = true
+		h.cmd.PluginMetadata = &pluginMetadata
+	}
+	*retVal = true
 	return nil
 }
 
@@ -142,11 +311,52 @@ func (cmd *CliRpcCmd) IsMinCliVersion(version string, retVal *bool) error {
 }
 
 func (cmd *CliRpcCmd) SetPluginMetadata(pluginMetadata plugin.PluginMetadata, retVal *bool) error {
+	cmd.metadataMutex.Lock()
+	defer cmd.metadataMutex.Unlock()
+
+	cmd.authenticatedPlugin = true
+	cmd.LegacyPlugin = false
 	cmd.PluginMetadata = &pluginMetadata
 	*retVal = true
 	return nil
 }
 
+func (cmd *CliRpcCmd) GetHookContext(_ string, retVal *plugin_models.HookContext) error {
+	cmd.hookMutex.Lock()
+	defer cmd.hookMutex.Unlock()
+
+	*retVal = cmd.hookContext
+	return nil
+}
+
+func (cmd *CliRpcCmd) SetHookResult(result plugin_models.HookResult, retVal *bool) error {
+	cmd.hookMutex.Lock()
+	defer cmd.hookMutex.Unlock()
+
+	cmd.hookResult = &result
+	*retVal = true
+	return nil
+}
+
+// startHook sets the hook the next plugin is run for, clearing the result of
+// the previous one.
+func (cmd *CliRpcCmd) startHook(hook plugin_models.HookContext) {
+	cmd.hookMutex.Lock()
+	defer cmd.hookMutex.Unlock()
+
+	cmd.servingHook = true
+	cmd.hookContext = hook
+	cmd.hookResult = nil
+}
+
+// hookOutcome is the result the plugin set for the hook, nil if it did not.
+func (cmd *CliRpcCmd) hookOutcome() *plugin_models.HookResult {
+	cmd.hookMutex.Lock()
+	defer cmd.hookMutex.Unlock()
+
+	return cmd.hookResult
+}
+
 func (cmd *CliRpcCmd) DisableTerminalOutput(disable bool, retVal *bool) error {
 	cmd.terminalOutputSwitch.DisableTerminalOutput(disable)
 	*retVal = true
@@ -175,7 +385,7 @@ func (cmd *CliRpcCmd) CallCoreCommand(args []string, retVal *bool) error {
 		//set command ui's TeePrinter to be the one used by RpcService, for output to be captured
 		deps.UI = terminal.NewUI(os.Stdin, cmd.outputCapture.(*terminal.TeePrinter), cmd.logger)
 
-		err = cmd.newCmdRunner.Command(args, deps, false)
+		err = cmd.runCommandWithHooks(args, deps)
 	} else {
 		*retVal = false
 		return nil
@@ -472,3 +682,367 @@ func (cmd *CliRpcCmd) GetService(serviceInstance string, retVal *plugin_models.G
 
 	return cmd.newCmdRunner.Command([]string{"service", serviceInstance}, deps, true)
 }
+
+func (cmd *CliRpcCmd) RpcApiVersion(_ string, retVal *int) error {
+	*retVal = plugin.RpcApiVersion
+
+	return nil
+}
+
+func (cmd *CliRpcCmd) GetRoutes(_ string, retVal *[]plugin_models.GetRoutes_Model) error {
+	if !cmd.cliConfig.HasSpace() {
+		return errors.New("No space targeted")
+	}
+
+	routes := []plugin_models.GetRoutes_Model{}
+	err := cmd.repoLocator.GetRouteRepository().ListRoutes(func(route models.Route) bool {
+		r := plugin_models.GetRoutes_Model{
+			Guid: route.GUID,
+			Host: route.Host,
+			Path: route.Path,
+			Port: route.Port,
+			Domain: plugin_models.GetRoutes_Domain{
+				Guid: route.Domain.GUID,
+				Name: route.Domain.Name,
+			},
+			Space: plugin_models.GetRoutes_Space{
+				Guid: route.Space.GUID,
+				Name: route.Space.Name,
+			},
+		}
+		for _, app := range route.Apps {
+			r.Apps = append(r.Apps, plugin_models.GetRoutes_App{Guid: app.GUID, Name: app.Name})
+		}
+		routes = append(routes, r)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	*retVal = routes
+	return nil
+}
+
+func (cmd *CliRpcCmd) GetDomains(_ string, retVal *[]plugin_models.GetDomains_Model) error {
+	if !cmd.cliConfig.HasOrganization() {
+		return errors.New("No org targeted")
+	}
+
+	domains := []plugin_models.GetDomains_Model{}
+	err := cmd.repoLocator.GetDomainRepository().ListDomainsForOrg(cmd.cliConfig.OrganizationFields().GUID, func(domain models.DomainFields) bool {
+		domains = append(domains, plugin_models.GetDomains_Model{
+			Guid:                   domain.GUID,
+			Name:                   domain.Name,
+			OwningOrganizationGuid: domain.OwningOrganizationGUID,
+			Shared:                 domain.Shared,
+			RouterGroupType:        domain.RouterGroupType,
+		})
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	*retVal = domains
+	return nil
+}
+
+func (cmd *CliRpcCmd) GetServiceKeys(serviceInstance string, retVal *[]plugin_models.GetServiceKeys_Model) error {
+	if !cmd.cliConfig.HasSpace() {
+		return errors.New("No space targeted")
+	}
+
+	instance, err := cmd.repoLocator.GetServiceRepository().FindInstanceByName(serviceInstance)
+	if err != nil {
+		return err
+	}
+
+	serviceKeys, err := cmd.repoLocator.GetServiceKeyRepository().ListServiceKeys(instance.GUID)
+	if err != nil {
+		return err
+	}
+
+	keys := []plugin_models.GetServiceKeys_Model{}
+	for _, serviceKey := range serviceKeys {
+		keys = append(keys, plugin_models.GetServiceKeys_Model{
+			Guid:                serviceKey.Fields.GUID,
+			Name:                serviceKey.Fields.Name,
+			ServiceInstanceGuid: instance.GUID,
+			Credentials:         serviceKey.Credentials,
+		})
+	}
+
+	*retVal = keys
+	return nil
+}
+
+func (cmd *CliRpcCmd) GetServiceBindings(serviceInstance string, retVal *[]plugin_models.GetServiceBindings_Model) error {
+	if !cmd.cliConfig.HasSpace() {
+		return errors.New("No space targeted")
+	}
+
+	instance, err := cmd.repoLocator.GetServiceRepository().FindInstanceByName(serviceInstance)
+	if err != nil {
+		return err
+	}
+
+	bindings := []plugin_models.GetServiceBindings_Model{}
+	for _, binding := range instance.ServiceBindings {
+		bindings = append(bindings, plugin_models.GetServiceBindings_Model{
+			Guid:    binding.GUID,
+			AppGuid: binding.AppGUID,
+			Url:     binding.URL,
+		})
+	}
+
+	*retVal = bindings
+	return nil
+}
+
+func (cmd *CliRpcCmd) GetAppEvents(appName string, retVal *[]plugin_models.GetAppEvents_Model) error {
+	if !cmd.cliConfig.HasSpace() {
+		return errors.New("No space targeted")
+	}
+
+	app, err := cmd.repoLocator.GetApplicationRepository().Read(appName)
+	if err != nil {
+		return err
+	}
+
+	appEvents, err := cmd.repoLocator.GetAppEventsRepository().RecentEvents(app.GUID, 50)
+	if err != nil {
+		return err
+	}
+
+	events := []plugin_models.GetAppEvents_Model{}
+	for _, event := range appEvents {
+		events = append(events, plugin_models.GetAppEvents_Model{
+			Guid:        event.GUID,
+			Name:        event.Name,
+			Timestamp:   event.Timestamp,
+			Description: event.Description,
+			ActorName:   event.ActorName,
+		})
+	}
+
+	*retVal = events
+	return nil
+}
+
+func (cmd *CliRpcCmd) GetSecurityGroups(_ string, retVal *[]plugin_models.GetSecurityGroups_Model) error {
+	securityGroups, err := cmd.repoLocator.GetSecurityGroupRepository().FindAll()
+	if err != nil {
+		return err
+	}
+
+	groups := []plugin_models.GetSecurityGroups_Model{}
+	for _, securityGroup := range securityGroups {
+		group := plugin_models.GetSecurityGroups_Model{
+			Guid:  securityGroup.GUID,
+			Name:  securityGroup.Name,
+			Rules: securityGroup.Rules,
+		}
+		for _, space := range securityGroup.Spaces {
+			group.Spaces = append(group.Spaces, plugin_models.GetSecurityGroups_Space{
+				Guid:    space.GUID,
+				Name:    space.Name,
+				OrgName: space.Organization.Name,
+			})
+		}
+		groups = append(groups, group)
+	}
+
+	*retVal = groups
+	return nil
+}
+
+func (cmd *CliRpcCmd) GetQuotas(_ string, retVal *[]plugin_models.GetQuotas_Model) error {
+	quotaFields, err := cmd.repoLocator.GetQuotaRepository().FindAll()
+	if err != nil {
+		return err
+	}
+
+	quotas := []plugin_models.GetQuotas_Model{}
+	for _, quota := range quotaFields {
+		quotas = append(quotas, plugin_models.GetQuotas_Model{
+			Guid:                    quota.GUID,
+			Name:                    quota.Name,
+			MemoryLimit:             quota.MemoryLimit,
+			InstanceMemoryLimit:     quota.InstanceMemoryLimit,
+			RoutesLimit:             quota.RoutesLimit,
+			ServicesLimit:           quota.ServicesLimit,
+			NonBasicServicesAllowed: quota.NonBasicServicesAllowed,
+			AppInstanceLimit:        quota.AppInstanceLimit,
+		})
+	}
+
+	*retVal = quotas
+	return nil
+}
+
+func (cmd *CliRpcCmd) GetStacks(_ string, retVal *[]plugin_models.GetStacks_Model) error {
+	stackModels, err := cmd.repoLocator.GetStackRepository().FindAll()
+	if err != nil {
+		return err
+	}
+
+	stacks := []plugin_models.GetStacks_Model{}
+	for _, stack := range stackModels {
+		stacks = append(stacks, plugin_models.GetStacks_Model{
+			Guid:        stack.GUID,
+			Name:        stack.Name,
+			Description: stack.Description,
+		})
+	}
+
+	*retVal = stacks
+	return nil
+}
+
+// CCRequest sends a request to the cloud controller the way `cf curl` does,
+// so that it uses the CLI's gateway, refreshes the access token when needed
+// and respects the SSL settings of the CLI.
+func (cmd *CliRpcCmd) CCRequest(args plugin_models.CCRequest_Args, retVal *plugin_models.CCResponse) error {
+	resHeaders, resBody, err := cmd.repoLocator.GetCurlRepository().Request(args.Method, args.Path, "", args.Body)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(resHeaders)), nil)
+	if err != nil {
+		return err
+	}
+
+	retVal.StatusCode = res.StatusCode
+	retVal.Header = res.Header
+	retVal.Body = resBody
+	return nil
+}
+
+// StartCoreCommandStream runs a core command in the background. Its output is
+// not printed to the terminal but kept for the plugin to read line by line
+// with GetCoreCommandStreamOutput. Only one streamed command runs at a time,
+// including a cancelled one that has not returned yet, as commands share the
+// config of the CLI.
+func (cmd *CliRpcCmd) StartCoreCommandStream(args []string, retVal *bool) error {
+	if len(args) == 0 || !commandregistry.Commands.CommandExists(args[0]) {
+		*retVal = false
+		return nil
+	}
+
+	cmd.streamMutex.Lock()
+	defer cmd.streamMutex.Unlock()
+
+	if cmd.stream != nil && cmd.stream.isRunning() {
+		return errors.New("Another streamed command is still running")
+	}
+
+	stream := newCoreCommandStream()
+	cmd.stream = stream
+
+	deps := commandregistry.NewDependency(cmd.logger)
+	deps.Config = cmd.cliConfig
+	deps.RepoLocator = cmd.repoLocator
+
+	printer := terminal.NewTeePrinter()
+	printer.DisableTerminalOutput(true)
+	printer.SetOutputBucket(stream)
+	deps.UI = terminal.NewUI(os.Stdin, printer, cmd.logger)
+
+	go func() {
+		var err error
+
+		defer func() {
+			if recover() != nil {
+				err = errors.New("Error executing cli core command")
+			}
+			stream.finish(err)
+		}()
+
+		err = cmd.runCommandWithHooks(args, deps)
+	}()
+
+	*retVal = true
+	return nil
+}
+
+// GetCoreCommandStreamOutput waits for the streamed command to print and
+// returns the new lines. Once the command is done it sets Done, or returns the
+// error of the command.
+func (cmd *CliRpcCmd) GetCoreCommandStreamOutput(_ bool, retVal *plugin_models.CoreCommandOutput) error {
+	cmd.streamMutex.Lock()
+	stream := cmd.stream
+	cmd.streamMutex.Unlock()
+
+	if stream == nil {
+		return errors.New("No streamed command was started")
+	}
+
+	lines, done, err := stream.next()
+	if err != nil {
+		return err
+	}
+
+	retVal.Lines = lines
+	retVal.Done = done
+	return nil
+}
+
+// CancelCoreCommandStream stops waiting for the streamed command and discards
+// the rest of its output. Core commands cannot be interrupted while they run,
+// so the command itself keeps running, and no other command can be streamed
+// until it returns.
+func (cmd *CliRpcCmd) CancelCoreCommandStream(_ bool, retVal *bool) error {
+	cmd.streamMutex.Lock()
+	defer cmd.streamMutex.Unlock()
+
+	*retVal = cmd.stream != nil && cmd.stream.cancel()
+	return nil
+}
+
+// runCommandWithHooks runs the core command a plugin asked for between the
+// hooks of the plugins subscribed to it, as when the user runs it, so that
+// plugins cannot get around pre hooks. Commands run by a plugin serving a
+// hook do not run hooks, so that hooks cannot recurse.
+func (cmd *CliRpcCmd) runCommandWithHooks(args []string, deps commandregistry.Dependency) error {
+	cmd.hookMutex.Lock()
+	servingHook := cmd.servingHook
+	cmd.hookMutex.Unlock()
+
+	meta := commandregistry.Commands.FindCommand(args[0]).MetaData()
+	if servingHook || !HasHooks(deps.PluginConfig.Plugins(), meta.Name) {
+		return cmd.newCmdRunner.Command(args, deps, false)
+	}
+
+	fc := flags.NewFlagContext(meta.Flags)
+	err := fc.Parse(args[1:]...)
+	if err != nil {
+		return err
+	}
+
+	hookService, err := newRpcService(rpc.NewServer(), cmd.outputCapture, cmd.terminalOutputSwitch, cmd.cliConfig, cmd.repoLocator, cmd.newCmdRunner, cmd.logger)
+	if err != nil {
+		return err
+	}
+
+	hook := NewHookContext(plugin.PreHook, meta, fc, deps.Config)
+	hookErrors := RunHooks(hookService, hook, deps.PluginConfig)
+	if len(hookErrors) > 0 {
+		return fmt.Errorf("Plugin %s stopped the command %s: %s", hookErrors[0].PluginName, meta.Name, hookErrors[0].Err.Error())
+	}
+
+	//commands fail by panicking too, post hooks then run on the way out
+	commandSucceeded := false
+	defer func() {
+		hook.Stage = string(plugin.PostHook)
+		hook.Failed = !commandSucceeded
+		for _, hookErr := range RunHooks(hookService, hook, deps.PluginConfig) {
+			deps.UI.Warn("The hook of plugin %s after the command %s failed: %s", hookErr.PluginName, meta.Name, hookErr.Err.Error())
+		}
+	}()
+
+	err = cmd.newCmdRunner.Command(args, deps, false)
+	commandSucceeded = err == nil
+	return err
+}
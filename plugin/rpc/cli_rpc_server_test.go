@@ -2,15 +2,25 @@ package rpc_test
 
 import (
 	"errors"
+	"io/ioutil"
 	"net"
 	"net/rpc"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/apifakes"
+	"github.com/cloudfoundry/cli/cf/api/appevents/appeventsfakes"
+	"github.com/cloudfoundry/cli/cf/api/applications/applicationsfakes"
 	"github.com/cloudfoundry/cli/cf/api/authentication/authenticationfakes"
+	"github.com/cloudfoundry/cli/cf/api/quotas/quotasfakes"
+	"github.com/cloudfoundry/cli/cf/api/securitygroups/securitygroupsfakes"
+	"github.com/cloudfoundry/cli/cf/api/stacks/stacksfakes"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
 	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/cloudfoundry/cli/plugin"
@@ -19,6 +29,7 @@ import (
 	cmdRunner "github.com/cloudfoundry/cli/plugin/rpc"
 	. "github.com/cloudfoundry/cli/plugin/rpc/fakecommand"
 	"github.com/cloudfoundry/cli/plugin/rpc/rpcfakes"
+	"github.com/cloudfoundry/cli/plugin/rpcauth"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -73,9 +84,17 @@ var _ = Describe("Server", func() {
 			//give time for server to stop
 			time.Sleep(50 * time.Millisecond)
 
-			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			client, err = dialCli(rpcService)
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("removes the directory of the unix socket", func() {
+			socketPath := rpcService.Address()
+			rpcService.Stop()
+
+			_, err := os.Stat(filepath.Dir(socketPath))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
 	})
 
 	Describe(".Start", func() {
@@ -97,8 +116,131 @@ var _ = Describe("Server", func() {
 		})
 
 		It("Start an Rpc server for communication", func() {
+			client, err = dialCli(rpcService)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("listens on a unix socket only the user can access", func() {
+			Expect(rpcService.Network()).To(Equal("unix"))
+
+			info, err := os.Stat(rpcService.Address())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("refuses calls that do not carry the secret", func() {
+			conn, err := net.Dial(rpcService.Network(), rpcService.Address())
+			Expect(err).ToNot(HaveOccurred())
+
+			client = rpc.NewClientWithCodec(rpcauth.NewClientCodec(conn, "not-the-secret"))
+
+			var result bool
+			err = client.Call("CliRpcCmd.IsLoggedIn", "", &result)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("only accepts the metadata of legacy plugins on the TCP port", func() {
+			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			Expect(err).ToNot(HaveOccurred())
+
+			var success bool
+			err = client.Call("CliRpcCmd.SetPluginMetadata", plugin.PluginMetadata{Name: "foo"}, &success)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rpcService.RpcCmd.PluginMetadata.Name).To(Equal("foo"))
+			Expect(rpcService.RpcCmd.LegacyPlugin).To(BeTrue())
+
+			var result bool
+			err = client.Call("CliRpcCmd.IsLoggedIn", "", &result)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("ignores the metadata sent on the TCP port once the plugin sent it over the authenticated transport", func() {
+			client, err = dialCli(rpcService)
+			Expect(err).ToNot(HaveOccurred())
+
+			var success bool
+			err = client.Call("CliRpcCmd.SetPluginMetadata", plugin.PluginMetadata{Name: "foo"}, &success)
+			Expect(err).ToNot(HaveOccurred())
+
+			legacyClient, err := rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			Expect(err).ToNot(HaveOccurred())
+			defer legacyClient.Close()
+
+			err = legacyClient.Call("CliRpcCmd.SetPluginMetadata", plugin.PluginMetadata{Name: "not-foo"}, &success)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rpcService.RpcCmd.PluginMetadata.Name).To(Equal("foo"))
+			Expect(rpcService.RpcCmd.LegacyPlugin).To(BeFalse())
+		})
+
+		It("does not record a legacy plugin when the metadata also came over the authenticated transport", func() {
+			legacyClient, err := rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			Expect(err).ToNot(HaveOccurred())
+			defer legacyClient.Close()
+
+			var success bool
+			err = legacyClient.Call("CliRpcCmd.SetPluginMetadata", plugin.PluginMetadata{Name: "not-foo"}, &success)
+			Expect(err).ToNot(HaveOccurred())
+
+			client, err = dialCli(rpcService)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.Call("CliRpcCmd.SetPluginMetadata", plugin.PluginMetadata{Name: "foo"}, &success)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rpcService.RpcCmd.PluginMetadata.Name).To(Equal("foo"))
+			Expect(rpcService.RpcCmd.LegacyPlugin).To(BeFalse())
+		})
+
+		It("serves again when started right after being stopped", func() {
+			rpcService.Stop()
+			err := rpcService.Start()
+			Expect(err).ToNot(HaveOccurred())
+
+			client, err = dialCli(rpcService)
+			Expect(err).ToNot(HaveOccurred())
+
+			var result bool
+			err = client.Call("CliRpcCmd.IsMinCliVersion", "6.0.0", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+
+		It("passes the port, the address and the secret to plugins", func() {
+			cmd := rpcService.PluginCommand("/path/to/plugin", "SendMetadata")
+
+			Expect(cmd.Args).To(Equal([]string{"/path/to/plugin", rpcService.Port(), "SendMetadata"}))
+			Expect(cmd.Env).To(ContainElement(rpcauth.NetworkEnvKey + "=unix"))
+			Expect(cmd.Env).To(ContainElement(rpcauth.AddressEnvKey + "=" + rpcService.Address()))
+			Expect(cmd.Env).To(ContainElement(rpcauth.SecretEnvKey + "=" + rpcService.Secret()))
+		})
+	})
+
+	Describe(".StartWithLegacyTransport", func() {
+		BeforeEach(func() {
+			config := testconfig.NewRepositoryWithDefaults()
+			rpcService, err = NewRpcService(nil, nil, config, api.RepositoryLocator{}, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			err := rpcService.StartWithLegacyTransport()
+			Expect(err).ToNot(HaveOccurred())
+
+			pingCli(rpcService.Port())
+		})
+
+		AfterEach(func() {
+			rpcService.Stop()
+
+			//give time for server to stop
+			time.Sleep(50 * time.Millisecond)
+		})
+
+		It("also offers the whole API on the TCP port", func() {
 			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
 			Expect(err).ToNot(HaveOccurred())
+
+			var result bool
+			err = client.Call("CliRpcCmd.IsLoggedIn", "", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeTrue())
 		})
 	})
 
@@ -112,7 +254,7 @@ var _ = Describe("Server", func() {
 
 			pingCli(rpcService.Port())
 
-			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			client, err = dialCli(rpcService)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
@@ -178,7 +320,7 @@ var _ = Describe("Server", func() {
 
 			pingCli(rpcService.Port())
 
-			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			client, err = dialCli(rpcService)
 			Expect(err).ToNot(HaveOccurred())
 
 			metadata = &plugin.PluginMetadata{
@@ -228,7 +370,7 @@ var _ = Describe("Server", func() {
 			})
 
 			It("should return the logs from the output capture", func() {
-				client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+				client, err = dialCli(rpcService)
 				Expect(err).ToNot(HaveOccurred())
 
 				success := false
@@ -262,7 +404,7 @@ var _ = Describe("Server", func() {
 		})
 
 		It("should disable the terminal output switch", func() {
-			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			client, err = dialCli(rpcService)
 			Expect(err).ToNot(HaveOccurred())
 
 			var success bool
@@ -292,7 +434,7 @@ var _ = Describe("Server", func() {
 
 			pingCli(rpcService.Port())
 
-			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			client, err = dialCli(rpcService)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
@@ -421,6 +563,426 @@ var _ = Describe("Server", func() {
 
 	})
 
+	Describe("Plugin API version 2", func() {
+		var (
+			config         coreconfig.Repository
+			routeRepo      *apifakes.FakeRouteRepository
+			domainRepo     *apifakes.FakeDomainRepository
+			serviceRepo    *apifakes.FakeServiceRepository
+			serviceKeyRepo *apifakes.FakeServiceKeyRepository
+			curlRepo       *apifakes.FakeCurlRepository
+			appRepo        *applicationsfakes.FakeApplicationRepository
+			appEventsRepo  *appeventsfakes.FakeAppEventsRepository
+			securityGroups *securitygroupsfakes.FakeSecurityGroupRepo
+			quotaRepo      *quotasfakes.FakeQuotaRepository
+			stackRepo      *stacksfakes.FakeStackRepository
+		)
+
+		BeforeEach(func() {
+			config = testconfig.NewRepositoryWithDefaults()
+			routeRepo = new(apifakes.FakeRouteRepository)
+			domainRepo = new(apifakes.FakeDomainRepository)
+			serviceRepo = new(apifakes.FakeServiceRepository)
+			serviceKeyRepo = new(apifakes.FakeServiceKeyRepository)
+			curlRepo = new(apifakes.FakeCurlRepository)
+			appRepo = new(applicationsfakes.FakeApplicationRepository)
+			appEventsRepo = new(appeventsfakes.FakeAppEventsRepository)
+			securityGroups = new(securitygroupsfakes.FakeSecurityGroupRepo)
+			quotaRepo = new(quotasfakes.FakeQuotaRepository)
+			stackRepo = new(stacksfakes.FakeStackRepository)
+		})
+
+		JustBeforeEach(func() {
+			locator := api.RepositoryLocator{}.
+				SetRouteRepository(routeRepo).
+				SetDomainRepository(domainRepo).
+				SetServiceRepository(serviceRepo).
+				SetServiceKeyRepository(serviceKeyRepo).
+				SetCurlRepository(curlRepo).
+				SetApplicationRepository(appRepo).
+				SetAppEventsRepository(appEventsRepo).
+				SetSecurityGroupRepository(securityGroups).
+				SetQuotaRepository(quotaRepo).
+				SetStackRepository(stackRepo)
+
+			rpcService, err = NewRpcService(nil, nil, config, locator, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			err := rpcService.Start()
+			Expect(err).ToNot(HaveOccurred())
+
+			pingCli(rpcService.Port())
+
+			client, err = dialCli(rpcService)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			rpcService.Stop()
+
+			//give time for server to stop
+			time.Sleep(50 * time.Millisecond)
+		})
+
+		It("returns the version of the RPC API", func() {
+			var result int
+			err = client.Call("CliRpcCmd.RpcApiVersion", "", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(plugin.RpcApiVersion))
+		})
+
+		It("returns the routes of the targeted space", func() {
+			routeRepo.ListRoutesStub = func(cb func(models.Route) bool) error {
+				cb(models.Route{
+					GUID:   "route-guid",
+					Host:   "my-host",
+					Path:   "/path",
+					Domain: models.DomainFields{GUID: "domain-guid", Name: "example.com"},
+					Space:  models.SpaceFields{GUID: "space-guid", Name: "my-space"},
+					Apps:   []models.ApplicationFields{{GUID: "app-guid", Name: "my-app"}},
+				})
+				return nil
+			}
+
+			var result []plugin_models.GetRoutes_Model
+			err = client.Call("CliRpcCmd.GetRoutes", "", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]plugin_models.GetRoutes_Model{{
+				Guid:   "route-guid",
+				Host:   "my-host",
+				Path:   "/path",
+				Domain: plugin_models.GetRoutes_Domain{Guid: "domain-guid", Name: "example.com"},
+				Space:  plugin_models.GetRoutes_Space{Guid: "space-guid", Name: "my-space"},
+				Apps:   []plugin_models.GetRoutes_App{{Guid: "app-guid", Name: "my-app"}},
+			}}))
+		})
+
+		Context("when no space is targeted", func() {
+			BeforeEach(func() {
+				config.SetSpaceFields(models.SpaceFields{})
+			})
+
+			It("returns an error instead of routes", func() {
+				var result []plugin_models.GetRoutes_Model
+				err = client.Call("CliRpcCmd.GetRoutes", "", &result)
+				Expect(err).To(MatchError("No space targeted"))
+				Expect(routeRepo.ListRoutesCallCount()).To(Equal(0))
+			})
+		})
+
+		It("returns the domains of the targeted org", func() {
+			domainRepo.ListDomainsForOrgStub = func(orgGUID string, cb func(models.DomainFields) bool) error {
+				cb(models.DomainFields{GUID: "domain-guid", Name: "example.com", Shared: true})
+				return nil
+			}
+
+			var result []plugin_models.GetDomains_Model
+			err = client.Call("CliRpcCmd.GetDomains", "", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]plugin_models.GetDomains_Model{{Guid: "domain-guid", Name: "example.com", Shared: true}}))
+
+			orgGUID, _ := domainRepo.ListDomainsForOrgArgsForCall(0)
+			Expect(orgGUID).To(Equal(config.OrganizationFields().GUID))
+		})
+
+		It("returns the keys of a service instance with their credentials", func() {
+			serviceRepo.FindInstanceByNameReturns(models.ServiceInstance{ServiceInstanceFields: models.ServiceInstanceFields{GUID: "instance-guid"}}, nil)
+			serviceKeyRepo.ListServiceKeysReturns([]models.ServiceKey{{
+				Fields:      models.ServiceKeyFields{GUID: "key-guid", Name: "my-key"},
+				Credentials: map[string]interface{}{"uri": "db://", "ports": []interface{}{float64(5432)}},
+			}}, nil)
+
+			var result []plugin_models.GetServiceKeys_Model
+			err = client.Call("CliRpcCmd.GetServiceKeys", "my-db", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]plugin_models.GetServiceKeys_Model{{
+				Guid:                "key-guid",
+				Name:                "my-key",
+				ServiceInstanceGuid: "instance-guid",
+				Credentials:         map[string]interface{}{"uri": "db://", "ports": []interface{}{float64(5432)}},
+			}}))
+
+			Expect(serviceRepo.FindInstanceByNameArgsForCall(0)).To(Equal("my-db"))
+			Expect(serviceKeyRepo.ListServiceKeysArgsForCall(0)).To(Equal("instance-guid"))
+		})
+
+		It("returns the error when the service instance cannot be found", func() {
+			serviceRepo.FindInstanceByNameReturns(models.ServiceInstance{}, errors.New("not found"))
+
+			var result []plugin_models.GetServiceBindings_Model
+			err = client.Call("CliRpcCmd.GetServiceBindings", "my-db", &result)
+			Expect(err).To(MatchError("not found"))
+		})
+
+		It("returns the bindings of a service instance", func() {
+			serviceRepo.FindInstanceByNameReturns(models.ServiceInstance{
+				ServiceBindings: []models.ServiceBindingFields{{GUID: "binding-guid", AppGUID: "app-guid", URL: "/v2/service_bindings/binding-guid"}},
+			}, nil)
+
+			var result []plugin_models.GetServiceBindings_Model
+			err = client.Call("CliRpcCmd.GetServiceBindings", "my-db", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]plugin_models.GetServiceBindings_Model{{Guid: "binding-guid", AppGuid: "app-guid", Url: "/v2/service_bindings/binding-guid"}}))
+		})
+
+		It("returns the recent events of an app", func() {
+			timestamp := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+			appRepo.ReadReturns(models.Application{ApplicationFields: models.ApplicationFields{GUID: "app-guid"}}, nil)
+			appEventsRepo.RecentEventsReturns([]models.EventFields{{GUID: "event-guid", Name: "audit.app.update", Timestamp: timestamp, ActorName: "admin"}}, nil)
+
+			var result []plugin_models.GetAppEvents_Model
+			err = client.Call("CliRpcCmd.GetAppEvents", "my-app", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Name).To(Equal("audit.app.update"))
+			Expect(result[0].Timestamp.Equal(timestamp)).To(BeTrue())
+
+			appGUID, limit := appEventsRepo.RecentEventsArgsForCall(0)
+			Expect(appGUID).To(Equal("app-guid"))
+			Expect(limit).To(Equal(int64(50)))
+		})
+
+		It("returns the security groups with their rules and spaces", func() {
+			securityGroups.FindAllReturns([]models.SecurityGroup{{
+				SecurityGroupFields: models.SecurityGroupFields{
+					GUID:  "group-guid",
+					Name:  "public",
+					Rules: []map[string]interface{}{{"protocol": "tcp", "destination": "0.0.0.0/0"}},
+				},
+				Spaces: []models.Space{{
+					SpaceFields:  models.SpaceFields{GUID: "space-guid", Name: "my-space"},
+					Organization: models.OrganizationFields{Name: "my-org"},
+				}},
+			}}, nil)
+
+			var result []plugin_models.GetSecurityGroups_Model
+			err = client.Call("CliRpcCmd.GetSecurityGroups", "", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]plugin_models.GetSecurityGroups_Model{{
+				Guid:   "group-guid",
+				Name:   "public",
+				Rules:  []map[string]interface{}{{"protocol": "tcp", "destination": "0.0.0.0/0"}},
+				Spaces: []plugin_models.GetSecurityGroups_Space{{Guid: "space-guid", Name: "my-space", OrgName: "my-org"}},
+			}}))
+		})
+
+		It("returns the quotas and stacks", func() {
+			quotaRepo.FindAllReturns([]models.QuotaFields{{GUID: "quota-guid", Name: "default", MemoryLimit: 1024, AppInstanceLimit: -1}}, nil)
+			stackRepo.FindAllReturns([]models.Stack{{GUID: "stack-guid", Name: "cflinuxfs2", Description: "Cloud Foundry Linux"}}, nil)
+
+			var quotas []plugin_models.GetQuotas_Model
+			err = client.Call("CliRpcCmd.GetQuotas", "", &quotas)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(quotas).To(Equal([]plugin_models.GetQuotas_Model{{Guid: "quota-guid", Name: "default", MemoryLimit: 1024, AppInstanceLimit: -1}}))
+
+			var stacks []plugin_models.GetStacks_Model
+			err = client.Call("CliRpcCmd.GetStacks", "", &stacks)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stacks).To(Equal([]plugin_models.GetStacks_Model{{Guid: "stack-guid", Name: "cflinuxfs2", Description: "Cloud Foundry Linux"}}))
+		})
+
+		Context(".CCRequest", func() {
+			It("sends the request through the curl repository and parses the response", func() {
+				curlRepo.RequestReturns("HTTP/1.1 404 Not Found\r\nContent-Type: application/json\r\n\r\n", `{"code":10000}`, nil)
+
+				var result plugin_models.CCResponse
+				err = client.Call("CliRpcCmd.CCRequest", plugin_models.CCRequest_Args{Method: "PUT", Path: "/v2/apps/guid", Body: `{"name":"new"}`}, &result)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.StatusCode).To(Equal(404))
+				Expect(result.Header["Content-Type"]).To(Equal([]string{"application/json"}))
+				Expect(result.Body).To(Equal(`{"code":10000}`))
+
+				method, path, headers, body := curlRepo.RequestArgsForCall(0)
+				Expect(method).To(Equal("PUT"))
+				Expect(path).To(Equal("/v2/apps/guid"))
+				Expect(headers).To(BeEmpty())
+				Expect(body).To(Equal(`{"name":"new"}`))
+			})
+
+			It("returns the error of the request", func() {
+				curlRepo.RequestReturns("", "", errors.New("connection refused"))
+
+				var result plugin_models.CCResponse
+				err = client.Call("CliRpcCmd.CCRequest", plugin_models.CCRequest_Args{Method: "GET", Path: "/v2/info"}, &result)
+				Expect(err).To(MatchError("connection refused"))
+			})
+		})
+	})
+
+	Describe("streaming core commands", func() {
+		var (
+			runner  *rpcfakes.FakeCommandRunner
+			release chan struct{}
+		)
+
+		BeforeEach(func() {
+			runner = new(rpcfakes.FakeCommandRunner)
+			release = make(chan struct{})
+
+			rpcService, err = NewRpcService(nil, nil, nil, api.RepositoryLocator{}, runner, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			err := rpcService.Start()
+			Expect(err).ToNot(HaveOccurred())
+
+			pingCli(rpcService.Port())
+
+			client, err = dialCli(rpcService)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			rpcService.Stop()
+
+			//give time for server to stop
+			time.Sleep(50 * time.Millisecond)
+		})
+
+		It("returns the output of the command line by line while it runs", func() {
+			runner.CommandStub = func(args []string, deps commandregistry.Dependency, pluginApiCall bool) error {
+				deps.UI.Say("first line")
+				<-release
+				deps.UI.Say("second line")
+				return nil
+			}
+
+			var started bool
+			err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			var output plugin_models.CoreCommandOutput
+			err = client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(Equal(plugin_models.CoreCommandOutput{Lines: []string{"first line"}}))
+
+			close(release)
+
+			output = plugin_models.CoreCommandOutput{}
+			err = client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.Lines).To(Equal([]string{"second line"}))
+
+			output = plugin_models.CoreCommandOutput{}
+			err = client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.Done).To(BeTrue())
+		})
+
+		It("does not start commands that do not exist", func() {
+			var started bool
+			err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"not_a_cmd"}, &started)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(started).To(BeFalse())
+			Expect(runner.CommandCallCount()).To(Equal(0))
+		})
+
+		It("returns the error of the command once its output was read", func() {
+			runner.CommandStub = func(args []string, deps commandregistry.Dependency, pluginApiCall bool) error {
+				deps.UI.Say("checking requirements")
+				return errors.New("something went wrong")
+			}
+
+			var started bool
+			err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+			Expect(err).ToNot(HaveOccurred())
+
+			var output plugin_models.CoreCommandOutput
+			err = client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.Lines).To(Equal([]string{"checking requirements"}))
+
+			err = client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &output)
+			Expect(err).To(MatchError("something went wrong"))
+		})
+
+		It("recovers from a panic of the command", func() {
+			runner.CommandStub = func(args []string, deps commandregistry.Dependency, pluginApiCall bool) error {
+				panic("boom")
+			}
+
+			var started bool
+			err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+			Expect(err).ToNot(HaveOccurred())
+
+			var output plugin_models.CoreCommandOutput
+			err = client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &output)
+			Expect(err).To(MatchError("Error executing cli core command"))
+		})
+
+		Context("when the command is cancelled", func() {
+			BeforeEach(func() {
+				//commands still running from other tests must not see the release of this one
+				release := release
+				runner.CommandStub = func(args []string, deps commandregistry.Dependency, pluginApiCall bool) error {
+					<-release
+					deps.UI.Say("too late")
+					return nil
+				}
+			})
+
+			AfterEach(func() {
+				select {
+				case <-release:
+				default:
+					close(release)
+				}
+			})
+
+			It("stops waiting for the command and returns an error", func() {
+				var started bool
+				err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+				Expect(err).ToNot(HaveOccurred())
+
+				outputErr := make(chan error)
+				go func() {
+					var output plugin_models.CoreCommandOutput
+					outputErr <- client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &output)
+				}()
+				Consistently(outputErr).ShouldNot(Receive())
+
+				var cancelled bool
+				err = client.Call("CliRpcCmd.CancelCoreCommandStream", true, &cancelled)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cancelled).To(BeTrue())
+
+				Eventually(outputErr).Should(Receive(MatchError("Command cancelled")))
+			})
+
+			It("allows another command to be streamed once the cancelled one has returned", func() {
+				var started bool
+				err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+				Expect(err).To(MatchError("Another streamed command is still running"))
+
+				var cancelled bool
+				err = client.Call("CliRpcCmd.CancelCoreCommandStream", true, &cancelled)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+				Expect(err).To(MatchError("Another streamed command is still running"))
+				Expect(runner.CommandCallCount()).To(Equal(1))
+
+				close(release)
+
+				Eventually(func() error {
+					return client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+				}).ShouldNot(HaveOccurred())
+				Expect(started).To(BeTrue())
+				Expect(runner.CommandCallCount()).To(Equal(2))
+			})
+		})
+
+		It("does nothing when cancelling without a running command", func() {
+			var cancelled bool
+			err = client.Call("CliRpcCmd.CancelCoreCommandStream", true, &cancelled)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cancelled).To(BeFalse())
+		})
+	})
+
 	Describe(".CallCoreCommand", func() {
 		var runner *rpcfakes.FakeCommandRunner
 
@@ -447,7 +1009,7 @@ var _ = Describe("Server", func() {
 			})
 
 			It("is able to call a command", func() {
-				client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+				client, err = dialCli(rpcService)
 				Expect(err).ToNot(HaveOccurred())
 
 				var success bool
@@ -461,6 +1023,86 @@ var _ = Describe("Server", func() {
 			})
 		})
 
+		Context("when plugins hook the command", func() {
+			var (
+				pluginHome         string
+				previousPluginHome string
+			)
+
+			BeforeEach(func() {
+				pluginHome, err = ioutil.TempDir("", "plugin-home")
+				Expect(err).ToNot(HaveOccurred())
+
+				hooksPath, err := filepath.Abs(filepath.Join("..", "..", "fixtures", "plugins", "hooks.exe"))
+				Expect(err).ToNot(HaveOccurred())
+
+				pluginConfig := pluginconfig.NewData()
+				pluginConfig.Plugins["Hooks"] = pluginconfig.PluginMetadata{
+					Location:         hooksPath,
+					AuthenticatedRPC: true,
+					Hooks: []plugin.Hook{
+						{Stage: plugin.PreHook, Command: "fake-command3"},
+						{Stage: plugin.PostHook, Command: "fake-command3"},
+					},
+				}
+				contents, err := pluginConfig.JSONMarshalV3()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(os.MkdirAll(filepath.Join(pluginHome, ".cf", "plugins"), 0700)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(pluginHome, ".cf", "plugins", "config.json"), contents, 0600)).To(Succeed())
+
+				previousPluginHome = os.Getenv("CF_PLUGIN_HOME")
+				os.Setenv("CF_PLUGIN_HOME", pluginHome)
+
+				runner = new(rpcfakes.FakeCommandRunner)
+				rpcService, err = NewRpcService(terminal.NewTeePrinter(), nil, testconfig.NewRepositoryWithDefaults(), api.RepositoryLocator{}, runner, nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = rpcService.Start()
+				Expect(err).ToNot(HaveOccurred())
+
+				client, err = dialCli(rpcService)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				rpcService.Stop()
+				os.Setenv("CF_PLUGIN_HOME", previousPluginHome)
+				os.Unsetenv("HOOKS_VETO")
+				os.RemoveAll(pluginHome)
+			})
+
+			It("runs the command between the hooks", func() {
+				var success bool
+				err = client.Call("CliRpcCmd.CallCoreCommand", []string{"fake-command3"}, &success)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(success).To(BeTrue())
+				Expect(runner.CommandCallCount()).To(Equal(1))
+			})
+
+			It("does not run the command when a pre hook fails", func() {
+				os.Setenv("HOOKS_VETO", "not from a plugin")
+
+				var success bool
+				err = client.Call("CliRpcCmd.CallCoreCommand", []string{"fake-command3"}, &success)
+				Expect(err).To(MatchError("Plugin Hooks stopped the command fake-command3: not from a plugin"))
+				Expect(runner.CommandCallCount()).To(Equal(0))
+			})
+
+			It("does not run the command of a streamed command when a pre hook fails", func() {
+				os.Setenv("HOOKS_VETO", "not from a plugin")
+
+				var started bool
+				err = client.Call("CliRpcCmd.StartCoreCommandStream", []string{"fake-command3"}, &started)
+				Expect(err).ToNot(HaveOccurred())
+
+				var output plugin_models.CoreCommandOutput
+				err = client.Call("CliRpcCmd.GetCoreCommandStreamOutput", true, &output)
+				Expect(err).To(MatchError("Plugin Hooks stopped the command fake-command3: not from a plugin"))
+				Expect(runner.CommandCallCount()).To(Equal(0))
+			})
+		})
+
 		Describe("CLI Config object methods", func() {
 			var (
 				config coreconfig.Repository
@@ -501,7 +1143,7 @@ var _ = Describe("Server", func() {
 				})
 
 				It("populates the plugin Organization object with the current org settings in config", func() {
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var org plugin_models.Organization
@@ -528,7 +1170,7 @@ var _ = Describe("Server", func() {
 				})
 
 				It("populates the plugin Space object with the current space settings in config", func() {
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var space plugin_models.Space
@@ -550,7 +1192,7 @@ var _ = Describe("Server", func() {
 				})
 
 				It("returns username, user guid and user email", func() {
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result string
@@ -579,7 +1221,7 @@ var _ = Describe("Server", func() {
 
 				It("returns the IsSSLDisabled setting in config", func() {
 					config.SetSSLDisabled(true)
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result bool
@@ -600,7 +1242,7 @@ var _ = Describe("Server", func() {
 
 				It("returns the IsLoggedIn setting in config", func() {
 					config.SetAccessToken("Logged-In-Token")
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result bool
@@ -620,7 +1262,7 @@ var _ = Describe("Server", func() {
 				})
 
 				It("returns the HasOrganization() and HasSpace() setting in config", func() {
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result bool
@@ -647,7 +1289,7 @@ var _ = Describe("Server", func() {
 					config.SetLoggregatorEndpoint("loggregator-endpoint-sample")
 					config.SetDopplerEndpoint("doppler-endpoint-sample")
 
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result string
@@ -674,7 +1316,7 @@ var _ = Describe("Server", func() {
 					config.SetAPIVersion("v1.1.1")
 					config.SetAPIEndpoint("www.fake-domain.com")
 
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result string
@@ -710,7 +1352,7 @@ var _ = Describe("Server", func() {
 				})
 
 				It("refreshes the token", func() {
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result string
@@ -723,7 +1365,7 @@ var _ = Describe("Server", func() {
 				It("returns the access token", func() {
 					authRepo.RefreshAuthTokenReturns("fake-access-token", nil)
 
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result string
@@ -735,7 +1377,7 @@ var _ = Describe("Server", func() {
 				It("returns the error from refreshing the access token", func() {
 					authRepo.RefreshAuthTokenReturns("", errors.New("refresh error"))
 
-					client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+					client, err = dialCli(rpcService)
 					Expect(err).ToNot(HaveOccurred())
 
 					var result string
@@ -759,7 +1401,7 @@ var _ = Describe("Server", func() {
 			})
 
 			It("returns false in success if the command cannot be found", func() {
-				client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+				client, err = dialCli(rpcService)
 				Expect(err).ToNot(HaveOccurred())
 
 				var success bool
@@ -769,7 +1411,7 @@ var _ = Describe("Server", func() {
 			})
 
 			It("returns an error if a command cannot parse provided flags", func() {
-				client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+				client, err = dialCli(rpcService)
 				Expect(err).ToNot(HaveOccurred())
 
 				var success bool
@@ -780,7 +1422,7 @@ var _ = Describe("Server", func() {
 			})
 
 			It("recovers from a panic from any core command", func() {
-				client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+				client, err = dialCli(rpcService)
 				Expect(err).ToNot(HaveOccurred())
 
 				var success bool
@@ -806,3 +1448,12 @@ func pingCli(port string) {
 	}
 	Expect(connErr).ToNot(HaveOccurred())
 }
+
+func dialCli(rpcService *CliRpcService) (*rpc.Client, error) {
+	conn, err := net.Dial(rpcService.Network(), rpcService.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	return rpc.NewClientWithCodec(rpcauth.NewClientCodec(conn, rpcService.Secret())), nil
+}
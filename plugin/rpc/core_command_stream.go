@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// coreCommandStream collects the output of a core command run for a plugin,
+// handing it out line by line as the command prints it.
+type coreCommandStream struct {
+	mutex   sync.Mutex
+	ready   *sync.Cond
+	lines   []string
+	partial string
+	done    bool
+	err     error
+	// returned is set once the command has returned, which for a cancelled
+	// command is later than done
+	returned bool
+}
+
+func newCoreCommandStream() *coreCommandStream {
+	stream := &coreCommandStream{}
+	stream.ready = sync.NewCond(&stream.mutex)
+	return stream
+}
+
+func (s *coreCommandStream) Write(p []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// output of a cancelled command is discarded
+	if s.done {
+		return len(p), nil
+	}
+
+	lines := strings.Split(s.partial+string(p), "\n")
+	s.partial = lines[len(lines)-1]
+	if len(lines) > 1 {
+		s.lines = append(s.lines, lines[:len(lines)-1]...)
+		s.ready.Broadcast()
+	}
+
+	return len(p), nil
+}
+
+// next waits for output and returns the lines printed since the last call.
+// Once the command is done and all of its output was read, it returns true
+// and the error of the command.
+func (s *coreCommandStream) next() ([]string, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for len(s.lines) == 0 && !s.done {
+		s.ready.Wait()
+	}
+
+	if len(s.lines) > 0 {
+		lines := s.lines
+		s.lines = nil
+		return lines, false, nil
+	}
+
+	return nil, true, s.err
+}
+
+func (s *coreCommandStream) finish(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.returned = true
+	if s.done {
+		return
+	}
+
+	if s.partial != "" {
+		s.lines = append(s.lines, s.partial)
+		s.partial = ""
+	}
+	s.done = true
+	s.err = err
+	s.ready.Broadcast()
+}
+
+// cancel stops the stream, discarding the output not read yet. The command
+// itself cannot be interrupted and keeps running until it returns. It returns
+// false when the command was already done.
+func (s *coreCommandStream) cancel() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.done {
+		return false
+	}
+
+	s.lines = nil
+	s.partial = ""
+	s.done = true
+	s.err = errors.New("Command cancelled")
+	s.ready.Broadcast()
+	return true
+}
+
+// isRunning tells whether the command has not returned yet, even if the
+// stream was cancelled.
+func (s *coreCommandStream) isRunning() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return !s.returned
+}
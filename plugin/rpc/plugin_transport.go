@@ -0,0 +1,54 @@
+package rpc
+
+import "github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+
+// DetectTransport finds out which transport the plugin was built for when its
+// metadata does not say, as for plugins installed before the CLI recorded it.
+// The plugin is asked for its metadata with only that handshake served on the
+// TCP port, the way install-plugin does. It returns the metadata with the
+// transport set, and whether it had to be detected and needs saving.
+func DetectTransport(rpcService *CliRpcService, metadata pluginconfig.PluginMetadata) (pluginconfig.PluginMetadata, bool, error) {
+	if metadata.AuthenticatedRPC || metadata.LegacyRPC {
+		return metadata, false, nil
+	}
+
+	err := rpcService.Start()
+	if err != nil {
+		return metadata, false, err
+	}
+
+	err = rpcService.PluginCommand(metadata.Location, "SendMetadata").Run()
+	rpcService.Stop()
+	if err != nil {
+		return metadata, false, err
+	}
+
+	metadata.LegacyRPC = rpcService.RpcCmd.LegacyPlugin
+	metadata.AuthenticatedRPC = !metadata.LegacyRPC
+	return metadata, true, nil
+}
+
+// StartForPlugin starts the service with the transport the plugin was built
+// for. Only plugins known to be built before the authenticated transport get
+// the whole API on the TCP port.
+func StartForPlugin(rpcService *CliRpcService, metadata pluginconfig.PluginMetadata) error {
+	if metadata.LegacyRPC {
+		return rpcService.StartWithLegacyTransport()
+	}
+	return rpcService.Start()
+}
+
+// startForInstalledPlugin detects the transport of the installed plugin name
+// if needed, saving it to the config, and starts the service with it.
+func startForInstalledPlugin(rpcService *CliRpcService, pluginConfig pluginconfig.PluginConfiguration, name string, metadata pluginconfig.PluginMetadata) error {
+	metadata, detected, err := DetectTransport(rpcService, metadata)
+	if err != nil {
+		return err
+	}
+
+	if detected {
+		pluginConfig.SetPlugin(name, metadata)
+	}
+
+	return StartForPlugin(rpcService, metadata)
+}
@@ -0,0 +1,88 @@
+package rpc_test
+
+import (
+	"net/rpc"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	. "github.com/cloudfoundry/cli/plugin/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugin transport", func() {
+	var rpcService *CliRpcService
+
+	BeforeEach(func() {
+		rpc.DefaultServer = rpc.NewServer()
+
+		var err error
+		rpcService, err = NewRpcService(nil, nil, nil, api.RepositoryLocator{}, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("DetectTransport", func() {
+		It("asks plugins installed before the transport was recorded for their metadata", func() {
+			metadata := pluginconfig.PluginMetadata{Location: filepath.Join("..", "..", "fixtures", "plugins", "test_1.exe")}
+
+			metadata, detected, err := DetectTransport(rpcService, metadata)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(detected).To(BeTrue())
+			Expect(metadata.AuthenticatedRPC).To(BeTrue())
+			Expect(metadata.LegacyRPC).To(BeFalse())
+		})
+
+		It("does not run plugins whose transport is known", func() {
+			metadata := pluginconfig.PluginMetadata{Location: filepath.Join("does", "not", "exist"), LegacyRPC: true}
+
+			detected, _, err := DetectTransport(rpcService, metadata)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(detected).To(Equal(metadata))
+		})
+
+		It("returns the error of running the plugin", func() {
+			metadata := pluginconfig.PluginMetadata{Location: filepath.Join("does", "not", "exist")}
+
+			_, detected, err := DetectTransport(rpcService, metadata)
+			Expect(err).To(HaveOccurred())
+			Expect(detected).To(BeFalse())
+		})
+	})
+
+	Describe("StartForPlugin", func() {
+		var client *rpc.Client
+
+		AfterEach(func() {
+			if client != nil {
+				client.Close()
+			}
+			rpcService.Stop()
+		})
+
+		It("only serves the metadata handshake on the TCP port to plugins not known to be legacy", func() {
+			err := StartForPlugin(rpcService, pluginconfig.PluginMetadata{})
+			Expect(err).ToNot(HaveOccurred())
+
+			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			Expect(err).ToNot(HaveOccurred())
+
+			var result bool
+			err = client.Call("CliRpcCmd.IsMinCliVersion", "6.0.0", &result)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("serves the whole API on the TCP port to legacy plugins", func() {
+			err := StartForPlugin(rpcService, pluginconfig.PluginMetadata{LegacyRPC: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			client, err = rpc.Dial("tcp", "127.0.0.1:"+rpcService.Port())
+			Expect(err).ToNot(HaveOccurred())
+
+			var result bool
+			err = client.Call("CliRpcCmd.IsMinCliVersion", "6.0.0", &result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+	})
+})
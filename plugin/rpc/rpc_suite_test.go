@@ -1,7 +1,10 @@
 package rpc_test
 
 import (
+	"path/filepath"
+
 	"github.com/cloudfoundry/cli/plugin/rpc"
+	"github.com/cloudfoundry/cli/testhelpers/pluginbuilder"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -12,5 +15,9 @@ var rpcService *rpc.CliRpcService
 
 func TestRpc(t *testing.T) {
 	RegisterFailHandler(Fail)
+
+	pluginbuilder.BuildTestBinary(filepath.Join("..", "..", "fixtures", "plugins"), "test_1")
+	pluginbuilder.BuildTestBinary(filepath.Join("..", "..", "fixtures", "plugins"), "hooks")
+
 	RunSpecs(t, "Rpc Suite")
 }
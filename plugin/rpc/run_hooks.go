@@ -0,0 +1,150 @@
+package rpc
+
+import (
+	"errors"
+	"os"
+	"sort"
+
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/coreconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/plugin"
+	"github.com/cloudfoundry/cli/plugin/models"
+)
+
+// HookError is the error a plugin returned from a hook, or the reason the
+// hook could not be run.
+type HookError struct {
+	PluginName string
+	Err        error
+}
+
+func (e *HookError) Error() string {
+	return e.PluginName + ": " + e.Err.Error()
+}
+
+// HasHooks tells whether any plugin hooks command, before or after it runs.
+func HasHooks(pluginList map[string]pluginconfig.PluginMetadata, command string) bool {
+	for _, metadata := range pluginList {
+		for _, hook := range metadata.Hooks {
+			if hook.Command == command {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewHookContext describes the core command about to run, or that just ran,
+// to the plugins hooking it.
+func NewHookContext(stage plugin.HookStage, meta commandregistry.CommandMetadata, fc flags.FlagContext, config coreconfig.Reader) plugin_models.HookContext {
+	flagValues := map[string]interface{}{}
+	for name, flag := range meta.Flags {
+		if !fc.IsSet(name) {
+			continue
+		}
+
+		switch flag.GetValue().(type) {
+		case bool:
+			flagValues[name] = fc.Bool(name)
+		case int:
+			flagValues[name] = fc.Int(name)
+		case float64:
+			flagValues[name] = fc.Float64(name)
+		case string:
+			flagValues[name] = fc.String(name)
+		case []string:
+			flagValues[name] = fc.StringSlice(name)
+		}
+	}
+
+	return plugin_models.HookContext{
+		Stage:   string(stage),
+		Command: meta.Name,
+		Args:    fc.Args(),
+		Flags:   flagValues,
+		Org: plugin_models.Organization{
+			OrganizationFields: plugin_models.OrganizationFields{
+				Guid: config.OrganizationFields().GUID,
+				Name: config.OrganizationFields().Name,
+			},
+		},
+		Space: plugin_models.Space{
+			SpaceFields: plugin_models.SpaceFields{
+				Guid: config.SpaceFields().GUID,
+				Name: config.SpaceFields().Name,
+			},
+		},
+		ApiEndpoint: config.APIEndpoint(),
+		Username:    config.Username(),
+	}
+}
+
+// RunHooks runs the plugins subscribed to the stage and command of hook, one
+// after the other in the order of their names. Pre hooks stop at the first
+// error, which vetoes the command; all post hooks run and their errors are
+// collected.
+func RunHooks(rpcService *CliRpcService, hook plugin_models.HookContext, pluginConfig pluginconfig.PluginConfiguration) []*HookError {
+	pluginList := pluginConfig.Plugins()
+
+	names := []string{}
+	for name, metadata := range pluginList {
+		if subscribes(metadata, plugin.HookStage(hook.Stage), hook.Command) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	hookErrors := []*HookError{}
+	for _, name := range names {
+		err := runHook(rpcService, hook, pluginConfig, name, pluginList[name])
+		if err != nil {
+			hookErrors = append(hookErrors, &HookError{PluginName: name, Err: err})
+			if plugin.HookStage(hook.Stage) == plugin.PreHook {
+				break
+			}
+		}
+	}
+
+	return hookErrors
+}
+
+func subscribes(metadata pluginconfig.PluginMetadata, stage plugin.HookStage, command string) bool {
+	for _, hook := range metadata.Hooks {
+		if hook.Stage == stage && hook.Command == command {
+			return true
+		}
+	}
+	return false
+}
+
+func runHook(rpcService *CliRpcService, hook plugin_models.HookContext, pluginConfig pluginconfig.PluginConfiguration, name string, metadata pluginconfig.PluginMetadata) error {
+	err := startForInstalledPlugin(rpcService, pluginConfig, name, metadata)
+	if err != nil {
+		return err
+	}
+	defer rpcService.Stop()
+
+	rpcService.RpcCmd.startHook(hook)
+
+	cmd := rpcService.PluginCommand(metadata.Location, "CLI-MESSAGE-HOOK")
+	cmd.Stdout = os.Stdout
+	cmd.Stdin = os.Stdin
+
+	err = cmd.Run()
+	if err != nil {
+		return err
+	}
+
+	result := rpcService.RpcCmd.hookOutcome()
+	if result == nil {
+		return errors.New("the plugin did not run the hook")
+	}
+
+	if result.Failed {
+		return errors.New(result.Message)
+	}
+
+	return nil
+}
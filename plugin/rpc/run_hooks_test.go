@@ -0,0 +1,147 @@
+package rpc_test
+
+import (
+	"net/rpc"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/commandregistry"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
+	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig/pluginconfigfakes"
+	"github.com/cloudfoundry/cli/flags"
+	"github.com/cloudfoundry/cli/plugin"
+	"github.com/cloudfoundry/cli/plugin/models"
+	. "github.com/cloudfoundry/cli/plugin/rpc"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hooks", func() {
+	var pluginList map[string]pluginconfig.PluginMetadata
+
+	BeforeEach(func() {
+		pluginList = map[string]pluginconfig.PluginMetadata{
+			"Before": {
+				Location: filepath.Join("does", "not", "exist", "before"),
+				Hooks:    []plugin.Hook{{Stage: plugin.PreHook, Command: "push"}},
+			},
+			"Around": {
+				Location: filepath.Join("does", "not", "exist", "around"),
+				Hooks: []plugin.Hook{
+					{Stage: plugin.PreHook, Command: "push"},
+					{Stage: plugin.PostHook, Command: "push"},
+				},
+			},
+			"Unrelated": {
+				Location: filepath.Join("does", "not", "exist", "unrelated"),
+				Hooks:    []plugin.Hook{{Stage: plugin.PostHook, Command: "delete"}},
+			},
+		}
+	})
+
+	Describe("HasHooks", func() {
+		It("tells whether any plugin hooks the command", func() {
+			Expect(HasHooks(pluginList, "push")).To(BeTrue())
+			Expect(HasHooks(pluginList, "delete")).To(BeTrue())
+			Expect(HasHooks(pluginList, "apps")).To(BeFalse())
+		})
+	})
+
+	Describe("NewHookContext", func() {
+		It("describes the command, the flags that were set and the target", func() {
+			meta := commandregistry.CommandMetadata{
+				Name: "push",
+				Flags: map[string]flags.FlagSet{
+					"i":          &flags.IntFlag{ShortName: "i"},
+					"no-start":   &flags.BoolFlag{Name: "no-start"},
+					"b":          &flags.StringFlag{ShortName: "b"},
+					"no-route":   &flags.BoolFlag{Name: "no-route"},
+					"hostnames":  &flags.StringSliceFlag{Name: "hostnames"},
+					"disk-quota": &flags.StringFlag{Name: "disk-quota"},
+				},
+			}
+			fc := flags.NewFlagContext(meta.Flags)
+			Expect(fc.Parse("my-app", "-i", "3", "--no-start", "-b", "go_buildpack", "--hostnames", "a", "--hostnames", "b")).To(Succeed())
+
+			config := testconfig.NewRepositoryWithDefaults()
+			config.SetAPIEndpoint("https://api.example.com")
+
+			hook := NewHookContext(plugin.PreHook, meta, fc, config)
+			Expect(hook.Stage).To(Equal("pre"))
+			Expect(hook.Command).To(Equal("push"))
+			Expect(hook.Args).To(Equal([]string{"my-app"}))
+			Expect(hook.Flags).To(Equal(map[string]interface{}{
+				"i":         3,
+				"no-start":  true,
+				"b":         "go_buildpack",
+				"hostnames": []string{"a", "b"},
+			}))
+			Expect(hook.Org.Name).To(Equal("my-org"))
+			Expect(hook.Org.Guid).To(Equal("my-org-guid"))
+			Expect(hook.Space.Name).To(Equal("my-space"))
+			Expect(hook.Space.Guid).To(Equal("my-space-guid"))
+			Expect(hook.ApiEndpoint).To(Equal("https://api.example.com"))
+			Expect(hook.Username).To(Equal("my-user"))
+		})
+	})
+
+	Describe("RunHooks", func() {
+		var (
+			rpcService   *CliRpcService
+			pluginConfig *pluginconfigfakes.FakePluginConfiguration
+		)
+
+		BeforeEach(func() {
+			rpc.DefaultServer = rpc.NewServer()
+
+			pluginConfig = new(pluginconfigfakes.FakePluginConfiguration)
+			pluginConfig.PluginsStub = func() map[string]pluginconfig.PluginMetadata { return pluginList }
+
+			var err error
+			rpcService, err = NewRpcService(nil, nil, nil, api.RepositoryLocator{}, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("stops at the first pre hook that fails", func() {
+			hookErrors := RunHooks(rpcService, plugin_models.HookContext{Stage: "pre", Command: "push"}, pluginConfig)
+			Expect(hookErrors).To(HaveLen(1))
+			Expect(hookErrors[0].PluginName).To(Equal("Around"))
+		})
+
+		It("runs every post hook of the command", func() {
+			pluginList["After"] = pluginconfig.PluginMetadata{
+				Location: filepath.Join("does", "not", "exist", "after"),
+				Hooks:    []plugin.Hook{{Stage: plugin.PostHook, Command: "push"}},
+			}
+
+			hookErrors := RunHooks(rpcService, plugin_models.HookContext{Stage: "post", Command: "push"}, pluginConfig)
+			Expect(hookErrors).To(HaveLen(2))
+			Expect(hookErrors[0].PluginName).To(Equal("After"))
+			Expect(hookErrors[1].PluginName).To(Equal("Around"))
+		})
+
+		It("does not run plugins that do not hook the command", func() {
+			hookErrors := RunHooks(rpcService, plugin_models.HookContext{Stage: "post", Command: "apps"}, pluginConfig)
+			Expect(hookErrors).To(BeEmpty())
+		})
+
+		It("saves the transport of plugins installed before it was recorded", func() {
+			pluginList = map[string]pluginconfig.PluginMetadata{
+				"Hooks": {
+					Location: filepath.Join("..", "..", "fixtures", "plugins", "hooks.exe"),
+					Hooks:    []plugin.Hook{{Stage: plugin.PostHook, Command: "version"}},
+				},
+			}
+
+			hookErrors := RunHooks(rpcService, plugin_models.HookContext{Stage: "post", Command: "version"}, pluginConfig)
+			Expect(hookErrors).To(BeEmpty())
+
+			Expect(pluginConfig.SetPluginCallCount()).To(Equal(1))
+			name, metadata := pluginConfig.SetPluginArgsForCall(0)
+			Expect(name).To(Equal("Hooks"))
+			Expect(metadata.AuthenticatedRPC).To(BeTrue())
+			Expect(metadata.LegacyRPC).To(BeFalse())
+		})
+	})
+})
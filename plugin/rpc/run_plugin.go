@@ -1,29 +1,32 @@
 package rpc
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 
 	"github.com/cloudfoundry/cli/cf/configuration/pluginconfig"
 )
 
-func RunMethodIfExists(rpcService *CliRpcService, args []string, pluginList map[string]pluginconfig.PluginMetadata) bool {
-	for _, metadata := range pluginList {
+func RunMethodIfExists(rpcService *CliRpcService, args []string, pluginConfig pluginconfig.PluginConfiguration) bool {
+	for name, metadata := range pluginConfig.Plugins() {
 		for _, command := range metadata.Commands {
 			if command.Name == args[0] || command.Alias == args[0] {
 				args[0] = command.Name
 
-				rpcService.Start()
+				err := startForInstalledPlugin(rpcService, pluginConfig, name, metadata)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
 				defer rpcService.Stop()
 
-				pluginArgs := append([]string{rpcService.Port()}, args...)
-
-				cmd := exec.Command(metadata.Location, pluginArgs...)
+				cmd := rpcService.PluginCommand(metadata.Location, args...)
 				cmd.Stdout = os.Stdout
 				cmd.Stdin = os.Stdin
 
 				defer stopPlugin(cmd)
-				err := cmd.Run()
+				err = cmd.Run()
 				if err != nil {
 					os.Exit(1)
 				}
@@ -0,0 +1,155 @@
+// Package rpcauth authenticates the RPC calls plugins make to the CLI. Every
+// request is preceded by a secret the CLI generates for each plugin
+// invocation and passes to the plugin in its environment.
+package rpcauth
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/rpc"
+)
+
+// Environment variables telling a plugin where the CLI serves the
+// authenticated RPC API and which secret to send with every call.
+const (
+	NetworkEnvKey = "CF_PLUGIN_RPC_NETWORK"
+	AddressEnvKey = "CF_PLUGIN_RPC_ADDRESS"
+	SecretEnvKey  = "CF_PLUGIN_RPC_SECRET"
+)
+
+var ErrInvalidSecret = errors.New("rpc: request does not carry the secret of the plugin invocation")
+
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+type clientCodec struct {
+	rwc    io.ReadWriteCloser
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+	secret string
+}
+
+// NewClientCodec returns the gob codec of net/rpc, sending secret ahead of
+// every request.
+func NewClientCodec(conn io.ReadWriteCloser, secret string) rpc.ClientCodec {
+	encBuf := bufio.NewWriter(conn)
+	return &clientCodec{
+		rwc:    conn,
+		dec:    gob.NewDecoder(conn),
+		enc:    gob.NewEncoder(encBuf),
+		encBuf: encBuf,
+		secret: secret,
+	}
+}
+
+func (c *clientCodec) WriteRequest(r *rpc.Request, body interface{}) error {
+	err := c.enc.Encode(c.secret)
+	if err != nil {
+		return err
+	}
+
+	err = c.enc.Encode(r)
+	if err != nil {
+		return err
+	}
+
+	err = c.enc.Encode(body)
+	if err != nil {
+		return err
+	}
+
+	return c.encBuf.Flush()
+}
+
+func (c *clientCodec) ReadResponseHeader(r *rpc.Response) error {
+	return c.dec.Decode(r)
+}
+
+func (c *clientCodec) ReadResponseBody(body interface{}) error {
+	return c.dec.Decode(body)
+}
+
+func (c *clientCodec) Close() error {
+	return c.rwc.Close()
+}
+
+type serverCodec struct {
+	rwc    io.ReadWriteCloser
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+	secret string
+	closed bool
+}
+
+// NewServerCodec returns the gob codec of net/rpc, refusing requests that do
+// not carry secret. The connection is closed on the first such request.
+func NewServerCodec(conn io.ReadWriteCloser, secret string) rpc.ServerCodec {
+	encBuf := bufio.NewWriter(conn)
+	return &serverCodec{
+		rwc:    conn,
+		dec:    gob.NewDecoder(conn),
+		enc:    gob.NewEncoder(encBuf),
+		encBuf: encBuf,
+		secret: secret,
+	}
+}
+
+func (c *serverCodec) ReadRequestHeader(r *rpc.Request) error {
+	var secret string
+	err := c.dec.Decode(&secret)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(c.secret)) != 1 {
+		return ErrInvalidSecret
+	}
+
+	return c.dec.Decode(r)
+}
+
+func (c *serverCodec) ReadRequestBody(body interface{}) error {
+	return c.dec.Decode(body)
+}
+
+func (c *serverCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	err := c.enc.Encode(r)
+	if err != nil {
+		if c.encBuf.Flush() == nil {
+			c.Close()
+		}
+		return err
+	}
+
+	err = c.enc.Encode(body)
+	if err != nil {
+		if c.encBuf.Flush() == nil {
+			c.Close()
+		}
+		return err
+	}
+
+	return c.encBuf.Flush()
+}
+
+func (c *serverCodec) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.rwc.Close()
+}
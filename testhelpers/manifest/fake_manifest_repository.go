@@ -6,16 +6,23 @@ import (
 
 type FakeManifestRepository struct {
 	ReadManifestArgs struct {
-		Path string
+		Path  string
+		Paths []string
 	}
 	ReadManifestReturns struct {
 		Manifest *manifest.Manifest
 		Error    error
 	}
+	ReadManifestStub func(inputPath string) (*manifest.Manifest, error)
 }
 
 func (repo *FakeManifestRepository) ReadManifest(inputPath string) (m *manifest.Manifest, err error) {
 	repo.ReadManifestArgs.Path = inputPath
+	repo.ReadManifestArgs.Paths = append(repo.ReadManifestArgs.Paths, inputPath)
+	if repo.ReadManifestStub != nil {
+		return repo.ReadManifestStub(inputPath)
+	}
+
 	if repo.ReadManifestReturns.Manifest != nil {
 		m = repo.ReadManifestReturns.Manifest
 	} else {
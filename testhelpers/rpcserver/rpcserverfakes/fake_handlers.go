@@ -280,6 +280,141 @@ type FakeHandlers struct {
 	getServiceReturns struct {
 		result1 error
 	}
+	RpcApiVersionStub        func(args string, retVal *int) error
+	rpcApiVersionMutex       sync.RWMutex
+	rpcApiVersionArgsForCall []struct {
+		args   string
+		retVal *int
+	}
+	rpcApiVersionReturns struct {
+		result1 error
+	}
+	GetRoutesStub        func(args string, retVal *[]plugin_models.GetRoutes_Model) error
+	getRoutesMutex       sync.RWMutex
+	getRoutesArgsForCall []struct {
+		args   string
+		retVal *[]plugin_models.GetRoutes_Model
+	}
+	getRoutesReturns struct {
+		result1 error
+	}
+	GetDomainsStub        func(args string, retVal *[]plugin_models.GetDomains_Model) error
+	getDomainsMutex       sync.RWMutex
+	getDomainsArgsForCall []struct {
+		args   string
+		retVal *[]plugin_models.GetDomains_Model
+	}
+	getDomainsReturns struct {
+		result1 error
+	}
+	GetServiceKeysStub        func(serviceInstance string, retVal *[]plugin_models.GetServiceKeys_Model) error
+	getServiceKeysMutex       sync.RWMutex
+	getServiceKeysArgsForCall []struct {
+		serviceInstance string
+		retVal          *[]plugin_models.GetServiceKeys_Model
+	}
+	getServiceKeysReturns struct {
+		result1 error
+	}
+	GetServiceBindingsStub        func(serviceInstance string, retVal *[]plugin_models.GetServiceBindings_Model) error
+	getServiceBindingsMutex       sync.RWMutex
+	getServiceBindingsArgsForCall []struct {
+		serviceInstance string
+		retVal          *[]plugin_models.GetServiceBindings_Model
+	}
+	getServiceBindingsReturns struct {
+		result1 error
+	}
+	GetAppEventsStub        func(appName string, retVal *[]plugin_models.GetAppEvents_Model) error
+	getAppEventsMutex       sync.RWMutex
+	getAppEventsArgsForCall []struct {
+		appName string
+		retVal  *[]plugin_models.GetAppEvents_Model
+	}
+	getAppEventsReturns struct {
+		result1 error
+	}
+	GetSecurityGroupsStub        func(args string, retVal *[]plugin_models.GetSecurityGroups_Model) error
+	getSecurityGroupsMutex       sync.RWMutex
+	getSecurityGroupsArgsForCall []struct {
+		args   string
+		retVal *[]plugin_models.GetSecurityGroups_Model
+	}
+	getSecurityGroupsReturns struct {
+		result1 error
+	}
+	GetQuotasStub        func(args string, retVal *[]plugin_models.GetQuotas_Model) error
+	getQuotasMutex       sync.RWMutex
+	getQuotasArgsForCall []struct {
+		args   string
+		retVal *[]plugin_models.GetQuotas_Model
+	}
+	getQuotasReturns struct {
+		result1 error
+	}
+	GetStacksStub        func(args string, retVal *[]plugin_models.GetStacks_Model) error
+	getStacksMutex       sync.RWMutex
+	getStacksArgsForCall []struct {
+		args   string
+		retVal *[]plugin_models.GetStacks_Model
+	}
+	getStacksReturns struct {
+		result1 error
+	}
+	CCRequestStub        func(args plugin_models.CCRequest_Args, retVal *plugin_models.CCResponse) error
+	cCRequestMutex       sync.RWMutex
+	cCRequestArgsForCall []struct {
+		args   plugin_models.CCRequest_Args
+		retVal *plugin_models.CCResponse
+	}
+	cCRequestReturns struct {
+		result1 error
+	}
+	StartCoreCommandStreamStub        func(args []string, retVal *bool) error
+	startCoreCommandStreamMutex       sync.RWMutex
+	startCoreCommandStreamArgsForCall []struct {
+		args   []string
+		retVal *bool
+	}
+	startCoreCommandStreamReturns struct {
+		result1 error
+	}
+	GetCoreCommandStreamOutputStub        func(args bool, retVal *plugin_models.CoreCommandOutput) error
+	getCoreCommandStreamOutputMutex       sync.RWMutex
+	getCoreCommandStreamOutputArgsForCall []struct {
+		args   bool
+		retVal *plugin_models.CoreCommandOutput
+	}
+	getCoreCommandStreamOutputReturns struct {
+		result1 error
+	}
+	CancelCoreCommandStreamStub        func(args bool, retVal *bool) error
+	cancelCoreCommandStreamMutex       sync.RWMutex
+	cancelCoreCommandStreamArgsForCall []struct {
+		args   bool
+		retVal *bool
+	}
+	cancelCoreCommandStreamReturns struct {
+		result1 error
+	}
+	GetHookContextStub        func(args string, retVal *plugin_models.HookContext) error
+	getHookContextMutex       sync.RWMutex
+	getHookContextArgsForCall []struct {
+		args   string
+		retVal *plugin_models.HookContext
+	}
+	getHookContextReturns struct {
+		result1 error
+	}
+	SetHookResultStub        func(args plugin_models.HookResult, retVal *bool) error
+	setHookResultMutex       sync.RWMutex
+	setHookResultArgsForCall []struct {
+		args   plugin_models.HookResult
+		retVal *bool
+	}
+	setHookResultReturns struct {
+		result1 error
+	}
 }
 
 func (fake *FakeHandlers) IsMinCliVersion(args string, retVal *bool) error {
@@ -1272,4 +1407,499 @@ func (fake *FakeHandlers) GetServiceReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeHandlers) RpcApiVersion(args string, retVal *int) error {
+	fake.rpcApiVersionMutex.Lock()
+	fake.rpcApiVersionArgsForCall = append(fake.rpcApiVersionArgsForCall, struct {
+		args   string
+		retVal *int
+	}{args, retVal})
+	fake.rpcApiVersionMutex.Unlock()
+	if fake.RpcApiVersionStub != nil {
+		return fake.RpcApiVersionStub(args, retVal)
+	} else {
+		return fake.rpcApiVersionReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) RpcApiVersionCallCount() int {
+	fake.rpcApiVersionMutex.RLock()
+	defer fake.rpcApiVersionMutex.RUnlock()
+	return len(fake.rpcApiVersionArgsForCall)
+}
+
+func (fake *FakeHandlers) RpcApiVersionArgsForCall(i int) (string, *int) {
+	fake.rpcApiVersionMutex.RLock()
+	defer fake.rpcApiVersionMutex.RUnlock()
+	return fake.rpcApiVersionArgsForCall[i].args, fake.rpcApiVersionArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) RpcApiVersionReturns(result1 error) {
+	fake.RpcApiVersionStub = nil
+	fake.rpcApiVersionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetRoutes(args string, retVal *[]plugin_models.GetRoutes_Model) error {
+	fake.getRoutesMutex.Lock()
+	fake.getRoutesArgsForCall = append(fake.getRoutesArgsForCall, struct {
+		args   string
+		retVal *[]plugin_models.GetRoutes_Model
+	}{args, retVal})
+	fake.getRoutesMutex.Unlock()
+	if fake.GetRoutesStub != nil {
+		return fake.GetRoutesStub(args, retVal)
+	} else {
+		return fake.getRoutesReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetRoutesCallCount() int {
+	fake.getRoutesMutex.RLock()
+	defer fake.getRoutesMutex.RUnlock()
+	return len(fake.getRoutesArgsForCall)
+}
+
+func (fake *FakeHandlers) GetRoutesArgsForCall(i int) (string, *[]plugin_models.GetRoutes_Model) {
+	fake.getRoutesMutex.RLock()
+	defer fake.getRoutesMutex.RUnlock()
+	return fake.getRoutesArgsForCall[i].args, fake.getRoutesArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetRoutesReturns(result1 error) {
+	fake.GetRoutesStub = nil
+	fake.getRoutesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetDomains(args string, retVal *[]plugin_models.GetDomains_Model) error {
+	fake.getDomainsMutex.Lock()
+	fake.getDomainsArgsForCall = append(fake.getDomainsArgsForCall, struct {
+		args   string
+		retVal *[]plugin_models.GetDomains_Model
+	}{args, retVal})
+	fake.getDomainsMutex.Unlock()
+	if fake.GetDomainsStub != nil {
+		return fake.GetDomainsStub(args, retVal)
+	} else {
+		return fake.getDomainsReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetDomainsCallCount() int {
+	fake.getDomainsMutex.RLock()
+	defer fake.getDomainsMutex.RUnlock()
+	return len(fake.getDomainsArgsForCall)
+}
+
+func (fake *FakeHandlers) GetDomainsArgsForCall(i int) (string, *[]plugin_models.GetDomains_Model) {
+	fake.getDomainsMutex.RLock()
+	defer fake.getDomainsMutex.RUnlock()
+	return fake.getDomainsArgsForCall[i].args, fake.getDomainsArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetDomainsReturns(result1 error) {
+	fake.GetDomainsStub = nil
+	fake.getDomainsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetServiceKeys(serviceInstance string, retVal *[]plugin_models.GetServiceKeys_Model) error {
+	fake.getServiceKeysMutex.Lock()
+	fake.getServiceKeysArgsForCall = append(fake.getServiceKeysArgsForCall, struct {
+		serviceInstance string
+		retVal          *[]plugin_models.GetServiceKeys_Model
+	}{serviceInstance, retVal})
+	fake.getServiceKeysMutex.Unlock()
+	if fake.GetServiceKeysStub != nil {
+		return fake.GetServiceKeysStub(serviceInstance, retVal)
+	} else {
+		return fake.getServiceKeysReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetServiceKeysCallCount() int {
+	fake.getServiceKeysMutex.RLock()
+	defer fake.getServiceKeysMutex.RUnlock()
+	return len(fake.getServiceKeysArgsForCall)
+}
+
+func (fake *FakeHandlers) GetServiceKeysArgsForCall(i int) (string, *[]plugin_models.GetServiceKeys_Model) {
+	fake.getServiceKeysMutex.RLock()
+	defer fake.getServiceKeysMutex.RUnlock()
+	return fake.getServiceKeysArgsForCall[i].serviceInstance, fake.getServiceKeysArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetServiceKeysReturns(result1 error) {
+	fake.GetServiceKeysStub = nil
+	fake.getServiceKeysReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetServiceBindings(serviceInstance string, retVal *[]plugin_models.GetServiceBindings_Model) error {
+	fake.getServiceBindingsMutex.Lock()
+	fake.getServiceBindingsArgsForCall = append(fake.getServiceBindingsArgsForCall, struct {
+		serviceInstance string
+		retVal          *[]plugin_models.GetServiceBindings_Model
+	}{serviceInstance, retVal})
+	fake.getServiceBindingsMutex.Unlock()
+	if fake.GetServiceBindingsStub != nil {
+		return fake.GetServiceBindingsStub(serviceInstance, retVal)
+	} else {
+		return fake.getServiceBindingsReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetServiceBindingsCallCount() int {
+	fake.getServiceBindingsMutex.RLock()
+	defer fake.getServiceBindingsMutex.RUnlock()
+	return len(fake.getServiceBindingsArgsForCall)
+}
+
+func (fake *FakeHandlers) GetServiceBindingsArgsForCall(i int) (string, *[]plugin_models.GetServiceBindings_Model) {
+	fake.getServiceBindingsMutex.RLock()
+	defer fake.getServiceBindingsMutex.RUnlock()
+	return fake.getServiceBindingsArgsForCall[i].serviceInstance, fake.getServiceBindingsArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetServiceBindingsReturns(result1 error) {
+	fake.GetServiceBindingsStub = nil
+	fake.getServiceBindingsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetAppEvents(appName string, retVal *[]plugin_models.GetAppEvents_Model) error {
+	fake.getAppEventsMutex.Lock()
+	fake.getAppEventsArgsForCall = append(fake.getAppEventsArgsForCall, struct {
+		appName string
+		retVal  *[]plugin_models.GetAppEvents_Model
+	}{appName, retVal})
+	fake.getAppEventsMutex.Unlock()
+	if fake.GetAppEventsStub != nil {
+		return fake.GetAppEventsStub(appName, retVal)
+	} else {
+		return fake.getAppEventsReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetAppEventsCallCount() int {
+	fake.getAppEventsMutex.RLock()
+	defer fake.getAppEventsMutex.RUnlock()
+	return len(fake.getAppEventsArgsForCall)
+}
+
+func (fake *FakeHandlers) GetAppEventsArgsForCall(i int) (string, *[]plugin_models.GetAppEvents_Model) {
+	fake.getAppEventsMutex.RLock()
+	defer fake.getAppEventsMutex.RUnlock()
+	return fake.getAppEventsArgsForCall[i].appName, fake.getAppEventsArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetAppEventsReturns(result1 error) {
+	fake.GetAppEventsStub = nil
+	fake.getAppEventsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetSecurityGroups(args string, retVal *[]plugin_models.GetSecurityGroups_Model) error {
+	fake.getSecurityGroupsMutex.Lock()
+	fake.getSecurityGroupsArgsForCall = append(fake.getSecurityGroupsArgsForCall, struct {
+		args   string
+		retVal *[]plugin_models.GetSecurityGroups_Model
+	}{args, retVal})
+	fake.getSecurityGroupsMutex.Unlock()
+	if fake.GetSecurityGroupsStub != nil {
+		return fake.GetSecurityGroupsStub(args, retVal)
+	} else {
+		return fake.getSecurityGroupsReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetSecurityGroupsCallCount() int {
+	fake.getSecurityGroupsMutex.RLock()
+	defer fake.getSecurityGroupsMutex.RUnlock()
+	return len(fake.getSecurityGroupsArgsForCall)
+}
+
+func (fake *FakeHandlers) GetSecurityGroupsArgsForCall(i int) (string, *[]plugin_models.GetSecurityGroups_Model) {
+	fake.getSecurityGroupsMutex.RLock()
+	defer fake.getSecurityGroupsMutex.RUnlock()
+	return fake.getSecurityGroupsArgsForCall[i].args, fake.getSecurityGroupsArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetSecurityGroupsReturns(result1 error) {
+	fake.GetSecurityGroupsStub = nil
+	fake.getSecurityGroupsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetQuotas(args string, retVal *[]plugin_models.GetQuotas_Model) error {
+	fake.getQuotasMutex.Lock()
+	fake.getQuotasArgsForCall = append(fake.getQuotasArgsForCall, struct {
+		args   string
+		retVal *[]plugin_models.GetQuotas_Model
+	}{args, retVal})
+	fake.getQuotasMutex.Unlock()
+	if fake.GetQuotasStub != nil {
+		return fake.GetQuotasStub(args, retVal)
+	} else {
+		return fake.getQuotasReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetQuotasCallCount() int {
+	fake.getQuotasMutex.RLock()
+	defer fake.getQuotasMutex.RUnlock()
+	return len(fake.getQuotasArgsForCall)
+}
+
+func (fake *FakeHandlers) GetQuotasArgsForCall(i int) (string, *[]plugin_models.GetQuotas_Model) {
+	fake.getQuotasMutex.RLock()
+	defer fake.getQuotasMutex.RUnlock()
+	return fake.getQuotasArgsForCall[i].args, fake.getQuotasArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetQuotasReturns(result1 error) {
+	fake.GetQuotasStub = nil
+	fake.getQuotasReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetStacks(args string, retVal *[]plugin_models.GetStacks_Model) error {
+	fake.getStacksMutex.Lock()
+	fake.getStacksArgsForCall = append(fake.getStacksArgsForCall, struct {
+		args   string
+		retVal *[]plugin_models.GetStacks_Model
+	}{args, retVal})
+	fake.getStacksMutex.Unlock()
+	if fake.GetStacksStub != nil {
+		return fake.GetStacksStub(args, retVal)
+	} else {
+		return fake.getStacksReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetStacksCallCount() int {
+	fake.getStacksMutex.RLock()
+	defer fake.getStacksMutex.RUnlock()
+	return len(fake.getStacksArgsForCall)
+}
+
+func (fake *FakeHandlers) GetStacksArgsForCall(i int) (string, *[]plugin_models.GetStacks_Model) {
+	fake.getStacksMutex.RLock()
+	defer fake.getStacksMutex.RUnlock()
+	return fake.getStacksArgsForCall[i].args, fake.getStacksArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetStacksReturns(result1 error) {
+	fake.GetStacksStub = nil
+	fake.getStacksReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) CCRequest(args plugin_models.CCRequest_Args, retVal *plugin_models.CCResponse) error {
+	fake.cCRequestMutex.Lock()
+	fake.cCRequestArgsForCall = append(fake.cCRequestArgsForCall, struct {
+		args   plugin_models.CCRequest_Args
+		retVal *plugin_models.CCResponse
+	}{args, retVal})
+	fake.cCRequestMutex.Unlock()
+	if fake.CCRequestStub != nil {
+		return fake.CCRequestStub(args, retVal)
+	} else {
+		return fake.cCRequestReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) CCRequestCallCount() int {
+	fake.cCRequestMutex.RLock()
+	defer fake.cCRequestMutex.RUnlock()
+	return len(fake.cCRequestArgsForCall)
+}
+
+func (fake *FakeHandlers) CCRequestArgsForCall(i int) (plugin_models.CCRequest_Args, *plugin_models.CCResponse) {
+	fake.cCRequestMutex.RLock()
+	defer fake.cCRequestMutex.RUnlock()
+	return fake.cCRequestArgsForCall[i].args, fake.cCRequestArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) CCRequestReturns(result1 error) {
+	fake.CCRequestStub = nil
+	fake.cCRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) StartCoreCommandStream(args []string, retVal *bool) error {
+	fake.startCoreCommandStreamMutex.Lock()
+	fake.startCoreCommandStreamArgsForCall = append(fake.startCoreCommandStreamArgsForCall, struct {
+		args   []string
+		retVal *bool
+	}{args, retVal})
+	fake.startCoreCommandStreamMutex.Unlock()
+	if fake.StartCoreCommandStreamStub != nil {
+		return fake.StartCoreCommandStreamStub(args, retVal)
+	} else {
+		return fake.startCoreCommandStreamReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) StartCoreCommandStreamCallCount() int {
+	fake.startCoreCommandStreamMutex.RLock()
+	defer fake.startCoreCommandStreamMutex.RUnlock()
+	return len(fake.startCoreCommandStreamArgsForCall)
+}
+
+func (fake *FakeHandlers) StartCoreCommandStreamArgsForCall(i int) ([]string, *bool) {
+	fake.startCoreCommandStreamMutex.RLock()
+	defer fake.startCoreCommandStreamMutex.RUnlock()
+	return fake.startCoreCommandStreamArgsForCall[i].args, fake.startCoreCommandStreamArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) StartCoreCommandStreamReturns(result1 error) {
+	fake.StartCoreCommandStreamStub = nil
+	fake.startCoreCommandStreamReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetCoreCommandStreamOutput(args bool, retVal *plugin_models.CoreCommandOutput) error {
+	fake.getCoreCommandStreamOutputMutex.Lock()
+	fake.getCoreCommandStreamOutputArgsForCall = append(fake.getCoreCommandStreamOutputArgsForCall, struct {
+		args   bool
+		retVal *plugin_models.CoreCommandOutput
+	}{args, retVal})
+	fake.getCoreCommandStreamOutputMutex.Unlock()
+	if fake.GetCoreCommandStreamOutputStub != nil {
+		return fake.GetCoreCommandStreamOutputStub(args, retVal)
+	} else {
+		return fake.getCoreCommandStreamOutputReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetCoreCommandStreamOutputCallCount() int {
+	fake.getCoreCommandStreamOutputMutex.RLock()
+	defer fake.getCoreCommandStreamOutputMutex.RUnlock()
+	return len(fake.getCoreCommandStreamOutputArgsForCall)
+}
+
+func (fake *FakeHandlers) GetCoreCommandStreamOutputArgsForCall(i int) (bool, *plugin_models.CoreCommandOutput) {
+	fake.getCoreCommandStreamOutputMutex.RLock()
+	defer fake.getCoreCommandStreamOutputMutex.RUnlock()
+	return fake.getCoreCommandStreamOutputArgsForCall[i].args, fake.getCoreCommandStreamOutputArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetCoreCommandStreamOutputReturns(result1 error) {
+	fake.GetCoreCommandStreamOutputStub = nil
+	fake.getCoreCommandStreamOutputReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) CancelCoreCommandStream(args bool, retVal *bool) error {
+	fake.cancelCoreCommandStreamMutex.Lock()
+	fake.cancelCoreCommandStreamArgsForCall = append(fake.cancelCoreCommandStreamArgsForCall, struct {
+		args   bool
+		retVal *bool
+	}{args, retVal})
+	fake.cancelCoreCommandStreamMutex.Unlock()
+	if fake.CancelCoreCommandStreamStub != nil {
+		return fake.CancelCoreCommandStreamStub(args, retVal)
+	} else {
+		return fake.cancelCoreCommandStreamReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) CancelCoreCommandStreamCallCount() int {
+	fake.cancelCoreCommandStreamMutex.RLock()
+	defer fake.cancelCoreCommandStreamMutex.RUnlock()
+	return len(fake.cancelCoreCommandStreamArgsForCall)
+}
+
+func (fake *FakeHandlers) CancelCoreCommandStreamArgsForCall(i int) (bool, *bool) {
+	fake.cancelCoreCommandStreamMutex.RLock()
+	defer fake.cancelCoreCommandStreamMutex.RUnlock()
+	return fake.cancelCoreCommandStreamArgsForCall[i].args, fake.cancelCoreCommandStreamArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) CancelCoreCommandStreamReturns(result1 error) {
+	fake.CancelCoreCommandStreamStub = nil
+	fake.cancelCoreCommandStreamReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) GetHookContext(args string, retVal *plugin_models.HookContext) error {
+	fake.getHookContextMutex.Lock()
+	fake.getHookContextArgsForCall = append(fake.getHookContextArgsForCall, struct {
+		args   string
+		retVal *plugin_models.HookContext
+	}{args, retVal})
+	fake.getHookContextMutex.Unlock()
+	if fake.GetHookContextStub != nil {
+		return fake.GetHookContextStub(args, retVal)
+	} else {
+		return fake.getHookContextReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) GetHookContextCallCount() int {
+	fake.getHookContextMutex.RLock()
+	defer fake.getHookContextMutex.RUnlock()
+	return len(fake.getHookContextArgsForCall)
+}
+
+func (fake *FakeHandlers) GetHookContextArgsForCall(i int) (string, *plugin_models.HookContext) {
+	fake.getHookContextMutex.RLock()
+	defer fake.getHookContextMutex.RUnlock()
+	return fake.getHookContextArgsForCall[i].args, fake.getHookContextArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) GetHookContextReturns(result1 error) {
+	fake.GetHookContextStub = nil
+	fake.getHookContextReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHandlers) SetHookResult(args plugin_models.HookResult, retVal *bool) error {
+	fake.setHookResultMutex.Lock()
+	fake.setHookResultArgsForCall = append(fake.setHookResultArgsForCall, struct {
+		args   plugin_models.HookResult
+		retVal *bool
+	}{args, retVal})
+	fake.setHookResultMutex.Unlock()
+	if fake.SetHookResultStub != nil {
+		return fake.SetHookResultStub(args, retVal)
+	} else {
+		return fake.setHookResultReturns.result1
+	}
+}
+
+func (fake *FakeHandlers) SetHookResultCallCount() int {
+	fake.setHookResultMutex.RLock()
+	defer fake.setHookResultMutex.RUnlock()
+	return len(fake.setHookResultArgsForCall)
+}
+
+func (fake *FakeHandlers) SetHookResultArgsForCall(i int) (plugin_models.HookResult, *bool) {
+	fake.setHookResultMutex.RLock()
+	defer fake.setHookResultMutex.RUnlock()
+	return fake.setHookResultArgsForCall[i].args, fake.setHookResultArgsForCall[i].retVal
+}
+
+func (fake *FakeHandlers) SetHookResultReturns(result1 error) {
+	fake.SetHookResultStub = nil
+	fake.setHookResultReturns = struct {
+		result1 error
+	}{result1}
+}
+
 var _ rpcserver.Handlers = new(FakeHandlers)
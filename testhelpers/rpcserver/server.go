@@ -46,6 +46,21 @@ type Handlers interface {
 	GetOrg(orgName string, retVal *plugin_models.GetOrg_Model) error
 	GetSpace(spaceName string, retVal *plugin_models.GetSpace_Model) error
 	GetService(serviceInstance string, retVal *plugin_models.GetService_Model) error
+	RpcApiVersion(args string, retVal *int) error
+	GetRoutes(args string, retVal *[]plugin_models.GetRoutes_Model) error
+	GetDomains(args string, retVal *[]plugin_models.GetDomains_Model) error
+	GetServiceKeys(serviceInstance string, retVal *[]plugin_models.GetServiceKeys_Model) error
+	GetServiceBindings(serviceInstance string, retVal *[]plugin_models.GetServiceBindings_Model) error
+	GetAppEvents(appName string, retVal *[]plugin_models.GetAppEvents_Model) error
+	GetSecurityGroups(args string, retVal *[]plugin_models.GetSecurityGroups_Model) error
+	GetQuotas(args string, retVal *[]plugin_models.GetQuotas_Model) error
+	GetStacks(args string, retVal *[]plugin_models.GetStacks_Model) error
+	CCRequest(args plugin_models.CCRequest_Args, retVal *plugin_models.CCResponse) error
+	StartCoreCommandStream(args []string, retVal *bool) error
+	GetCoreCommandStreamOutput(args bool, retVal *plugin_models.CoreCommandOutput) error
+	CancelCoreCommandStream(args bool, retVal *bool) error
+	GetHookContext(args string, retVal *plugin_models.HookContext) error
+	SetHookResult(args plugin_models.HookResult, retVal *bool) error
 }
 
 type TestServer struct {
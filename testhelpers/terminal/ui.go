@@ -24,6 +24,7 @@ type FakeUI struct {
 	FailedWithUsageCommandName string
 	PanickedQuietly            bool
 	ShowConfigurationCalled    bool
+	OutputFormat               term.OutputFormat
 
 	sayMutex sync.Mutex
 }
@@ -152,8 +153,9 @@ func (ui *FakeUI) Wait(duration time.Duration) {
 
 func (ui *FakeUI) Table(headers []string) *term.UITable {
 	return &term.UITable{
-		UI:    ui,
-		Table: term.NewTable(headers),
+		UI:           ui,
+		Table:        term.NewTable(headers),
+		OutputFormat: ui.OutputFormat,
 	}
 }
 